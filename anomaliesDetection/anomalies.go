@@ -16,6 +16,7 @@ package anomalies
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"time"
@@ -32,7 +33,8 @@ type (
 	// AnalyzedCostProductMeta can be the additional metadata in AnalyzedCostEssentialMeta.
 	// It's used to detect product anomalies and store them in ElasticSearch with more info.
 	AnalyzedCostProductMeta struct {
-		Product string
+		Product   string
+		Algorithm string
 	}
 
 	// AnalyzedCostEssentialMeta is the mandatory metadata ignored by the algorithm
@@ -42,7 +44,7 @@ type (
 		Date           string
 	}
 
-	// AnalyzedCost is returned by Bollinger Band algorithm and contains
+	// AnalyzedCost is returned by the detection algorithms and contains
 	// every necessary data for it. It also contains metadata, ignored by
 	// the algorithm.
 	AnalyzedCost struct {
@@ -79,13 +81,23 @@ type (
 	}
 )
 
-// RunAnomaliesDetection run every anomaly detection algorithms and store results in ElasticSearch.
-func RunAnomaliesDetection(account aws.AwsAccount, lastUpdate time.Time, ctx context.Context) (time.Time, error) {
+// RunAnomaliesDetection run the anomaly detection algorithms selected for the
+// account, store results in ElasticSearch and email the new anomalies to
+// the account owner.
+func RunAnomaliesDetection(account aws.AwsAccount, lastUpdate time.Time, tx *sql.Tx, ctx context.Context) (time.Time, error) {
 	esIndex := es.IndexNameForUserId(account.UserId, s3.IndexPrefixLineItem)
 	begin, end, err := getDateRange(account, lastUpdate, ctx)
 	if err != nil {
 		return begin, err
 	}
+	selection, err := getDetectorSelection(tx, account)
+	if err != nil {
+		return begin, err
+	}
+	settings, err := GetSettings(tx, account.Id)
+	if err != nil {
+		return begin, err
+	}
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	logger.Info("Starting anomalies detection", map[string]interface{}{
 		"awsAccount": account.Id,
@@ -98,7 +110,16 @@ func RunAnomaliesDetection(account aws.AwsAccount, lastUpdate time.Time, ctx con
 		Account:   account.AwsIdentity,
 		Index:     esIndex,
 	}
-	return end, runAnomaliesDetectionForProducts(parsedParams, account, ctx)
+	if err = runAnomaliesDetectionForProducts(parsedParams, account, selection, settings, ctx); err != nil {
+		return end, err
+	}
+	if err = emailAnomalies(ctx, tx, parsedParams, account, settings); err != nil {
+		logger.Error("Failed to email anomalies", map[string]interface{}{
+			"awsAccount": account.Id,
+			"error":      err.Error(),
+		})
+	}
+	return end, nil
 }
 
 // makeElasticSearchDateRangeRequest makes the ElasticSearch request to get begin or end date
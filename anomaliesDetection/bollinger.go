@@ -59,10 +59,16 @@ func deviation(sigma float64, period int) float64 {
 	return deviation
 }
 
-// analyseAnomalies calculates anomalies with Bollinger Bands algorithm and
-// const values above. It consists in generating an upper band, which, if
+// bollingerDetector detects anomalies with the Bollinger Bands algorithm.
+type bollingerDetector struct{}
+
+func (bollingerDetector) Name() string { return AlgorithmBollinger }
+func (bollingerDetector) Period() int  { return config.AnomalyDetectionBollingerBandPeriod }
+
+// Detect calculates anomalies with Bollinger Bands algorithm and
+// config values. It consists in generating an upper band, which, if
 // exceeded, make an alert.
-func analyseAnomalies(aCosts AnalyzedCosts) AnalyzedCosts {
+func (bollingerDetector) Detect(aCosts AnalyzedCosts) AnalyzedCosts {
 	for index := range aCosts {
 		if index > 0 {
 			a := &aCosts[index]
@@ -98,9 +104,9 @@ func addPadding(aCosts AnalyzedCosts, dateBegin time.Time) AnalyzedCosts {
 }
 
 // computeAnomalies calls every functions to well format
-// AnalyzedCosts and do BollingerBand.
-func computeAnomalies(ctx context.Context, aCosts AnalyzedCosts, dateBegin time.Time) AnalyzedCosts {
+// AnalyzedCosts and run the detector on them.
+func computeAnomalies(ctx context.Context, aCosts AnalyzedCosts, dateBegin time.Time, detector Detector) AnalyzedCosts {
 	aCosts = addPadding(aCosts, dateBegin)
-	aCosts = analyseAnomalies(aCosts)
+	aCosts = detector.Detect(aCosts)
 	return aCosts
 }
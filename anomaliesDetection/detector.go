@@ -0,0 +1,130 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/models"
+)
+
+const (
+	AlgorithmBollinger = "bollinger"
+	AlgorithmSeasonal  = "seasonal"
+	AlgorithmEwma      = "ewma"
+	AlgorithmMad       = "mad"
+)
+
+type (
+	// Detector is an anomaly detection algorithm. AnalyzedCosts are
+	// passed to it as a daily series without any gap: it sets the
+	// UpperBand and Anomaly fields of every element using only the
+	// elements which precede it.
+	Detector interface {
+		// Name returns the name of the algorithm, as stored in ElasticSearch.
+		Name() string
+		// Period returns the number of days of history needed to
+		// analyze the first day of a series.
+		Period() int
+		// Detect computes the upper band and flags the anomalies.
+		Detect(AnalyzedCosts) AnalyzedCosts
+	}
+
+	// detectorSelection contains the detectors chosen for an AWS account.
+	detectorSelection struct {
+		byProduct map[string]Detector
+		fallback  Detector
+	}
+)
+
+// detectors contains every available Detector by name.
+var detectors = map[string]Detector{
+	AlgorithmBollinger: bollingerDetector{},
+	AlgorithmSeasonal:  seasonalDetector{},
+	AlgorithmEwma:      ewmaDetector{},
+	AlgorithmMad:       madDetector{},
+}
+
+// GetDetector returns the Detector registered with the given name.
+func GetDetector(name string) (Detector, bool) {
+	d, ok := detectors[name]
+	return d, ok
+}
+
+// AlgorithmNames returns the sorted names of every available Detector.
+func AlgorithmNames() []string {
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultDetector returns the Detector set in the config, or the
+// Bollinger Band one if the config is invalid.
+func defaultDetector() Detector {
+	if d, ok := GetDetector(config.AnomalyDetectionAlgorithm); ok {
+		return d
+	}
+	return bollingerDetector{}
+}
+
+// maxDetectorPeriod returns the longest period required by the detectors.
+// It is used to fetch enough history before the analyzed date range.
+func maxDetectorPeriod() int {
+	var period int
+	for _, d := range detectors {
+		if p := d.Period(); p > period {
+			period = p
+		}
+	}
+	return period
+}
+
+// getDetectorSelection gets the detectors chosen for an AWS account in
+// the database. A row without product sets the default detector of the
+// account. Unknown algorithms are ignored.
+func getDetectorSelection(tx *sql.Tx, account aws.AwsAccount) (detectorSelection, error) {
+	selection := detectorSelection{
+		byProduct: make(map[string]Detector),
+		fallback:  defaultDetector(),
+	}
+	dbAlgorithms, err := models.AnomalyDetectionAlgorithmsByAwsAccountID(tx, account.Id)
+	if err != nil {
+		return selection, err
+	}
+	for _, dbAlgorithm := range dbAlgorithms {
+		if d, ok := GetDetector(dbAlgorithm.Algorithm); !ok {
+			continue
+		} else if dbAlgorithm.Product == "" {
+			selection.fallback = d
+		} else {
+			selection.byProduct[dbAlgorithm.Product] = d
+		}
+	}
+	return selection, nil
+}
+
+// forProduct returns the Detector to use for a product.
+func (s detectorSelection) forProduct(product string) Detector {
+	if d, ok := s.byProduct[product]; ok {
+		return d
+	}
+	return s.fallback
+}
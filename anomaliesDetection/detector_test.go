@@ -0,0 +1,168 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trackit/trackit/config"
+)
+
+// setDetectorsConfig sets the detectors config to its default values for
+// the duration of a test, so that the results do not depend on the flags.
+func setDetectorsConfig(t *testing.T) {
+	saved := []interface{}{
+		config.AnomalyDetectionBollingerBandPeriod,
+		config.AnomalyDetectionBollingerBandStandardDeviationCoefficient,
+		config.AnomalyDetectionBollingerBandUpperBandCoefficient,
+		config.AnomalyDetectionSeasonalWeeks,
+		config.AnomalyDetectionSeasonalStandardDeviationCoefficient,
+		config.AnomalyDetectionEwmaPeriod,
+		config.AnomalyDetectionEwmaAlpha,
+		config.AnomalyDetectionEwmaStandardDeviationCoefficient,
+		config.AnomalyDetectionMadPeriod,
+		config.AnomalyDetectionMadCoefficient,
+	}
+	config.AnomalyDetectionBollingerBandPeriod = 3
+	config.AnomalyDetectionBollingerBandStandardDeviationCoefficient = 3.0
+	config.AnomalyDetectionBollingerBandUpperBandCoefficient = 1.05
+	config.AnomalyDetectionSeasonalWeeks = 4
+	config.AnomalyDetectionSeasonalStandardDeviationCoefficient = 3.0
+	config.AnomalyDetectionEwmaPeriod = 7
+	config.AnomalyDetectionEwmaAlpha = 0.3
+	config.AnomalyDetectionEwmaStandardDeviationCoefficient = 3.0
+	config.AnomalyDetectionMadPeriod = 14
+	config.AnomalyDetectionMadCoefficient = 3.5
+	t.Cleanup(func() {
+		config.AnomalyDetectionBollingerBandPeriod = saved[0].(int)
+		config.AnomalyDetectionBollingerBandStandardDeviationCoefficient = saved[1].(float64)
+		config.AnomalyDetectionBollingerBandUpperBandCoefficient = saved[2].(float64)
+		config.AnomalyDetectionSeasonalWeeks = saved[3].(int)
+		config.AnomalyDetectionSeasonalStandardDeviationCoefficient = saved[4].(float64)
+		config.AnomalyDetectionEwmaPeriod = saved[5].(int)
+		config.AnomalyDetectionEwmaAlpha = saved[6].(float64)
+		config.AnomalyDetectionEwmaStandardDeviationCoefficient = saved[7].(float64)
+		config.AnomalyDetectionMadPeriod = saved[8].(int)
+		config.AnomalyDetectionMadCoefficient = saved[9].(float64)
+	})
+}
+
+// series builds a daily series of days costs with the given function.
+func series(days int, cost func(day int) float64) AnalyzedCosts {
+	aCosts := make(AnalyzedCosts, days)
+	for day := range aCosts {
+		aCosts[day].Cost = cost(day)
+	}
+	return aCosts
+}
+
+// withSpikes replaces the cost of some days of a series.
+func withSpikes(aCosts AnalyzedCosts, spikes map[int]float64) AnalyzedCosts {
+	for day, cost := range spikes {
+		aCosts[day].Cost = cost
+	}
+	return aCosts
+}
+
+// flat costs 10 every day.
+func flat(int) float64 { return 10 }
+
+// noisy costs 11, 9 and 10 in turn.
+func noisy(day int) float64 { return []float64{11, 9, 10}[day%3] }
+
+// weekly costs 10 every day but the last day of each week, which costs 30.
+func weekly(day int) float64 {
+	if day%daysInWeek == daysInWeek-1 {
+		return 30
+	}
+	return 10
+}
+
+// anomalousDays returns the days of a series flagged as anomalies.
+func anomalousDays(aCosts AnalyzedCosts) []int {
+	days := []int{}
+	for day, a := range aCosts {
+		if a.Anomaly {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func TestDetectors(t *testing.T) {
+	setDetectorsConfig(t)
+	for _, tc := range []struct {
+		name     string
+		detector Detector
+		aCosts   AnalyzedCosts
+		expected []int
+	}{
+		{"Bollinger flat", bollingerDetector{}, series(28, flat), []int{}},
+		{"Bollinger noisy", bollingerDetector{}, series(28, noisy), []int{}},
+		{"Bollinger spike", bollingerDetector{}, withSpikes(series(28, flat), map[int]float64{20: 50}), []int{20}},
+		{"Bollinger noisy spike", bollingerDetector{}, withSpikes(series(28, noisy), map[int]float64{20: 30}), []int{20}},
+		{"Bollinger weekly peaks", bollingerDetector{}, series(28, weekly), []int{6, 13, 20, 27}},
+		{"Seasonal flat", seasonalDetector{}, series(28, flat), []int{}},
+		{"Seasonal spike", seasonalDetector{}, withSpikes(series(28, flat), map[int]float64{20: 50}), []int{20}},
+		{"Seasonal weekly peaks", seasonalDetector{}, series(28, weekly), []int{}},
+		{"Seasonal spike among weekly peaks", seasonalDetector{}, withSpikes(series(28, weekly), map[int]float64{24: 40}), []int{24}},
+		{"Seasonal needs two previous weeks", seasonalDetector{}, withSpikes(series(28, flat), map[int]float64{10: 50}), []int{}},
+		{"EWMA flat", ewmaDetector{}, series(28, flat), []int{}},
+		{"EWMA noisy", ewmaDetector{}, series(28, noisy), []int{}},
+		{"EWMA spike", ewmaDetector{}, withSpikes(series(28, flat), map[int]float64{20: 50}), []int{20}},
+		{"EWMA noisy spike", ewmaDetector{}, withSpikes(series(28, noisy), map[int]float64{20: 30}), []int{20}},
+		{"EWMA warm up", ewmaDetector{}, withSpikes(series(28, flat), map[int]float64{3: 50}), []int{}},
+		{"MAD flat", madDetector{}, series(28, flat), []int{}},
+		{"MAD noisy", madDetector{}, series(28, noisy), []int{}},
+		{"MAD spike", madDetector{}, withSpikes(series(28, flat), map[int]float64{20: 50}), []int{20}},
+		{"MAD spike after a spike", madDetector{}, withSpikes(series(28, noisy), map[int]float64{20: 30, 23: 20}), []int{20, 23}},
+		{"MAD needs three previous days", madDetector{}, withSpikes(series(28, flat), map[int]float64{2: 50}), []int{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			aCosts := tc.detector.Detect(tc.aCosts)
+			if days := anomalousDays(aCosts); !reflect.DeepEqual(days, tc.expected) {
+				t.Errorf("Expected anomalies on days %v, got %v", tc.expected, days)
+			}
+			for day, a := range aCosts {
+				if a.Anomaly && a.Cost <= a.UpperBand {
+					t.Errorf("Day %d is an anomaly but its cost %f does not exceed its upper band %f", day, a.Cost, a.UpperBand)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectorsUpperBand(t *testing.T) {
+	setDetectorsConfig(t)
+	for _, tc := range []struct {
+		name     string
+		detector Detector
+		day      int
+		expected float64
+	}{
+		{"Bollinger", bollingerDetector{}, 10, 10.5},
+		{"Seasonal", seasonalDetector{}, 20, 10},
+		{"EWMA", ewmaDetector{}, 10, 10},
+		{"MAD", madDetector{}, 10, 10},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			aCosts := tc.detector.Detect(series(28, flat))
+			if upperBand := aCosts[tc.day].UpperBand; upperBand != tc.expected {
+				t.Errorf("Expected an upper band of %f on day %d, got %f", tc.expected, tc.day, upperBand)
+			}
+		})
+	}
+}
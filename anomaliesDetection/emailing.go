@@ -0,0 +1,140 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/mail"
+	"github.com/trackit/trackit/models"
+)
+
+// emailingMaxAge is the age above which an anomaly is not emailed anymore.
+// It prevents the owner of a newly added AWS account from receiving its
+// whole history of anomalies.
+const emailingMaxAge = 7 * 24 * time.Hour
+
+// emailedAnomaly is an anomaly which will be sent by email.
+type emailedAnomaly struct {
+	esProductAnomaly
+	date        time.Time
+	prettyLevel string
+}
+
+// emailAnomalies sends by email the anomalies of an AWS account which
+// reach the emailing min level of the account settings. Each anomaly is
+// only sent once.
+func emailAnomalies(ctx context.Context, tx *sql.Tx, params AnomalyEsQueryParams, account aws.AwsAccount, settings Settings) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	if minBegin := time.Now().UTC().Add(-emailingMaxAge); params.DateBegin.Before(minBegin) {
+		params.DateBegin = minBegin
+	}
+	if !params.DateBegin.Before(params.DateEnd) {
+		return nil
+	}
+	params.Index = es.IndexNameForUserId(account.UserId, IndexPrefixAnomaliesDetection)
+	raw, err := getAnomaliesFromEs(ctx, params)
+	if err != nil {
+		return err
+	}
+	toEmail, err := getAnomaliesToEmail(tx, raw, account, settings)
+	if err != nil || len(toEmail) == 0 {
+		return err
+	}
+	user, err := models.UserByID(tx, account.UserId)
+	if err != nil {
+		return err
+	}
+	if err = mail.SendMail(user.Email, getEmailSubject(account, toEmail), getEmailBody(account, toEmail), ctx); err != nil {
+		logger.Error("Failed to send anomalies email.", err.Error())
+		return err
+	}
+	for _, an := range toEmail {
+		dbEmailedAnomaly := models.EmailedAnomaly{
+			AwsAccountID: account.Id,
+			Product:      an.Product,
+			Recipient:    user.Email,
+			Date:         an.date,
+		}
+		if err = dbEmailedAnomaly.Insert(tx); err != nil {
+			return err
+		}
+	}
+	logger.Info("Anomalies emailed", map[string]interface{}{
+		"awsAccount": account.Id,
+		"amount":     len(toEmail),
+	})
+	return nil
+}
+
+// getAnomaliesToEmail filters the anomalies which are not recurrent, reach
+// the emailing min level and have not been emailed yet.
+func getAnomaliesToEmail(tx *sql.Tx, raw esProductAnomaliesWithId, account aws.AwsAccount, settings Settings) ([]emailedAnomaly, error) {
+	recurrent := make(map[string]bool)
+	for _, products := range transformAnomaliesToMap(raw) {
+		for _, an := range detectRecurrence(products, settings.RecurrenceThreshold) {
+			recurrent[an.Id] = true
+		}
+	}
+	res := make([]emailedAnomaly, 0)
+	for _, an := range raw {
+		if !an.Source.Abnormal || an.Source.Recurrent || recurrent[an.Id] {
+			continue
+		}
+		level, prettyLevel := settings.Level(an.Source.Cost.Value, an.Source.Cost.MaxExpected)
+		if level < settings.EmailingMinLevel {
+			continue
+		}
+		date, err := time.Parse("2006-01-02T15:04:05Z", an.Source.Date)
+		if err != nil {
+			continue
+		}
+		if emailed, err := models.IsAnomalyAlreadyEmailed(tx, account.Id, an.Source.Product, date); err != nil {
+			return nil, err
+		} else if !emailed {
+			res = append(res, emailedAnomaly{an.Source, date, prettyLevel})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].date.Before(res[j].date)
+	})
+	return res, nil
+}
+
+// getEmailSubject returns the subject of the anomalies email.
+func getEmailSubject(account aws.AwsAccount, anomalies []emailedAnomaly) string {
+	return fmt.Sprintf("%d new cost anomalies detected on %s", len(anomalies), account.Pretty)
+}
+
+// getEmailBody returns the body of the anomalies email.
+func getEmailBody(account aws.AwsAccount, anomalies []emailedAnomaly) string {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "New cost anomalies have been detected on your AWS account %s (%s):\n\n", account.Pretty, account.AwsIdentity)
+	for _, an := range anomalies {
+		fmt.Fprintf(&body, "- %s, %s: $%.2f spent instead of $%.2f at most expected (%s)\n",
+			an.date.Format("2006-01-02"), an.Product, an.Cost.Value, an.Cost.MaxExpected, an.prettyLevel)
+	}
+	body.WriteString("\nYou can see the details on https://re.trackit.io/.\n")
+	return body.String()
+}
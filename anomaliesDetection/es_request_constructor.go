@@ -18,8 +18,6 @@ import (
 	"time"
 
 	"github.com/olivere/elastic"
-
-	"github.com/trackit/trackit/config"
 )
 
 const (
@@ -37,9 +35,9 @@ func createQueryAccountFilter(account string) *elastic.TermQuery {
 
 // createQueryTimeRange creates and return a new *elastic.RangeQuery based on the duration
 // defined by durationBegin and durationEnd.
-// durationBegin is reduced by period. This offset is deleted later.
+// durationBegin is reduced by the longest period of the detectors. This offset is deleted later.
 func createQueryTimeRange(durationBegin time.Time, durationEnd time.Time) *elastic.RangeQuery {
-	periodDuration := time.Duration(config.AnomalyDetectionBollingerBandPeriod) * 24 * time.Hour
+	periodDuration := time.Duration(maxDetectorPeriod()) * 24 * time.Hour
 	durationBegin = durationBegin.Add(-periodDuration - 1)
 	return elastic.NewRangeQuery("usageStartDate").
 		From(durationBegin).To(durationEnd)
@@ -0,0 +1,52 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"math"
+
+	"github.com/trackit/trackit/config"
+)
+
+// ewmaDetector detects anomalies with an exponentially weighted moving
+// average and standard deviation. Recent days weigh more than older ones,
+// which lets the baseline follow a steadily growing spend.
+type ewmaDetector struct{}
+
+func (ewmaDetector) Name() string { return AlgorithmEwma }
+func (ewmaDetector) Period() int  { return config.AnomalyDetectionEwmaPeriod }
+
+// Detect compares every day to the weighted average and deviation of the
+// days preceding it, then updates them with the cost of the day. No day is
+// flagged before the warm up period is over.
+func (ewmaDetector) Detect(aCosts AnalyzedCosts) AnalyzedCosts {
+	alpha := config.AnomalyDetectionEwmaAlpha
+	var avg, variance float64
+	for index := range aCosts {
+		a := &aCosts[index]
+		if index == 0 {
+			avg = a.Cost
+			continue
+		}
+		a.UpperBand = avg + math.Sqrt(variance)*config.AnomalyDetectionEwmaStandardDeviationCoefficient
+		if index >= config.AnomalyDetectionEwmaPeriod && a.Cost > a.UpperBand {
+			a.Anomaly = true
+		}
+		diff := a.Cost - avg
+		avg += alpha * diff
+		variance = (1 - alpha) * (variance + alpha*diff*diff)
+	}
+	return aCosts
+}
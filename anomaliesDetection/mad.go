@@ -0,0 +1,72 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"math"
+	"sort"
+
+	"github.com/trackit/trackit/config"
+)
+
+// madScaleFactor makes the median absolute deviation a consistent
+// estimator of the standard deviation for normally distributed data.
+const madScaleFactor = 1.4826
+
+// madDetector detects anomalies with the median and the median absolute
+// deviation of the previous days. Unlike the average and the standard
+// deviation, they are not skewed by a past spike.
+type madDetector struct{}
+
+func (madDetector) Name() string { return AlgorithmMad }
+func (madDetector) Period() int  { return config.AnomalyDetectionMadPeriod }
+
+// median returns the median of a slice of float64. The slice is sorted.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
+
+// Detect generates, for every day, an upper band based on the median and
+// the scaled median absolute deviation of the previous days. At least
+// three previous days are needed to analyze a day.
+func (madDetector) Detect(aCosts AnalyzedCosts) AnalyzedCosts {
+	for index := range aCosts {
+		windowSize := min(index, config.AnomalyDetectionMadPeriod)
+		if windowSize < 3 {
+			continue
+		}
+		window := aCosts[index-windowSize : index]
+		values := make([]float64, len(window))
+		for i := range window {
+			values[i] = window[i].Cost
+		}
+		med := median(values)
+		for i := range values {
+			values[i] = math.Abs(values[i] - med)
+		}
+		mad := median(values)
+		a := &aCosts[index]
+		a.UpperBand = med + mad*madScaleFactor*config.AnomalyDetectionMadCoefficient
+		if a.Cost > a.UpperBand {
+			a.Anomaly = true
+		}
+	}
+	return aCosts
+}
@@ -42,7 +42,7 @@ func init() {
 const TemplateAnomaliesDetection = `
 {
 	"template": "*-` + IndexPrefixAnomaliesDetection + `",
-	"version": 2,
+	"version": 3,
 	"mappings": {
 		"` + TypeProductAnomaliesDetection + `": {
 			"properties": {
@@ -61,6 +61,9 @@ const TemplateAnomaliesDetection = `
 				"recurrent" : {
 					"type": "boolean"
 				},
+				"algorithm" : {
+					"type": "keyword"
+				},
 				"cost": {
 					"type": "object",
 					"properties": {
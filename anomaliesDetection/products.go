@@ -25,7 +25,6 @@ import (
 
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/aws/usageReports"
-	"github.com/trackit/trackit/config"
 	"github.com/trackit/trackit/es"
 )
 
@@ -43,6 +42,7 @@ type (
 		Product   string               `json:"product"`
 		Abnormal  bool                 `json:"abnormal"`
 		Recurrent bool                 `json:"recurrent"`
+		Algorithm string               `json:"algorithm"`
 		Cost      esProductAnomalyCost `json:"cost"`
 	}
 
@@ -81,12 +81,12 @@ type (
 
 // runAnomaliesDetectionForProducts will get data from ElasticSearch,
 // compute anomalies and ingest the result in ElasticSearch.
-func runAnomaliesDetectionForProducts(parsedParams AnomalyEsQueryParams, account aws.AwsAccount, ctx context.Context) (err error) {
+func runAnomaliesDetectionForProducts(parsedParams AnomalyEsQueryParams, account aws.AwsAccount, selection detectorSelection, settings Settings, ctx context.Context) (err error) {
 	var res AnalyzedCosts
-	if res, err = productGetAnomaliesData(ctx, parsedParams); err != nil {
+	if res, err = productGetAnomaliesData(ctx, parsedParams, selection, settings); err != nil {
 	} else if err = productSaveAnomaliesData(ctx, res, account); err != nil {
 	} else {
-		err = removeRecurrence(ctx, parsedParams, account)
+		err = removeRecurrence(ctx, parsedParams, account, settings)
 	}
 	return
 }
@@ -107,12 +107,14 @@ func productSaveAnomaliesData(ctx context.Context, aCosts AnalyzedCosts, account
 		return err
 	}
 	for _, aCost := range aCosts {
+		meta := aCost.Meta.AdditionalMeta.(AnalyzedCostProductMeta)
 		doc := esProductAnomaly{
 			Account:   account.AwsIdentity,
 			Date:      aCost.Meta.Date,
-			Product:   aCost.Meta.AdditionalMeta.(AnalyzedCostProductMeta).Product,
+			Product:   meta.Product,
 			Abnormal:  aCost.Anomaly,
 			Recurrent: false,
+			Algorithm: meta.Algorithm,
 			Cost: esProductAnomalyCost{
 				Value:       aCost.Cost,
 				MaxExpected: aCost.UpperBand,
@@ -159,14 +161,14 @@ func productGenerateElasticSearchDocumentId(doc esProductAnomaly) (id string, er
 	return
 }
 
-// productClearDisturbances clears fake alerts with thresholds in config.
-func productClearDisturbances(aCosts AnalyzedCosts, totalCostByDay totalCostByDay, highestSpendersByDay highestSpendersByDay) AnalyzedCosts {
+// productClearDisturbances clears fake alerts with thresholds in the account settings.
+func productClearDisturbances(aCosts AnalyzedCosts, totalCostByDay totalCostByDay, highestSpendersByDay highestSpendersByDay, settings Settings) AnalyzedCosts {
 	for index, aCost := range aCosts {
 		if aCost.Anomaly {
 			date := aCost.Meta.Date
 			increaseAmount := aCost.Cost - aCost.UpperBand
-			if increaseAmount < totalCostByDay[date]*config.AnomalyDetectionDisturbanceCleaningMinPercentOfDailyBill/100 ||
-				aCost.Cost < config.AnomalyDetectionDisturbanceCleaningMinAbsoluteCost {
+			if increaseAmount < totalCostByDay[date]*settings.MinPercentOfDailyBill/100 ||
+				aCost.Cost < settings.MinAbsoluteCost {
 				aCosts[index].Anomaly = false
 			} else {
 				spenderInPodium := false
@@ -195,7 +197,7 @@ func productAddCostToCosts(product string, cost float64, costs []costWithProduct
 }
 
 // productGetHighestSpendersByDay gets a podium of the highest spenders.
-func productGetHighestSpendersByDay(typedDocument esProductTypedResult) highestSpendersByDay {
+func productGetHighestSpendersByDay(typedDocument esProductTypedResult, settings Settings) highestSpendersByDay {
 	costByDayByProduct := map[string][]costWithProduct{}
 	for _, product := range typedDocument.Products.Buckets {
 		for _, date := range product.Dates.Buckets {
@@ -207,7 +209,7 @@ func productGetHighestSpendersByDay(typedDocument esProductTypedResult) highestS
 		sort.Slice(products, func(i, j int) bool {
 			return products[i].cost > products[j].cost
 		})
-		for i := 0; i < config.AnomalyDetectionDisturbanceCleaningHighestSpendingMinRank && i < len(products); i++ {
+		for i := 0; i < settings.HighestSpendingMinRank && i < len(products); i++ {
 			highestSpendersByDay[day] = append(highestSpendersByDay[day], products[i].product)
 		}
 	}
@@ -226,7 +228,8 @@ func productGetTotalCostByDay(typedDocument esProductTypedResult) totalCostByDay
 }
 
 // productGetAnomaliesData returns product anomalies based on query params, in JSON format.
-func productGetAnomaliesData(ctx context.Context, params AnomalyEsQueryParams) (AnalyzedCosts, error) {
+// Each product is analyzed by the detector selected for it.
+func productGetAnomaliesData(ctx context.Context, params AnomalyEsQueryParams, selection detectorSelection, settings Settings) (AnalyzedCosts, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	sr, err := makeElasticSearchRequest(ctx, getProductElasticSearchParams, params)
 	if err != nil {
@@ -240,14 +243,16 @@ func productGetAnomaliesData(ctx context.Context, params AnomalyEsQueryParams) (
 	}
 	totalAnalyzedCosts := make(AnalyzedCosts, 0)
 	totalCostsByDay := productGetTotalCostByDay(typedDocument)
-	highestSpendersByDay := productGetHighestSpendersByDay(typedDocument)
+	highestSpendersByDay := productGetHighestSpendersByDay(typedDocument, settings)
 	for _, product := range typedDocument.Products.Buckets {
+		detector := selection.forProduct(product.Key)
 		aCosts := make(AnalyzedCosts, 0, len(product.Dates.Buckets))
 		for _, date := range product.Dates.Buckets {
 			aCosts = append(aCosts, AnalyzedCost{
 				Meta: AnalyzedCostEssentialMeta{
 					AdditionalMeta: AnalyzedCostProductMeta{
-						Product: product.Key,
+						Product:   product.Key,
+						Algorithm: detector.Name(),
 					},
 					Date: date.Key,
 				},
@@ -255,10 +260,10 @@ func productGetAnomaliesData(ctx context.Context, params AnomalyEsQueryParams) (
 				Anomaly: false,
 			})
 		}
-		aCosts = computeAnomalies(ctx, aCosts, params.DateBegin)
+		aCosts = computeAnomalies(ctx, aCosts, params.DateBegin, detector)
 		aCosts = deleteOffset(aCosts, params.DateBegin)
 		totalAnalyzedCosts = append(totalAnalyzedCosts, aCosts...)
 	}
-	totalAnalyzedCosts = productClearDisturbances(totalAnalyzedCosts, totalCostsByDay, highestSpendersByDay)
+	totalAnalyzedCosts = productClearDisturbances(totalAnalyzedCosts, totalCostsByDay, highestSpendersByDay, settings)
 	return totalAnalyzedCosts, nil
 }
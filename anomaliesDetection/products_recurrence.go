@@ -9,7 +9,6 @@ import (
 
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/aws/usageReports"
-	"github.com/trackit/trackit/config"
 	"github.com/trackit/trackit/es"
 )
 
@@ -31,7 +30,7 @@ type (
 )
 
 // removeRecurrence gets all anomalies from ElasticSearch and removes recurrent anomalies.
-func removeRecurrence(ctx context.Context, params AnomalyEsQueryParams, account aws.AwsAccount) error {
+func removeRecurrence(ctx context.Context, params AnomalyEsQueryParams, account aws.AwsAccount, settings Settings) error {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	logger.Info("Removing recurrent anomalies", nil)
 	params.Index = es.IndexNameForUserId(account.UserId, IndexPrefixAnomaliesDetection)
@@ -41,7 +40,7 @@ func removeRecurrence(ctx context.Context, params AnomalyEsQueryParams, account
 		res := transformAnomaliesToMap(raw)
 		var recurrentAnomalies esProductAnomaliesWithId
 		for product := range res {
-			recurrentAnomalies = append(recurrentAnomalies, detectRecurrence(res[product])...)
+			recurrentAnomalies = append(recurrentAnomalies, detectRecurrence(res[product], settings.RecurrenceThreshold)...)
 		}
 		err := applyRecurrentAnomaliesToEs(ctx, account, recurrentAnomalies)
 		return err
@@ -82,18 +81,17 @@ func applyRecurrentAnomaliesToEs(ctx context.Context, account aws.AwsAccount, re
 }
 
 // approximateCostComparison compares two float64 with
-// an approximation of config.AnomalyDetectionRecurrenceCleaningThreshold.
+// an approximation of the threshold t.
 // For example +/- 10% if it is set to 0.1.
-func approximateCostComparison(a, b float64) bool {
-	t := config.AnomalyDetectionRecurrenceCleaningThreshold
+func approximateCostComparison(a, b, t float64) bool {
 	return a+a*t > b && a-a*t < b
 }
 
 // detectRecurrence detects recurrent anomalies.
-func detectRecurrence(an anomaliesByDate) (res esProductAnomaliesWithId) {
+func detectRecurrence(an anomaliesByDate, threshold float64) (res esProductAnomaliesWithId) {
 	for date := range an {
 		prev := date.AddDate(0, -1, 0)
-		if an[prev].Source.Abnormal && approximateCostComparison(an[date].Source.Cost.Value, an[prev].Source.Cost.Value) {
+		if an[prev].Source.Abnormal && approximateCostComparison(an[date].Source.Cost.Value, an[prev].Source.Cost.Value, threshold) {
 			res = append(res, an[date])
 		}
 	}
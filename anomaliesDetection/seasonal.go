@@ -0,0 +1,55 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"math"
+
+	"github.com/trackit/trackit/config"
+)
+
+// daysInWeek is the distance between two same weekdays in a daily series.
+const daysInWeek = 7
+
+// seasonalDetector detects anomalies by comparing a day with the same
+// weekday of the previous weeks. It avoids alerts on days whose cost
+// is always higher, such as the day after weekly batch jobs.
+type seasonalDetector struct{}
+
+func (seasonalDetector) Name() string { return AlgorithmSeasonal }
+func (seasonalDetector) Period() int  { return config.AnomalyDetectionSeasonalWeeks * daysInWeek }
+
+// Detect generates, for every day, an upper band based on the average
+// and the standard deviation of the same weekday in the previous weeks.
+// At least two previous weeks are needed to analyze a day.
+func (seasonalDetector) Detect(aCosts AnalyzedCosts) AnalyzedCosts {
+	for index := range aCosts {
+		var sameWeekdays AnalyzedCosts
+		for week := 1; week <= config.AnomalyDetectionSeasonalWeeks && index-week*daysInWeek >= 0; week++ {
+			sameWeekdays = append(sameWeekdays, aCosts[index-week*daysInWeek])
+		}
+		if len(sameWeekdays) < 2 {
+			continue
+		}
+		a := &aCosts[index]
+		avg := average(sameWeekdays)
+		deviation := math.Sqrt(sigma(sameWeekdays, avg) / float64(len(sameWeekdays)))
+		a.UpperBand = avg + deviation*config.AnomalyDetectionSeasonalStandardDeviationCoefficient
+		if a.Cost > a.UpperBand {
+			a.Anomaly = true
+		}
+	}
+	return aCosts
+}
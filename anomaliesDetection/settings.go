@@ -0,0 +1,141 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/models"
+)
+
+// Settings are the thresholds used to clean and classify the anomalies of
+// an AWS account. Every field not set in the database falls back to the
+// value of its flag in config.
+type Settings struct {
+	Levels                 string  `json:"levels"`
+	PrettyLevels           string  `json:"prettyLevels"`
+	MinPercentOfDailyBill  float64 `json:"minPercentOfDailyBill"`
+	MinAbsoluteCost        float64 `json:"minAbsoluteCost"`
+	HighestSpendingMinRank int     `json:"highestSpendingMinRank"`
+	RecurrenceThreshold    float64 `json:"recurrenceThreshold"`
+	EmailingMinLevel       int     `json:"emailingMinLevel"`
+}
+
+// DefaultSettings returns the Settings set in config.
+func DefaultSettings() Settings {
+	return Settings{
+		Levels:                 config.AnomalyDetectionLevels,
+		PrettyLevels:           config.AnomalyDetectionPrettyLevels,
+		MinPercentOfDailyBill:  config.AnomalyDetectionDisturbanceCleaningMinPercentOfDailyBill,
+		MinAbsoluteCost:        config.AnomalyDetectionDisturbanceCleaningMinAbsoluteCost,
+		HighestSpendingMinRank: config.AnomalyDetectionDisturbanceCleaningHighestSpendingMinRank,
+		RecurrenceThreshold:    config.AnomalyDetectionRecurrenceCleaningThreshold,
+		EmailingMinLevel:       config.AnomalyEmailingMinLevel,
+	}
+}
+
+// GetSettings returns the Settings of an AWS account: the default ones
+// overridden by the values stored in the database.
+func GetSettings(db models.DB, awsAccountId int) (Settings, error) {
+	settings := DefaultSettings()
+	dbSettings, err := models.AnomalySettingByAwsAccountID(db, awsAccountId)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	} else if err != nil {
+		return settings, err
+	}
+	return settings.Override(dbSettings), nil
+}
+
+// Override returns a copy of s with the fields set in dbSettings.
+func (s Settings) Override(dbSettings *models.AnomalySetting) Settings {
+	if dbSettings.Levels.Valid {
+		s.Levels = dbSettings.Levels.String
+	}
+	if dbSettings.PrettyLevels.Valid {
+		s.PrettyLevels = dbSettings.PrettyLevels.String
+	}
+	if dbSettings.MinPercentOfDailyBill.Valid {
+		s.MinPercentOfDailyBill = dbSettings.MinPercentOfDailyBill.Float64
+	}
+	if dbSettings.MinAbsoluteCost.Valid {
+		s.MinAbsoluteCost = dbSettings.MinAbsoluteCost.Float64
+	}
+	if dbSettings.HighestSpendingMinRank.Valid {
+		s.HighestSpendingMinRank = int(dbSettings.HighestSpendingMinRank.Int64)
+	}
+	if dbSettings.RecurrenceThreshold.Valid {
+		s.RecurrenceThreshold = dbSettings.RecurrenceThreshold.Float64
+	}
+	if dbSettings.EmailingMinLevel.Valid {
+		s.EmailingMinLevel = int(dbSettings.EmailingMinLevel.Int64)
+	}
+	return s
+}
+
+// parseLevels parses the levels and their pretty names.
+func (s Settings) parseLevels() ([]float64, []string, error) {
+	rawLevels := strings.Split(s.Levels, ",")
+	prettyLevels := strings.Split(s.PrettyLevels, ",")
+	if len(rawLevels) != len(prettyLevels) {
+		return nil, nil, errors.New("levels and pretty levels must have the same length")
+	}
+	levels := make([]float64, len(rawLevels))
+	for i, rawLevel := range rawLevels {
+		l, err := strconv.ParseFloat(strings.TrimSpace(rawLevel), 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid level: %s", rawLevel)
+		} else if i > 0 && l <= levels[i-1] {
+			return nil, nil, errors.New("levels must be sorted in ascending order")
+		}
+		levels[i] = l
+	}
+	return levels, prettyLevels, nil
+}
+
+// Validate checks that the Settings are consistent.
+func (s Settings) Validate() error {
+	if _, _, err := s.parseLevels(); err != nil {
+		return err
+	} else if s.MinPercentOfDailyBill < 0 || s.MinAbsoluteCost < 0 || s.RecurrenceThreshold < 0 {
+		return errors.New("thresholds must be positive")
+	} else if s.HighestSpendingMinRank < 1 {
+		return errors.New("highest spending min rank must be at least 1")
+	} else if s.EmailingMinLevel < 0 {
+		return errors.New("emailing min level must be positive")
+	}
+	return nil
+}
+
+// Level returns the level of an anomaly and its pretty name, depending on
+// how much its cost exceeds the expected one.
+func (s Settings) Level(cost, maxExpected float64) (int, string) {
+	levels, prettyLevels, err := s.parseLevels()
+	if err != nil || len(levels) == 0 {
+		return 0, ""
+	}
+	percent := (cost * 100) / maxExpected
+	for i, l := range levels[1:] {
+		if percent < l {
+			return i, strings.TrimSpace(prettyLevels[i])
+		}
+	}
+	return len(levels) - 1, strings.TrimSpace(prettyLevels[len(levels)-1])
+}
@@ -0,0 +1,282 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package audit records the actions users perform on their accounts, so
+// that it can later be told who did what and when.
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+)
+
+// Action is the kind of an audit event.
+type Action string
+
+const (
+	ActionAwsAccountCreate     = Action("awsAccount.create")
+	ActionAwsAccountUpdate     = Action("awsAccount.update")
+	ActionAwsAccountDelete     = Action("awsAccount.delete")
+	ActionAwsSubaccountUpdate  = Action("awsAccount.updateSubaccount")
+	ActionBillRepositoryCreate = Action("billRepository.create")
+	ActionBillRepositoryUpdate = Action("billRepository.update")
+	ActionBillRepositoryDelete = Action("billRepository.delete")
+	ActionSharingInvite        = Action("sharing.invite")
+	ActionSharingUpdate        = Action("sharing.update")
+	ActionSharingDelete        = Action("sharing.delete")
+	ActionAnomalySnooze        = Action("anomaly.snooze")
+	ActionAnomalyUnsnooze      = Action("anomaly.unsnooze")
+	ActionUserPasswordChange   = Action("user.passwordChange")
+	ActionUserPasswordReset    = Action("user.passwordReset")
+)
+
+type auditArgumentKey uint
+
+const (
+	actorArgument = auditArgumentKey(iota)
+	eventArgument
+)
+
+// Actor identifies who performs the actions of a request: the user, the
+// viewer acting as them and the API token they used, if any.
+type Actor struct {
+	UserId     int
+	ViewerId   int
+	ApiTokenId int
+}
+
+// Event is an action performed by a user.
+type Event struct {
+	Id            int                    `json:"id"`
+	UserId        int                    `json:"userId,omitempty"`
+	ViewerId      int                    `json:"viewerId,omitempty"`
+	ApiTokenId    int                    `json:"apiTokenId,omitempty"`
+	AwsAccountId  int                    `json:"awsAccountId,omitempty"`
+	Action        Action                 `json:"action"`
+	Method        string                 `json:"method"`
+	Path          string                 `json:"path"`
+	Status        int                    `json:"status"`
+	RemoteAddress string                 `json:"remoteAddress"`
+	Details       map[string]interface{} `json:"details"`
+	Created       time.Time              `json:"created"`
+}
+
+// SetActor records who performs the actions of a request. It is called by
+// the authentication decorator.
+func SetActor(a routes.Arguments, actor Actor) {
+	a[actorArgument] = actor
+}
+
+// SetAwsAccount sets the AWS account the audited action of a request is
+// about. It does nothing if the request is not audited.
+func SetAwsAccount(a routes.Arguments, awsAccountId int) {
+	if event, ok := a[eventArgument].(*Event); ok {
+		event.AwsAccountId = awsAccountId
+	}
+}
+
+// SetUser sets the user an audited action is performed on behalf of, for
+// requests which are not authenticated. It does nothing if the request is not
+// audited.
+func SetUser(a routes.Arguments, userId int) {
+	if event, ok := a[eventArgument].(*Event); ok {
+		event.UserId = userId
+	}
+}
+
+// SetAction replaces the action of the audit event of a request, for
+// handlers performing one of several actions. It does nothing if the request
+// is not audited.
+func SetAction(a routes.Arguments, action Action) {
+	if event, ok := a[eventArgument].(*Event); ok {
+		event.Action = action
+	}
+}
+
+// AddDetail adds a detail to the audit event of a request. It does nothing
+// if the request is not audited.
+func AddDetail(a routes.Arguments, key string, value interface{}) {
+	if event, ok := a[eventArgument].(*Event); ok {
+		event.Details[key] = value
+	}
+}
+
+// newEvent builds the audit event of a request.
+func newEvent(r *http.Request, a routes.Arguments, action Action) *Event {
+	event := &Event{
+		Action:        action,
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		RemoteAddress: remoteAddress(r),
+		Details:       map[string]interface{}{},
+	}
+	if actor, ok := a[actorArgument].(Actor); ok {
+		event.UserId = actor.UserId
+		event.ViewerId = actor.ViewerId
+		event.ApiTokenId = actor.ApiTokenId
+	}
+	return event
+}
+
+// trustedProxies are the networks of the proxies whose X-Forwarded-For
+// header is trusted, set with the trusted-proxies option.
+var trustedProxies = parseTrustedProxies(config.TrustedProxies)
+
+// parseTrustedProxies parses a comma-separated list of addresses and CIDR
+// ranges. Invalid entries are logged and ignored.
+func parseTrustedProxies(list string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		cidr := entry
+		if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+			cidr += "/32"
+		} else if ip != nil {
+			cidr += "/128"
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			jsonlog.DefaultLogger.Warning("Ignoring invalid trusted proxy.", map[string]interface{}{
+				"proxy": entry,
+				"error": err.Error(),
+			})
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isTrustedProxy tells whether an address belongs to a trusted proxy.
+func isTrustedProxy(address string, proxies []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddress returns the address of the client.
+func remoteAddress(r *http.Request) string {
+	return remoteAddressBehind(r, trustedProxies)
+}
+
+// remoteAddressBehind returns the address of the client of a request which
+// may have gone through the given proxies. X-Forwarded-For is only used when
+// the request comes from one of them, and only its rightmost address which
+// was not added by a trusted proxy is kept: the ones on its left are sent by
+// the client and can be forged.
+func remoteAddressBehind(r *http.Request, proxies []*net.IPNet) string {
+	address := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	if !isTrustedProxy(address, proxies) {
+		return address
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for index := len(forwarded) - 1; index >= 0; index-- {
+		if entry := strings.TrimSpace(forwarded[index]); entry != "" {
+			address = entry
+			if !isTrustedProxy(entry, proxies) {
+				break
+			}
+		}
+	}
+	return address
+}
+
+// nullInt64 converts an optional ID, zero meaning none.
+func nullInt64(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// Record saves an audit event.
+func Record(db models.DB, event Event) error {
+	if event.Details == nil {
+		event.Details = map[string]interface{}{}
+	}
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return err
+	}
+	dbEvent := models.AuditEvent{
+		UserID:        nullInt64(event.UserId),
+		ViewerID:      nullInt64(event.ViewerId),
+		ApiTokenID:    nullInt64(event.ApiTokenId),
+		AwsAccountID:  nullInt64(event.AwsAccountId),
+		Action:        string(event.Action),
+		Method:        event.Method,
+		Path:          event.Path,
+		Status:        event.Status,
+		RemoteAddress: event.RemoteAddress,
+		Details:       details,
+		Created:       time.Now().UTC(),
+	}
+	return dbEvent.Insert(db)
+}
+
+// eventFromDbEvent builds an Event from its database row.
+func eventFromDbEvent(dbEvent models.AuditEvent) (Event, error) {
+	event := Event{
+		Id:            dbEvent.ID,
+		UserId:        int(dbEvent.UserID.Int64),
+		ViewerId:      int(dbEvent.ViewerID.Int64),
+		ApiTokenId:    int(dbEvent.ApiTokenID.Int64),
+		AwsAccountId:  int(dbEvent.AwsAccountID.Int64),
+		Action:        Action(dbEvent.Action),
+		Method:        dbEvent.Method,
+		Path:          dbEvent.Path,
+		Status:        dbEvent.Status,
+		RemoteAddress: dbEvent.RemoteAddress,
+		Created:       dbEvent.Created,
+	}
+	err := json.Unmarshal(dbEvent.Details, &event.Details)
+	return event, err
+}
+
+// GetEvents returns the audit events matching a filter.
+func GetEvents(db models.DB, filter models.AuditEventFilter) ([]Event, error) {
+	dbEvents, err := models.AuditEventsByFilter(db, filter)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(dbEvents))
+	for _, dbEvent := range dbEvents {
+		event, err := eventFromDbEvent(*dbEvent)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,54 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package audit
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRemoteAddressBehind(t *testing.T) {
+	proxies := parseTrustedProxies("10.0.0.0/8, 192.0.2.1,invalid, 2001:db8::1")
+	if len(proxies) != 3 {
+		t.Fatalf("Expected 3 trusted proxies, got %d", len(proxies))
+	}
+	for _, tc := range []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{"No proxy", "203.0.113.5:4242", nil, "203.0.113.5"},
+		{"Untrusted proxy", "203.0.113.5:4242", []string{"198.51.100.7"}, "203.0.113.5"},
+		{"Trusted proxy", "10.0.1.2:4242", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"Trusted proxy without header", "10.0.1.2:4242", nil, "10.0.1.2"},
+		{"Forged entries", "10.0.1.2:4242", []string{"1.2.3.4, 198.51.100.7"}, "198.51.100.7"},
+		{"Chained proxies", "10.0.1.2:4242", []string{"1.2.3.4, 198.51.100.7, 192.0.2.1"}, "198.51.100.7"},
+		{"Several headers", "10.0.1.2:4242", []string{"1.2.3.4", "198.51.100.7"}, "198.51.100.7"},
+		{"Only trusted proxies", "10.0.1.2:4242", []string{"10.0.0.3, 192.0.2.1"}, "10.0.0.3"},
+		{"IPv6 proxy", "[2001:db8::1]:4242", []string{"2001:db8::2"}, "2001:db8::2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+			for _, forwarded := range tc.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+			if address := remoteAddressBehind(r, proxies); address != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, address)
+			}
+		})
+	}
+}
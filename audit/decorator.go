@@ -0,0 +1,88 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package audit
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+)
+
+const TagAudited = "audit:action"
+
+// Logged decorates handlers so that their successful requests are recorded
+// as audit events. It must be used after RequireAuthenticatedUser and the
+// QueryArgs decorator, inside the request transaction so that the action and
+// its record are committed together.
+//
+// The AWS account of the event is taken from AwsAccountIdQueryArg or
+// ShareIdQueryArg, before the handler runs; handlers can set it, change the
+// action or add details with SetAwsAccount, SetAction and AddDetail.
+type Logged struct {
+	Action Action
+}
+
+func (d Logged) Decorate(h routes.Handler) routes.Handler {
+	h.Func = d.getFunc(h.Func)
+	h.Documentation = d.getDocumentation(h.Documentation)
+	return h
+}
+
+func (d Logged) getFunc(hf routes.HandlerFunc) routes.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, a routes.Arguments) (int, interface{}) {
+		logger := jsonlog.LoggerFromContextOrDefault(r.Context())
+		tx, ok := a[db.Transaction].(*sql.Tx)
+		if !ok {
+			logger.Error("Missing transaction for audited handler.", nil)
+			return http.StatusInternalServerError, nil
+		}
+		event := newEvent(r, a, d.Action)
+		if aaId, ok := a[routes.AwsAccountIdQueryArg].(int); ok {
+			event.AwsAccountId = aaId
+		} else if shareId, ok := a[routes.ShareIdQueryArg].(int); ok {
+			if dbSharedAccount, err := models.SharedAccountByID(tx, shareId); err == nil {
+				event.AwsAccountId = dbSharedAccount.AccountID
+			}
+		}
+		a[eventArgument] = event
+		status, output := hf(w, r, a)
+		if _, isError := output.(error); isError || status >= http.StatusBadRequest {
+			return status, output
+		}
+		event.Status = status
+		if err := Record(tx, *event); err != nil {
+			logger.Error("Failed to record audit event.", map[string]interface{}{
+				"action": event.Action,
+				"error":  err.Error(),
+			})
+			return http.StatusInternalServerError, errors.New("Failed to record audit event.")
+		}
+		return status, output
+	}
+}
+
+func (d Logged) getDocumentation(hd routes.HandlerDocumentation) routes.HandlerDocumentation {
+	if hd.Tags == nil {
+		hd.Tags = make(routes.Tags)
+	}
+	hd.Tags[TagAudited] = []string{string(d.Action)}
+	return hd
+}
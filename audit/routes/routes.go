@@ -0,0 +1,182 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package routes
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/audit"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 1000
+)
+
+var (
+	userIdQueryArg = routes.QueryArg{
+		Name:        "user",
+		Type:        routes.QueryArgInt{},
+		Description: "The ID of the user who performed the actions.",
+		Optional:    true,
+	}
+	actionQueryArg = routes.QueryArg{
+		Name:        "action",
+		Type:        routes.QueryArgString{},
+		Description: "The action performed, such as awsAccount.create.",
+		Optional:    true,
+	}
+	beginQueryArg = routes.QueryArg{
+		Name:        "begin",
+		Type:        routes.QueryArgDate{},
+		Description: "First day of the actions. Format is ISO8601",
+		Optional:    true,
+	}
+	endQueryArg = routes.QueryArg{
+		Name:        "end",
+		Type:        routes.QueryArgDate{},
+		Description: "Last day of the actions. Format is ISO8601",
+		Optional:    true,
+	}
+	limitQueryArg = routes.QueryArg{
+		Name:        "limit",
+		Type:        routes.QueryArgInt{},
+		Description: fmt.Sprintf("The maximum number of events, %d by default and at most %d.", defaultEventsLimit, maxEventsLimit),
+		Optional:    true,
+	}
+)
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getAuditEvents).With(
+			db.RequestTransaction{Db: db.Db},
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.QueryArgs{
+				userIdQueryArg,
+				routes.AwsAccountIdsOptionalQueryArg,
+				actionQueryArg,
+				beginQueryArg,
+				endQueryArg,
+				limitQueryArg,
+			},
+			routes.Documentation{
+				Summary:     "get the audit log",
+				Description: "Responds with the most recent actions performed by the user or on the AWS accounts they manage, filtered by user, AWS account, action and days. The log can be exported as CSV with the text/csv Accept header.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+	}.H().Register("/audit")
+}
+
+// events is the response of the audit route.
+type events []audit.Event
+
+// ToCSVable generates the CSV content from the audit events.
+func (e events) ToCSVable() [][]string {
+	csv := [][]string{{"id", "created", "userId", "viewerId", "apiTokenId", "awsAccountId", "action", "method", "path", "status", "remoteAddress", "details"}}
+	for _, event := range e {
+		details, _ := json.Marshal(event.Details)
+		csv = append(csv, []string{
+			strconv.Itoa(event.Id),
+			event.Created.Format(time.RFC3339),
+			optionalId(event.UserId),
+			optionalId(event.ViewerId),
+			optionalId(event.ApiTokenId),
+			optionalId(event.AwsAccountId),
+			string(event.Action),
+			event.Method,
+			event.Path,
+			strconv.Itoa(event.Status),
+			event.RemoteAddress,
+			string(details),
+		})
+	}
+	return csv
+}
+
+// optionalId formats an optional ID, zero meaning none.
+func optionalId(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+// getAuditEvents responds with the audit events the user may see: their own
+// and the ones on the AWS accounts they manage.
+func getAuditEvents(r *http.Request, a routes.Arguments) (int, interface{}) {
+	logger := jsonlog.LoggerFromContextOrDefault(r.Context())
+	user := a[users.AuthenticatedUser].(users.User)
+	tx := a[db.Transaction].(*sql.Tx)
+	filter := models.AuditEventFilter{
+		VisibleUserID: user.Id,
+		Limit:         defaultEventsLimit,
+	}
+	if userId, ok := a[userIdQueryArg].(int); ok {
+		filter.UserID = userId
+	}
+	if aaIds, ok := a[routes.AwsAccountIdsOptionalQueryArg].([]int); ok {
+		filter.AwsAccountIDs = aaIds
+	}
+	if action, ok := a[actionQueryArg].(string); ok {
+		filter.Action = action
+	}
+	if begin, ok := a[beginQueryArg].(time.Time); ok {
+		filter.Begin = begin
+	}
+	if end, ok := a[endQueryArg].(time.Time); ok {
+		filter.End = end.AddDate(0, 0, 1)
+	}
+	if limit, ok := a[limitQueryArg].(int); ok {
+		if limit <= 0 || limit > maxEventsLimit {
+			return http.StatusBadRequest, fmt.Errorf("Limit must be between 1 and %d.", maxEventsLimit)
+		}
+		filter.Limit = limit
+	}
+	permissions, err := users.GetAwsAccountsPermissions(tx, user)
+	if err != nil {
+		logger.Error("Failed to get AWS accounts permissions.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve audit events.")
+	}
+	for aaId, permissionSet := range permissions {
+		if permissionSet.Has(users.PermissionManageAccounts) {
+			filter.VisibleAwsAccountIDs = append(filter.VisibleAwsAccountIDs, aaId)
+		}
+	}
+	res, err := audit.GetEvents(tx, filter)
+	if err != nil {
+		logger.Error("Failed to retrieve audit events.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve audit events.")
+	}
+	return http.StatusOK, events(res)
+}
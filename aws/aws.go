@@ -84,6 +84,9 @@ func GetAwsAccountsFromUser(u users.User, tx *sql.Tx) ([]AwsAccount, error) {
 		return nil, err
 	}
 	for _, key := range dbAwsAccounts {
+		if !u.CanAccessAwsAccount(key.ID) {
+			continue
+		}
 		res = append(res, AwsAccount{
 			key.ID,
 			key.UserID,
@@ -97,6 +100,9 @@ func GetAwsAccountsFromUser(u users.User, tx *sql.Tx) ([]AwsAccount, error) {
 			key.ParentID})
 	}
 	for _, key := range dbShareAccounts {
+		if !u.CanAccessAwsAccount(key.AccountID) {
+			continue
+		}
 		dbAwsAccountById, err := models.AwsAccountByID(tx, key.AccountID)
 		if err != nil {
 			return nil, err
@@ -131,7 +137,9 @@ func GetAwsAccountWithId(aaid int, tx *sql.Tx) (AwsAccount, error) {
 // the user.
 func GetAwsAccountWithIdFromUser(u users.User, aaid int, tx *sql.Tx) (AwsAccount, error) {
 	var aaz AwsAccount
-	if aa, err := GetAwsAccountWithId(aaid, tx); err != nil {
+	if !u.CanAccessAwsAccount(aaid) {
+		return aaz, errors.New("aws account is not allowed for this token")
+	} else if aa, err := GetAwsAccountWithId(aaid, tx); err != nil {
 		return aaz, err
 	} else if aa.UserId == u.Id {
 		return aa, nil
@@ -0,0 +1,443 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package pricings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/models"
+)
+
+var (
+	// EBSProduct is the product under which the EBS catalog is stored
+	// EBS has no service code of its own: it is priced in the EC2 offer,
+	// whose catalog also gives the EC2Pricing
+	EBSProduct        = "AmazonEBS"
+	S3ServiceCode     = "AmazonS3"
+	LambdaServiceCode = "AWSLambda"
+	// RDSCatalogProduct, ElastiCacheCatalogProduct and ESCatalogProduct are the
+	// products under which the catalogs of these services are stored, apart
+	// from the InstancePricing derived from them and stored under their service code
+	RDSCatalogProduct         = "AmazonRDSCatalog"
+	ElastiCacheCatalogProduct = "AmazonElastiCacheCatalog"
+	ESCatalogProduct          = "AmazonESCatalog"
+)
+
+// PriceDimension is an on demand price of a SKU
+// It applies to the units between BeginRange and EndRange, EndRange being -1 for the last tier
+type PriceDimension struct {
+	Description  string  `json:"description"`
+	Unit         string  `json:"unit"`
+	BeginRange   float64 `json:"beginRange"`
+	EndRange     float64 `json:"endRange"`
+	PricePerUnit float64 `json:"pricePerUnit"`
+}
+
+// ReservedPrice is the hourly price of a no upfront reservation of a SKU
+// OfferingClass is empty for the services which have a single class of reservations
+type ReservedPrice struct {
+	LeaseContractLength string  `json:"leaseContractLength"`
+	OfferingClass       string  `json:"offeringClass,omitempty"`
+	HourlyCost          float64 `json:"hourlyCost"`
+}
+
+// SkuPricing stores the normalized attributes, on demand prices and no upfront
+// reservation prices of a SKU
+type SkuPricing struct {
+	Sku           string            `json:"sku"`
+	ProductFamily string            `json:"productFamily"`
+	Region        string            `json:"region"`
+	Attributes    map[string]string `json:"attributes"`
+	OnDemand      []PriceDimension  `json:"onDemand"`
+	NoUpfront     []ReservedPrice   `json:"noUpfront,omitempty"`
+}
+
+// ServiceCatalog maps the SKUs of a product to their SkuPricing
+type ServiceCatalog struct {
+	Product string                 `json:"product"`
+	Skus    map[string]*SkuPricing `json:"skus"`
+}
+
+// catalogService describes which products of the price list are fetched in the
+// catalog of a service
+type catalogService struct {
+	serviceCode     string
+	productFamilies []string
+	// filters are the attributes the products of a product family must have
+	filters map[string]map[string]string
+}
+
+// ebsProductFamilies are the product families of the EC2 offer stored in the EBS catalog
+var ebsProductFamilies = []string{"Storage", "Storage Snapshot", "System Operation"}
+
+var (
+	ec2CatalogService = catalogService{
+		serviceCode:     EC2ServiceCode,
+		productFamilies: append([]string{ec2InstanceProductFamily}, ebsProductFamilies...),
+		filters: map[string]map[string]string{
+			ec2InstanceProductFamily: {"PreInstalledSw": "NA"},
+		},
+	}
+	s3CatalogService = catalogService{
+		serviceCode:     S3ServiceCode,
+		productFamilies: []string{"Storage", "API Request"},
+	}
+	lambdaCatalogService = catalogService{
+		serviceCode:     LambdaServiceCode,
+		productFamilies: []string{"Serverless"},
+	}
+	rdsCatalogService = catalogService{
+		serviceCode:     RDSServiceCode,
+		productFamilies: []string{"Database Instance", "Database Storage", "Provisioned IOPS", "Storage Snapshot"},
+	}
+	elastiCacheCatalogService = catalogService{
+		serviceCode:     ElastiCacheServiceCode,
+		productFamilies: []string{"Cache Instance"},
+	}
+	esCatalogService = catalogService{
+		serviceCode:     ESServiceCode,
+		productFamilies: []string{"Elastic Search Instance", "Amazon OpenSearch Service Instance", "Elastic Search Volume", "Amazon OpenSearch Service Volume"},
+	}
+)
+
+// getCatalogProductInput takes a service code, a product family and attribute filters
+// and returns a pricing.GetProductsInput retrieving the products of all regions
+func getCatalogProductInput(serviceCode, productFamily string, filters map[string]string) *pricing.GetProductsInput {
+	input := &pricing.GetProductsInput{
+		Filters: []*pricing.Filter{
+			{
+				Field: aws.String("ServiceCode"),
+				Type:  aws.String("TERM_MATCH"),
+				Value: aws.String(serviceCode),
+			},
+			{
+				Field: aws.String("ProductFamily"),
+				Type:  aws.String("TERM_MATCH"),
+				Value: aws.String(productFamily),
+			},
+		},
+		FormatVersion: aws.String("aws_v1"),
+		ServiceCode:   aws.String(serviceCode),
+		MaxResults:    aws.Int64(100),
+	}
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		input.Filters = append(input.Filters, &pricing.Filter{
+			Field: aws.String(field),
+			Type:  aws.String("TERM_MATCH"),
+			Value: aws.String(filters[field]),
+		})
+	}
+	return input
+}
+
+// getRegionCode returns the region code of an item's attributes
+// Older offers only have a location, which is converted with EC2RegionCodeToPricingLocationName
+func getRegionCode(attributes map[string]string) string {
+	if regionCode, ok := attributes["regionCode"]; ok {
+		return regionCode
+	}
+	for regionCode, locationName := range EC2RegionCodeToPricingLocationName {
+		if attributes["location"] == locationName {
+			return regionCode
+		}
+	}
+	return ""
+}
+
+// parseRange parses the beginRange or endRange of a price dimension
+// "Inf" is returned as -1.0
+func parseRange(value interface{}) float64 {
+	if str, ok := value.(string); !ok || str == "Inf" {
+		return -1.0
+	} else if res, err := strconv.ParseFloat(str, 64); err == nil {
+		return res
+	}
+	return -1.0
+}
+
+// getOnDemandPriceDimensions takes an item from the aws json pricing and returns
+// its on demand price dimensions sorted by range
+func getOnDemandPriceDimensions(item aws.JSONValue) []PriceDimension {
+	res := []PriceDimension{}
+	if terms := getTerms(item); terms == nil {
+	} else if onDemand, ok := terms["OnDemand"].(map[string]interface{}); ok {
+		for _, onDemandItem := range onDemand {
+			for _, priceDimension := range getPriceDimensions(onDemandItem.(map[string]interface{})) {
+				dimension := priceDimension.(map[string]interface{})
+				description, _ := dimension["description"].(string)
+				unit, _ := dimension["unit"].(string)
+				res = append(res, PriceDimension{
+					Description:  description,
+					Unit:         unit,
+					BeginRange:   parseRange(dimension["beginRange"]),
+					EndRange:     parseRange(dimension["endRange"]),
+					PricePerUnit: getUSDPricePerUnit(dimension),
+				})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].BeginRange < res[j].BeginRange })
+	return res
+}
+
+// getNoUpfrontPrices takes an item from the aws json pricing and returns the hourly
+// prices of its no upfront reservations
+func getNoUpfrontPrices(item aws.JSONValue) []ReservedPrice {
+	res := []ReservedPrice{}
+	if terms := getTerms(item); terms == nil {
+	} else if reserved := getReserved(terms); reserved != nil {
+		for _, reservationType := range reserved {
+			termAttributes := getTermAttributes(reservationType.(map[string]interface{}))
+			if termAttributes == nil || termAttributes["PurchaseOption"] != "No Upfront" {
+				continue
+			}
+			leaseContractLength, _ := termAttributes["LeaseContractLength"].(string)
+			offeringClass, _ := termAttributes["OfferingClass"].(string)
+			for _, priceDimension := range getPriceDimensions(reservationType.(map[string]interface{})) {
+				if cost := getUSDPricePerUnit(priceDimension.(map[string]interface{})); cost > 0 {
+					res = append(res, ReservedPrice{leaseContractLength, offeringClass, cost})
+					break
+				}
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].LeaseContractLength != res[j].LeaseContractLength {
+			return res[i].LeaseContractLength < res[j].LeaseContractLength
+		}
+		return res[i].OfferingClass < res[j].OfferingClass
+	})
+	return res
+}
+
+// getSkuPricing takes an item from the aws json pricing and returns its SkuPricing
+func getSkuPricing(item aws.JSONValue) (SkuPricing, error) {
+	product, _ := item["product"].(map[string]interface{})
+	sku, _ := product["sku"].(string)
+	attributes := make(map[string]string)
+	for key, value := range getItemAttributes(item) {
+		if str, ok := value.(string); ok {
+			attributes[key] = str
+		}
+	}
+	skuPricing := SkuPricing{
+		Sku:           sku,
+		ProductFamily: getProductFamily(item),
+		Region:        getRegionCode(attributes),
+		Attributes:    attributes,
+		OnDemand:      getOnDemandPriceDimensions(item),
+		NoUpfront:     getNoUpfrontPrices(item),
+	}
+	if sku == "" || len(skuPricing.OnDemand) == 0 {
+		return skuPricing, errors.New("Failed to parse SKU")
+	}
+	for _, dimension := range skuPricing.OnDemand {
+		if dimension.PricePerUnit == -1.0 {
+			return skuPricing, errors.New("Failed to parse SKU price")
+		}
+	}
+	return skuPricing, nil
+}
+
+// fetchServiceCatalog fetches the SKUs of a catalog from a product source
+// SKUs which are not available in a region are ignored
+func fetchServiceCatalog(ctx context.Context, svc productSource, service catalogService) (ServiceCatalog, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	parsingError := false
+	catalog := ServiceCatalog{
+		Product: service.serviceCode,
+		Skus:    make(map[string]*SkuPricing),
+	}
+	for _, productFamily := range service.productFamilies {
+		logger.Info("Fetching pricing catalog", map[string]interface{}{
+			"service":       service.serviceCode,
+			"productFamily": productFamily,
+		})
+		input := getCatalogProductInput(service.serviceCode, productFamily, service.filters[productFamily])
+		err := svc.GetProductsPages(input,
+			func(page *pricing.GetProductsOutput, lastPage bool) bool {
+				for _, item := range page.PriceList {
+					skuPricing, err := getSkuPricing(item)
+					if err != nil {
+						// In case of format change, the error will be logged at the end of the function
+						// to avoid sending multiple alerts
+						parsingError = true
+						continue
+					} else if skuPricing.Region == "" {
+						continue
+					}
+					catalog.Skus[skuPricing.Sku] = &skuPricing
+				}
+				return !lastPage
+			})
+		if err != nil {
+			logger.Error("Failed to get products pages", err.Error())
+			return catalog, err
+		}
+	}
+	if parsingError {
+		logger.Error("Parsing error while retrieving pricing catalog", map[string]interface{}{"service": service.serviceCode})
+		return catalog, errors.New("Parsing error while retrieving " + service.serviceCode + " pricings")
+	}
+	return catalog, nil
+}
+
+// GetServiceCatalog retrieves the catalog of a product from the database
+func GetServiceCatalog(tx models.DB, product string) (ServiceCatalog, error) {
+	catalog := ServiceCatalog{}
+	pricingDb, err := models.AwsPricingByProduct(tx, product)
+	if err != nil {
+		return catalog, err
+	}
+	err = json.Unmarshal(pricingDb.Pricing, &catalog)
+	return catalog, err
+}
+
+// subset returns the catalog of the SKUs of some product families, stored under a product
+// Every SKU is kept if no product family is given
+func (c ServiceCatalog) subset(product string, productFamilies ...string) ServiceCatalog {
+	res := ServiceCatalog{
+		Product: product,
+		Skus:    make(map[string]*SkuPricing, len(c.Skus)),
+	}
+	for sku, skuPricing := range c.Skus {
+		if len(productFamilies) == 0 || hasProductFamily(*skuPricing, productFamilies) {
+			res.Skus[sku] = skuPricing
+		}
+	}
+	return res
+}
+
+// skusOf returns the SKUs of some product families sorted by SKU, so that the
+// pricings derived from them do not depend on the order of the map
+func (c ServiceCatalog) skusOf(productFamilies ...string) []SkuPricing {
+	res := []SkuPricing{}
+	for _, skuPricing := range c.Skus {
+		if hasProductFamily(*skuPricing, productFamilies) {
+			res = append(res, *skuPricing)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Sku < res[j].Sku })
+	return res
+}
+
+// hasProductFamily returns true if a SKU belongs to one of the product families
+func hasProductFamily(skuPricing SkuPricing, productFamilies []string) bool {
+	for _, productFamily := range productFamilies {
+		if skuPricing.ProductFamily == productFamily {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSku returns the pricing of a SKU
+func (c ServiceCatalog) GetSku(sku string) (SkuPricing, error) {
+	if skuPricing, ok := c.Skus[sku]; ok {
+		return *skuPricing, nil
+	}
+	return SkuPricing{}, errors.New("SKU not found in pricings")
+}
+
+// Find returns the SKUs of a region and a product family which have all the given attributes
+// An empty product family matches every product family
+func (c ServiceCatalog) Find(region, productFamily string, attributes map[string]string) []SkuPricing {
+	res := []SkuPricing{}
+	for _, skuPricing := range c.Skus {
+		if skuPricing.Region != region || (productFamily != "" && skuPricing.ProductFamily != productFamily) {
+			continue
+		} else if skuPricing.hasAttributes(attributes) {
+			res = append(res, *skuPricing)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Sku < res[j].Sku })
+	return res
+}
+
+// FindOne returns the only SKU matching the parameters of Find
+func (c ServiceCatalog) FindOne(region, productFamily string, attributes map[string]string) (SkuPricing, error) {
+	skus := c.Find(region, productFamily, attributes)
+	if len(skus) == 0 {
+		return SkuPricing{}, errors.New("SKU not found in pricings")
+	} else if len(skus) > 1 {
+		return SkuPricing{}, errors.New("Several SKUs match the attributes")
+	}
+	return skus[0], nil
+}
+
+// hasAttributes returns true if a SKU has all the given attributes
+func (s SkuPricing) hasAttributes(attributes map[string]string) bool {
+	for key, value := range attributes {
+		if s.Attributes[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Cost returns the on demand cost of a quantity of units, applying each price tier
+func (s SkuPricing) Cost(quantity float64) float64 {
+	cost := 0.0
+	for _, dimension := range s.OnDemand {
+		if quantity <= dimension.BeginRange {
+			continue
+		}
+		end := quantity
+		if dimension.EndRange >= 0 && dimension.EndRange < end {
+			end = dimension.EndRange
+		}
+		cost += (end - dimension.BeginRange) * dimension.PricePerUnit
+	}
+	return cost
+}
+
+// OnDemandHourlyCost returns the on demand price of the first unit of a SKU,
+// or -1.0 if it has no on demand price
+func (s SkuPricing) OnDemandHourlyCost() float64 {
+	if len(s.OnDemand) == 0 {
+		return -1.0
+	}
+	return s.OnDemand[0].PricePerUnit
+}
+
+// NoUpfrontHourlyCost returns the hourly cost of the no upfront reservation of a duration
+// ("1yr" or "3yr") and an offering class ("standard" or "convertible"), or -1.0 if the SKU
+// has no such reservation
+// Reservations without an offering class are standard ones
+func (s SkuPricing) NoUpfrontHourlyCost(duration, offeringClass string) float64 {
+	for _, reserved := range s.NoUpfront {
+		class := reserved.OfferingClass
+		if class == "" {
+			class = "standard"
+		}
+		if reserved.LeaseContractLength == duration && class == offeringClass {
+			return reserved.HourlyCost
+		}
+	}
+	return -1.0
+}
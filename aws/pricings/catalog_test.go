@@ -0,0 +1,108 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package pricings
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestFetchServiceCatalogFromOfferFile(t *testing.T) {
+	catalog, err := fetchServiceCatalog(context.Background(), newOfferFileSource("testdata"), lambdaCatalogService)
+	if err != nil {
+		t.Fatalf("fetchServiceCatalog should succeed. Failed with %s.", err.Error())
+	}
+	if len(catalog.Skus) != 2 {
+		t.Errorf("Catalog should have 2 SKUs, has %d instead.", len(catalog.Skus))
+	}
+	if _, err := catalog.GetSku("CCCCCCCCCCCCCCCC"); err == nil {
+		t.Errorf("SKUs of other product families should not be in the catalog.")
+	}
+	requests, err := catalog.FindOne("eu-west-3", "Serverless", map[string]string{"group": "AWS-Lambda-Requests"})
+	if err != nil {
+		t.Fatalf("FindOne should succeed with the location of the SKU. Failed with %s.", err.Error())
+	}
+	if requests.Sku != "BBBBBBBBBBBBBBBB" {
+		t.Errorf("SKU should be BBBBBBBBBBBBBBBB, is %s instead.", requests.Sku)
+	}
+	if skus := catalog.Find("us-east-1", "", map[string]string{"group": "AWS-Lambda-Requests"}); len(skus) != 0 {
+		t.Errorf("Find should not return SKUs of other regions, returned %d.", len(skus))
+	}
+}
+
+func TestSkuPricingCost(t *testing.T) {
+	catalog, err := fetchServiceCatalog(context.Background(), newOfferFileSource("testdata"), lambdaCatalogService)
+	if err != nil {
+		t.Fatalf("fetchServiceCatalog should succeed. Failed with %s.", err.Error())
+	}
+	duration, err := catalog.GetSku("AAAAAAAAAAAAAAAA")
+	if err != nil {
+		t.Fatalf("GetSku should succeed. Failed with %s.", err.Error())
+	}
+	for _, c := range []struct {
+		quantity float64
+		cost     float64
+	}{
+		{0, 0},
+		{1000000, 16.6667},
+		{7000000000, 6000000000*0.0000166667 + 1000000000*0.000015},
+	} {
+		if cost := duration.Cost(c.quantity); math.Abs(cost-c.cost) > 1e-6 {
+			t.Errorf("Cost of %f units should be %f, is %f instead.", c.quantity, c.cost, cost)
+		}
+	}
+}
+
+func TestFetchInstanceServiceCatalogsFromOfferFiles(t *testing.T) {
+	for _, c := range []struct {
+		service       catalogService
+		skus          int
+		excluded      string
+		region        string
+		productFamily string
+		attributes    map[string]string
+		sku           string
+		hourlyCost    float64
+	}{
+		{rdsCatalogService, 3, "RDSDATATRANSFER1", "us-east-1", "Database Instance", map[string]string{"instanceType": "db.m5.large", "deploymentOption": "Multi-AZ"}, "RDSINSTANCE00002", 0.342},
+		{rdsCatalogService, 3, "RDSDATATRANSFER1", "eu-west-3", "Database Storage", map[string]string{"volumeType": "General Purpose"}, "RDSSTORAGE000001", 0.133},
+		{elastiCacheCatalogService, 2, "CACHESNAPSHOT001", "eu-west-3", "Cache Instance", map[string]string{"instanceType": "cache.r5.large"}, "CACHEINSTANCE002", 0.252},
+		{esCatalogService, 3, "ESDATATRANSFER01", "us-east-1", "", map[string]string{"instanceType": "m5.large.elasticsearch"}, "ESINSTANCE000001", 0.142},
+		{esCatalogService, 3, "ESDATATRANSFER01", "eu-west-3", "Amazon OpenSearch Service Instance", map[string]string{"instanceType": "r6g.large.search"}, "ESINSTANCE000002", 0.196},
+	} {
+		catalog, err := fetchServiceCatalog(context.Background(), newOfferFileSource("testdata"), c.service)
+		if err != nil {
+			t.Fatalf("fetchServiceCatalog of %s should succeed. Failed with %s.", c.service.serviceCode, err.Error())
+		}
+		if catalog.Product != c.service.serviceCode {
+			t.Errorf("Catalog product should be %s, is %s instead.", c.service.serviceCode, catalog.Product)
+		}
+		if len(catalog.Skus) != c.skus {
+			t.Errorf("Catalog %s should have %d SKUs, has %d instead.", c.service.serviceCode, c.skus, len(catalog.Skus))
+		}
+		if _, err := catalog.GetSku(c.excluded); err == nil {
+			t.Errorf("SKUs of other product families should not be in the %s catalog.", c.service.serviceCode)
+		}
+		skuPricing, err := catalog.FindOne(c.region, c.productFamily, c.attributes)
+		if err != nil {
+			t.Errorf("FindOne in %s should succeed. Failed with %s.", c.service.serviceCode, err.Error())
+		} else if skuPricing.Sku != c.sku {
+			t.Errorf("SKU should be %s, is %s instead.", c.sku, skuPricing.Sku)
+		} else if cost := skuPricing.Cost(1); math.Abs(cost-c.hourlyCost) > 1e-9 {
+			t.Errorf("Cost of a unit of %s should be %f, is %f instead.", c.sku, c.hourlyCost, cost)
+		}
+	}
+}
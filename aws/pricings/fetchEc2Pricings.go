@@ -21,7 +21,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/pricing"
 	"github.com/trackit/jsonlog"
 )
 
@@ -37,6 +36,9 @@ type EC2Specs struct {
 	OnDemandHourlyCost                    float64 `json:"onDemandHourlyCost"`
 	OneYearStandardNoUpfrontHourlyCost    float64 `json:"oneYearStandardNoUpfrontHourlyCost"`
 	ThreeYearsStandardNoUpfrontHourlyCost float64 `json:"threeYearsStandardNoUpfrontHourlyCost"`
+	// Convertible reservations are priced like the Compute Savings Plans
+	OneYearConvertibleNoUpfrontHourlyCost    float64 `json:"oneYearConvertibleNoUpfrontHourlyCost"`
+	ThreeYearsConvertibleNoUpfrontHourlyCost float64 `json:"threeYearsConvertibleNoUpfrontHourlyCost"`
 }
 
 // EC2Type maps an instance type to a EC2Specs struct
@@ -54,37 +56,8 @@ type EC2Pricing struct {
 	Region map[string]EC2Platform `json:"region"`
 }
 
-// getPricingProductInput takes a locationName (human readable region) and returns
-// a pricing.GetProductsInput with the correct filters to retrieve EC2 products
-func getPricingProductInput(locationName string) *pricing.GetProductsInput {
-	return &pricing.GetProductsInput{
-		Filters: []*pricing.Filter{
-			{
-				Field: aws.String("ServiceCode"),
-				Type:  aws.String("TERM_MATCH"),
-				Value: aws.String(EC2ServiceCode),
-			},
-			{
-				Field: aws.String("PreInstalledSw"),
-				Type:  aws.String("TERM_MATCH"),
-				Value: aws.String("NA"),
-			},
-			{
-				Field: aws.String("Location"),
-				Type:  aws.String("TERM_MATCH"),
-				Value: aws.String(locationName),
-			},
-			{
-				Field: aws.String("ProductFamily"),
-				Type:  aws.String("TERM_MATCH"),
-				Value: aws.String("Compute Instance"),
-			},
-		},
-		FormatVersion: aws.String("aws_v1"),
-		ServiceCode:   aws.String("AmazonEC2"),
-		MaxResults:    aws.Int64(100),
-	}
-}
+// ec2InstanceProductFamily is the product family of the EC2 instances in the price list
+const ec2InstanceProductFamily = "Compute Instance"
 
 // getItemAttributes takes an item from the aws json pricing and returns its
 // "attributes" attribute
@@ -97,64 +70,28 @@ func getItemAttributes(item aws.JSONValue) map[string]interface{} {
 	return nil
 }
 
-// getNormalizedPlatform takes an item from the aws json pricing and returns
+// getNormalizedPlatform takes the attributes of an instance SKU and returns
 // its normalized platform name
-func getNormalizedPlatform(item aws.JSONValue) string {
-	if attributes := getItemAttributes(item); attributes == nil {
-	} else if os, ok := attributes["operatingSystem"]; !ok {
-	} else {
-		if os.(string) == "Linux" {
-			return "Linux/UNIX"
-		}
-		return os.(string)
-	}
-	return ""
-}
-
-// getInstanceType takes an item from the aws json pricing and returns its instance type
-func getInstanceType(item aws.JSONValue) string {
-	if attributes := getItemAttributes(item); attributes == nil {
-	} else if instanceType, ok := attributes["instanceType"]; !ok {
-	} else {
-		return instanceType.(string)
-	}
-	return ""
-}
-
-// isCurrentGeneration takes an item from the aws json pricing and returns
-// true if it is a current generation instance
-func isCurrentGeneration(item aws.JSONValue) bool {
-	isCurrentGen := false
-	if attributes := getItemAttributes(item); attributes == nil {
-	} else if currentGen, ok := attributes["currentGeneration"]; !ok {
-	} else {
-		isCurrentGen = currentGen.(string) == "Yes"
+func getNormalizedPlatform(attributes map[string]string) string {
+	if attributes["operatingSystem"] == "Linux" {
+		return "Linux/UNIX"
 	}
-	return isCurrentGen
+	return attributes["operatingSystem"]
 }
 
-// isBoxUsage takes an item from the aws json pricing and returns true if
-// the item is a "BoxUsage" (an hourly instance cost)
-func isBoxUsage(item aws.JSONValue) bool {
-	if attributes := getItemAttributes(item); attributes == nil {
-	} else if usageType, ok := attributes["usagetype"]; !ok {
-	} else {
-		// The usage type is not formated the same way in all regions
-		return strings.HasPrefix(usageType.(string), "BoxUsage") ||
-			strings.Contains(usageType.(string), "-BoxUsage:")
-	}
-	return false
+// isBoxUsage takes the attributes of an instance SKU and returns true if
+// the SKU is a "BoxUsage" (an hourly instance cost)
+func isBoxUsage(attributes map[string]string) bool {
+	// The usage type is not formated the same way in all regions
+	return strings.HasPrefix(attributes["usagetype"], "BoxUsage") ||
+		strings.Contains(attributes["usagetype"], "-BoxUsage:")
 }
 
-// isBYOL return true if the item is a "Bring your own licence" type
+// isBYOL return true if the SKU is a "Bring your own licence" type
 // BYOL don't have reservations so we don't want to parse them
-func isBYOL(item aws.JSONValue) bool {
-	if attributes := getItemAttributes(item); attributes == nil {
-	} else if licenseModel, ok := attributes["licenseModel"]; !ok {
-	} else {
-		return licenseModel.(string) == "Bring your own license"
-	}
-	return true
+func isBYOL(attributes map[string]string) bool {
+	licenseModel, ok := attributes["licenseModel"]
+	return !ok || licenseModel == "Bring your own license"
 }
 
 // getTerms takes an item fron the aws json pricing and returns its terms
@@ -189,23 +126,6 @@ func getUSDPricePerUnit(priceDimention map[string]interface{}) float64 {
 	return -1.0
 }
 
-// getOnDemandCost takes an item from the aws json pricing and returns its
-// on demand cost. It returns -1.0 in case of error
-func getOnDemandCost(item aws.JSONValue) float64 {
-	if terms := getTerms(item); terms == nil {
-	} else if onDemand, ok := terms["OnDemand"]; !ok {
-	} else {
-		for _, onDemandItem := range onDemand.(map[string]interface{}) {
-			if priceDimensions := getPriceDimensions(onDemandItem.(map[string]interface{})); priceDimensions != nil {
-				for _, priceDimension := range priceDimensions {
-					return getUSDPricePerUnit(priceDimension.(map[string]interface{}))
-				}
-			}
-		}
-	}
-	return -1.0
-}
-
 // getReserved takes the terms of an item from the aws json pricing as a parameter
 // and returns the reserved attribute
 func getReserved(terms aws.JSONValue) map[string]interface{} {
@@ -225,88 +145,47 @@ func getTermAttributes(reservationType map[string]interface{}) map[string]interf
 	return nil
 }
 
-// isStandardNoUpfront takes a termAttributes and returns true if the term is
-// standard no upfront
-func isStandardNoUpfront(termAttributes map[string]interface{}, duration string) bool {
-	return termAttributes["LeaseContractLength"] == duration &&
-		termAttributes["OfferingClass"] == "standard" &&
-		termAttributes["PurchaseOption"] == "No Upfront"
-}
-
-// getRIStandardNoUpfrontCost takes an item fron the aws JSON pricing and a
-// duration ("1yr" or "3yr")
-// It returns the hourly cost for the reservation or -1.0 if the reservation plan
-// does not exist for this item
-func getRIStandardNoUpfrontCost(item aws.JSONValue, duration string) float64 {
-	if terms := getTerms(item); terms == nil {
-	} else if reserved := getReserved(terms); reserved == nil {
-	} else {
-		for _, reservationType := range reserved {
-			if termAttributes := getTermAttributes(reservationType.(map[string]interface{})); termAttributes == nil {
-			} else if !isStandardNoUpfront(termAttributes, duration) {
-			} else {
-				if priceDimensions := getPriceDimensions(reservationType.(map[string]interface{})); priceDimensions != nil {
-					for _, priceDimension := range priceDimensions {
-						return getUSDPricePerUnit(priceDimension.(map[string]interface{}))
-					}
-				}
-			}
-		}
-	}
-	return -1.0
-}
-
-// FetchEc2Pricings fetches the EC2 pricings for all regions
+// ec2PricingFromCatalog derives the EC2 pricings of all regions from the EC2 catalog
 // The information that is retrieved is the instance size, the platform,
 // the hourly costs for on demand, one year no upfront and 3 years no upfront
+// standard and convertible reservations
 // If one of the buying options is not available, its cost is set to -1.0
-// FetchEc2Pricings returns an EC2Pricing struct and an error
-func FetchEc2Pricings(ctx context.Context) (EC2Pricing, error) {
+func ec2PricingFromCatalog(ctx context.Context, catalog ServiceCatalog) (EC2Pricing, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	parsingError := false
 	ec2Pricings := EC2Pricing{Region: make(map[string]EC2Platform, len(EC2RegionCodeToPricingLocationName))}
-	svc := getPricingClient()
-	for regionCode, locationName := range EC2RegionCodeToPricingLocationName {
-		logger.Info("Fetching pricings for region", map[string]interface{}{"region": regionCode})
+	for regionCode := range EC2RegionCodeToPricingLocationName {
 		ec2Pricings.Region[regionCode] = EC2Platform{Platform: make(map[string]EC2Type)}
-		input := getPricingProductInput(locationName)
-		err := svc.GetProductsPages(input,
-			func(page *pricing.GetProductsOutput, lastPage bool) bool {
-				for _, item := range page.PriceList {
-					if !isBoxUsage(item) || isBYOL(item) {
-						continue
-					}
-					platform := getNormalizedPlatform(item)
-					instanceType := getInstanceType(item)
-					currentGen := isCurrentGeneration(item)
-					onDemandCost := getOnDemandCost(item)
-					if platform == "" || instanceType == "" || onDemandCost == -1.0 {
-						// This case should not happen unless the pricing format has changed
-						// In case of format change, the error will be logged at the end of the function
-						// to avoid sending multiple alerts
-						parsingError = true
-						continue
-					}
-					if _, ok := ec2Pricings.Region[regionCode].Platform[platform]; !ok {
-						ec2Pricings.Region[regionCode].Platform[platform] = EC2Type{Type: make(map[string]*EC2Specs, 1)}
-					}
-					if _, ok := ec2Pricings.Region[regionCode].Platform[platform].Type[instanceType]; !ok {
-						ec2Pricings.Region[regionCode].Platform[platform].Type[instanceType] = &EC2Specs{}
-					}
-					ec2Pricings.Region[regionCode].Platform[platform].Type[instanceType].CurrentGeneration = currentGen
-					ec2Pricings.Region[regionCode].Platform[platform].Type[instanceType].OnDemandHourlyCost = onDemandCost
-					// We do not verify that RI costs where extracted successfully because
-					// some instance types don't have reservations
-					oneYearNoUpfrontCost := getRIStandardNoUpfrontCost(item, "1yr")
-					threeYearsNoUpfrontCost := getRIStandardNoUpfrontCost(item, "3yr")
-					ec2Pricings.Region[regionCode].Platform[platform].Type[instanceType].OneYearStandardNoUpfrontHourlyCost = oneYearNoUpfrontCost
-					ec2Pricings.Region[regionCode].Platform[platform].Type[instanceType].ThreeYearsStandardNoUpfrontHourlyCost = threeYearsNoUpfrontCost
-				}
-				return !lastPage
-			})
-		if err != nil {
-			logger.Error("Failed to get products pages", err.Error())
-			return ec2Pricings, err
+	}
+	for _, sku := range catalog.skusOf(ec2InstanceProductFamily) {
+		if !isBoxUsage(sku.Attributes) || isBYOL(sku.Attributes) {
+			continue
+		}
+		platform := getNormalizedPlatform(sku.Attributes)
+		instanceType := sku.Attributes["instanceType"]
+		onDemandCost := sku.OnDemandHourlyCost()
+		if sku.Region == "" || platform == "" || instanceType == "" || onDemandCost == -1.0 {
+			// This case should not happen unless the pricing format has changed
+			// In case of format change, the error will be logged at the end of the function
+			// to avoid sending multiple alerts
+			parsingError = true
+			continue
+		}
+		if _, ok := ec2Pricings.Region[sku.Region]; !ok {
+			ec2Pricings.Region[sku.Region] = EC2Platform{Platform: make(map[string]EC2Type)}
+		}
+		if _, ok := ec2Pricings.Region[sku.Region].Platform[platform]; !ok {
+			ec2Pricings.Region[sku.Region].Platform[platform] = EC2Type{Type: make(map[string]*EC2Specs, 1)}
+		}
+		// We do not verify that RI costs where extracted successfully because
+		// some instance types don't have reservations
+		ec2Pricings.Region[sku.Region].Platform[platform].Type[instanceType] = &EC2Specs{
+			CurrentGeneration:                        sku.Attributes["currentGeneration"] == "Yes",
+			OnDemandHourlyCost:                       onDemandCost,
+			OneYearStandardNoUpfrontHourlyCost:       sku.NoUpfrontHourlyCost("1yr", "standard"),
+			ThreeYearsStandardNoUpfrontHourlyCost:    sku.NoUpfrontHourlyCost("3yr", "standard"),
+			OneYearConvertibleNoUpfrontHourlyCost:    sku.NoUpfrontHourlyCost("1yr", "convertible"),
+			ThreeYearsConvertibleNoUpfrontHourlyCost: sku.NoUpfrontHourlyCost("3yr", "convertible"),
 		}
 	}
 	if parsingError {
@@ -0,0 +1,193 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package pricings
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/trackit/jsonlog"
+)
+
+var (
+	RDSServiceCode         = "AmazonRDS"
+	ElastiCacheServiceCode = "AmazonElastiCache"
+	ESServiceCode          = "AmazonES"
+)
+
+// InstanceSpecs stores the cost specifications for an instance type of a managed service
+type InstanceSpecs struct {
+	OnDemandHourlyCost            float64 `json:"onDemandHourlyCost"`
+	OneYearNoUpfrontHourlyCost    float64 `json:"oneYearNoUpfrontHourlyCost"`
+	ThreeYearsNoUpfrontHourlyCost float64 `json:"threeYearsNoUpfrontHourlyCost"`
+}
+
+// InstanceType maps an instance type to a InstanceSpecs struct
+type InstanceType struct {
+	Type map[string]*InstanceSpecs `json:"type"`
+}
+
+// InstanceEngine maps an engine to a InstanceType struct
+type InstanceEngine struct {
+	Engine map[string]InstanceType `json:"engine"`
+}
+
+// InstancePricing maps regions to a InstanceEngine struct
+type InstancePricing struct {
+	Region map[string]InstanceEngine `json:"region"`
+}
+
+// instancePricingService describes how to derive the instance pricings of a managed
+// service from its catalog
+type instancePricingService struct {
+	serviceCode     string
+	productFamilies []string
+	// getEngine returns the engine key of a SKU, or an empty string if the SKU must be skipped
+	getEngine func(attributes map[string]string) string
+	// getType returns the normalized instance type of a SKU
+	getType func(attributes map[string]string) string
+}
+
+var (
+	rdsPricingService = instancePricingService{
+		serviceCode:     RDSServiceCode,
+		productFamilies: []string{"Database Instance"},
+		getEngine:       getRdsPricingEngine,
+		getType:         getAttribute("instanceType"),
+	}
+	elastiCachePricingService = instancePricingService{
+		serviceCode:     ElastiCacheServiceCode,
+		productFamilies: []string{"Cache Instance"},
+		getEngine:       getElastiCachePricingEngine,
+		getType:         getAttribute("instanceType"),
+	}
+	esPricingService = instancePricingService{
+		serviceCode:     ESServiceCode,
+		productFamilies: []string{"Elastic Search Instance", "Amazon OpenSearch Service Instance"},
+		getEngine:       getEsPricingEngine,
+		getType:         getEsPricingType,
+	}
+)
+
+// getAttribute returns a function retrieving an attribute of a SKU
+func getAttribute(name string) func(map[string]string) string {
+	return func(attributes map[string]string) string {
+		return attributes[name]
+	}
+}
+
+// RdsPricingEngine returns the key of an RDS engine and deployment in an InstancePricing
+func RdsPricingEngine(engine string, multiAZ bool) string {
+	if multiAZ {
+		return engine + "/Multi-AZ"
+	}
+	return engine + "/Single-AZ"
+}
+
+// getRdsPricingEngine returns the engine key of an RDS SKU
+// SKUs with a license to bring are skipped since they have no reservation of their own
+func getRdsPricingEngine(attributes map[string]string) string {
+	engine := attributes["databaseEngine"]
+	deployment := attributes["deploymentOption"]
+	if engine == "" || attributes["licenseModel"] == "Bring your own license" {
+		return ""
+	} else if deployment != "Single-AZ" && deployment != "Multi-AZ" {
+		return ""
+	}
+	return RdsPricingEngine(engine, deployment == "Multi-AZ")
+}
+
+// getElastiCachePricingEngine returns the engine key of an ElastiCache SKU
+func getElastiCachePricingEngine(attributes map[string]string) string {
+	return strings.ToLower(attributes["cacheEngine"])
+}
+
+// EsPricingEngine is the only engine key of the ES pricings
+const EsPricingEngine = "opensearch"
+
+// getEsPricingEngine returns the engine key of an ES SKU
+func getEsPricingEngine(attributes map[string]string) string {
+	return EsPricingEngine
+}
+
+// EsPricingType removes the ".elasticsearch" or ".search" suffix of an ES instance type
+// which depends on the version of the service
+func EsPricingType(instanceType string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(instanceType, ".elasticsearch"), ".search")
+}
+
+// getEsPricingType returns the normalized instance type of an ES SKU
+func getEsPricingType(attributes map[string]string) string {
+	return EsPricingType(attributes["instanceType"])
+}
+
+// instancePricingFromCatalog derives the instance pricings of a managed service for
+// all regions from its catalog
+// If one of the buying options is not available, its cost is set to -1.0
+func instancePricingFromCatalog(ctx context.Context, catalog ServiceCatalog, service instancePricingService) (InstancePricing, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	parsingError := false
+	instancePricings := InstancePricing{Region: make(map[string]InstanceEngine, len(EC2RegionCodeToPricingLocationName))}
+	for regionCode := range EC2RegionCodeToPricingLocationName {
+		instancePricings.Region[regionCode] = InstanceEngine{Engine: make(map[string]InstanceType)}
+	}
+	for _, sku := range catalog.skusOf(service.productFamilies...) {
+		engine := service.getEngine(sku.Attributes)
+		instanceType := service.getType(sku.Attributes)
+		if engine == "" {
+			continue
+		}
+		onDemandCost := sku.OnDemandHourlyCost()
+		if sku.Region == "" || instanceType == "" || onDemandCost == -1.0 {
+			// In case of format change, the error will be logged at the end of the function
+			// to avoid sending multiple alerts
+			parsingError = true
+			continue
+		}
+		if _, ok := instancePricings.Region[sku.Region]; !ok {
+			instancePricings.Region[sku.Region] = InstanceEngine{Engine: make(map[string]InstanceType)}
+		}
+		if _, ok := instancePricings.Region[sku.Region].Engine[engine]; !ok {
+			instancePricings.Region[sku.Region].Engine[engine] = InstanceType{Type: make(map[string]*InstanceSpecs, 1)}
+		}
+		// We do not verify that RI costs where extracted successfully because
+		// some instance types don't have reservations
+		// Unlike EC2, some services do not have an offering class: their reservations are standard ones
+		instancePricings.Region[sku.Region].Engine[engine].Type[instanceType] = &InstanceSpecs{
+			OnDemandHourlyCost:            onDemandCost,
+			OneYearNoUpfrontHourlyCost:    sku.NoUpfrontHourlyCost("1yr", "standard"),
+			ThreeYearsNoUpfrontHourlyCost: sku.NoUpfrontHourlyCost("3yr", "standard"),
+		}
+	}
+	if parsingError {
+		logger.Error("Parsing error while retrieving instance pricings", map[string]interface{}{"service": service.serviceCode})
+		return instancePricings, errors.New("Parsing error while retrieving " + service.serviceCode + " pricings")
+	}
+	return instancePricings, nil
+}
+
+// GetSpecs returns the pricings for a given region/engine/type combination
+func (p InstancePricing) GetSpecs(region, engine, instanceType string) (InstanceSpecs, error) {
+	if engines, ok := p.Region[region]; !ok {
+		return InstanceSpecs{}, errors.New("Region not found in pricings")
+	} else if types, ok := engines.Engine[engine]; !ok {
+		return InstanceSpecs{}, errors.New("Engine not found in pricings")
+	} else if costSpecs, ok := types.Type[instanceType]; !ok {
+		return InstanceSpecs{}, errors.New("Type not found in pricings")
+	} else {
+		return *costSpecs, nil
+	}
+}
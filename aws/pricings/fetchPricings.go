@@ -0,0 +1,109 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package pricings
+
+import (
+	"context"
+	"errors"
+)
+
+// Pricing is a product derived from the catalog of a service, stored in the database under its name
+type Pricing struct {
+	Product string
+	Pricing interface{}
+}
+
+// pricingLoader fetches the catalog of a service and derives the products stored from it
+type pricingLoader struct {
+	catalog catalogService
+	derive  func(context.Context, ServiceCatalog) ([]Pricing, error)
+}
+
+// PricingServiceCodes are the services whose pricings are fetched by FetchServicePricings
+var PricingServiceCodes = []string{EC2ServiceCode, RDSServiceCode, ElastiCacheServiceCode, ESServiceCode, S3ServiceCode, LambdaServiceCode}
+
+var pricingLoaders = map[string]pricingLoader{
+	EC2ServiceCode: {
+		catalog: ec2CatalogService,
+		derive: func(ctx context.Context, catalog ServiceCatalog) ([]Pricing, error) {
+			ec2Pricing, err := ec2PricingFromCatalog(ctx, catalog)
+			return []Pricing{
+				{EC2ServiceCode, ec2Pricing},
+				{EBSProduct, catalog.subset(EBSProduct, ebsProductFamilies...)},
+			}, err
+		},
+	},
+	RDSServiceCode: {
+		catalog: rdsCatalogService,
+		derive:  deriveInstancePricings(rdsPricingService, RDSCatalogProduct),
+	},
+	ElastiCacheServiceCode: {
+		catalog: elastiCacheCatalogService,
+		derive:  deriveInstancePricings(elastiCachePricingService, ElastiCacheCatalogProduct),
+	},
+	ESServiceCode: {
+		catalog: esCatalogService,
+		derive:  deriveInstancePricings(esPricingService, ESCatalogProduct),
+	},
+	S3ServiceCode: {
+		catalog: s3CatalogService,
+		derive:  deriveCatalog,
+	},
+	LambdaServiceCode: {
+		catalog: lambdaCatalogService,
+		derive:  deriveCatalog,
+	},
+}
+
+// deriveCatalog stores the catalog of a service as is, under its service code
+func deriveCatalog(ctx context.Context, catalog ServiceCatalog) ([]Pricing, error) {
+	return []Pricing{{catalog.Product, catalog}}, nil
+}
+
+// deriveInstancePricings stores the InstancePricing of a managed service under its
+// service code and its catalog under catalogProduct
+func deriveInstancePricings(service instancePricingService, catalogProduct string) func(context.Context, ServiceCatalog) ([]Pricing, error) {
+	return func(ctx context.Context, catalog ServiceCatalog) ([]Pricing, error) {
+		instancePricing, err := instancePricingFromCatalog(ctx, catalog, service)
+		return []Pricing{
+			{service.serviceCode, instancePricing},
+			{catalogProduct, catalog.subset(catalogProduct)},
+		}, err
+	}
+}
+
+// FetchServicePricings fetches the catalog of a service for all regions, with a single
+// download of its price list, and returns every product derived from it
+func FetchServicePricings(ctx context.Context, serviceCode string) ([]Pricing, error) {
+	return fetchServicePricings(ctx, getProductSource(), serviceCode)
+}
+
+// fetchServicePricings fetches the catalog of a service from a product source and
+// returns every product derived from it
+func fetchServicePricings(ctx context.Context, svc productSource, serviceCode string) ([]Pricing, error) {
+	loader, ok := pricingLoaders[serviceCode]
+	if !ok {
+		return nil, errors.New("No pricings for service " + serviceCode)
+	}
+	catalog, err := fetchServiceCatalog(ctx, svc, loader.catalog)
+	if err != nil {
+		return nil, err
+	}
+	res, err := loader.derive(ctx, catalog)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
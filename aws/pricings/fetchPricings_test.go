@@ -0,0 +1,152 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package pricings
+
+import (
+	"context"
+	"testing"
+)
+
+// fetchTestPricings fetches the pricings of a service from the offer files and
+// returns them by product
+func fetchTestPricings(t *testing.T, serviceCode string) map[string]interface{} {
+	res, err := fetchServicePricings(context.Background(), newOfferFileSource("testdata"), serviceCode)
+	if err != nil {
+		t.Fatalf("fetchServicePricings of %s should succeed. Failed with %s.", serviceCode, err.Error())
+	}
+	products := make(map[string]interface{}, len(res))
+	for _, pricing := range res {
+		products[pricing.Product] = pricing.Pricing
+	}
+	return products
+}
+
+func TestFetchServicePricingsUnknownService(t *testing.T) {
+	if _, err := fetchServicePricings(context.Background(), newOfferFileSource("testdata"), "AmazonUnknown"); err == nil {
+		t.Errorf("fetchServicePricings should fail for a service without pricings.")
+	}
+}
+
+func TestFetchEc2Pricings(t *testing.T) {
+	products := fetchTestPricings(t, EC2ServiceCode)
+	if len(products) != 2 {
+		t.Fatalf("EC2 pricings should give 2 products, gave %d instead.", len(products))
+	}
+	ec2Pricing, ok := products[EC2ServiceCode].(EC2Pricing)
+	if !ok {
+		t.Fatalf("%s should be stored as an EC2Pricing.", EC2ServiceCode)
+	}
+	if len(ec2Pricing.Region) != len(EC2RegionCodeToPricingLocationName) {
+		t.Errorf("EC2 pricings should have %d regions, have %d instead.", len(EC2RegionCodeToPricingLocationName), len(ec2Pricing.Region))
+	}
+	for _, c := range []struct {
+		region       string
+		platform     string
+		instanceType string
+		specs        EC2Specs
+	}{
+		{"us-east-1", "Linux/UNIX", "m5.large", EC2Specs{true, 0.096, 0.06, 0.041, 0.07, -1}},
+		{"us-east-1", "Windows", "m5.large", EC2Specs{true, 0.188, -1, -1, -1, -1}},
+		{"eu-west-3", "Linux/UNIX", "m4.large", EC2Specs{false, 0.116, -1, -1, -1, -1}},
+	} {
+		specs, ok := ec2Pricing.Region[c.region].Platform[c.platform].Type[c.instanceType]
+		if !ok {
+			t.Errorf("%s %s in %s should be in the EC2 pricings.", c.platform, c.instanceType, c.region)
+		} else if *specs != c.specs {
+			t.Errorf("Specs of %s %s in %s should be %v, are %v instead.", c.platform, c.instanceType, c.region, c.specs, *specs)
+		}
+	}
+	if types := ec2Pricing.Region["us-east-1"].Platform["Linux/UNIX"].Type; len(types) != 1 {
+		t.Errorf("Only the BoxUsage without preinstalled software should be in the pricings, got %d types.", len(types))
+	}
+	ebsCatalog, ok := products[EBSProduct].(ServiceCatalog)
+	if !ok {
+		t.Fatalf("%s should be stored as a ServiceCatalog.", EBSProduct)
+	}
+	if ebsCatalog.Product != EBSProduct {
+		t.Errorf("EBS catalog product should be %s, is %s instead.", EBSProduct, ebsCatalog.Product)
+	}
+	if len(ebsCatalog.Skus) != 1 {
+		t.Errorf("EBS catalog should only have the storage SKU, has %d SKUs instead.", len(ebsCatalog.Skus))
+	} else if _, err := ebsCatalog.FindOne("us-east-1", "Storage", map[string]string{"volumeApiName": "gp2"}); err != nil {
+		t.Errorf("FindOne in the EBS catalog should succeed. Failed with %s.", err.Error())
+	}
+}
+
+func TestFetchInstancePricings(t *testing.T) {
+	for _, c := range []struct {
+		serviceCode    string
+		catalogProduct string
+		skus           int
+		region         string
+		engine         string
+		instanceType   string
+		specs          InstanceSpecs
+	}{
+		{RDSServiceCode, RDSCatalogProduct, 3, "us-east-1", RdsPricingEngine("MySQL", false), "db.m5.large", InstanceSpecs{0.171, 0.114, 0.079}},
+		{RDSServiceCode, RDSCatalogProduct, 3, "us-east-1", RdsPricingEngine("MySQL", true), "db.m5.large", InstanceSpecs{0.342, -1, -1}},
+		{ElastiCacheServiceCode, ElastiCacheCatalogProduct, 2, "eu-west-3", "memcached", "cache.r5.large", InstanceSpecs{0.252, -1, -1}},
+		{ESServiceCode, ESCatalogProduct, 3, "us-east-1", EsPricingEngine, "m5.large", InstanceSpecs{0.142, -1, -1}},
+		{ESServiceCode, ESCatalogProduct, 3, "eu-west-3", EsPricingEngine, "r6g.large", InstanceSpecs{0.196, -1, -1}},
+	} {
+		products := fetchTestPricings(t, c.serviceCode)
+		instancePricing, ok := products[c.serviceCode].(InstancePricing)
+		if !ok {
+			t.Fatalf("%s should be stored as an InstancePricing.", c.serviceCode)
+		}
+		if specs, err := instancePricing.GetSpecs(c.region, c.engine, c.instanceType); err != nil {
+			t.Errorf("GetSpecs of %s %s in %s should succeed. Failed with %s.", c.engine, c.instanceType, c.region, err.Error())
+		} else if specs != c.specs {
+			t.Errorf("Specs of %s %s in %s should be %v, are %v instead.", c.engine, c.instanceType, c.region, c.specs, specs)
+		}
+		catalog, ok := products[c.catalogProduct].(ServiceCatalog)
+		if !ok {
+			t.Fatalf("%s should be stored as a ServiceCatalog.", c.catalogProduct)
+		}
+		if catalog.Product != c.catalogProduct {
+			t.Errorf("Catalog product should be %s, is %s instead.", c.catalogProduct, catalog.Product)
+		}
+		if len(catalog.Skus) != c.skus {
+			t.Errorf("Catalog %s should have %d SKUs, has %d instead.", c.catalogProduct, c.skus, len(catalog.Skus))
+		}
+	}
+}
+
+func TestNoUpfrontHourlyCost(t *testing.T) {
+	skuPricing := SkuPricing{
+		NoUpfront: []ReservedPrice{
+			{"1yr", "", 0.1},
+			{"1yr", "convertible", 0.12},
+			{"3yr", "standard", 0.07},
+		},
+	}
+	for _, c := range []struct {
+		duration      string
+		offeringClass string
+		cost          float64
+	}{
+		{"1yr", "standard", 0.1},
+		{"1yr", "convertible", 0.12},
+		{"3yr", "standard", 0.07},
+		{"3yr", "convertible", -1},
+	} {
+		if cost := skuPricing.NoUpfrontHourlyCost(c.duration, c.offeringClass); cost != c.cost {
+			t.Errorf("%s %s no upfront cost should be %f, is %f instead.", c.duration, c.offeringClass, c.cost, cost)
+		}
+	}
+	if cost := (SkuPricing{}).OnDemandHourlyCost(); cost != -1 {
+		t.Errorf("On demand cost of a SKU without on demand price should be -1, is %f instead.", cost)
+	}
+}
@@ -0,0 +1,148 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package pricings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+
+	"github.com/trackit/trackit/config"
+)
+
+// productSource retrieves the products of the AWS price list
+// It is implemented by the Pricing API client and by offerFileSource
+type productSource interface {
+	GetProductsPages(*pricing.GetProductsInput, func(*pricing.GetProductsOutput, bool) bool) error
+}
+
+// offerFile is the format of an AWS offer file (the bulk price list of a service)
+type offerFile struct {
+	OfferCode string                                       `json:"offerCode"`
+	Products  map[string]map[string]interface{}            `json:"products"`
+	Terms     map[string]map[string]map[string]interface{} `json:"terms"`
+}
+
+// offerFileSource serves the products of offer files stored in a local directory
+// The offer file of a service must be named <ServiceCode>.json
+type offerFileSource struct {
+	path   string
+	offers map[string][]aws.JSONValue
+}
+
+// getProductSource returns the offer files directory if one is configured
+// and the Pricing API client otherwise
+func getProductSource() productSource {
+	if config.PricingOfferFilesPath != "" {
+		return newOfferFileSource(config.PricingOfferFilesPath)
+	}
+	return getPricingClient()
+}
+
+func newOfferFileSource(path string) *offerFileSource {
+	return &offerFileSource{
+		path:   path,
+		offers: make(map[string][]aws.JSONValue),
+	}
+}
+
+// GetProductsPages returns the products of the offer file matching the input's filters
+// in a single page. Offer files are only read once per source.
+func (s *offerFileSource) GetProductsPages(input *pricing.GetProductsInput, fn func(*pricing.GetProductsOutput, bool) bool) error {
+	items, err := s.getOfferItems(aws.StringValue(input.ServiceCode))
+	if err != nil {
+		return err
+	}
+	page := &pricing.GetProductsOutput{FormatVersion: input.FormatVersion}
+	for _, item := range items {
+		if matchesFilters(item, input.Filters) {
+			page.PriceList = append(page.PriceList, item)
+		}
+	}
+	fn(page, true)
+	return nil
+}
+
+// getOfferItems reads the offer file of a service and converts its products
+// to the format returned by the Pricing API
+func (s *offerFileSource) getOfferItems(serviceCode string) ([]aws.JSONValue, error) {
+	if items, ok := s.offers[serviceCode]; ok {
+		return items, nil
+	}
+	file, err := os.Open(filepath.Join(s.path, serviceCode+".json"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var offer offerFile
+	if err = json.NewDecoder(file).Decode(&offer); err != nil {
+		return nil, err
+	}
+	items := make([]aws.JSONValue, 0, len(offer.Products))
+	for sku, product := range offer.Products {
+		terms := make(map[string]interface{}, len(offer.Terms))
+		for termType, termsBySku := range offer.Terms {
+			if skuTerms, ok := termsBySku[sku]; ok {
+				terms[termType] = skuTerms
+			}
+		}
+		items = append(items, aws.JSONValue{
+			"serviceCode": serviceCode,
+			"product":     product,
+			"terms":       terms,
+		})
+	}
+	s.offers[serviceCode] = items
+	return items, nil
+}
+
+// matchesFilters returns true if an item matches all the TERM_MATCH filters
+// Filters other than the service code and the product family are matched against the attributes
+func matchesFilters(item aws.JSONValue, filters []*pricing.Filter) bool {
+	for _, filter := range filters {
+		field := aws.StringValue(filter.Field)
+		value := aws.StringValue(filter.Value)
+		var itemValue string
+		switch field {
+		case "ServiceCode":
+			itemValue, _ = item["serviceCode"].(string)
+		case "ProductFamily":
+			itemValue = getProductFamily(item)
+		default:
+			for key, attribute := range getItemAttributes(item) {
+				if strings.EqualFold(key, field) {
+					itemValue, _ = attribute.(string)
+				}
+			}
+		}
+		if !strings.EqualFold(itemValue, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// getProductFamily takes an item from the aws json pricing and returns its product family
+func getProductFamily(item aws.JSONValue) string {
+	if product, ok := item["product"].(map[string]interface{}); !ok {
+	} else if productFamily, ok := product["productFamily"].(string); ok {
+		return productFamily
+	}
+	return ""
+}
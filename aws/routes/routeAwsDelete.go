@@ -22,6 +22,7 @@ import (
 
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/es"
@@ -53,6 +54,8 @@ func deleteAwsAccount(r *http.Request, a routes.Arguments) (int, interface{}) {
 	l := jsonlog.LoggerFromContextOrDefault(r.Context())
 	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
 	accountToDeleteID := aa.Id
+	audit.AddDetail(a, "awsIdentity", aa.AwsIdentity)
+	audit.AddDetail(a, "pretty", aa.Pretty)
 	dbAwsBillRepositories, err := models.AwsBillRepositoryByAwsAccountID(tx, aa.Id)
 	if err != nil {
 		l.Error("unable to retrieve bill repositories", err.Error())
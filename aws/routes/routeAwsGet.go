@@ -40,6 +40,7 @@ func init() {
 				Summary:     "get user's bill repositories and info about their update status",
 				Description: "Gets the list of the user's bill repositories and info about when they have updated or will update.",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().With(
 		db.RequestTransaction{db.Db},
@@ -21,6 +21,7 @@ import (
 
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/routes"
@@ -48,6 +49,9 @@ func patchAwsAccount(r *http.Request, a routes.Arguments) (int, interface{}) {
 		tx := a[db.Transaction].(*sql.Tx)
 		u := a[users.AuthenticatedUser].(users.User)
 		id := a[routes.AwsAccountIdQueryArg].(int)
+		audit.AddDetail(a, "pretty", body.Pretty)
+		audit.AddDetail(a, "payer", body.Payer)
+		audit.AddDetail(a, "roleArn", body.RoleArn)
 		return patchAwsAccountWithValidBody(r, tx, u, body, int(id))
 	} else {
 		return http.StatusBadRequest, errors.New("body is invalid")
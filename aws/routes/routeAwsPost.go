@@ -23,6 +23,7 @@ import (
 
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/models"
@@ -53,7 +54,12 @@ func postAwsAccount(r *http.Request, a routes.Arguments) (int, interface{}) {
 	routes.MustRequestBody(a, &body)
 	tx := a[db.Transaction].(*sql.Tx)
 	u := a[users.AuthenticatedUser].(users.User)
-	return postAwsAccountWithValidBody(r, tx, u, body)
+	status, output := postAwsAccountWithValidBody(r, tx, u, body)
+	if account, ok := output.(aws.AwsAccount); ok {
+		audit.SetAwsAccount(a, account.Id)
+		audit.AddDetail(a, "roleArn", account.RoleArn)
+	}
+	return status, output
 }
 
 // postAwsAccountWithValidBody handles the logic of postAwsAccount assuming the
@@ -23,6 +23,7 @@ import (
 
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/routes"
@@ -42,6 +43,7 @@ func patchAwsSubaccount(r *http.Request, a routes.Arguments) (int, interface{})
 	tx := a[db.Transaction].(*sql.Tx)
 	u := a[users.AuthenticatedUser].(users.User)
 	id := a[routes.AwsAccountIdQueryArg].(int)
+	audit.AddDetail(a, "roleArn", body.RoleArn)
 	return patchAwsSubaccountWithValidBody(r, tx, u, body, id)
 }
 
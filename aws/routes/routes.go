@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/routes"
@@ -35,6 +36,7 @@ func init() {
 			routes.QueryArgs{
 				routes.AwsAccountIdsOptionalQueryArg,
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 		http.MethodPost: routes.H(postAwsAccount).With(
 			users.RequireAuthenticatedUser{users.ViewerCannot},
@@ -48,6 +50,8 @@ func init() {
 				Summary:     "add an aws account",
 				Description: "Adds an AWS account to the user's list of accounts, validating it before succeeding.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionAwsAccountCreate},
 		),
 		http.MethodPatch: routes.H(patchAwsAccount).With(
 			users.RequireAuthenticatedUser{users.ViewerCannot},
@@ -57,6 +61,8 @@ func init() {
 				Summary:     "edit an aws account",
 				Description: "Edits an AWS account from the user's list of accounts.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionAwsAccountUpdate},
 		),
 		http.MethodDelete: routes.H(deleteAwsAccount).With(
 			users.RequireAuthenticatedUser{users.ViewerCannot},
@@ -66,6 +72,8 @@ func init() {
 				Summary:     "delete an aws account",
 				Description: "Delete the aws account passed in the query args.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionAwsAccountDelete},
 		),
 	}.H().With(
 		db.RequestTransaction{db.Db},
@@ -89,6 +97,8 @@ func init() {
 				Summary:     "link a role to a subaccount",
 				Description: "Edits an AWS subaccount from the user's list of accounts.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionAwsSubaccountUpdate},
 		),
 	}.H().With(
 		db.RequestTransaction{db.Db},
@@ -107,6 +117,7 @@ func init() {
 				Summary:     "get data to add next aws account",
 				Description: "Gets data the user must have in order to successfully set up their account with the product.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
 		),
 	}.H().Register("/aws/next")
 }
@@ -120,6 +131,7 @@ func init() {
 				Summary:     "get status of aws accounts",
 				Description: "Gets status of AWS Accounts and their bill repositories.",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/aws/status")
 }
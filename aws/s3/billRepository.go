@@ -25,6 +25,7 @@ import (
 
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/es"
@@ -42,6 +43,7 @@ func init() {
 				Summary:     "get aws account's bill repositories",
 				Description: "Gets the list of bill repositories for an AWS account.",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 		http.MethodPost: routes.H(postBillRepository).With(
 			users.RequireAuthenticatedUser{users.ViewerCannot},
@@ -55,6 +57,8 @@ func init() {
 				Summary:     "add a new bill repository to an aws account",
 				Description: "Adds a bill repository to an AWS account.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionBillRepositoryCreate},
 		),
 		http.MethodPatch: routes.H(patchBillRepository).With(
 			users.RequireAuthenticatedUser{users.ViewerCannot},
@@ -69,6 +73,8 @@ func init() {
 				Summary:     "add a new bill repository to an aws account",
 				Description: "Adds a bill repository to an AWS account.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionBillRepositoryUpdate},
 		),
 		http.MethodDelete: routes.H(deleteBillRepository).With(
 			users.RequireAuthenticatedUser{users.ViewerCannot},
@@ -79,6 +85,8 @@ func init() {
 				Summary:     "delete a bill repository from an aws account",
 				Description: "delete a bill repository from an AWS account.",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionBillRepositoryDelete},
 		),
 	}.H().With(
 		db.RequestTransaction{db.Db},
@@ -231,7 +239,13 @@ func postBillRepository(r *http.Request, a routes.Arguments) (int, interface{})
 		return http.StatusBadRequest, err
 	}
 	tx := a[db.Transaction].(*sql.Tx)
-	return postBillRepositoryWithValidBody(r, tx, aa, body)
+	audit.AddDetail(a, "bucket", body.Bucket)
+	audit.AddDetail(a, "prefix", body.Prefix)
+	status, output := postBillRepositoryWithValidBody(r, tx, aa, body)
+	if br, ok := output.(BillRepository); ok {
+		audit.AddDetail(a, "billRepositoryId", br.Id)
+	}
+	return status, output
 }
 
 func postBillRepositoryWithValidBody(
@@ -273,6 +287,9 @@ func patchBillRepository(r *http.Request, a routes.Arguments) (int, interface{})
 	}
 	tx := a[db.Transaction].(*sql.Tx)
 	brId := a[routes.BillPositoryQueryArg].(int)
+	audit.AddDetail(a, "billRepositoryId", brId)
+	audit.AddDetail(a, "bucket", body.Bucket)
+	audit.AddDetail(a, "prefix", body.Prefix)
 	return patchBillRepositoryWithValidBody(r, tx, aa, brId, body)
 }
 
@@ -379,6 +396,7 @@ func deleteBillRepository(r *http.Request, a routes.Arguments) (int, interface{}
 	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
 	brId := a[routes.BillPositoryQueryArg].(int)
 	tx := a[db.Transaction].(*sql.Tx)
+	audit.AddDetail(a, "billRepositoryId", brId)
 	err := DeleteBillRepositoryById(brId, tx)
 	if err == nil {
 		go func() {
@@ -0,0 +1,111 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+)
+
+const (
+	// CostMetricUnblended is the cost billed for the usage, without any
+	// reservation or savings plan amortization.
+	CostMetricUnblended = "unblended"
+	// CostMetricBlended is the cost averaged over the accounts of an
+	// organization.
+	CostMetricBlended = "blended"
+	// CostMetricNetUnblended is the unblended cost after discounts.
+	CostMetricNetUnblended = "netunblended"
+	// CostMetricAmortized is the cost with the reservations and savings
+	// plans fees spread over the usage they cover.
+	CostMetricAmortized = "amortized"
+
+	// DefaultCostMetric is the cost metric used when none is requested.
+	DefaultCostMetric = CostMetricUnblended
+)
+
+// costMetricFields maps each cost metric to the line item field it is
+// summed from.
+var costMetricFields = map[string]string{
+	CostMetricUnblended:    "unblendedCost",
+	CostMetricBlended:      "blendedCost",
+	CostMetricNetUnblended: "netUnblendedCost",
+	CostMetricAmortized:    "amortizedCost",
+}
+
+// costMetricsIngestedField is set on every line item ingested since the cost
+// metrics other than the unblended cost exist.
+const costMetricsIngestedField = "amortizedCost"
+
+// ErrCostMetricNotIngested is returned when some line items were ingested
+// before the requested cost metric was available.
+var ErrCostMetricNotIngested = errors.New("The bills of this period were ingested before this cost metric was available. Use the unblended cost or ingest the bills again.")
+
+// CostMetricField returns the line item field of a cost metric. An empty
+// metric is the DefaultCostMetric.
+func CostMetricField(metric string) (string, error) {
+	if metric == "" {
+		metric = DefaultCostMetric
+	}
+	if field, ok := costMetricFields[metric]; ok {
+		return field, nil
+	}
+	return "", fmt.Errorf("Invalid cost metric : %s. Possible values are %s", metric, strings.Join(CostMetrics(), ", "))
+}
+
+// CostMetrics returns the sorted names of the available cost metrics.
+func CostMetrics() []string {
+	metrics := make([]string, 0, len(costMetricFields))
+	for metric := range costMetricFields {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+// CheckCostMetricIngested returns ErrCostMetricNotIngested if the line items
+// of accounts between two dates include some ingested before the metric was
+// available, whose cost for the metric would be missing. The unblended cost
+// is always available.
+func CheckCostMetricIngested(ctx context.Context, client *elastic.Client, index string, accountList []string, durationBegin, durationEnd time.Time, metric string) error {
+	if metric == "" || metric == CostMetricUnblended {
+		return nil
+	}
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewRangeQuery("usageStartDate").From(durationBegin).To(durationEnd)).
+		MustNot(elastic.NewExistsQuery(costMetricsIngestedField))
+	if len(accountList) > 0 {
+		accounts := make([]interface{}, len(accountList))
+		for i, account := range accountList {
+			accounts[i] = account
+		}
+		query = query.Filter(elastic.NewTermsQuery("usageAccountId", accounts...))
+	}
+	count, err := client.Count(index).Query(query).Do(ctx)
+	if elastic.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	} else if count > 0 {
+		return ErrCostMetricNotIngested
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ package s3
 import (
 	"context"
 	"database/sql"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -195,6 +196,7 @@ func ingestLineItems(ctx context.Context, bp *elastic.BulkProcessor, index strin
 			}
 			li.BillRepositoryId = br.Id
 			li = extractTags(li)
+			li = computeCosts(li)
 			rq := elastic.NewBulkIndexRequest()
 			rq = rq.Index(index)
 			rq = rq.OpType(opTypeCreate)
@@ -257,11 +259,13 @@ func manifestModifedAfterAndBefore(after time.Time, before time.Time) ManifestPr
 }
 
 // extractTags extracts tags from a LineItem's Any field. It retrieves user
-// tags only and stores them in the Tags map with a clean key.
+// tags only and stores them in the Tags map with a clean key. The bills have
+// a column for every tag key, which is empty for the resources without the
+// tag: such tags are dropped.
 func extractTags(li LineItem) LineItem {
 	var tags []LineItemTags
 	for k, v := range li.Any {
-		if strings.HasPrefix(k, tagPrefix) {
+		if strings.HasPrefix(k, tagPrefix) && v != "" {
 			tags = append(tags, LineItemTags{strings.TrimPrefix(k, tagPrefix), v})
 		}
 	}
@@ -270,6 +274,47 @@ func extractTags(li LineItem) LineItem {
 	return li
 }
 
+// parseCost parses a cost column of a LineItem. Empty or invalid costs are
+// considered as zero.
+func parseCost(cost string) float64 {
+	if f, err := strconv.ParseFloat(cost, 64); err == nil {
+		return f
+	}
+	return 0
+}
+
+// computeCosts fills the cost metrics of a LineItem which are not directly
+// available in the bill. The net unblended cost defaults to the unblended
+// cost when there is no discount. The amortized cost spreads the
+// reservations and savings plans fees over the usage they cover, following
+// the rules of the AWS Cost and Usage Report.
+func computeCosts(li LineItem) LineItem {
+	if li.NetUnblendedCost == "" {
+		li.NetUnblendedCost = li.UnblendedCost
+	}
+	switch li.LineItemType {
+	case "SavingsPlanCoveredUsage":
+		li.AmortizedCost = parseCost(li.SavingsPlanEffectiveCost)
+	case "SavingsPlanRecurringFee":
+		li.AmortizedCost = parseCost(li.SavingsPlanTotalCommitmentToDate) - parseCost(li.SavingsPlanUsedCommitment)
+	case "SavingsPlanNegation", "SavingsPlanUpfrontFee":
+		li.AmortizedCost = 0
+	case "DiscountedUsage":
+		li.AmortizedCost = parseCost(li.ReservationEffectiveCost)
+	case "RIFee":
+		li.AmortizedCost = parseCost(li.ReservationUnusedAmortizedUpfrontFee) + parseCost(li.ReservationUnusedRecurringFee)
+	case "Fee":
+		if li.ReservationARN != "" {
+			li.AmortizedCost = 0
+		} else {
+			li.AmortizedCost = parseCost(li.UnblendedCost)
+		}
+	default:
+		li.AmortizedCost = parseCost(li.UnblendedCost)
+	}
+	return li
+}
+
 func beforeBulk(ctx context.Context) func(int64, []elastic.BulkableRequest) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	return func(execId int64, reqs []elastic.BulkableRequest) {
@@ -0,0 +1,33 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package s3
+
+import (
+	"testing"
+)
+
+func TestExtractTagsDropsEmptyValues(t *testing.T) {
+	li := extractTags(LineItem{Any: map[string]string{
+		"resourceTags/user:Project":     "website",
+		"resourceTags/user:Environment": "",
+		"resourceTags/aws:createdBy":    "root",
+	}})
+	if len(li.Tags) != 1 || li.Tags[0] != (LineItemTags{"Project", "website"}) {
+		t.Errorf("Only the non-empty user tag should be kept, got %v.", li.Tags)
+	}
+	if li.Any != nil {
+		t.Errorf("Any should be cleared, is %v.", li.Any)
+	}
+}
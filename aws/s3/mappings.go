@@ -42,7 +42,7 @@ func init() {
 const TemplateLineItem = `
 {
 	"template": "*-lineitems",
-	"version": 8,
+	"version": 10,
 	"mappings": {
 		"lineitem": {
 			"properties": {
@@ -109,6 +109,38 @@ const TemplateLineItem = `
 					"type": "float",
 					"index": false
 				},
+				"blendedCost": {
+					"type": "float",
+					"index": false
+				},
+				"netUnblendedCost": {
+					"type": "float",
+					"index": false
+				},
+				"amortizedCost": {
+					"type": "float",
+					"index": false
+				},
+				"reservationEffectiveCost": {
+					"type": "float",
+					"index": false
+				},
+				"savingsPlanArn": {
+					"type": "keyword",
+					"norms": false
+				},
+				"savingsPlanEffectiveCost": {
+					"type": "float",
+					"index": false
+				},
+				"savingsPlanTotalCommitment": {
+					"type": "float",
+					"index": false
+				},
+				"savingsPlanUsedCommitment": {
+					"type": "float",
+					"index": false
+				},
 				"taxType": {
 					"type": "keyword",
 					"norms": false
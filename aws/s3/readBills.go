@@ -114,9 +114,21 @@ type LineItem struct {
 	ServiceCode        string            `csv:"product/servicecode"          json:"serviceCode"`
 	CurrencyCode       string            `csv:"lineItem/CurrencyCode"        json:"currencyCode"`
 	UnblendedCost      string            `csv:"lineItem/UnblendedCost"       json:"unblendedCost"`
+	BlendedCost        string            `csv:"lineItem/BlendedCost"         json:"blendedCost,omitempty"`
+	NetUnblendedCost   string            `csv:"lineItem/NetUnblendedCost"    json:"netUnblendedCost,omitempty"`
 	TaxType            string            `csv:"lineItem/TaxType"             json:"taxType"`
 	Any                map[string]string `csv:",any"                         json:"-"`
 	Tags               []LineItemTags    `csv:"-"                            json:"tags,omitempty"`
+	AmortizedCost      float64           `csv:"-"                            json:"amortizedCost"`
+
+	ReservationARN                       string `csv:"reservation/ReservationARN"                            json:"-"`
+	ReservationEffectiveCost             string `csv:"reservation/EffectiveCost"                             json:"reservationEffectiveCost,omitempty"`
+	ReservationUnusedAmortizedUpfrontFee string `csv:"reservation/UnusedAmortizedUpfrontFeeForBillingPeriod" json:"-"`
+	ReservationUnusedRecurringFee        string `csv:"reservation/UnusedRecurringFee"                        json:"-"`
+	SavingsPlanARN                       string `csv:"savingsPlan/SavingsPlanARN"                            json:"savingsPlanArn,omitempty"`
+	SavingsPlanEffectiveCost             string `csv:"savingsPlan/SavingsPlanEffectiveCost"                  json:"savingsPlanEffectiveCost,omitempty"`
+	SavingsPlanTotalCommitmentToDate     string `csv:"savingsPlan/TotalCommitmentToDate"                     json:"savingsPlanTotalCommitment,omitempty"`
+	SavingsPlanUsedCommitment            string `csv:"savingsPlan/UsedCommitment"                            json:"savingsPlanUsedCommitment,omitempty"`
 }
 
 type LineItemTags struct {
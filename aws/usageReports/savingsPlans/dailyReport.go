@@ -0,0 +1,186 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package savingsPlans
+
+import (
+	"context"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/trackit/jsonlog"
+
+	taws "github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/s3"
+	"github.com/trackit/trackit/aws/usageReports"
+	"github.com/trackit/trackit/es"
+)
+
+const maxAggregationSize = 0x7FFFFFFF
+
+// previousMonthRefreshDays is the number of days of a month during which the
+// report of the previous month is still refreshed
+const previousMonthRefreshDays = 5
+
+var (
+	// eligibleProductCodes are the products whose usage can be covered by a Savings Plan
+	eligibleProductCodes = []interface{}{"AmazonEC2", "AWSLambda", "AmazonECS", "AmazonEKS", "AmazonSageMaker"}
+	// eligibleUsageTypes are the patterns of the usage types which can be covered by a Savings Plan
+	eligibleUsageTypes = []string{
+		"*BoxUsage*",
+		"*DedicatedUsage*",
+		"*HostUsage*",
+		"*Fargate-vCPU-Hours*",
+		"*Fargate-GB-Hours*",
+		"*Lambda-GB-Second*",
+		"*Lambda-Provisioned*",
+		"*ml.*",
+	}
+)
+
+// EligibleUsageQuery returns a query on the line items matching the on demand usage which
+// could have been covered by a Savings Plan
+func EligibleUsageQuery() *elastic.BoolQuery {
+	usageTypes := elastic.NewBoolQuery().MinimumNumberShouldMatch(1)
+	for _, pattern := range eligibleUsageTypes {
+		usageTypes = usageTypes.Should(elastic.NewWildcardQuery("usageType", pattern))
+	}
+	return elastic.NewBoolQuery().
+		Filter(elastic.NewTermQuery("lineItemType", "Usage")).
+		Filter(elastic.NewTermsQuery("productCode", eligibleProductCodes...)).
+		Filter(usageTypes)
+}
+
+// getElasticSearchSavingsPlansParams builds the request aggregating the savingsPlan/*
+// columns of the line items of an account between begin and end
+func getElasticSearchSavingsPlansParams(account string, begin, end time.Time, client *elastic.Client, index string) *elastic.SearchService {
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewTermQuery("usageAccountId", account)).
+		Filter(elastic.NewRangeQuery("usageStartDate").From(begin).To(end))
+	search := client.Search().Index(index).Size(0).Query(query)
+	search.Aggregation("covered", elastic.NewFilterAggregation().Filter(elastic.NewTermQuery("lineItemType", "SavingsPlanCoveredUsage")).
+		SubAggregation("onDemandCost", elastic.NewSumAggregation().Field("unblendedCost")).
+		SubAggregation("effectiveCost", elastic.NewSumAggregation().Field("savingsPlanEffectiveCost")))
+	search.Aggregation("uncovered", elastic.NewFilterAggregation().Filter(EligibleUsageQuery()).
+		SubAggregation("onDemandCost", elastic.NewSumAggregation().Field("unblendedCost")))
+	search.Aggregation("fees", elastic.NewFilterAggregation().Filter(elastic.NewTermQuery("lineItemType", "SavingsPlanRecurringFee")).
+		SubAggregation("plans", elastic.NewTermsAggregation().Field("savingsPlanArn").Size(maxAggregationSize).
+			SubAggregation("totalCommitment", elastic.NewSumAggregation().Field("savingsPlanTotalCommitment")).
+			SubAggregation("usedCommitment", elastic.NewSumAggregation().Field("savingsPlanUsedCommitment"))))
+	return search
+}
+
+// sumValue returns the value of a sum aggregation, or 0 if it is missing
+func sumValue(aggregations elastic.Aggregations, name string) float64 {
+	if sum, found := aggregations.Sum(name); found && sum.Value != nil {
+		return *sum.Value
+	}
+	return 0
+}
+
+// parseSavingsPlansResult computes the coverage, the utilization and the unused
+// commitment from the result of the request built by getElasticSearchSavingsPlansParams
+func parseSavingsPlansResult(res *elastic.SearchResult) SavingsPlans {
+	savingsPlans := SavingsPlans{
+		Plans: make([]SavingsPlan, 0),
+	}
+	if covered, found := res.Aggregations.Filter("covered"); found {
+		savingsPlans.CoveredOnDemandCost = sumValue(covered.Aggregations, "onDemandCost")
+		savingsPlans.EffectiveCost = sumValue(covered.Aggregations, "effectiveCost")
+	}
+	if uncovered, found := res.Aggregations.Filter("uncovered"); found {
+		savingsPlans.UncoveredOnDemandCost = sumValue(uncovered.Aggregations, "onDemandCost")
+	}
+	if fees, found := res.Aggregations.Filter("fees"); found {
+		if plans, found := fees.Aggregations.Terms("plans"); found {
+			for _, bucket := range plans.Buckets {
+				arn, _ := bucket.Key.(string)
+				plan := SavingsPlan{
+					Arn:             arn,
+					TotalCommitment: sumValue(bucket.Aggregations, "totalCommitment"),
+					UsedCommitment:  sumValue(bucket.Aggregations, "usedCommitment"),
+				}
+				plan.UnusedCommitment = plan.TotalCommitment - plan.UsedCommitment
+				plan.Utilization = ratio(plan.UsedCommitment, plan.TotalCommitment)
+				savingsPlans.TotalCommitment += plan.TotalCommitment
+				savingsPlans.UsedCommitment += plan.UsedCommitment
+				savingsPlans.Plans = append(savingsPlans.Plans, plan)
+			}
+		}
+	}
+	savingsPlans.UnusedCommitment = savingsPlans.TotalCommitment - savingsPlans.UsedCommitment
+	savingsPlans.Utilization = ratio(savingsPlans.UsedCommitment, savingsPlans.TotalCommitment)
+	savingsPlans.Coverage = ratio(savingsPlans.CoveredOnDemandCost, savingsPlans.CoveredOnDemandCost+savingsPlans.UncoveredOnDemandCost)
+	savingsPlans.Savings = savingsPlans.CoveredOnDemandCost - savingsPlans.EffectiveCost
+	return savingsPlans
+}
+
+// getSavingsPlansUsage aggregates the line items of an AwsAccount between begin and end
+func getSavingsPlansUsage(ctx context.Context, aa taws.AwsAccount, begin, end time.Time) (SavingsPlans, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	index := es.IndexNameForUserId(aa.UserId, s3.IndexPrefixLineItem)
+	res, err := getElasticSearchSavingsPlansParams(aa.AwsIdentity, begin, end, es.Client, index).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			logger.Warning("Line items index does not exist", map[string]interface{}{
+				"index": index,
+				"error": err.Error(),
+			})
+			return SavingsPlans{Begin: begin, End: end, Plans: []SavingsPlan{}}, nil
+		}
+		logger.Error("Failed to get Savings Plans usage from line items", err.Error())
+		return SavingsPlans{}, err
+	}
+	savingsPlans := parseSavingsPlansResult(res)
+	savingsPlans.Begin = begin
+	savingsPlans.End = end
+	return savingsPlans, nil
+}
+
+// putSavingsPlansReport computes the Savings Plans usage of an AwsAccount between
+// begin and end and imports it in ElasticSearch as the daily report of end.
+func putSavingsPlansReport(ctx context.Context, aa taws.AwsAccount, begin, end time.Time) error {
+	savingsPlans, err := getSavingsPlansUsage(ctx, aa, begin, end)
+	if err != nil {
+		return err
+	}
+	return importSavingsPlansReportToEs(ctx, aa, SavingsPlansReport{
+		ReportBase: utils.ReportBase{
+			Account:    aa.AwsIdentity,
+			ReportDate: end,
+			ReportType: "daily",
+		},
+		SavingsPlans: savingsPlans,
+	})
+}
+
+// FetchDailySavingsPlansStats computes the month to date Savings Plans coverage and
+// utilization of an AwsAccount from its line items and imports it in ElasticSearch.
+// The report of the day is overwritten each time the task runs. During the first
+// days of a month, the report of the previous month is refreshed as well since the
+// CUR of a closed billing period keeps being updated for a few days.
+func FetchDailySavingsPlansStats(ctx context.Context, awsAccount taws.AwsAccount) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	logger.Info("Fetching Savings Plans stats", map[string]interface{}{"awsAccountId": awsAccount.Id})
+	now := time.Now().UTC()
+	begin := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).UTC()
+	if now.Day() <= previousMonthRefreshDays {
+		previousBegin := begin.AddDate(0, -1, 0)
+		previousEnd := begin.Add(-time.Millisecond)
+		if err := putSavingsPlansReport(ctx, awsAccount, previousBegin, previousEnd); err != nil {
+			return err
+		}
+	}
+	return putSavingsPlansReport(ctx, awsAccount, begin, now)
+}
@@ -0,0 +1,123 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package savingsPlans
+
+import (
+	"context"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/es"
+)
+
+const TypeSavingsPlansReport = "savingsplans-report"
+const IndexPrefixSavingsPlansReport = "savingsplans-reports"
+const TemplateNameSavingsPlansReport = "savingsplans-reports"
+
+// put the ElasticSearch index for *-savingsplans-reports indices at startup.
+func init() {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+	res, err := es.Client.IndexPutTemplate(TemplateNameSavingsPlansReport).BodyString(TemplateSavingsPlansReport).Do(ctx)
+	if err != nil {
+		jsonlog.DefaultLogger.Error("Failed to put ES index SavingsPlansReport.", err)
+	} else {
+		jsonlog.DefaultLogger.Info("Put ES index SavingsPlansReport.", res)
+	}
+}
+
+const TemplateSavingsPlansReport = `
+{
+	"template": "*-savingsplans-reports",
+	"version": 1,
+	"mappings": {
+		"savingsplans-report": {
+			"properties": {
+				"account": {
+					"type": "keyword"
+				},
+				"reportDate": {
+					"type": "date"
+				},
+				"reportType": {
+					"type": "keyword"
+				},
+				"savingsPlans": {
+					"properties": {
+						"begin": {
+							"type": "date"
+						},
+						"end": {
+							"type": "date"
+						},
+						"totalCommitment": {
+							"type": "double"
+						},
+						"usedCommitment": {
+							"type": "double"
+						},
+						"unusedCommitment": {
+							"type": "double"
+						},
+						"utilization": {
+							"type": "double"
+						},
+						"coveredOnDemandCost": {
+							"type": "double"
+						},
+						"uncoveredOnDemandCost": {
+							"type": "double"
+						},
+						"coverage": {
+							"type": "double"
+						},
+						"effectiveCost": {
+							"type": "double"
+						},
+						"savings": {
+							"type": "double"
+						},
+						"plans": {
+							"properties": {
+								"arn": {
+									"type": "keyword"
+								},
+								"totalCommitment": {
+									"type": "double"
+								},
+								"usedCommitment": {
+									"type": "double"
+								},
+								"unusedCommitment": {
+									"type": "double"
+								},
+								"utilization": {
+									"type": "double"
+								}
+							}
+						}
+					}
+				}
+			},
+			"_all": {
+				"enabled": false
+			},
+			"numeric_detection": false,
+			"date_detection": false
+		}
+	}
+}
+`
@@ -0,0 +1,121 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package savingsPlans
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/usageReports"
+	"github.com/trackit/trackit/es"
+)
+
+type (
+	// SavingsPlansReport is saved in ES to have the Savings Plans usage of an account over a period
+	SavingsPlansReport struct {
+		utils.ReportBase
+		SavingsPlans SavingsPlans `json:"savingsPlans"`
+	}
+
+	// SavingsPlans contains the coverage, the utilization and the unused commitment
+	// of the Savings Plans of an account, derived from the CUR savingsPlan/* columns
+	SavingsPlans struct {
+		Begin                 time.Time     `json:"begin"`
+		End                   time.Time     `json:"end"`
+		TotalCommitment       float64       `json:"totalCommitment"`
+		UsedCommitment        float64       `json:"usedCommitment"`
+		UnusedCommitment      float64       `json:"unusedCommitment"`
+		Utilization           float64       `json:"utilization"`
+		CoveredOnDemandCost   float64       `json:"coveredOnDemandCost"`
+		UncoveredOnDemandCost float64       `json:"uncoveredOnDemandCost"`
+		Coverage              float64       `json:"coverage"`
+		EffectiveCost         float64       `json:"effectiveCost"`
+		Savings               float64       `json:"savings"`
+		Plans                 []SavingsPlan `json:"plans"`
+	}
+
+	// SavingsPlan contains the commitment usage of a single Savings Plan
+	SavingsPlan struct {
+		Arn              string  `json:"arn"`
+		TotalCommitment  float64 `json:"totalCommitment"`
+		UsedCommitment   float64 `json:"usedCommitment"`
+		UnusedCommitment float64 `json:"unusedCommitment"`
+		Utilization      float64 `json:"utilization"`
+	}
+)
+
+// ratio returns the percentage of part over total, or 0 if total is 0
+func ratio(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return part / total * 100
+}
+
+// importSavingsPlansReportToEs imports a Savings Plans report in ElasticSearch.
+func importSavingsPlansReportToEs(ctx context.Context, aa aws.AwsAccount, report SavingsPlansReport) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	logger.Info("Updating Savings Plans report for AWS account.", map[string]interface{}{
+		"awsAccount": aa,
+	})
+	index := es.IndexNameForUserId(aa.UserId, IndexPrefixSavingsPlansReport)
+	bp, err := utils.GetBulkProcessor(ctx)
+	if err != nil {
+		logger.Error("Failed to get bulk processor.", err.Error())
+		return err
+	}
+	id, err := generateId(report)
+	if err != nil {
+		logger.Error("Error when marshaling Savings Plans report var", err.Error())
+		return err
+	}
+	bp = utils.AddDocToBulkProcessor(bp, report, TypeSavingsPlansReport, index, id)
+	err = bp.Flush()
+	if closeErr := bp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logger.Error("Failed to put Savings Plans report in ES", err.Error())
+		return err
+	}
+	logger.Info("Savings Plans report put in ES", nil)
+	return nil
+}
+
+// generateId returns an id which is the same for every report of an account
+// for a given day, so that running the task several times a day overwrites the report.
+func generateId(report SavingsPlansReport) (string, error) {
+	ji, err := json.Marshal(struct {
+		Account    string `json:"account"`
+		ReportDate string `json:"reportDate"`
+		Type       string `json:"reportType"`
+	}{
+		report.Account,
+		report.ReportDate.Format("2006-01-02"),
+		report.ReportType,
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := md5.Sum(ji)
+	hash64 := base64.URLEncoding.EncodeToString(hash[:])
+	return hash64, nil
+}
@@ -84,7 +84,10 @@ func (uc UsersCache) getFunc(hf routes.HandlerFunc) routes.HandlerFunc {
 			return hf(writer, request, args)
 		}
 		rdCache, err := initialiseCacheInfos(request.URL.String(), args, logger)
-		if err != nil {
+		if err == errAwsAccountNotAllowed {
+			writeHeaderCacheStatus(writer, cacheStatusError, "ACCOUNT-NOT-ALLOWED")
+			return hf(writer, request, args)
+		} else if err != nil {
 			logger.Error("Error during cache initialization", map[string]interface{}{
 				"error": err.Error(),
 			})
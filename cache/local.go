@@ -17,6 +17,7 @@ package cache
 import (
 	"crypto/md5"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -54,60 +55,81 @@ func parseRouteFromUrl(url string, rc *redisCache) {
 	}
 }
 
-// getAwsIdentityFromSharedAcc retrieves all shared accounts based on the user's ID.
-// Then the AWS identity, from each respective account, is added to the list of
-// AWS identities concerned by the cache.
-func getAwsIdentityFromSharedAcc(user users.User, identities *[]string, context *sql.Tx, logger jsonlog.Logger) error {
-	sharedAcc, err := models.SharedAccountByUserID(db.Db, user.Id)
+// errAwsAccountNotAllowed is returned when the request selects an AWS account
+// the user may not access, in which case the request is not cached.
+var errAwsAccountNotAllowed = errors.New("AWS account not allowed")
+
+// getAllowedAwsIdentities returns the AWS identities of the accounts the user
+// owns or has been shared, and which they may access during the request.
+// RequirePermission restricts them to the accounts the user has the
+// permission of the route on.
+func getAllowedAwsIdentities(user users.User, tx *sql.Tx, logger jsonlog.Logger) (map[string]bool, error) {
+	identities := make(map[string]bool)
+	awsAccs, err := models.AwsAccountByUserID(tx, user.Id)
+	if err != nil {
+		logger.Error("Unable to retrieve AWS' accounts by user id.", map[string]interface{}{
+			"error":  err.Error(),
+			"userId": user.Id,
+		})
+		return nil, err
+	}
+	for _, userAccContent := range awsAccs {
+		if user.CanAccessAwsAccount(userAccContent.ID) {
+			identities[userAccContent.AwsIdentity] = true
+		}
+	}
+	sharedAcc, err := models.SharedAccountByUserID(tx, user.Id)
 	if err != nil {
 		logger.Error("Unable to retrieve AWS' shared accounts by user id.", map[string]interface{}{
 			"error":  err.Error(),
 			"userId": user.Id,
 		})
-		return err
+		return nil, err
 	}
 	for _, sharedAccContent := range sharedAcc {
-		localAcc, localErr := models.AwsAccountByID(context, sharedAccContent.AccountID)
+		if !user.CanAccessAwsAccount(sharedAccContent.AccountID) {
+			continue
+		}
+		localAcc, localErr := models.AwsAccountByID(tx, sharedAccContent.AccountID)
 		if localErr != nil {
 			logger.Error("Unable to retrieve AWS' account by shared user id.", map[string]interface{}{
 				"error":     localErr.Error(),
 				"accountID": sharedAccContent.AccountID,
 			})
-			return localErr
+			return nil, localErr
 		}
-		*identities = append(*identities, localAcc.AwsIdentity)
+		identities[localAcc.AwsIdentity] = true
 	}
-	return nil
+	return identities, nil
 }
 
-// Initialize cache information by getting a list of all AWS identities and
-// retrieving different information from the URL. The user's key is also formatted
-// depending of the previous information.
+// Initialize cache information by getting the list of AWS identities the
+// request is about and retrieving different information from the URL. The
+// identities are the ones selected in the arguments, or all those the user
+// may access; selecting one they may not access returns
+// errAwsAccountNotAllowed. The user's key is also formatted depending of the
+// previous information.
 func initialiseCacheInfos(url string, args routes.Arguments, logger jsonlog.Logger) (rtn redisCache, err error) {
-	var allAcc []string
 	parseRouteFromUrl(url, &rtn)
-	if args[routes.AwsAccountsOptionalQueryArg] != nil {
-		allAcc = args[routes.AwsAccountsOptionalQueryArg].([]string)
-	} else {
-		tx := args[db.Transaction].(*sql.Tx)
-		user := args[users.AuthenticatedUser].(users.User)
-		awsAccs, awsAccsErr := models.AwsAccountByUserID(tx, user.Id)
-		if awsAccsErr != nil {
-			logger.Error("Unable to retrieve AWS' accounts by user id.", map[string]interface{}{
-				"error":  awsAccsErr.Error(),
-				"userId": user.Id,
-			})
-			err = awsAccsErr
-			return
-		}
-		for _, userAccContent := range awsAccs {
-			allAcc = append(allAcc, userAccContent.AwsIdentity)
+	tx := args[db.Transaction].(*sql.Tx)
+	user := args[users.AuthenticatedUser].(users.User)
+	allowed, err := getAllowedAwsIdentities(user, tx, logger)
+	if err != nil {
+		return
+	}
+	if selected, ok := args[routes.AwsAccountsOptionalQueryArg].([]string); ok {
+		for _, identity := range selected {
+			if !allowed[identity] {
+				err = errAwsAccountNotAllowed
+				return
+			}
 		}
-		if err = getAwsIdentityFromSharedAcc(user, &allAcc, tx, logger); err != nil {
-			return
+		rtn.awsAccount = append(rtn.awsAccount, selected...)
+	} else {
+		for identity := range allowed {
+			rtn.awsAccount = append(rtn.awsAccount, identity)
 		}
 	}
-	rtn.awsAccount = append(rtn.awsAccount, allAcc...)
 	sort.Strings(rtn.awsAccount)
 	formatKey(&rtn)
 	return
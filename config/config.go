@@ -72,20 +72,44 @@ var (
 	SmtpSender string
 	// UrlEc2Pricing is the URL used by downloadJson to fetch the EC2 pricing.
 	UrlEc2Pricing string
+	// PricingOfferFilesPath is a directory of AWS offer files used instead of the Pricing API.
+	PricingOfferFilesPath string
 	// Task is the task to be run. "server", by default.
 	Task string
 	// Periodics, if true, indicates periodic tasks should be run in goroutines within the process.
 	Periodics bool
+	// PeriodicsAdmins lists the IDs of the users allowed to see the periodic tasks.
+	PeriodicsAdmins string
+	// IdleRdsMaxConnections is the number of connections under which an RDS instance is considered idle.
+	IdleRdsMaxConnections float64
+	// TrustedProxies lists the addresses of the proxies whose X-Forwarded-For header is trusted.
+	TrustedProxies string
 	// Aws Market place product code
 	MarketPlaceProductCode string
 	// Aws Market place product code for Tagbot
 	TagbotMarketPlaceProductCode string
+	// AnomalyDetectionAlgorithm is the algorithm used to detect anomalies when none is set for an AWS account or a product.
+	AnomalyDetectionAlgorithm string
 	// AnomalyDetectionBollingerBandPeriod is the period in day used to generate the upper band.
 	AnomalyDetectionBollingerBandPeriod int
 	// AnomalyDetectionBollingerBandStandardDeviationCoefficient is the coefficient applied to the standard deviation used to generate the upper band.
 	AnomalyDetectionBollingerBandStandardDeviationCoefficient float64
 	// AnomalyDetectionBollingerBandUpperBandCoefficient is the coefficient applied to the upper band.
 	AnomalyDetectionBollingerBandUpperBandCoefficient float64
+	// AnomalyDetectionSeasonalWeeks is the number of previous weeks used by the seasonal algorithm to generate the baseline of a weekday.
+	AnomalyDetectionSeasonalWeeks int
+	// AnomalyDetectionSeasonalStandardDeviationCoefficient is the coefficient applied to the standard deviation of the weekday baseline.
+	AnomalyDetectionSeasonalStandardDeviationCoefficient float64
+	// AnomalyDetectionEwmaPeriod is the period in day used to warm up the exponentially weighted moving average.
+	AnomalyDetectionEwmaPeriod int
+	// AnomalyDetectionEwmaAlpha is the smoothing factor of the exponentially weighted moving average, between 0 and 1.
+	AnomalyDetectionEwmaAlpha float64
+	// AnomalyDetectionEwmaStandardDeviationCoefficient is the coefficient applied to the exponentially weighted standard deviation.
+	AnomalyDetectionEwmaStandardDeviationCoefficient float64
+	// AnomalyDetectionMadPeriod is the period in day used to compute the median and the median absolute deviation.
+	AnomalyDetectionMadPeriod int
+	// AnomalyDetectionMadCoefficient is the coefficient applied to the scaled median absolute deviation.
+	AnomalyDetectionMadCoefficient float64
 	// AnomalyDetectionDisturbanceCleaningMinPercentOfDailyBill is the percentage of the daily bill an anomaly has to exceed. Otherwise, it's considered as a disturbance.
 	AnomalyDetectionDisturbanceCleaningMinPercentOfDailyBill float64
 	// AnomalyDetectionDisturbanceCleaningMinAbsoluteCost is the cost an anomaly has to exceed. Otherwise, it's considered as a disturbance.
@@ -110,6 +134,20 @@ var (
 	SQSQueueName string
 	// Environment (prod, stg, dev).
 	Environment string
+	// OidcIssuer is the URL of the OpenID Connect provider used for single sign-on.
+	OidcIssuer string
+	// OidcClientId is the client ID registered with the OpenID Connect provider.
+	OidcClientId string
+	// OidcClientSecret is the client secret registered with the OpenID Connect provider.
+	OidcClientSecret string
+	// OidcRedirectUrl is the URL the OpenID Connect provider redirects users to after they logged in.
+	OidcRedirectUrl string
+	// OidcParentUser is the ID of the user under which single sign-on users are provisioned.
+	OidcParentUser int
+	// OidcGroupsClaim is the ID token claim holding the groups of the user.
+	OidcGroupsClaim string
+	// OidcGroupPermissions maps groups to shared account permission levels.
+	OidcGroupPermissions string
 )
 
 func init() {
@@ -134,6 +172,7 @@ func init() {
 	flag.IntVar(&RedisDB, "redis-db", 1, "The DB to use in Redis")
 	flag.BoolVar(&PrettyJsonResponses, "pretty-json-responses", false, "JSON HTTP responses should be pretty.")
 	flag.StringVar(&UrlEc2Pricing, "url-ec2-pricing", "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/index.json", "The URL used to download the EC2 pricing.")
+	flag.StringVar(&PricingOfferFilesPath, "pricing-offer-files-path", "", "A directory of AWS offer files named <ServiceCode>.json used to fetch the pricings instead of the Pricing API.")
 	flag.StringVar(&SmtpAddress, "smtp-address", "", "The address of the SMTP server.")
 	flag.StringVar(&SmtpPort, "smtp-port", "", "The port of the SMTP server.")
 	flag.StringVar(&SmtpUser, "smtp-user", "", "The user for the SMTP server.")
@@ -141,11 +180,22 @@ func init() {
 	flag.StringVar(&SmtpSender, "smtp-sender", "", "The mail address used to send mails.")
 	flag.StringVar(&Task, "task", "server", "The task to be run.")
 	flag.BoolVar(&Periodics, "periodics", true, "Periodic jobs should be run by the process.")
+	flag.StringVar(&PeriodicsAdmins, "periodics-admins", "", "Comma-separated IDs of the users allowed to see the periodic tasks, their runs and errors. Nobody can if left empty.")
+	flag.Float64Var(&IdleRdsMaxConnections, "idle-rds-max-connections", 1, "RDS instances whose number of connections stayed at or below this value during the last week are reported as idle.")
+	flag.StringVar(&TrustedProxies, "trusted-proxies", "", "Comma-separated addresses or CIDR ranges of the proxies, such as the load balancer, whose X-Forwarded-For header is trusted. The header is ignored if left empty.")
 	flag.StringVar(&MarketPlaceProductCode, "market-place-product-code", "productcode", "Aws market place product code.")
 	flag.StringVar(&TagbotMarketPlaceProductCode, "tagbot-market-place-product-code", "productcode", "Aws market place product code for Tagbot.")
+	flag.StringVar(&AnomalyDetectionAlgorithm, "anomaly-detection-algorithm", "bollinger", "Default algorithm used to detect anomalies. Possible values are bollinger, seasonal, ewma and mad.")
 	flag.IntVar(&AnomalyDetectionBollingerBandPeriod, "anomaly-detection-bollinger-band-period", 3, "Period used by the Bollinger Band algorithm.")
 	flag.Float64Var(&AnomalyDetectionBollingerBandStandardDeviationCoefficient, "anomaly-detection-bollinger-band-standard-deviation-coefficient", 3.0, "Coefficient used by the Bollinger Band algorithm to generate the standard deviation.")
 	flag.Float64Var(&AnomalyDetectionBollingerBandUpperBandCoefficient, "anomaly-detection-bollinger-band-upper-band-coefficient", 1.05, "Coefficient used by the Bollinger Band algorithm to generate the upper band.")
+	flag.IntVar(&AnomalyDetectionSeasonalWeeks, "anomaly-detection-seasonal-weeks", 4, "Number of previous weeks used by the seasonal algorithm.")
+	flag.Float64Var(&AnomalyDetectionSeasonalStandardDeviationCoefficient, "anomaly-detection-seasonal-standard-deviation-coefficient", 3.0, "Coefficient used by the seasonal algorithm to generate the standard deviation.")
+	flag.IntVar(&AnomalyDetectionEwmaPeriod, "anomaly-detection-ewma-period", 7, "Period used to warm up the EWMA algorithm.")
+	flag.Float64Var(&AnomalyDetectionEwmaAlpha, "anomaly-detection-ewma-alpha", 0.3, "Smoothing factor used by the EWMA algorithm.")
+	flag.Float64Var(&AnomalyDetectionEwmaStandardDeviationCoefficient, "anomaly-detection-ewma-standard-deviation-coefficient", 3.0, "Coefficient used by the EWMA algorithm to generate the standard deviation.")
+	flag.IntVar(&AnomalyDetectionMadPeriod, "anomaly-detection-mad-period", 14, "Period used by the median/MAD algorithm.")
+	flag.Float64Var(&AnomalyDetectionMadCoefficient, "anomaly-detection-mad-coefficient", 3.5, "Coefficient used by the median/MAD algorithm to generate the upper band.")
 	flag.Float64Var(&AnomalyDetectionDisturbanceCleaningMinPercentOfDailyBill, "anomaly-detection-disturbance-cleaning-min-percent-of-daily-bill", 5.0, "Percentage of the daily bill an anomaly has to exceed.")
 	flag.Float64Var(&AnomalyDetectionDisturbanceCleaningMinAbsoluteCost, "anomaly-detection-disturbance-cleaning-absolute-cost", 20.0, "Absolute cost an anomaly has to exceed.")
 	flag.IntVar(&AnomalyDetectionDisturbanceCleaningHighestSpendingMinRank, "anomaly-detection-disturbance-cleaning-highest-spending-min-rank", 5, "Minimum rank of the service where the anomaly has been detected.")
@@ -158,6 +208,13 @@ func init() {
 	flag.BoolVar(&Worker, "worker", false, "Whether to start API as a worker or not.")
 	flag.StringVar(&SQSQueueName, "sqs-queue-name", "trackit-dispatcher-queue", "Name of the SQS Queue for workers.")
 	flag.StringVar(&Environment, "env", "dev", "Environment of the Trackit API.")
+	flag.StringVar(&OidcIssuer, "oidc-issuer", "", "The URL of the OpenID Connect provider. Single sign-on is disabled if left empty.")
+	flag.StringVar(&OidcClientId, "oidc-client-id", "", "The client ID registered with the OpenID Connect provider.")
+	flag.StringVar(&OidcClientSecret, "oidc-client-secret", "", "The client secret registered with the OpenID Connect provider.")
+	flag.StringVar(&OidcRedirectUrl, "oidc-redirect-url", "https://re.trackit.io/sso/callback", "The URL the OpenID Connect provider redirects users to after they logged in.")
+	flag.IntVar(&OidcParentUser, "oidc-parent-user", 0, "The ID of the user under which single sign-on users are provisioned.")
+	flag.StringVar(&OidcGroupsClaim, "oidc-groups-claim", "groups", "The ID token claim holding the groups of the user.")
+	flag.StringVar(&OidcGroupPermissions, "oidc-group-permissions", "", "Groups mapped to shared account permission levels, as a comma-separated list of group:level with level being admin, standard or read.")
 	flag.Parse()
 	if len(EsAddress) == 0 {
 		EsAddress = stringArray{"http://127.0.0.1:9200"}
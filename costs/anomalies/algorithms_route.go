@@ -0,0 +1,184 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/anomaliesDetection"
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+type (
+	// AlgorithmSelection is the algorithm used to detect the anomalies of a
+	// product. An empty product sets the algorithm of the whole AWS account.
+	AlgorithmSelection struct {
+		Product   string `json:"product"`
+		Algorithm string `json:"algorithm" req:"nonzero"`
+	}
+
+	// AlgorithmsBody is the body sent by getAnomaliesAlgorithms
+	// and required by postAnomaliesAlgorithms.
+	AlgorithmsBody struct {
+		Algorithms []AlgorithmSelection `json:"algorithms"`
+	}
+
+	// algorithmsResponse is the response of getAnomaliesAlgorithms.
+	algorithmsResponse struct {
+		Available  []string             `json:"available"`
+		Default    string               `json:"default"`
+		Algorithms []AlgorithmSelection `json:"algorithms"`
+	}
+)
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getAnomaliesAlgorithms).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			aws.RequireAwsAccountId{},
+			routes.Documentation{
+				Summary:     "get the anomalies detection algorithms",
+				Description: "Responds with the available algorithms and the ones selected for the AWS account and its products",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+		http.MethodPost: routes.H(postAnomaliesAlgorithms).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			aws.RequireAwsAccountId{},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{AlgorithmsBody{
+				Algorithms: []AlgorithmSelection{
+					{Product: "", Algorithm: anomalies.AlgorithmSeasonal},
+					{Product: "AmazonEC2", Algorithm: anomalies.AlgorithmMad},
+				},
+			}},
+			routes.Documentation{
+				Summary:     "edit the anomalies detection algorithms",
+				Description: "Replaces the algorithms selected for the AWS account and its products. An empty product sets the algorithm of the whole AWS account.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.QueryArgs{routes.AwsAccountIdQueryArg},
+	).Register("/costs/anomalies/algorithms")
+}
+
+// getAlgorithmSelections returns the algorithms selected for an AWS account.
+func getAlgorithmSelections(tx *sql.Tx, aa aws.AwsAccount) ([]AlgorithmSelection, error) {
+	dbAlgorithms, err := models.AnomalyDetectionAlgorithmsByAwsAccountID(tx, aa.Id)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]AlgorithmSelection, len(dbAlgorithms))
+	for i, dbAlgorithm := range dbAlgorithms {
+		res[i] = AlgorithmSelection{
+			Product:   dbAlgorithm.Product,
+			Algorithm: dbAlgorithm.Algorithm,
+		}
+	}
+	return res, nil
+}
+
+// getAnomaliesAlgorithms is a route handler which returns the
+// algorithms selected for an AWS account.
+func getAnomaliesAlgorithms(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	selections, err := getAlgorithmSelections(tx, aa)
+	if err != nil {
+		l.Error("Failed to get anomalies detection algorithms", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve algorithms.")
+	}
+	return http.StatusOK, algorithmsResponse{
+		Available:  anomalies.AlgorithmNames(),
+		Default:    config.AnomalyDetectionAlgorithm,
+		Algorithms: selections,
+	}
+}
+
+// validateAlgorithmSelections checks that every algorithm exists and that
+// every product is selected at most once.
+func validateAlgorithmSelections(selections []AlgorithmSelection) error {
+	products := make(map[string]bool)
+	for _, selection := range selections {
+		if _, ok := anomalies.GetDetector(selection.Algorithm); !ok {
+			return fmt.Errorf("Unknown algorithm: %s.", selection.Algorithm)
+		} else if products[selection.Product] {
+			return fmt.Errorf("Product selected twice: %s.", selection.Product)
+		}
+		products[selection.Product] = true
+	}
+	return nil
+}
+
+// postAnomaliesAlgorithms is a route handler which replaces the
+// algorithms selected for an AWS account.
+func postAnomaliesAlgorithms(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	var body AlgorithmsBody
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	if err := validateAlgorithmSelections(body.Algorithms); err != nil {
+		return http.StatusBadRequest, err
+	}
+	dbAlgorithms, err := models.AnomalyDetectionAlgorithmsByAwsAccountID(tx, aa.Id)
+	if err != nil {
+		l.Error("Failed to get anomalies detection algorithms", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to update algorithms.")
+	}
+	for _, dbAlgorithm := range dbAlgorithms {
+		if err := dbAlgorithm.Delete(tx); err != nil {
+			l.Error("Failed to delete anomalies detection algorithm", map[string]interface{}{
+				"awsAccountId": aa.Id,
+				"error":        err.Error(),
+			})
+			return http.StatusInternalServerError, errors.New("Failed to update algorithms.")
+		}
+	}
+	for _, selection := range body.Algorithms {
+		dbAlgorithm := models.AnomalyDetectionAlgorithm{
+			AwsAccountID: aa.Id,
+			Product:      selection.Product,
+			Algorithm:    selection.Algorithm,
+		}
+		if err := dbAlgorithm.Insert(tx); err != nil {
+			l.Error("Failed to insert anomalies detection algorithm", map[string]interface{}{
+				"awsAccountId": aa.Id,
+				"error":        err.Error(),
+			})
+			return http.StatusInternalServerError, errors.New("Failed to update algorithms.")
+		}
+	}
+	return http.StatusOK, body
+}
@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -28,8 +27,8 @@ import (
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/anomaliesDetection"
+	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/cache"
-	"github.com/trackit/trackit/config"
 	"github.com/trackit/trackit/costs/anomalies/anomalyFilters"
 	"github.com/trackit/trackit/costs/anomalies/anomalyType"
 	"github.com/trackit/trackit/db"
@@ -49,6 +48,7 @@ type (
 		Product   string `json:"product"`
 		Abnormal  bool   `json:"abnormal"`
 		Recurrent bool   `json:"recurrent"`
+		Algorithm string `json:"algorithm"`
 		Cost      struct {
 			Value       float64 `json:"value"`
 			MaxExpected float64 `json:"maxExpected"`
@@ -69,6 +69,7 @@ func init() {
 			db.RequestTransaction{Db: db.Db},
 			users.RequireAuthenticatedUser{users.ViewerAsParent},
 			routes.QueryArgs(anomalyQueryArgs),
+			users.RequirePermission{users.PermissionViewCosts},
 			cache.UsersCache{},
 			routes.Documentation{
 				Summary:     "get the cost anomalies",
@@ -129,29 +130,36 @@ func getSnoozedAnomalies(userId int, tx *sql.Tx) (map[string]bool, error) {
 	}
 }
 
-// getAnomalyLevel get anomaly level depending on their cost.
-func getAnomalyLevel(typedDocument esProductAnomalyTypedResult) (int, string) {
+// getSettingsByAwsIdentity gets the anomaly settings of every AWS account
+// of the user, by AWS identity.
+func getSettingsByAwsIdentity(user users.User, tx *sql.Tx) (map[string]anomalies.Settings, error) {
+	awsAccounts, err := aws.GetAwsAccountsFromUser(user, tx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]anomalies.Settings, len(awsAccounts))
+	for _, awsAccount := range awsAccounts {
+		if res[awsAccount.AwsIdentity], err = anomalies.GetSettings(tx, awsAccount.Id); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// getAnomalyLevel get anomaly level depending on their cost and the
+// settings of their AWS account.
+func getAnomalyLevel(typedDocument esProductAnomalyTypedResult, settingsByAwsIdentity map[string]anomalies.Settings) (int, string) {
 	if !typedDocument.Abnormal {
 		return 0, ""
 	}
-	prettyLevels := strings.Split(config.AnomalyDetectionPrettyLevels, ",")
-	percent := (typedDocument.Cost.Value * 100) / typedDocument.Cost.MaxExpected
-	levels := strings.Split(config.AnomalyDetectionLevels, ",")
-	for i, level := range levels[1:] {
-		l, err := strconv.ParseFloat(level, 64)
-		if err != nil {
-			jsonlog.DefaultLogger.Error("Failed to parse one of the numbers from anomaly-detection-levels option", map[string]interface{}{
-				"error": err.Error(),
-			})
-		}
-		if percent < l {
-			return i, prettyLevels[i]
-		}
+	settings, ok := settingsByAwsIdentity[typedDocument.Account]
+	if !ok {
+		settings = anomalies.DefaultSettings()
 	}
-	return len(levels) - 1, prettyLevels[len(levels)-1]
+	return settings.Level(typedDocument.Cost.Value, typedDocument.Cost.MaxExpected)
 }
 
-func formatAnomaliesData(raw *elastic.SearchResult, snoozedAnomalies map[string]bool, ctx context.Context) (anomalyType.AnomaliesDetectionResponse, error) {
+func formatAnomaliesData(raw *elastic.SearchResult, snoozedAnomalies map[string]bool, settingsByAwsIdentity map[string]anomalies.Settings, ctx context.Context) (anomalyType.AnomaliesDetectionResponse, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	res := make(anomalyType.AnomaliesDetectionResponse)
 	for i := range raw.Hits.Hits {
@@ -167,7 +175,7 @@ func formatAnomaliesData(raw *elastic.SearchResult, snoozedAnomalies map[string]
 		if _, ok := res[typedDocument.Account][typedDocument.Product]; !ok {
 			res[typedDocument.Account][typedDocument.Product] = make([]anomalyType.ProductAnomaly, 0)
 		}
-		level, prettyLevel := getAnomalyLevel(typedDocument)
+		level, prettyLevel := getAnomalyLevel(typedDocument, settingsByAwsIdentity)
 		if date, err := time.Parse("2006-01-02T15:04:05.000Z", typedDocument.Date); err == nil {
 			res[typedDocument.Account][typedDocument.Product] = append(res[typedDocument.Account][typedDocument.Product], anomalyType.ProductAnomaly{
 				Id:          typedDocument.Id,
@@ -180,6 +188,7 @@ func formatAnomaliesData(raw *elastic.SearchResult, snoozedAnomalies map[string]
 				Snoozed:     snoozedAnomalies[typedDocument.Id],
 				Level:       level,
 				PrettyLevel: prettyLevel,
+				Algorithm:   typedDocument.Algorithm,
 			})
 		}
 	}
@@ -261,7 +270,11 @@ func getAnomaliesData(request *http.Request, a routes.Arguments) (int, interface
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
-	res, err := formatAnomaliesData(raw, snoozedAnomalies, request.Context())
+	settingsByAwsIdentity, err := getSettingsByAwsIdentity(user, tx)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	res, err := formatAnomaliesData(raw, snoozedAnomalies, settingsByAwsIdentity, request.Context())
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
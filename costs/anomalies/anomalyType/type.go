@@ -26,6 +26,7 @@ type (
 		Snoozed     bool      `json:"snoozed"`
 		Level       int       `json:"level"`
 		PrettyLevel string    `json:"pretty_level"`
+		Algorithm   string    `json:"algorithm"`
 	}
 
 	// ProductAnomalies is used to respond to the request.
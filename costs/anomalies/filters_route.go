@@ -35,6 +35,7 @@ func init() {
 				Summary:     "get the anomalies filters",
 				Description: "Responds with the anomalies filters",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 		http.MethodPost: routes.H(postAnomaliesFilters).With(
 			db.RequestTransaction{Db: db.Db},
@@ -55,6 +56,7 @@ func init() {
 				Summary:     "edit the anomalies filters",
 				Description: "Edits the anomalies filters based on the body",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
 		),
 	}.H().Register("/costs/anomalies/filters")
 }
@@ -0,0 +1,243 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package anomalies
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/anomaliesDetection"
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+type (
+	// SettingsBody is the body required by putAnomaliesSettings. It
+	// contains the settings overridden for an AWS account: a missing
+	// field falls back to its default value.
+	SettingsBody struct {
+		Levels                 *string  `json:"levels,omitempty"`
+		PrettyLevels           *string  `json:"prettyLevels,omitempty"`
+		MinPercentOfDailyBill  *float64 `json:"minPercentOfDailyBill,omitempty"`
+		MinAbsoluteCost        *float64 `json:"minAbsoluteCost,omitempty"`
+		HighestSpendingMinRank *int     `json:"highestSpendingMinRank,omitempty"`
+		RecurrenceThreshold    *float64 `json:"recurrenceThreshold,omitempty"`
+		EmailingMinLevel       *int     `json:"emailingMinLevel,omitempty"`
+	}
+
+	// settingsResponse is the response of the /costs/anomalies/settings route.
+	settingsResponse struct {
+		Defaults  anomalies.Settings `json:"defaults"`
+		Overrides SettingsBody       `json:"overrides"`
+		Settings  anomalies.Settings `json:"settings"`
+	}
+)
+
+func init() {
+	levels := "0,150,300"
+	prettyLevels := "low,high,critical"
+	minAbsoluteCost := 50.0
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getAnomaliesSettings).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			aws.RequireAwsAccountId{},
+			routes.Documentation{
+				Summary:     "get the anomalies detection settings",
+				Description: "Responds with the default anomalies detection settings, the ones overridden for the AWS account and the resulting ones",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+		http.MethodPut: routes.H(putAnomaliesSettings).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			aws.RequireAwsAccountId{},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{SettingsBody{
+				Levels:          &levels,
+				PrettyLevels:    &prettyLevels,
+				MinAbsoluteCost: &minAbsoluteCost,
+			}},
+			routes.Documentation{
+				Summary:     "edit the anomalies detection settings",
+				Description: "Replaces the anomalies detection settings overridden for the AWS account. Missing settings fall back to their default value.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+		http.MethodDelete: routes.H(deleteAnomaliesSettings).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			aws.RequireAwsAccountId{},
+			routes.Documentation{
+				Summary:     "reset the anomalies detection settings",
+				Description: "Removes the anomalies detection settings overridden for the AWS account",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.QueryArgs{routes.AwsAccountIdQueryArg},
+	).Register("/costs/anomalies/settings")
+}
+
+// toModel sets the overridden settings in an AnomalySetting.
+func (b SettingsBody) toModel(dbSettings *models.AnomalySetting) {
+	dbSettings.Levels = sql.NullString{}
+	dbSettings.PrettyLevels = sql.NullString{}
+	dbSettings.MinPercentOfDailyBill = sql.NullFloat64{}
+	dbSettings.MinAbsoluteCost = sql.NullFloat64{}
+	dbSettings.HighestSpendingMinRank = sql.NullInt64{}
+	dbSettings.RecurrenceThreshold = sql.NullFloat64{}
+	dbSettings.EmailingMinLevel = sql.NullInt64{}
+	if b.Levels != nil {
+		dbSettings.Levels = sql.NullString{String: *b.Levels, Valid: true}
+	}
+	if b.PrettyLevels != nil {
+		dbSettings.PrettyLevels = sql.NullString{String: *b.PrettyLevels, Valid: true}
+	}
+	if b.MinPercentOfDailyBill != nil {
+		dbSettings.MinPercentOfDailyBill = sql.NullFloat64{Float64: *b.MinPercentOfDailyBill, Valid: true}
+	}
+	if b.MinAbsoluteCost != nil {
+		dbSettings.MinAbsoluteCost = sql.NullFloat64{Float64: *b.MinAbsoluteCost, Valid: true}
+	}
+	if b.HighestSpendingMinRank != nil {
+		dbSettings.HighestSpendingMinRank = sql.NullInt64{Int64: int64(*b.HighestSpendingMinRank), Valid: true}
+	}
+	if b.RecurrenceThreshold != nil {
+		dbSettings.RecurrenceThreshold = sql.NullFloat64{Float64: *b.RecurrenceThreshold, Valid: true}
+	}
+	if b.EmailingMinLevel != nil {
+		dbSettings.EmailingMinLevel = sql.NullInt64{Int64: int64(*b.EmailingMinLevel), Valid: true}
+	}
+}
+
+// settingsBodyFromModel returns the settings overridden in an AnomalySetting.
+func settingsBodyFromModel(dbSettings *models.AnomalySetting) (b SettingsBody) {
+	if dbSettings.Levels.Valid {
+		b.Levels = &dbSettings.Levels.String
+	}
+	if dbSettings.PrettyLevels.Valid {
+		b.PrettyLevels = &dbSettings.PrettyLevels.String
+	}
+	if dbSettings.MinPercentOfDailyBill.Valid {
+		b.MinPercentOfDailyBill = &dbSettings.MinPercentOfDailyBill.Float64
+	}
+	if dbSettings.MinAbsoluteCost.Valid {
+		b.MinAbsoluteCost = &dbSettings.MinAbsoluteCost.Float64
+	}
+	if dbSettings.HighestSpendingMinRank.Valid {
+		rank := int(dbSettings.HighestSpendingMinRank.Int64)
+		b.HighestSpendingMinRank = &rank
+	}
+	if dbSettings.RecurrenceThreshold.Valid {
+		b.RecurrenceThreshold = &dbSettings.RecurrenceThreshold.Float64
+	}
+	if dbSettings.EmailingMinLevel.Valid {
+		level := int(dbSettings.EmailingMinLevel.Int64)
+		b.EmailingMinLevel = &level
+	}
+	return
+}
+
+// getSettingsModel returns the AnomalySetting of an AWS account, or a new
+// one if none is stored in the database.
+func getSettingsModel(tx *sql.Tx, aa aws.AwsAccount) (*models.AnomalySetting, error) {
+	dbSettings, err := models.AnomalySettingByAwsAccountID(tx, aa.Id)
+	if err == sql.ErrNoRows {
+		return &models.AnomalySetting{AwsAccountID: aa.Id}, nil
+	}
+	return dbSettings, err
+}
+
+// getSettingsResponse builds the response of the route from an AnomalySetting.
+func getSettingsResponse(dbSettings *models.AnomalySetting) settingsResponse {
+	defaults := anomalies.DefaultSettings()
+	return settingsResponse{
+		Defaults:  defaults,
+		Overrides: settingsBodyFromModel(dbSettings),
+		Settings:  defaults.Override(dbSettings),
+	}
+}
+
+// getAnomaliesSettings is a route handler which returns the
+// anomalies detection settings of an AWS account.
+func getAnomaliesSettings(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	dbSettings, err := getSettingsModel(tx, aa)
+	if err != nil {
+		l.Error("Failed to get anomalies settings", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve settings.")
+	}
+	return http.StatusOK, getSettingsResponse(dbSettings)
+}
+
+// putAnomaliesSettings is a route handler which replaces the
+// anomalies detection settings overridden for an AWS account.
+func putAnomaliesSettings(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	var body SettingsBody
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	dbSettings, err := getSettingsModel(tx, aa)
+	if err != nil {
+		l.Error("Failed to get anomalies settings", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to update settings.")
+	}
+	body.toModel(dbSettings)
+	if err := anomalies.DefaultSettings().Override(dbSettings).Validate(); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := dbSettings.Save(tx); err != nil {
+		l.Error("Failed to save anomalies settings", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to update settings.")
+	}
+	return http.StatusOK, getSettingsResponse(dbSettings)
+}
+
+// deleteAnomaliesSettings is a route handler which removes the
+// anomalies detection settings overridden for an AWS account.
+func deleteAnomaliesSettings(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	dbSettings, err := getSettingsModel(tx, aa)
+	if err == nil {
+		err = dbSettings.Delete(tx)
+	}
+	if err != nil {
+		l.Error("Failed to delete anomalies settings", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to reset settings.")
+	}
+	return http.StatusOK, getSettingsResponse(&models.AnomalySetting{AwsAccountID: aa.Id})
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/aws"
 	"github.com/trackit/trackit/cache"
 	"github.com/trackit/trackit/db"
@@ -43,6 +44,8 @@ func init() {
 				Summary:     "snooze the anomalies",
 				Description: "Snoozes one or many anomalies with their id passed in query args",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionAnomalySnooze},
 		),
 	}.H().Register("/costs/anomalies/snooze")
 	routes.MethodMuxer{
@@ -54,6 +57,8 @@ func init() {
 				Summary:     "unsnooze the anomalies",
 				Description: "Unsnoozes one or many anomalies with their id passed in query args",
 			},
+			users.RequirePermission{users.PermissionManageAccounts},
+			audit.Logged{audit.ActionAnomalyUnsnooze},
 		),
 	}.H().Register("/costs/anomalies/unsnooze")
 }
@@ -75,6 +80,7 @@ func snoozeAnomalies(request *http.Request, a routes.Arguments) (int, interface{
 			res.Anomalies = append(res.Anomalies, anomalyId)
 		}
 	}
+	audit.AddDetail(a, "anomalies", res.Anomalies)
 	if aa, err := aws.GetAwsAccountWithId(user.Id, tx); err != nil {
 		l.Error("Failed to get Aws Account", map[string]interface{}{
 			"userId": user.Id,
@@ -103,6 +109,7 @@ func unsnoozeAnomalies(request *http.Request, a routes.Arguments) (int, interfac
 			res.Anomalies = append(res.Anomalies, anomalyId)
 		}
 	}
+	audit.AddDetail(a, "anomalies", res.Anomalies)
 	if aa, err := aws.GetAwsAccountWithId(user.Id, tx); err != nil {
 		l.Error("Failed to get Aws Account", map[string]interface{}{
 			"userId": user.Id,
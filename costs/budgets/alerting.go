@@ -0,0 +1,132 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package budgets
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/mail"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/users"
+)
+
+// crossedThreshold is a threshold reached by the spend of a budget.
+type crossedThreshold struct {
+	Threshold
+	spend float64
+}
+
+// CheckBudget sends by email the thresholds of a budget crossed by its
+// actual or forecasted spend during the period containing date. Each
+// threshold is only sent once per period. The alerts are inserted in tx,
+// which must be rolled back if an error is returned.
+func CheckBudget(ctx context.Context, tx *sql.Tx, dbBudget models.Budget, date time.Time) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	budget, err := budgetFromDbBudget(dbBudget)
+	if err != nil {
+		return err
+	}
+	user, err := users.GetUserWithId(tx, dbBudget.UserID)
+	if err != nil {
+		return err
+	}
+	spend, err := getBudgetSpend(ctx, tx, user, budget, date)
+	if err != nil {
+		return err
+	}
+	toEmail, err := getThresholdsToEmail(tx, budget, spend)
+	if err != nil || len(toEmail) == 0 {
+		return err
+	}
+	// The alerts are recorded before the email is sent: a concurrent check
+	// fails on the unique key of the alerts instead of sending the email
+	// twice, and a failure to send the email rolls the alerts back so that
+	// the thresholds are sent by the next check
+	for _, threshold := range toEmail {
+		dbBudgetAlert := models.BudgetAlert{
+			BudgetID:    budget.Id,
+			PeriodBegin: spend.PeriodBegin,
+			Threshold:   threshold.Percentage,
+			Type:        threshold.Type,
+			Spend:       threshold.spend,
+			Recipient:   user.Email,
+			Date:        time.Now().UTC(),
+		}
+		if err = dbBudgetAlert.Insert(tx); err != nil {
+			return err
+		}
+	}
+	if err = mail.SendMail(user.Email, getEmailSubject(budget, toEmail), getEmailBody(budget, spend, toEmail), ctx); err != nil {
+		logger.Error("Failed to send budget email.", err.Error())
+		return err
+	}
+	logger.Info("Budget thresholds emailed", map[string]interface{}{
+		"budgetId": budget.Id,
+		"amount":   len(toEmail),
+	})
+	return nil
+}
+
+// getThresholdsToEmail returns the thresholds crossed by the spend which
+// have not been emailed yet during the period.
+func getThresholdsToEmail(tx *sql.Tx, budget Budget, spend Spend) ([]crossedThreshold, error) {
+	res := make([]crossedThreshold, 0)
+	for _, threshold := range budget.Thresholds {
+		value := spend.Actual
+		if threshold.Type == ThresholdForecast {
+			value = spend.Forecast
+		}
+		if value < budget.Amount*threshold.Percentage/100 {
+			continue
+		}
+		if _, err := models.BudgetAlertByBudgetIDPeriodBeginThresholdType(tx, budget.Id, spend.PeriodBegin, threshold.Percentage, threshold.Type); err == nil {
+			continue
+		} else if err != sql.ErrNoRows {
+			return nil, err
+		}
+		res = append(res, crossedThreshold{threshold, value})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Percentage < res[j].Percentage
+	})
+	return res, nil
+}
+
+// getEmailSubject returns the subject of the budget email.
+func getEmailSubject(budget Budget, thresholds []crossedThreshold) string {
+	highest := thresholds[len(thresholds)-1]
+	return fmt.Sprintf("Budget %s: %s spend reached %.0f%%", budget.Name, highest.Type, highest.Percentage)
+}
+
+// getEmailBody returns the body of the budget email.
+func getEmailBody(budget Budget, spend Spend, thresholds []crossedThreshold) string {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "Your %s budget %s of $%.2f for the period starting on %s has crossed the following thresholds:\n\n",
+		budget.Period, budget.Name, budget.Amount, spend.PeriodBegin.Format("2006-01-02"))
+	for _, threshold := range thresholds {
+		fmt.Fprintf(&body, "- %.0f%% of the budget ($%.2f): %s spend is $%.2f\n",
+			threshold.Percentage, budget.Amount*threshold.Percentage/100, threshold.Type, threshold.spend)
+	}
+	fmt.Fprintf(&body, "\nActual spend: $%.2f\nForecasted spend: $%.2f\n", spend.Actual, spend.Forecast)
+	body.WriteString("\nYou can see the details on https://re.trackit.io/.\n")
+	return body.String()
+}
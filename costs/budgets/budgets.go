@@ -0,0 +1,180 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package budgets lets users define cost budgets and alerts them when the
+// actual or forecasted spend of a budget crosses one of its thresholds.
+package budgets
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/costs"
+	"github.com/trackit/trackit/models"
+)
+
+const (
+	// PeriodMonthly is the period of a budget renewed each month
+	PeriodMonthly = "monthly"
+	// PeriodQuarterly is the period of a budget renewed each quarter
+	PeriodQuarterly = "quarterly"
+
+	// ThresholdActual is a threshold on the spend since the beginning of the period
+	ThresholdActual = "actual"
+	// ThresholdForecast is a threshold on the spend forecasted at the end of the period
+	ThresholdForecast = "forecast"
+)
+
+type (
+	// Scope restricts the line items counted in a budget. Empty lists do
+	// not restrict anything: a budget without accounts covers all the
+	// accounts of its user.
+	Scope struct {
+		Accounts []string `json:"accounts"`
+		Products []string `json:"products"`
+		// Tags are in the form '<TAG_KEY>=<TAG_VALUE>'
+		Tags []string `json:"tags"`
+	}
+
+	// Threshold is a percentage of the budget amount which triggers an
+	// alert once reached by the actual or the forecasted spend.
+	Threshold struct {
+		Percentage float64 `json:"percentage"`
+		Type       string  `json:"type"`
+	}
+
+	// Budget is a spending limit over a period.
+	Budget struct {
+		Id         int         `json:"id"`
+		Name       string      `json:"name"`
+		Period     string      `json:"period"`
+		Amount     float64     `json:"amount"`
+		Scope      Scope       `json:"scope"`
+		Thresholds []Threshold `json:"thresholds"`
+	}
+)
+
+// validate checks the period, amount, scope and thresholds of a budget.
+func (b Budget) validate() error {
+	if b.Name == "" {
+		return errors.New("Budget name is required")
+	} else if b.Period != PeriodMonthly && b.Period != PeriodQuarterly {
+		return fmt.Errorf("Invalid budget period : %s. Possible values are %s and %s", b.Period, PeriodMonthly, PeriodQuarterly)
+	} else if b.Amount <= 0 {
+		return errors.New("Budget amount must be positive")
+	} else if len(b.Scope.Accounts) > 0 {
+		if err := aws.ValidateAwsAccounts(b.Scope.Accounts); err != nil {
+			return err
+		}
+	}
+	if _, err := costs.ParseTagFilters(b.Scope.Tags, false); err != nil {
+		return err
+	}
+	for _, threshold := range b.Thresholds {
+		if threshold.Percentage <= 0 {
+			return errors.New("Threshold percentage must be positive")
+		} else if threshold.Type != ThresholdActual && threshold.Type != ThresholdForecast {
+			return fmt.Errorf("Invalid threshold type : %s. Possible values are %s and %s", threshold.Type, ThresholdActual, ThresholdForecast)
+		}
+	}
+	return nil
+}
+
+// filters returns the costs filters matching the products and tags of the
+// scope.
+func (s Scope) filters() ([]costs.CostsFilter, error) {
+	filters, err := costs.ParseTagFilters(s.Tags, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Products) > 0 {
+		filters = append(filters, costs.CostsFilter{Field: "productCode", Values: s.Products})
+	}
+	return filters, nil
+}
+
+// PeriodBounds returns the beginning and the end (excluded) of the budget
+// period containing date.
+func (b Budget) PeriodBounds(date time.Time) (time.Time, time.Time) {
+	date = date.UTC()
+	month := date.Month()
+	months := 1
+	if b.Period == PeriodQuarterly {
+		month -= (month - 1) % 3
+		months = 3
+	}
+	begin := time.Date(date.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	return begin, begin.AddDate(0, months, 0)
+}
+
+// budgetFromDbBudget builds a Budget from a models.Budget.
+func budgetFromDbBudget(dbBudget models.Budget) (Budget, error) {
+	budget := Budget{
+		Id:     dbBudget.ID,
+		Name:   dbBudget.Name,
+		Period: dbBudget.Period,
+		Amount: dbBudget.Amount,
+	}
+	if err := json.Unmarshal(dbBudget.Scope, &budget.Scope); err != nil {
+		return budget, err
+	}
+	err := json.Unmarshal(dbBudget.Thresholds, &budget.Thresholds)
+	return budget, err
+}
+
+// setDbBudget copies a Budget to a models.Budget.
+func setDbBudget(dbBudget *models.Budget, budget Budget) (err error) {
+	dbBudget.Name = budget.Name
+	dbBudget.Period = budget.Period
+	dbBudget.Amount = budget.Amount
+	if dbBudget.Scope, err = json.Marshal(budget.Scope); err != nil {
+		return
+	}
+	if budget.Thresholds == nil {
+		budget.Thresholds = []Threshold{}
+	}
+	dbBudget.Thresholds, err = json.Marshal(budget.Thresholds)
+	return
+}
+
+// GetBudgetsForUser retrieves the budgets of a user.
+func GetBudgetsForUser(tx *sql.Tx, userId int) ([]Budget, error) {
+	dbBudgets, err := models.BudgetByUserID(tx, userId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Budget, len(dbBudgets))
+	for i, dbBudget := range dbBudgets {
+		if res[i], err = budgetFromDbBudget(*dbBudget); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// getDbBudgetForUser retrieves a budget by its ID, ensuring it belongs to
+// the user.
+func getDbBudgetForUser(tx *sql.Tx, userId, budgetId int) (*models.Budget, error) {
+	dbBudget, err := models.BudgetByID(tx, budgetId)
+	if err != nil {
+		return nil, err
+	} else if dbBudget.UserID != userId {
+		return nil, errors.New("budget does not belong to user")
+	}
+	return dbBudget, nil
+}
@@ -0,0 +1,227 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package budgets
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+// budgetIdQueryArg allows to get the ID of a budget in the URL parameters.
+var budgetIdQueryArg = routes.QueryArg{
+	Name:        "budget",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of a budget.",
+}
+
+// budgetDateQueryArg allows to get the date of the period to check.
+var budgetDateQueryArg = routes.QueryArg{
+	Name:        "date",
+	Type:        routes.QueryArgDate{},
+	Description: "Date within the budget periods to check, defaults to today. Format is ISO8601",
+	Optional:    true,
+}
+
+// budgetExample is the example body of the budget routes.
+var budgetExample = Budget{
+	Name:   "Production",
+	Period: PeriodMonthly,
+	Amount: 10000,
+	Scope: Scope{
+		Accounts: []string{"123456789012"},
+		Products: []string{"AmazonEC2"},
+		Tags:     []string{"env=prod"},
+	},
+	Thresholds: []Threshold{
+		{Percentage: 80, Type: ThresholdActual},
+		{Percentage: 100, Type: ThresholdForecast},
+	},
+}
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getBudgets).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.Documentation{
+				Summary:     "get the budgets",
+				Description: "Responds with the budgets of the user.",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+		http.MethodPost: routes.H(postBudget).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{budgetExample},
+			routes.Documentation{
+				Summary:     "create a budget",
+				Description: "Creates a budget with a monthly or quarterly amount, a scope and alerting thresholds.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+		http.MethodPatch: routes.H(patchBudget).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.QueryArgs{budgetIdQueryArg},
+			routes.RequestBody{budgetExample},
+			routes.Documentation{
+				Summary:     "edit a budget",
+				Description: "Replaces the name, period, amount, scope and thresholds of a budget.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+		http.MethodDelete: routes.H(deleteBudget).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.QueryArgs{budgetIdQueryArg},
+			routes.Documentation{
+				Summary:     "delete a budget",
+				Description: "Deletes a budget and its alerts history.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.Documentation{
+			Summary:     "interact with the budgets",
+			Description: "A budget is a spending limit over a month or a quarter. Its users are alerted by email when the actual or forecasted spend crosses one of its thresholds.",
+		},
+	).Register("/costs/budgets")
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getBudgetsStatus).With(
+			db.RequestTransaction{Db: db.Db},
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.QueryArgs{budgetDateQueryArg},
+			routes.Documentation{
+				Summary:     "get the budgets status",
+				Description: "Responds with the budgets of the user with their actual and forecasted spend.",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+	}.H().Register("/costs/budgets/status")
+}
+
+// getBudgets is a route handler which returns the budgets of the user.
+func getBudgets(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	budgets, err := GetBudgetsForUser(tx, user.Id)
+	if err != nil {
+		l.Error("Failed to get budgets.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve budgets.")
+	}
+	return http.StatusOK, budgets
+}
+
+// getBudgetsStatus is a route handler which returns the budgets of the user
+// with their spend.
+func getBudgetsStatus(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	date := time.Now().UTC()
+	if argDate, ok := a[budgetDateQueryArg].(time.Time); ok {
+		date = argDate
+	}
+	status, err := GetBudgetsStatus(r.Context(), tx, user, date)
+	if err != nil {
+		l.Error("Failed to get budgets status.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve budgets status.")
+	}
+	return http.StatusOK, status
+}
+
+// postBudget is a route handler which creates a budget.
+func postBudget(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Budget
+	routes.MustRequestBody(a, &body)
+	if err := body.validate(); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbBudget := models.Budget{UserID: user.Id}
+	return saveBudget(r, tx, &dbBudget, body)
+}
+
+// patchBudget is a route handler which replaces a budget.
+func patchBudget(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Budget
+	routes.MustRequestBody(a, &body)
+	if err := body.validate(); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbBudget, err := getDbBudgetForUser(tx, user.Id, a[budgetIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Budget not found.")
+	}
+	return saveBudget(r, tx, dbBudget, body)
+}
+
+// saveBudget saves a valid budget in the database.
+func saveBudget(r *http.Request, tx *sql.Tx, dbBudget *models.Budget, budget Budget) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	err := setDbBudget(dbBudget, budget)
+	if err == nil {
+		err = dbBudget.Save(tx)
+	}
+	if err == nil {
+		budget, err = budgetFromDbBudget(*dbBudget)
+	}
+	if err != nil {
+		l.Error("Failed to save budget.", map[string]interface{}{
+			"budget": budget,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save budget.")
+	}
+	return http.StatusOK, budget
+}
+
+// deleteBudget is a route handler which deletes a budget.
+func deleteBudget(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbBudget, err := getDbBudgetForUser(tx, user.Id, a[budgetIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Budget not found.")
+	}
+	if err := dbBudget.Delete(tx); err != nil {
+		l.Error("Failed to delete budget.", map[string]interface{}{
+			"budgetId": dbBudget.ID,
+			"error":    err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to delete budget.")
+	}
+	return http.StatusOK, nil
+}
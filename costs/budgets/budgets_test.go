@@ -0,0 +1,60 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package budgets
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	date := time.Date(2021, time.August, 17, 13, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		period string
+		begin  time.Time
+		end    time.Time
+	}{
+		{PeriodMonthly, time.Date(2021, time.August, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, time.September, 1, 0, 0, 0, 0, time.UTC)},
+		{PeriodQuarterly, time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		begin, end := Budget{Period: c.period}.PeriodBounds(date)
+		if !begin.Equal(c.begin) || !end.Equal(c.end) {
+			t.Errorf("%s period should be [%s, %s), is [%s, %s) instead.", c.period, c.begin, c.end, begin, end)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Budget{
+		Name:       "Production",
+		Period:     PeriodQuarterly,
+		Amount:     1000,
+		Scope:      Scope{Tags: []string{"env=prod"}},
+		Thresholds: []Threshold{{Percentage: 90, Type: ThresholdForecast}},
+	}
+	if err := valid.validate(); err != nil {
+		t.Errorf("Budget should be valid. Failed with %s.", err.Error())
+	}
+	invalid := []Budget{valid, valid, valid, valid}
+	invalid[0].Period = "yearly"
+	invalid[1].Amount = 0
+	invalid[2].Scope = Scope{Tags: []string{"env"}}
+	invalid[3].Thresholds = []Threshold{{Percentage: 90, Type: "average"}}
+	for i, budget := range invalid {
+		if err := budget.validate(); err == nil {
+			t.Errorf("Budget %d should be invalid.", i)
+		}
+	}
+}
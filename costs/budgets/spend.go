@@ -0,0 +1,107 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package budgets
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trackit/trackit/aws/s3"
+	"github.com/trackit/trackit/costs"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/tagging/aliases"
+	"github.com/trackit/trackit/users"
+)
+
+// Spend is the spend of a budget over its current period.
+type Spend struct {
+	PeriodBegin time.Time `json:"periodBegin"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Actual      float64   `json:"actual"`
+	Forecast    float64   `json:"forecast"`
+}
+
+// BudgetStatus is a budget with its spend.
+type BudgetStatus struct {
+	Budget
+	Spend Spend `json:"spend"`
+}
+
+// getBudgetSpend computes the actual spend of a budget since the beginning
+// of the period containing date, and forecasts the spend at the end of the
+// period by extending the daily average of the days already billed.
+func getBudgetSpend(ctx context.Context, tx *sql.Tx, user users.User, budget Budget, date time.Time) (Spend, error) {
+	begin, end := budget.PeriodBounds(date)
+	spend := Spend{PeriodBegin: begin, PeriodEnd: end}
+	accountsAndIndexes, _, err := es.GetAccountsAndIndexes(budget.Scope.Accounts, user, tx, s3.IndexPrefixLineItem)
+	if err != nil {
+		return spend, err
+	}
+	filters, err := budget.Scope.filters()
+	if err != nil {
+		return spend, err
+	}
+	resolver, err := aliases.GetResolverForUser(tx, user.Id)
+	if err != nil {
+		return spend, err
+	}
+	params := costs.EsQueryParams{
+		DateBegin:         begin,
+		DateEnd:           end.Add(-time.Nanosecond),
+		AccountList:       accountsAndIndexes.Accounts,
+		IndexList:         accountsAndIndexes.Indexes,
+		AggregationParams: []string{"day"},
+		Filters:           filters,
+		Aliases:           resolver,
+	}
+	// A missing index is not an error: the accounts have not been billed yet
+	res, status, err := costs.MakeElasticSearchRequestAndParseIt(ctx, params)
+	if err != nil && status != http.StatusOK {
+		return spend, err
+	}
+	var lastBilledDay time.Time
+	for _, day := range res.Children {
+		spend.Actual += day.Value
+		if dayDate, err := time.Parse("2006-01-02", strings.Split(day.Key, "T")[0]); err == nil && day.Value != 0 && dayDate.After(lastBilledDay) {
+			lastBilledDay = dayDate
+		}
+	}
+	if !lastBilledDay.IsZero() {
+		billedDays := lastBilledDay.Sub(begin).Hours()/24 + 1
+		periodDays := end.Sub(begin).Hours() / 24
+		spend.Forecast = spend.Actual / billedDays * periodDays
+	}
+	return spend, nil
+}
+
+// GetBudgetsStatus returns the budgets of a user with their spend over the
+// period containing date.
+func GetBudgetsStatus(ctx context.Context, tx *sql.Tx, user users.User, date time.Time) ([]BudgetStatus, error) {
+	budgets, err := GetBudgetsForUser(tx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]BudgetStatus, len(budgets))
+	for i, budget := range budgets {
+		res[i].Budget = budget
+		if res[i].Spend, err = getBudgetSpend(ctx, tx, user, budget, date); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/trackit/trackit/errors"
 	"github.com/trackit/trackit/es"
 	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/tagging/aliases"
 	"github.com/trackit/trackit/users"
 )
 
@@ -55,6 +56,9 @@ type EsQueryParams struct {
 	AccountList       []string
 	IndexList         []string
 	AggregationParams []string
+	Filters           []CostsFilter
+	Metric            string
+	Aliases           aliases.Resolver
 }
 
 // costQueryArgs allows to get required queryArgs params
@@ -64,7 +68,7 @@ var costsQueryArgs = []routes.QueryArg{
 	routes.DateEndQueryArg,
 	{
 		Name:        "by",
-		Description: "Criteria for the ES aggregation, comma separated. Possible values are year, month, week, day, account, product, region, tag(soon)",
+		Description: "Criteria for the ES aggregation, comma separated. Possible values are year, month, week, day, account, product, region, availabilityzone, tag:<TAG_KEY>",
 		Type:        routes.QueryArgStringSlice{},
 		Optional:    false,
 	},
@@ -75,7 +79,9 @@ func init() {
 		http.MethodGet: routes.H(getCostData).With(
 			db.RequestTransaction{Db: db.Db},
 			users.RequireAuthenticatedUser{users.ViewerAsParent},
-			routes.QueryArgs(costsQueryArgs),
+			routes.QueryArgs(append(costsQueryArgs, costsFiltersQueryArgs()...)),
+			routes.QueryArgs{routes.CostMetricQueryArg},
+			users.RequirePermission{users.PermissionViewCosts},
 			cache.UsersCache{},
 			routes.Documentation{
 				Summary:     "get the costs data",
@@ -88,14 +94,12 @@ func init() {
 // validateCriteraParam will validate the different criterions.
 // It validate the criterion by checking its presence in the simpleCriterionMap
 // or, in the case of the special criterion tag, will check if it is in the
-// correct format : 'tag:*' (with no more than one ':')
-// Right now the tags are not enabled and will generate an error if they are
-// used because they are not yet implemented in the new ElasticSearch mapping
+// correct format : 'tag:<TAG_KEY>' with a non empty key
 func validateCriteriaParam(parsedParams EsQueryParams) error {
 	for _, criterion := range parsedParams.AggregationParams {
 		if !simpleCriterionMap[criterion] {
-			if len(criterion) >= 5 && criterion[:4] == "tag:" && strings.Count(criterion, ":") == 1 {
-				return fmt.Errorf("tags not yet implemented")
+			if strings.HasPrefix(criterion, "tag:") && len(criterion) > len("tag:") {
+				continue
 			}
 			return fmt.Errorf("Error parsing criterion : %s", criterion)
 		}
@@ -112,11 +116,24 @@ func validateCriteriaParam(parsedParams EsQueryParams) error {
 func MakeElasticSearchRequestAndParseIt(ctx context.Context, parsedParams EsQueryParams) (es.SimplifiedCostsDocument, int, error) {
 	l := jsonlog.LoggerFromContextOrDefault(ctx)
 	index := strings.Join(parsedParams.IndexList, ",")
+	metricField, err := s3.CostMetricField(parsedParams.Metric)
+	if err != nil {
+		return es.SimplifiedCostsDocument{}, http.StatusBadRequest, err
+	}
+	if err = s3.CheckCostMetricIngested(ctx, es.Client, index, parsedParams.AccountList, parsedParams.DateBegin, parsedParams.DateEnd, parsedParams.Metric); err == s3.ErrCostMetricNotIngested {
+		return es.SimplifiedCostsDocument{}, http.StatusBadRequest, err
+	} else if err != nil {
+		l.Error("Failed to check the cost metric of line items", map[string]interface{}{"error": err.Error()})
+		return es.SimplifiedCostsDocument{}, http.StatusInternalServerError, errors.GetErrorMessage(ctx, err)
+	}
 	searchService := GetElasticSearchParams(
 		parsedParams.AccountList,
 		parsedParams.DateBegin,
 		parsedParams.DateEnd,
 		parsedParams.AggregationParams,
+		parsedParams.Filters,
+		parsedParams.Aliases,
+		metricField,
 		es.Client,
 		index,
 	)
@@ -138,6 +155,10 @@ func MakeElasticSearchRequestAndParseIt(ctx context.Context, parsedParams EsQuer
 		}
 		return es.SimplifiedCostsDocument{}, http.StatusInternalServerError, errors.GetErrorMessage(ctx, err)
 	}
+	if err = normalizeTagAggregations(res); err != nil {
+		l.Error("Error normalizing tag aggregations : "+err.Error(), nil)
+		return es.SimplifiedCostsDocument{}, http.StatusInternalServerError, fmt.Errorf("could not parse ElasticSearch response")
+	}
 	simplifiedCostDocument, err := es.SimplifyCostsDocument(ctx, res)
 	if err != nil {
 		l.Error("Error parsing cost response : "+err.Error(), nil)
@@ -161,6 +182,17 @@ func getCostData(request *http.Request, a routes.Arguments) (int, interface{}) {
 	if err := validateCriteriaParam(parsedParams); err != nil {
 		return http.StatusBadRequest, err
 	}
+	filters, err := getCostsFilters(a)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	parsedParams.Filters = filters
+	if a[routes.CostMetricQueryArg] != nil {
+		parsedParams.Metric = a[routes.CostMetricQueryArg].(string)
+	}
+	if _, err := s3.CostMetricField(parsedParams.Metric); err != nil {
+		return http.StatusBadRequest, err
+	}
 	tx := a[db.Transaction].(*sql.Tx)
 	accountsAndIndexes, returnCode, err := es.GetAccountsAndIndexes(parsedParams.AccountList, user, tx, s3.IndexPrefixLineItem)
 	if err != nil {
@@ -168,6 +200,9 @@ func getCostData(request *http.Request, a routes.Arguments) (int, interface{}) {
 	}
 	parsedParams.AccountList = accountsAndIndexes.Accounts
 	parsedParams.IndexList = accountsAndIndexes.Indexes
+	if parsedParams.Aliases, err = aliases.GetResolverForUser(tx, user.Id); err != nil {
+		return http.StatusInternalServerError, errors.GetErrorMessage(request.Context(), err)
+	}
 	simplifiedCostDocument, returnCode, err := MakeElasticSearchRequestAndParseIt(request.Context(), parsedParams)
 	if err != nil {
 		if returnCode == http.StatusOK {
@@ -56,6 +56,7 @@ type esQueryParams struct {
 	accountList       []string
 	indexList         []string
 	aggregationPeriod string
+	metric            string
 }
 
 // diffQueryArgs allows to get required queryArgs params
@@ -77,6 +78,8 @@ func init() {
 			db.RequestTransaction{Db: db.Db},
 			users.RequireAuthenticatedUser{users.ViewerAsParent},
 			routes.QueryArgs(diffQueryArgs),
+			routes.QueryArgs{routes.CostMetricQueryArg},
+			users.RequirePermission{users.PermissionViewCosts},
 			cache.UsersCache{},
 			routes.Documentation{
 				Summary:     "get the cost diff",
@@ -95,11 +98,22 @@ func init() {
 func makeElasticSearchRequest(ctx context.Context, parsedParams esQueryParams) (*elastic.SearchResult, int, error) {
 	l := jsonlog.LoggerFromContextOrDefault(ctx)
 	index := strings.Join(parsedParams.indexList, ",")
+	metricField, err := s3.CostMetricField(parsedParams.metric)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if err = s3.CheckCostMetricIngested(ctx, es.Client, index, parsedParams.accountList, parsedParams.dateBegin, parsedParams.dateEnd, parsedParams.metric); err == s3.ErrCostMetricNotIngested {
+		return nil, http.StatusBadRequest, err
+	} else if err != nil {
+		l.Error("Failed to check the cost metric of line items", map[string]interface{}{"error": err.Error()})
+		return nil, http.StatusInternalServerError, errors.GetErrorMessage(ctx, err)
+	}
 	searchService := GetElasticSearchParams(
 		parsedParams.accountList,
 		parsedParams.dateBegin,
 		parsedParams.dateEnd,
 		parsedParams.aggregationPeriod,
+		metricField,
 		es.Client,
 		index,
 	)
@@ -151,12 +165,14 @@ func convertDiffData(ctx context.Context, diffData interface{}) (costDiff, error
 }
 
 // TaskDiffData prepares an elasticsearch query and retrieves cost differentiator data
-func TaskDiffData(ctx context.Context, aa aws.AwsAccount, dateRange DateRange, aggregationPeriod string) (data costDiff, err error) {
+// computed with the given cost metric
+func TaskDiffData(ctx context.Context, aa aws.AwsAccount, dateRange DateRange, aggregationPeriod string, metric string) (data costDiff, err error) {
 	parsedParams := esQueryParams{
 		accountList:       []string{aa.AwsIdentity},
 		dateBegin:         dateRange.Begin,
 		dateEnd:           dateRange.End,
 		aggregationPeriod: aggregationPeriod,
+		metric:            metric,
 	}
 	var tx *sql.Tx
 	if tx, err = db.Db.BeginTx(ctx, nil); err != nil {
@@ -190,6 +206,12 @@ func prepareGetDiffData(request *http.Request, a routes.Arguments) (int, interfa
 	if _, ok := validAggregationPeriodMap[parsedParams.aggregationPeriod]; !ok {
 		return http.StatusBadRequest, fmt.Errorf("invalid aggregation period : %s", parsedParams.aggregationPeriod)
 	}
+	if a[routes.CostMetricQueryArg] != nil {
+		parsedParams.metric = a[routes.CostMetricQueryArg].(string)
+	}
+	if _, err := s3.CostMetricField(parsedParams.metric); err != nil {
+		return http.StatusBadRequest, err
+	}
 	tx := a[db.Transaction].(*sql.Tx)
 	accountsAndIndexes, returnCode, err := es.GetAccountsAndIndexes(parsedParams.accountList, user, tx, s3.IndexPrefixLineItem)
 	if err != nil {
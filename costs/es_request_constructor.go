@@ -16,11 +16,12 @@
 package costs
 
 import (
-	"fmt"
 	"strings"
 	"time"
 
 	"github.com/olivere/elastic"
+
+	"github.com/trackit/trackit/tagging/aliases"
 )
 
 // aggregationBuilder is an alias for the function type that is used in the
@@ -170,33 +171,27 @@ func createAggregationPerYear(_ []string) []paramAggrAndName {
 	}
 }
 
-// createAggregationPerTag creates and returns a new []paramAggrAndName of size 2 which consists
-// of two aggregations that are required for the tag param.
-// The first aggregation is a FilterAggregation on the field 'tag.key', and with a value of
-// the tag key passed in the parameter 'paramSplit' in the form "user:<TAG_KEY_VALUE>".
-// The second aggregation is a TermsAggregation that creates bucket aggregation on the field
-// 'tag.value'.
-// No SubAggregation is created in this function, as it needs to be created in the nestAggregation function
+// createAggregationPerTag creates and returns a new []paramAggrAndName of size 1 which creates a
+// bucket aggregation on the values of the tag key passed in the parameter 'paramSplit' in the
+// form ["tag", "<TAG_KEY>"]. Line items without this tag key are put in an "untagged" bucket.
+// The SubAggregation is created in the nestAggregation function, and the response has to be
+// normalized with normalizeTagAggregations before being simplified.
 func createAggregationPerTag(paramSplit []string) []paramAggrAndName {
 	return []paramAggrAndName{
 		{
-			name: "by-tag_key",
-			aggr: elastic.NewFilterAggregation().
-				Filter(elastic.NewTermQuery("tag.key", fmt.Sprintf("user:%v", paramSplit[1])))},
-		{
-			name: "tag_value",
-			aggr: elastic.NewTermsAggregation().
-				Field("tag.value").Size(aggregationMaxSize)},
+			name: tagAggregationName,
+			aggr: &tagAggregation{key: paramSplit[1]},
+		},
 	}
 }
 
 // createCostSumAggregation : Creates and return a new []paramAggrAndName of size 1, which creates a
-// SumAggregation on the field 'cost'
-func createCostSumAggregation(_ []string) []paramAggrAndName {
+// SumAggregation on the cost field passed in the parameter 'paramSplit' in the form ["cost", "<FIELD>"]
+func createCostSumAggregation(paramSplit []string) []paramAggrAndName {
 	return []paramAggrAndName{
 		{
 			name: "value",
-			aggr: elastic.NewSumAggregation().Field("unblendedCost"),
+			aggr: elastic.NewSumAggregation().Field(paramSplit[1]),
 		},
 	}
 }
@@ -213,8 +208,8 @@ func reverseAggregationArray(aggregationArray []paramAggrAndName) []paramAggrAnd
 // nestAggregation takes a slice of paramAggrAndName type, and will nest the different aggregations.
 // Aggregations are nested by creating a chain of SubAggregation
 // A type switch is required to simulate downcasting from the interface elastic.Aggregation.
-// Current types on the type switch are TermsAggregation, FilterAggregation, SumAggregation and
-// DateHistogramAggregation.
+// Current types on the type switch are TermsAggregation, FilterAggregation, SumAggregation,
+// DateHistogramAggregation and tagAggregation.
 // If a new function creating a type that is not listed here is added to the paramNameToFuncPtr map
 // it should be added to the type switch, or the function will create bugged SubAggregations
 func nestAggregation(allAggrSlice []paramAggrAndName) elastic.Aggregation {
@@ -231,6 +226,9 @@ func nestAggregation(allAggrSlice []paramAggrAndName) elastic.Aggregation {
 		case *elastic.DateHistogramAggregation:
 			aggrBuff := assertedBaseAggr.SubAggregation(aggrToNest.name, aggrToNest.aggr)
 			aggrToNest = paramAggrAndName{name: baseAggr.name, aggr: aggrBuff}
+		case *tagAggregation:
+			aggrBuff := assertedBaseAggr.SubAggregation(aggrToNest.name, aggrToNest.aggr)
+			aggrToNest = paramAggrAndName{name: baseAggr.name, aggr: aggrBuff}
 		}
 	}
 	return aggrToNest.aggr
@@ -249,11 +247,17 @@ func nestAggregation(allAggrSlice []paramAggrAndName) elastic.Aggregation {
 //		- "availabilityzone" : It will create a TermsAggregation on the field 'availability_zone'
 //		- "region" : It will create a TermsAggregation on the field 'region'
 //		- "account" : It will create a TermsAggregation on the field 'linked_account_id'
-//		- "tag:<TAG_KEY>" : It will create a FilterAggregation on the field 'tag.key',
-//		filtering on the value 'user:<TAG_KEY>'.
-//		It will then create a TermsAggregation on the field 'tag.value'
+//		- "tag:<TAG_KEY>" : It will create a bucket aggregation on the field 'tags.tag' of the nested
+//		tags whose 'tags.key' is <TAG_KEY>, with an "untagged" bucket for the line items without it
 //		- "[day|week|month|year]": It will create a DateHistogramAggregation on the specified duration on
 //		the field 'usage_start_date'
+//	- filters []CostsFilter : The filters restricting the line items. Included values are added
+//	to the query as filter clauses and excluded ones as must_not clauses.
+//	- resolver aliases.Resolver : The tag key aliases of the user. The tag keys of the 'tag:<TAG_KEY>'
+//	param and of the filters also match their aliases, and the tag values are resolved to their
+//	canonical value
+//	- metricField string : The line item field summed to compute the costs, as returned by
+//	s3.CostMetricField
 //	- client *elastic.Client : an instance of *elastic.Client that represent an Elastic Search client.
 //	It needs to be fully configured and ready to execute a client.Search()
 //	- index string : The Elastic Search index on which to execute the query. In this context the default value
@@ -268,19 +272,25 @@ func nestAggregation(allAggrSlice []paramAggrAndName) elastic.Aggregation {
 // We are excluding AWSDataTransfer products because it's value is always zero.
 // Data transfer costs are included in other products' costs.
 func GetElasticSearchParams(accountList []string, durationBegin time.Time,
-	durationEnd time.Time, params []string, client *elastic.Client, index string) *elastic.SearchService {
+	durationEnd time.Time, params []string, filters []CostsFilter, resolver aliases.Resolver, metricField string, client *elastic.Client, index string) *elastic.SearchService {
 	query := elastic.NewBoolQuery()
 	if len(accountList) > 0 {
 		query = query.Filter(createQueryAccountFilter(accountList))
 	}
 	query = query.Filter(createQueryTimeRange(durationBegin, durationEnd),
 		elastic.NewBoolQuery().MustNot(elastic.NewTermQuery("productCode", "AWSDataTransfer")))
+	query = applyQueryFilters(query, filters, resolver)
 	search := client.Search().Index(index).Size(0).Query(query)
-	params = append(params, "cost")
+	params = append(params, "cost:"+metricField)
 	var allAggregationSlice []paramAggrAndName
 	for _, paramName := range params {
-		paramNameSplit := strings.Split(paramName, ":")
+		paramNameSplit := strings.SplitN(paramName, ":", 2)
 		paramAggr := paramNameToFuncPtr[paramNameSplit[0]](paramNameSplit)
+		for _, aggr := range paramAggr {
+			if tagAggr, ok := aggr.aggr.(*tagAggregation); ok {
+				tagAggr.aliases = resolver
+			}
+		}
 		allAggregationSlice = append(allAggregationSlice, paramAggr...)
 	}
 	aggregationParamName := allAggregationSlice[0].name
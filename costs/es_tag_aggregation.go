@@ -0,0 +1,169 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package costs
+
+import (
+	"encoding/json"
+
+	"github.com/olivere/elastic"
+
+	"github.com/trackit/trackit/tagging/aliases"
+)
+
+const (
+	// tagAggregationName is the name of the aggregation created for the
+	// 'tag:<TAG_KEY>' param.
+	tagAggregationName = "by-tag"
+	// untaggedBucketKey is the key of the bucket containing the costs of
+	// the line items without a value for the requested tag key.
+	untaggedBucketKey = "untagged"
+
+	tagTaggedName   = "tagged"
+	tagUntaggedName = "untagged"
+	tagKeyName      = "key"
+	tagValuesName   = "values"
+	tagReverseName  = "rev"
+	docCountKey     = "doc_count"
+)
+
+// tagAggregation is the elastic.Aggregation created for the 'tag:<TAG_KEY>'
+// param. Line items tags are nested documents, so the costs of a tag value
+// are aggregated in a nested aggregation, and the costs of the line items
+// without the tag key are aggregated in a sibling filter aggregation. Both
+// are merged back into a single bucket aggregation by
+// normalizeTagAggregations. The tag key also matches its aliases, and the
+// tag values are resolved to their canonical value.
+type tagAggregation struct {
+	key       string
+	aliases   aliases.Resolver
+	childName string
+	child     elastic.Aggregation
+}
+
+// SubAggregation sets the aggregation nested in every tag value bucket.
+func (a *tagAggregation) SubAggregation(name string, subAggregation elastic.Aggregation) *tagAggregation {
+	a.childName = name
+	a.child = subAggregation
+	return a
+}
+
+// Source returns the JSON-serializable data of the aggregation. Tags with an
+// empty value, which the bills contain for the resources without them, count
+// as missing.
+func (a *tagAggregation) Source() (interface{}, error) {
+	keyQuery := elastic.NewBoolQuery().
+		Must(a.aliases.KeysQuery("tags.key", a.key)).
+		MustNot(elastic.NewTermQuery("tags.tag", ""))
+	values := elastic.NewTermsAggregation().Size(aggregationMaxSize)
+	if script := a.aliases.ValueScript("tags.key", "tags.tag"); script != nil {
+		values = values.Script(script)
+	} else {
+		values = values.Field("tags.tag")
+	}
+	untagged := elastic.NewFilterAggregation().
+		Filter(elastic.NewBoolQuery().MustNot(elastic.NewNestedQuery("tags", keyQuery)))
+	if a.child != nil {
+		values = values.SubAggregation(tagReverseName, elastic.NewReverseNestedAggregation().SubAggregation(a.childName, a.child))
+		untagged = untagged.SubAggregation(a.childName, a.child)
+	}
+	tagged := elastic.NewNestedAggregation().Path("tags").SubAggregation(tagKeyName,
+		elastic.NewFilterAggregation().Filter(keyQuery).SubAggregation(tagValuesName, values))
+	return elastic.NewFilterAggregation().Filter(elastic.NewMatchAllQuery()).
+		SubAggregation(tagTaggedName, tagged).
+		SubAggregation(tagUntaggedName, untagged).
+		Source()
+}
+
+// normalizeTagAggregations rewrites the tag aggregations of an ElasticSearch
+// response into regular bucket aggregations, so that the response can be
+// simplified by es.SimplifyCostsDocument. The line items without the tag
+// key are put in the "untagged" bucket.
+func normalizeTagAggregations(sr *elastic.SearchResult) error {
+	for name, raw := range sr.Aggregations {
+		if raw == nil {
+			continue
+		}
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(*raw, &parsed); err != nil {
+			return err
+		}
+		doc := map[string]interface{}{name: parsed}
+		normalizeTagAggregationsRec(doc)
+		normalized, err := json.Marshal(doc[name])
+		if err != nil {
+			return err
+		}
+		rm := json.RawMessage(normalized)
+		sr.Aggregations[name] = &rm
+	}
+	return nil
+}
+
+// normalizeTagAggregationsRec recursively rewrites the tag aggregations of
+// a bucket.
+func normalizeTagAggregationsRec(doc map[string]interface{}) {
+	for k, v := range doc {
+		agg, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if k == tagAggregationName {
+			doc[k] = map[string]interface{}{
+				"buckets": getTagBuckets(agg),
+			}
+		} else if buckets, ok := agg["buckets"].([]interface{}); ok {
+			for _, b := range buckets {
+				if bucket, ok := b.(map[string]interface{}); ok {
+					normalizeTagAggregationsRec(bucket)
+				}
+			}
+		}
+	}
+}
+
+// getTagBuckets returns the buckets of a tag aggregation: one per tag value,
+// and the untagged one if some line items do not have the tag key.
+func getTagBuckets(agg map[string]interface{}) []interface{} {
+	buckets := make([]interface{}, 0)
+	tagged, _ := agg[tagTaggedName].(map[string]interface{})
+	key, _ := tagged[tagKeyName].(map[string]interface{})
+	values, _ := key[tagValuesName].(map[string]interface{})
+	valueBuckets, _ := values["buckets"].([]interface{})
+	for _, b := range valueBuckets {
+		if valueBucket, ok := b.(map[string]interface{}); ok {
+			rev, _ := valueBucket[tagReverseName].(map[string]interface{})
+			buckets = append(buckets, newTagBucket(valueBucket["key"], rev))
+		}
+	}
+	if untagged, ok := agg[tagUntaggedName].(map[string]interface{}); ok {
+		if count, _ := untagged[docCountKey].(float64); count > 0 {
+			buckets = append(buckets, newTagBucket(untaggedBucketKey, untagged))
+		}
+	}
+	return buckets
+}
+
+// newTagBucket creates a bucket with the given key and the aggregations of
+// a single bucket aggregation.
+func newTagBucket(key interface{}, aggs map[string]interface{}) map[string]interface{} {
+	bucket := map[string]interface{}{"key": key}
+	for k, v := range aggs {
+		if k != docCountKey {
+			bucket[k] = v
+		}
+	}
+	normalizeTagAggregationsRec(bucket)
+	return bucket
+}
@@ -0,0 +1,92 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package costs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// emptyTagValueQuery is the query excluding tags with an empty value.
+const emptyTagValueQuery = `{"term":{"tags.tag":""}}`
+
+func TestTagAggregationTreatsEmptyValuesAsUntagged(t *testing.T) {
+	src, err := (&tagAggregation{key: "Project"}).Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var aggregation struct {
+		Aggregations struct {
+			Tagged struct {
+				Aggregations struct {
+					Key struct {
+						Filter struct {
+							Bool struct {
+								MustNot json.RawMessage `json:"must_not"`
+							} `json:"bool"`
+						} `json:"filter"`
+					} `json:"key"`
+				} `json:"aggregations"`
+			} `json:"tagged"`
+			Untagged struct {
+				Filter struct {
+					Bool struct {
+						MustNot struct {
+							Nested struct {
+								Query struct {
+									Bool struct {
+										MustNot json.RawMessage `json:"must_not"`
+									} `json:"bool"`
+								} `json:"query"`
+							} `json:"nested"`
+						} `json:"must_not"`
+					} `json:"bool"`
+				} `json:"filter"`
+			} `json:"untagged"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(raw, &aggregation); err != nil {
+		t.Fatal(err)
+	}
+	if tagged := string(aggregation.Aggregations.Tagged.Aggregations.Key.Filter.Bool.MustNot); tagged != emptyTagValueQuery {
+		t.Errorf("Tagged line items should exclude empty values with %s, got %s in %s.", emptyTagValueQuery, tagged, raw)
+	}
+	if untagged := string(aggregation.Aggregations.Untagged.Filter.Bool.MustNot.Nested.Query.Bool.MustNot); untagged != emptyTagValueQuery {
+		t.Errorf("Untagged line items should include empty values with %s, got %s in %s.", emptyTagValueQuery, untagged, raw)
+	}
+}
+
+func TestGetTagBucketsPutsMissingValuesInUntagged(t *testing.T) {
+	var agg map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"tagged": {"key": {"values": {"buckets": [{"key": "website", "rev": {"doc_count": 2}}]}}},
+		"untagged": {"doc_count": 3}
+	}`), &agg); err != nil {
+		t.Fatal(err)
+	}
+	buckets := getTagBuckets(agg)
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %v.", buckets)
+	}
+	for i, expected := range []string{"website", untaggedBucketKey} {
+		if key := buckets[i].(map[string]interface{})["key"]; key != expected {
+			t.Errorf("Bucket %d should have key %q, has %q.", i, expected, key)
+		}
+	}
+}
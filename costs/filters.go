@@ -0,0 +1,171 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package costs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/olivere/elastic"
+
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/tagging/aliases"
+)
+
+// CostsFilter restricts the line items to the ones whose field is one of the
+// values, or to the ones whose field is none of them if Exclude is set.
+// A field in the form 'tag:<TAG_KEY>' filters on the values of a tag.
+type CostsFilter struct {
+	Field   string
+	Values  []string
+	Exclude bool
+}
+
+// costsFilterField is a line item field which can be filtered with an
+// include and an exclude query arg.
+type costsFilterField struct {
+	field   string
+	include routes.QueryArg
+	exclude routes.QueryArg
+}
+
+// newCostsFilterField creates the include and exclude query args of a field.
+func newCostsFilterField(name, field, description string) costsFilterField {
+	return costsFilterField{
+		field: field,
+		include: routes.QueryArg{
+			Name:        name,
+			Description: fmt.Sprintf("Comma separated %s to include.", description),
+			Type:        routes.QueryArgStringSlice{},
+			Optional:    true,
+		},
+		exclude: routes.QueryArg{
+			Name:        "exclude-" + name,
+			Description: fmt.Sprintf("Comma separated %s to exclude.", description),
+			Type:        routes.QueryArgStringSlice{},
+			Optional:    true,
+		},
+	}
+}
+
+// tagFilterField is the field used for the tags filters. Their values are
+// in the form '<TAG_KEY>=<TAG_VALUE>'.
+const tagFilterField = "tags"
+
+// costsFilterFields are the fields which can be filtered in the line items.
+var costsFilterFields = []costsFilterField{
+	newCostsFilterField("products", "productCode", "products"),
+	newCostsFilterField("regions", "region", "regions"),
+	newCostsFilterField("availabilityzones", "availabilityZone", "availability zones"),
+	newCostsFilterField("usagetypes", "usageType", "usage types"),
+	newCostsFilterField("lineitemtypes", "lineItemType", "line item types"),
+	newCostsFilterField("tags", tagFilterField, "tags in the form key=value"),
+}
+
+// costsFiltersQueryArgs returns the include and exclude query args of every
+// field in costsFilterFields.
+func costsFiltersQueryArgs() []routes.QueryArg {
+	queryArgs := make([]routes.QueryArg, 0, len(costsFilterFields)*2)
+	for _, f := range costsFilterFields {
+		queryArgs = append(queryArgs, f.include, f.exclude)
+	}
+	return queryArgs
+}
+
+// getCostsFilters returns the filters passed in the query args.
+func getCostsFilters(a routes.Arguments) ([]CostsFilter, error) {
+	var filters []CostsFilter
+	for _, f := range costsFilterFields {
+		for _, arg := range []routes.QueryArg{f.include, f.exclude} {
+			values, ok := a[arg].([]string)
+			if !ok || len(values) == 0 {
+				continue
+			}
+			exclude := arg.Name == f.exclude.Name
+			if f.field != tagFilterField {
+				filters = append(filters, CostsFilter{f.field, values, exclude})
+			} else if tagFilters, err := ParseTagFilters(values, exclude); err != nil {
+				return nil, err
+			} else {
+				filters = append(filters, tagFilters...)
+			}
+		}
+	}
+	return filters, nil
+}
+
+// ParseTagFilters parses values in the form '<TAG_KEY>=<TAG_VALUE>' into one
+// filter per tag key.
+func ParseTagFilters(values []string, exclude bool) ([]CostsFilter, error) {
+	valuesByKey := make(map[string][]string)
+	for _, value := range values {
+		keyValue := strings.SplitN(value, "=", 2)
+		if len(keyValue) != 2 || keyValue[0] == "" {
+			return nil, fmt.Errorf("Error parsing tag filter : %s", value)
+		}
+		valuesByKey[keyValue[0]] = append(valuesByKey[keyValue[0]], keyValue[1])
+	}
+	keys := make([]string, 0, len(valuesByKey))
+	for key := range valuesByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	filters := make([]CostsFilter, len(keys))
+	for i, key := range keys {
+		filters[i] = CostsFilter{"tag:" + key, valuesByKey[key], exclude}
+	}
+	return filters, nil
+}
+
+// createQueryFilter creates and returns the elastic.Query matching the line
+// items selected by a filter, ignoring whether it is an exclusion. The tag
+// filters also match the aliases of the tag key and the values mapped to the
+// filtered values.
+func createQueryFilter(filter CostsFilter, resolver aliases.Resolver) elastic.Query {
+	if strings.HasPrefix(filter.Field, "tag:") {
+		key := strings.TrimPrefix(filter.Field, "tag:")
+		return elastic.NewNestedQuery("tags", elastic.NewBoolQuery().Filter(
+			resolver.KeysQuery("tags.key", key),
+			elastic.NewTermsQuery("tags.tag", toInterfaces(resolver.Values(key, filter.Values))...),
+		))
+	}
+	return elastic.NewTermsQuery(filter.Field, toInterfaces(filter.Values)...)
+}
+
+// toInterfaces converts a slice of strings to a slice of interfaces.
+func toInterfaces(values []string) []interface{} {
+	res := make([]interface{}, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+	return res
+}
+
+// applyQueryFilters adds the filters to a bool query: the included values
+// are combined with a filter clause and the excluded ones with a must_not
+// clause.
+func applyQueryFilters(query *elastic.BoolQuery, filters []CostsFilter, resolver aliases.Resolver) *elastic.BoolQuery {
+	for _, filter := range filters {
+		if len(filter.Values) == 0 {
+			continue
+		} else if filter.Exclude {
+			query = query.MustNot(createQueryFilter(filter, resolver))
+		} else {
+			query = query.Filter(createQueryFilter(filter, resolver))
+		}
+	}
+	return query
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/es"
 	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/tagging/aliases"
 	"github.com/trackit/trackit/users"
 )
 
@@ -34,6 +35,7 @@ func init() {
 			db.RequestTransaction{Db: db.Db},
 			users.RequireAuthenticatedUser{users.ViewerAsParent},
 			routes.QueryArgs(tagsValuesQueryArgs),
+			users.RequirePermission{users.PermissionViewCosts},
 			cache.UsersCache{},
 			routes.Documentation{
 				Summary:     "get the tag values and their cost with a filter",
@@ -46,6 +48,7 @@ func init() {
 			db.RequestTransaction{Db: db.Db},
 			users.RequireAuthenticatedUser{users.ViewerAsParent},
 			routes.QueryArgs(tagsKeysQueryArgs),
+			users.RequirePermission{users.PermissionViewCosts},
 			cache.UsersCache{},
 			routes.Documentation{
 				Summary:     "get every tag keys",
@@ -83,13 +86,15 @@ var tagsValuesQueryArgs = []routes.QueryArg{
 
 // TagsValuesQueryParams will store the parsed query params for /tags/values endpoint
 type TagsValuesQueryParams struct {
-	AccountList []string  `json:"awsAccounts"`
-	IndexList   []string  `json:"indexes"`
-	DateBegin   time.Time `json:"begin"`
-	DateEnd     time.Time `json:"end"`
-	TagsKeys    []string  `json:"keys"`
-	By          string    `json:"by"`
-	Detailed    bool      `json:"detailed"`
+	AccountList []string         `json:"awsAccounts"`
+	IndexList   []string         `json:"indexes"`
+	DateBegin   time.Time        `json:"begin"`
+	DateEnd     time.Time        `json:"end"`
+	TagsKeys    []string         `json:"keys"`
+	By          string           `json:"by"`
+	Detailed    bool             `json:"detailed"`
+	Metric      string           `json:"metric"`
+	Aliases     aliases.Resolver `json:"-"`
 }
 
 // getTagsValues returns tags and their values (cost) based on the query params, in JSON format.
@@ -123,6 +128,12 @@ func getTagsValues(request *http.Request, a routes.Arguments) (int, interface{})
 	if getTagsValuesFilter(parsedParams.By).Filter == "error" {
 		return http.StatusBadRequest, errors.New("Invalid filter: " + parsedParams.By)
 	}
+	if parsedParams.Aliases, err = aliases.GetResolverForUser(tx, user.Id); err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to retrieve tag key aliases.")
+	}
+	for i, key := range parsedParams.TagsKeys {
+		parsedParams.TagsKeys[i] = parsedParams.Aliases.Key(key)
+	}
 	returnCode, res, err := GetTagsValuesWithParsedParams(request.Context(), parsedParams)
 	if returnCode == http.StatusOK {
 		return returnCode, res
@@ -139,10 +150,11 @@ var tagsKeysQueryArgs = []routes.QueryArg{
 
 // TagsKeysQueryParams will store the parsed query params for /tags/keys endpoint
 type TagsKeysQueryParams struct {
-	AccountList []string  `json:"awsAccounts"`
-	IndexList   []string  `json:"indexes"`
-	DateBegin   time.Time `json:"begin"`
-	DateEnd     time.Time `json:"end"`
+	AccountList []string         `json:"awsAccounts"`
+	IndexList   []string         `json:"indexes"`
+	DateBegin   time.Time        `json:"begin"`
+	DateEnd     time.Time        `json:"end"`
+	Aliases     aliases.Resolver `json:"-"`
 }
 
 // getTagsKeys returns the list of the tag keys based on the query params, in JSON format.
@@ -164,6 +176,9 @@ func getTagsKeys(request *http.Request, a routes.Arguments) (int, interface{}) {
 	}
 	parsedParams.AccountList = accountsAndIndexes.Accounts
 	parsedParams.IndexList = accountsAndIndexes.Indexes
+	if parsedParams.Aliases, err = aliases.GetResolverForUser(tx, user.Id); err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to retrieve tag key aliases.")
+	}
 	returnCode, res, err := GetTagsKeysWithParsedParams(request.Context(), parsedParams)
 	if returnCode == http.StatusOK {
 		return returnCode, res
@@ -26,6 +26,7 @@ import (
 
 	"github.com/trackit/trackit/errors"
 	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/tagging/aliases"
 )
 
 type (
@@ -80,7 +81,7 @@ func makeElasticSearchRequestForTagsKeys(ctx context.Context, params TagsKeysQue
 	index := strings.Join(params.IndexList, ",")
 	search := client.Search().Index(index).Size(0).Query(query)
 	search.Aggregation("data", elastic.NewNestedAggregation().Path("tags").
-		SubAggregation("keys", elastic.NewTermsAggregation().Field("tags.key").Size(maxAggregationSize)))
+		SubAggregation("keys", getKeysAggregation(params.Aliases)))
 	res, err := search.Do(ctx)
 	if err != nil {
 		if elastic.IsNotFound(err) {
@@ -102,6 +103,15 @@ func makeElasticSearchRequestForTagsKeys(ctx context.Context, params TagsKeysQue
 	return res, http.StatusOK, nil
 }
 
+// getKeysAggregation returns the terms aggregation on the canonical keys of the tags
+func getKeysAggregation(resolver aliases.Resolver) *elastic.TermsAggregation {
+	aggregation := elastic.NewTermsAggregation().Size(maxAggregationSize)
+	if script := resolver.KeyScript("tags.key"); script != nil {
+		return aggregation.Script(script)
+	}
+	return aggregation.Field("tags.key")
+}
+
 // getTagsKeysQuery will generate a query for the ElasticSearch based on params
 func getTagsKeysQuery(params TagsKeysQueryParams) *elastic.BoolQuery {
 	query := elastic.NewBoolQuery()
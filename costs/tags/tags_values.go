@@ -24,8 +24,10 @@ import (
 	"github.com/olivere/elastic"
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/aws/s3"
 	"github.com/trackit/trackit/errors"
 	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/tagging/aliases"
 )
 
 type (
@@ -177,35 +179,44 @@ func getTagsResponse(typedDocument esTagsValuesDetailedResult, params TagsValues
 	return response
 }
 
-//getAggregationForTagsValues get NewReversedNestedAggregation if detailed is true or false
-func getAggregationForTagsValues(params TagsValuesQueryParams, filter FilterType) (aggregation *elastic.ReverseNestedAggregation) {
+//getAggregationForTagsValues get NewReversedNestedAggregation if detailed is true or false, summing the cost metric field
+func getAggregationForTagsValues(params TagsValuesQueryParams, filter FilterType, metricField string) (aggregation *elastic.ReverseNestedAggregation) {
 	if params.Detailed {
 		aggregation = elastic.NewReverseNestedAggregation().
 			SubAggregation("filter", elastic.NewTermsAggregation().Field(filter.Filter).Size(maxAggregationSize).
 				SubAggregation("type", elastic.NewTermsAggregation().Field("usageType").Size(maxAggregationSize).
-					SubAggregation("cost", elastic.NewSumAggregation().Field("unblendedCost"))))
+					SubAggregation("cost", elastic.NewSumAggregation().Field(metricField))))
 		if filter.Type == "time" {
 			aggregation = elastic.NewReverseNestedAggregation().
 				SubAggregation("filter", elastic.NewDateHistogramAggregation().
 					Field("usageStartDate").MinDocCount(0).Interval(filter.Filter).
 					SubAggregation("type", elastic.NewTermsAggregation().Field("usageType").Size(maxAggregationSize).
-						SubAggregation("cost", elastic.NewSumAggregation().Field("unblendedCost"))))
+						SubAggregation("cost", elastic.NewSumAggregation().Field(metricField))))
 		}
 		return
 	} else {
 		aggregation = elastic.NewReverseNestedAggregation().
 			SubAggregation("filter", elastic.NewTermsAggregation().Field(filter.Filter).Size(maxAggregationSize).
-				SubAggregation("cost", elastic.NewSumAggregation().Field("unblendedCost")))
+				SubAggregation("cost", elastic.NewSumAggregation().Field(metricField)))
 		if filter.Type == "time" {
 			aggregation = elastic.NewReverseNestedAggregation().
 				SubAggregation("filter", elastic.NewDateHistogramAggregation().
 					Field("usageStartDate").MinDocCount(0).Interval(filter.Filter).
-					SubAggregation("cost", elastic.NewSumAggregation().Field("unblendedCost")))
+					SubAggregation("cost", elastic.NewSumAggregation().Field(metricField)))
 		}
 		return
 	}
 }
 
+// getValuesAggregation returns the terms aggregation on the canonical values of the tags
+func getValuesAggregation(resolver aliases.Resolver) *elastic.TermsAggregation {
+	aggregation := elastic.NewTermsAggregation().Size(maxAggregationSize)
+	if script := resolver.ValueScript("tags.key", "tags.tag"); script != nil {
+		return aggregation.Script(script)
+	}
+	return aggregation.Field("tags.tag")
+}
+
 // makeElasticSearchRequestForTagsValues will make the actual request to the ElasticSearch
 // It will return the data, an http status code (as int) and an error.
 // Because an error can be generated, but is not critical and is not needed to be known by
@@ -217,11 +228,21 @@ func makeElasticSearchRequestForTagsValues(ctx context.Context, params TagsValue
 	filter := getTagsValuesFilter(params.By)
 	query := getTagsValuesQuery(params)
 	index := strings.Join(params.IndexList, ",")
-	aggregation := getAggregationForTagsValues(params, filter)
+	metricField, err := s3.CostMetricField(params.Metric)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if err = s3.CheckCostMetricIngested(ctx, client, index, params.AccountList, params.DateBegin, params.DateEnd, params.Metric); err == s3.ErrCostMetricNotIngested {
+		return nil, http.StatusBadRequest, err
+	} else if err != nil {
+		l.Error("Failed to check the cost metric of line items", map[string]interface{}{"error": err.Error()})
+		return nil, http.StatusInternalServerError, err
+	}
+	aggregation := getAggregationForTagsValues(params, filter, metricField)
 	search := client.Search().Index(index).Size(0).Query(query)
 	search.Aggregation("data", elastic.NewNestedAggregation().Path("tags").
-		SubAggregation("keys", elastic.NewTermsAggregation().Field("tags.key").Size(maxAggregationSize).
-			SubAggregation("tags", elastic.NewTermsAggregation().Field("tags.tag").Size(maxAggregationSize).
+		SubAggregation("keys", getKeysAggregation(params.Aliases).
+			SubAggregation("tags", getValuesAggregation(params.Aliases).
 				SubAggregation("rev", aggregation))))
 	res, err := search.Do(ctx)
 	if err != nil {
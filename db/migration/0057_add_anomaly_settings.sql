@@ -0,0 +1,30 @@
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE anomaly_settings (
+	id                        INTEGER      NOT NULL AUTO_INCREMENT,
+	created                   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	modified                  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	aws_account_id            INTEGER      NOT NULL,
+	levels                    VARCHAR(255) NULL DEFAULT NULL,
+	pretty_levels             VARCHAR(255) NULL DEFAULT NULL,
+	min_percent_of_daily_bill DOUBLE       NULL DEFAULT NULL,
+	min_absolute_cost         DOUBLE       NULL DEFAULT NULL,
+	highest_spending_min_rank INTEGER      NULL DEFAULT NULL,
+	recurrence_threshold      DOUBLE       NULL DEFAULT NULL,
+	emailing_min_level        INTEGER      NULL DEFAULT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT unique_aws_account UNIQUE KEY (aws_account_id),
+	CONSTRAINT foreign_aws_account FOREIGN KEY (aws_account_id) REFERENCES aws_account(id) ON DELETE CASCADE
+);
@@ -0,0 +1,26 @@
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE role (
+	id          INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id     INTEGER      NOT NULL,
+	name        VARCHAR(255) NOT NULL,
+	permissions BLOB         NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+	CONSTRAINT unique_user_name UNIQUE KEY (user_id, name)
+);
+
+ALTER TABLE shared_account ADD role_id INTEGER NULL DEFAULT NULL;
+ALTER TABLE shared_account ADD CONSTRAINT foreign_role FOREIGN KEY (role_id) REFERENCES role(id) ON DELETE SET NULL;
@@ -0,0 +1,31 @@
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE audit_event (
+	id             INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id        INTEGER      NULL DEFAULT NULL,
+	viewer_id      INTEGER      NULL DEFAULT NULL,
+	api_token_id   INTEGER      NULL DEFAULT NULL,
+	aws_account_id INTEGER      NULL DEFAULT NULL,
+	action         VARCHAR(64)  NOT NULL,
+	method         VARCHAR(8)   NOT NULL,
+	path           VARCHAR(255) NOT NULL,
+	status         INTEGER      NOT NULL,
+	remote_address VARCHAR(255) NOT NULL,
+	details        BLOB         NOT NULL,
+	created        DATETIME     NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	INDEX user_created (user_id, created),
+	INDEX aws_account_created (aws_account_id, created)
+);
@@ -0,0 +1,25 @@
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE scheduled_task (
+	id            INTEGER       NOT NULL AUTO_INCREMENT,
+	name          VARCHAR(255)  NOT NULL,
+	last_run      DATETIME(6)   NULL DEFAULT NULL,
+	last_finished DATETIME(6)   NULL DEFAULT NULL,
+	last_error    VARCHAR(1024) NOT NULL DEFAULT '',
+	lease_holder  VARCHAR(255)  NOT NULL DEFAULT '',
+	lease_until   DATETIME(6)   NULL DEFAULT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT unique_name UNIQUE KEY (name)
+);
@@ -1264,4 +1264,427 @@ ALTER TABLE tagbot_user ADD CONSTRAINT foreign_discount_code FOREIGN KEY (discou
 UPDATE tagbot_user INNER JOIN user ON user.id = tagbot_user.user_id SET tagbot_user.free_tier_end_at = DATE_ADD(user.created, INTERVAL 14 DAY);
 
 ALTER TABLE aws_account ADD tagbot_onboarding_started TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
-ALTER TABLE aws_account ADD tagbot_onboarding VARCHAR(255) NOT NULL DEFAULT 'NEEDED';
\ No newline at end of file
+ALTER TABLE aws_account ADD tagbot_onboarding VARCHAR(255) NOT NULL DEFAULT 'NEEDED';
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE anomaly_detection_algorithm (
+	id             INTEGER      NOT NULL AUTO_INCREMENT,
+	created        TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	modified       TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	aws_account_id INTEGER      NOT NULL,
+	product        VARCHAR(255) NOT NULL DEFAULT "",
+	algorithm      VARCHAR(63)  NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT unique_account_product UNIQUE KEY (aws_account_id, product),
+	CONSTRAINT foreign_aws_account FOREIGN KEY (aws_account_id) REFERENCES aws_account(id) ON DELETE CASCADE
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE anomaly_settings (
+	id                        INTEGER      NOT NULL AUTO_INCREMENT,
+	created                   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	modified                  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	aws_account_id            INTEGER      NOT NULL,
+	levels                    VARCHAR(255) NULL DEFAULT NULL,
+	pretty_levels             VARCHAR(255) NULL DEFAULT NULL,
+	min_percent_of_daily_bill DOUBLE       NULL DEFAULT NULL,
+	min_absolute_cost         DOUBLE       NULL DEFAULT NULL,
+	highest_spending_min_rank INTEGER      NULL DEFAULT NULL,
+	recurrence_threshold      DOUBLE       NULL DEFAULT NULL,
+	emailing_min_level        INTEGER      NULL DEFAULT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT unique_aws_account UNIQUE KEY (aws_account_id),
+	CONSTRAINT foreign_aws_account FOREIGN KEY (aws_account_id) REFERENCES aws_account(id) ON DELETE CASCADE
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+ALTER TABLE aws_account_update_job ADD savingsPlansError VARCHAR(255) NOT NULL DEFAULT "";
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+ALTER TABLE aws_account_update_job ADD odToRiRdsError VARCHAR(255) NOT NULL DEFAULT "";
+ALTER TABLE aws_account_update_job ADD odToRiElastiCacheError VARCHAR(255) NOT NULL DEFAULT "";
+ALTER TABLE aws_account_update_job ADD odToRiEsError VARCHAR(255) NOT NULL DEFAULT "";
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE budget (
+	id         INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id    INTEGER      NOT NULL,
+	name       VARCHAR(255) NOT NULL,
+	period     VARCHAR(16)  NOT NULL,
+	amount     DOUBLE       NOT NULL,
+	scope      BLOB         NOT NULL,
+	thresholds BLOB         NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+CREATE TABLE budget_alert (
+	id           INTEGER      NOT NULL AUTO_INCREMENT,
+	budget_id    INTEGER      NOT NULL,
+	period_begin DATETIME     NOT NULL,
+	threshold    DOUBLE       NOT NULL,
+	type         VARCHAR(16)  NOT NULL,
+	spend        DOUBLE       NOT NULL,
+	recipient    VARCHAR(255) NOT NULL,
+	date         TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT UNIQUE KEY unique_budget_alert (budget_id, period_begin, threshold, type),
+	CONSTRAINT foreign_budget FOREIGN KEY (budget_id) REFERENCES budget(id) ON DELETE CASCADE
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE plugin_finding_suppression (
+	id               INTEGER      NOT NULL AUTO_INCREMENT,
+	aws_account_id   INTEGER      NOT NULL,
+	plugin_name      VARCHAR(255) NOT NULL,
+	resource_pattern VARCHAR(255) NOT NULL,
+	reason           TEXT         NOT NULL,
+	expiry           DATETIME     NULL DEFAULT NULL,
+	author           VARCHAR(255) NOT NULL,
+	created          TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_aws_account FOREIGN KEY (aws_account_id) REFERENCES aws_account(id) ON DELETE CASCADE
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE tag_policy (
+	id             INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id        INTEGER      NOT NULL,
+	name           VARCHAR(255) NOT NULL,
+	resource_types BLOB         NOT NULL,
+	rules          BLOB         NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE tag_remediation (
+	id             INTEGER       NOT NULL AUTO_INCREMENT,
+	aws_account_id INTEGER       NOT NULL,
+	resource_arn   VARCHAR(2048) NOT NULL,
+	previous_tags  BLOB          NOT NULL,
+	tags           BLOB          NOT NULL,
+	dry_run        BOOLEAN       NOT NULL DEFAULT 0,
+	status         VARCHAR(16)   NOT NULL,
+	error          TEXT          NOT NULL,
+	author         VARCHAR(255)  NOT NULL,
+	created        TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_aws_account FOREIGN KEY (aws_account_id) REFERENCES aws_account(id) ON DELETE CASCADE
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE tag_key_alias (
+	id            INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id       INTEGER      NOT NULL,
+	canonical_key VARCHAR(255) NOT NULL,
+	aliases       BLOB         NOT NULL,
+	value_mapping BLOB         NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+	CONSTRAINT unique_user_canonical_key UNIQUE KEY (user_id, canonical_key)
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE report_definition (
+	id             INTEGER      NOT NULL AUTO_INCREMENT,
+	aws_account_id INTEGER      NOT NULL,
+	name           VARCHAR(255) NOT NULL,
+	master         BOOLEAN      NOT NULL DEFAULT 0,
+	modules        BLOB         NOT NULL,
+	aws_accounts   BLOB         NOT NULL,
+	date_policy    VARCHAR(32)  NOT NULL DEFAULT "previousMonth",
+	format         VARCHAR(16)  NOT NULL DEFAULT "xlsx",
+	recipients     BLOB         NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_aws_account FOREIGN KEY (aws_account_id) REFERENCES aws_account(id) ON DELETE CASCADE
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE api_token (
+	id           INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id      INTEGER      NOT NULL,
+	name         VARCHAR(255) NOT NULL,
+	token_hash   CHAR(64)     NOT NULL,
+	scope        VARCHAR(16)  NOT NULL,
+	aws_accounts BLOB         NOT NULL,
+	expires      DATETIME     NULL DEFAULT NULL,
+	created      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_used    DATETIME     NULL DEFAULT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+	CONSTRAINT unique_token_hash UNIQUE KEY (token_hash)
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE sso_identity (
+	id      INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id INTEGER      NOT NULL,
+	issuer  VARCHAR(255) NOT NULL,
+	subject VARCHAR(255) NOT NULL,
+	created TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+	CONSTRAINT unique_issuer_subject UNIQUE KEY (issuer, subject)
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE role (
+	id          INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id     INTEGER      NOT NULL,
+	name        VARCHAR(255) NOT NULL,
+	permissions BLOB         NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT foreign_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+	CONSTRAINT unique_user_name UNIQUE KEY (user_id, name)
+);
+
+ALTER TABLE shared_account ADD role_id INTEGER NULL DEFAULT NULL;
+ALTER TABLE shared_account ADD CONSTRAINT foreign_role FOREIGN KEY (role_id) REFERENCES role(id) ON DELETE SET NULL;
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE audit_event (
+	id             INTEGER      NOT NULL AUTO_INCREMENT,
+	user_id        INTEGER      NULL DEFAULT NULL,
+	viewer_id      INTEGER      NULL DEFAULT NULL,
+	api_token_id   INTEGER      NULL DEFAULT NULL,
+	aws_account_id INTEGER      NULL DEFAULT NULL,
+	action         VARCHAR(64)  NOT NULL,
+	method         VARCHAR(8)   NOT NULL,
+	path           VARCHAR(255) NOT NULL,
+	status         INTEGER      NOT NULL,
+	remote_address VARCHAR(255) NOT NULL,
+	details        BLOB         NOT NULL,
+	created        DATETIME     NOT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	INDEX user_created (user_id, created),
+	INDEX aws_account_created (aws_account_id, created)
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE scheduled_task (
+	id            INTEGER       NOT NULL AUTO_INCREMENT,
+	name          VARCHAR(255)  NOT NULL,
+	last_run      DATETIME(6)   NULL DEFAULT NULL,
+	last_finished DATETIME(6)   NULL DEFAULT NULL,
+	last_error    VARCHAR(1024) NOT NULL DEFAULT '',
+	lease_holder  VARCHAR(255)  NOT NULL DEFAULT '',
+	lease_until   DATETIME(6)   NULL DEFAULT NULL,
+	CONSTRAINT PRIMARY KEY (id),
+	CONSTRAINT unique_name UNIQUE KEY (name)
+);
+
+--   Copyright 2021 MSolution.IO
+--
+--   Licensed under the Apache License, Version 2.0 (the "License");
+--   you may not use this file except in compliance with the License.
+--   You may obtain a copy of the License at
+--
+--       http://www.apache.org/licenses/LICENSE-2.0
+--
+--   Unless required by applicable law or agreed to in writing, software
+--   distributed under the License is distributed on an "AS IS" BASIS,
+--   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+--   See the License for the specific language governing permissions and
+--   limitations under the License.
+
+CREATE TABLE sso_login_state (
+	nonce_hash CHAR(64)    NOT NULL,
+	expires    DATETIME(6) NOT NULL,
+	CONSTRAINT PRIMARY KEY (nonce_hash)
+);
@@ -15,9 +15,11 @@
 package mail
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
+	"mime/quotedprintable"
 	"net"
 	"net/smtp"
 
@@ -54,6 +56,22 @@ func SendMail(recipient string, subject, body string, ctx context.Context) error
 	return mail.Send(ctx)
 }
 
+// SendHtmlMail sends a mail whose body is an HTML page.
+// It gets the SMTP information from the config file.
+func SendHtmlMail(recipient string, subject, body string, ctx context.Context) error {
+	mail := Mail{
+		config.SmtpAddress,
+		config.SmtpPort,
+		config.SmtpUser,
+		config.SmtpPassword,
+		config.SmtpSender,
+		recipient,
+		subject,
+		body,
+	}
+	return mail.SendHtml(ctx)
+}
+
 func (m Mail) buildMessage() []byte {
 	message := ""
 	message += fmt.Sprintf("From: %s\r\n", m.Sender)
@@ -63,6 +81,26 @@ func (m Mail) buildMessage() []byte {
 	return []byte(message)
 }
 
+// buildHtmlMessage builds a message whose body is an HTML page, encoded as
+// quoted-printable to keep the lines of the message short.
+func (m Mail) buildHtmlMessage() ([]byte, error) {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", m.Sender)
+	fmt.Fprintf(&message, "To: %s\r\n", m.Recipient)
+	fmt.Fprintf(&message, "Subject: %s\r\n", m.Subject)
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	message.WriteString("\r\n")
+	w := quotedprintable.NewWriter(&message)
+	if _, err := w.Write([]byte(m.Body)); err != nil {
+		return nil, err
+	} else if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
+
 func (m Mail) setTlsConfig(client *smtp.Client) error {
 	tlsconfig := &tls.Config{
 		InsecureSkipVerify: true,
@@ -118,12 +156,12 @@ func (m Mail) setAddresses(client *smtp.Client) error {
 	return nil
 }
 
-func (m Mail) setMessage(client *smtp.Client) error {
+func (m Mail) setMessage(client *smtp.Client, message []byte) error {
 	w, err := client.Data()
 	if err != nil {
 		return err
 	}
-	if _, err := w.Write(m.buildMessage()); err != nil {
+	if _, err := w.Write(message); err != nil {
 		return err
 	}
 	if err := w.Close(); err != nil {
@@ -135,6 +173,19 @@ func (m Mail) setMessage(client *smtp.Client) error {
 // Send provides a way to send a mail with SMTP information
 // from the Mail structure.
 func (m Mail) Send(ctx context.Context) error {
+	return m.send(ctx, m.buildMessage())
+}
+
+// SendHtml sends the mail like Send, its body being an HTML page.
+func (m Mail) SendHtml(ctx context.Context) error {
+	message, err := m.buildHtmlMessage()
+	if err != nil {
+		return err
+	}
+	return m.send(ctx, message)
+}
+
+func (m Mail) send(ctx context.Context, message []byte) error {
 	dataLogged := map[string]interface{}{"subject": m.Subject, "recipient": m.Recipient}
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	logger.Info("Sending mail.", dataLogged)
@@ -146,7 +197,7 @@ func (m Mail) Send(ctx context.Context) error {
 	if err := m.setAddresses(client); err != nil {
 		return err
 	}
-	if err := m.setMessage(client); err != nil {
+	if err := m.setMessage(client, message); err != nil {
 		return err
 	}
 	if err := client.Quit(); err != nil {
@@ -16,6 +16,9 @@ package mail
 
 import (
 	"bytes"
+	"io"
+	"mime/quotedprintable"
+	"strings"
 	"testing"
 )
 
@@ -42,3 +45,41 @@ func TestSendMail(t *testing.T) {
 		t.Fatalf("Unexpected message: (%s) instead of (%s)", msg, template)
 	}
 }
+
+func TestBuildHtmlMessage(t *testing.T) {
+	m := Mail{
+		"",
+		"",
+		"",
+		"",
+		"team@msolution.io",
+		"thibaut@trackit.io",
+		"test subject!",
+		"<p>test body=" + strings.Repeat("a", 100) + "</p>",
+	}
+	msg, err := m.buildHtmlMessage()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	headers := "From: " + m.Sender + "\r\n" +
+		"To: " + m.Recipient + "\r\n" +
+		"Subject: " + m.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n"
+	if !bytes.HasPrefix(msg, []byte(headers)) {
+		t.Fatalf("Unexpected headers: (%s)", msg)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(msg[len(headers):])))
+	if err != nil {
+		t.Fatalf("Unexpected error decoding the body: %s", err.Error())
+	} else if string(body) != m.Body {
+		t.Fatalf("Unexpected body: (%s) instead of (%s)", body, m.Body)
+	}
+	for _, line := range bytes.Split(msg, []byte("\r\n")) {
+		if len(line) > 76 {
+			t.Fatalf("Line longer than 76 characters: (%s)", line)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// AuditEventFilter selects audit events. Events are visible if they were
+// performed by VisibleUserID or on one of VisibleAwsAccountIDs; the other
+// fields are ignored when zero.
+type AuditEventFilter struct {
+	VisibleUserID        int
+	VisibleAwsAccountIDs []int
+	UserID               int
+	AwsAccountIDs        []int
+	Action               string
+	Begin                time.Time
+	End                  time.Time
+	Limit                int
+}
+
+// AuditEventsByFilter returns the audit events matching a filter, the most
+// recent first.
+func AuditEventsByFilter(db DB, filter AuditEventFilter) ([]*AuditEvent, error) {
+	visible := []string{"user_id = ?"}
+	args := []interface{}{filter.VisibleUserID}
+	if len(filter.VisibleAwsAccountIDs) > 0 {
+		visible = append(visible, "aws_account_id IN (?"+strings.Repeat(", ?", len(filter.VisibleAwsAccountIDs)-1)+")")
+		for _, id := range filter.VisibleAwsAccountIDs {
+			args = append(args, id)
+		}
+	}
+	where := []string{"(" + strings.Join(visible, " OR ") + ")"}
+	if filter.UserID != 0 {
+		where = append(where, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if len(filter.AwsAccountIDs) > 0 {
+		where = append(where, "aws_account_id IN (?"+strings.Repeat(", ?", len(filter.AwsAccountIDs)-1)+")")
+		for _, id := range filter.AwsAccountIDs {
+			args = append(args, id)
+		}
+	}
+	if filter.Action != "" {
+		where = append(where, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if !filter.Begin.IsZero() {
+		where = append(where, "created >= ?")
+		args = append(args, filter.Begin)
+	}
+	if !filter.End.IsZero() {
+		where = append(where, "created < ?")
+		args = append(args, filter.End)
+	}
+	args = append(args, filter.Limit)
+	sqlstr := `SELECT ` +
+		`id, user_id, viewer_id, api_token_id, aws_account_id, action, method, path, status, remote_address, details, created ` +
+		`FROM trackit.audit_event ` +
+		`WHERE ` + strings.Join(where, " AND ") + ` ` +
+		`ORDER BY created DESC, id DESC LIMIT ?`
+	logf(sqlstr, args...)
+	q, err := db.Query(sqlstr, args...)
+	if err != nil {
+		return nil, logerror(err)
+	}
+	defer q.Close()
+	res := []*AuditEvent{}
+	for q.Next() {
+		ae := AuditEvent{
+			_exists: true,
+		}
+		err = q.Scan(&ae.ID, &ae.UserID, &ae.ViewerID, &ae.ApiTokenID, &ae.AwsAccountID, &ae.Action, &ae.Method, &ae.Path, &ae.Status, &ae.RemoteAddress, &ae.Details, &ae.Created)
+		if err != nil {
+			return nil, logerror(err)
+		}
+		res = append(res, &ae)
+	}
+	if err := q.Err(); err != nil {
+		return nil, logerror(err)
+	}
+	return res, nil
+}
@@ -30,6 +30,10 @@ type AwsAccountUpdateJob struct {
 	SqsError                string         `json:"sqsError"`                  // sqsError
 	CloudFormationError     string         `json:"cloudFormationError"`       // cloudFormationError
 	Route53error            string         `json:"route53Error"`              // route53Error
+	SavingsPlansError       string         `json:"savingsPlansError"`         // savingsPlansError
+	OdToRiRdsError          string         `json:"odToRiRdsError"`            // odToRiRdsError
+	OdToRiElastiCacheError  string         `json:"odToRiElastiCacheError"`    // odToRiElastiCacheError
+	OdToRiEsError           string         `json:"odToRiEsError"`             // odToRiEsError
 	// xo fields
 	_exists, _deleted bool
 }
@@ -55,13 +59,13 @@ func (aauj *AwsAccountUpdateJob) Insert(db DB) error {
 	}
 	// insert (primary key generated and returned by database)
 	const sqlstr = `INSERT INTO trackit.aws_account_update_job (` +
-		`aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error` +
+		`aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error, savingsPlansError, odToRiRdsError, odToRiElastiCacheError, odToRiEsError` +
 		`) VALUES (` +
-		`?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?` +
+		`?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?` +
 		`)`
 	// run
-	logf(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error)
-	res, err := db.Exec(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error)
+	logf(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError)
+	res, err := db.Exec(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError)
 	if err != nil {
 		return err
 	}
@@ -86,11 +90,11 @@ func (aauj *AwsAccountUpdateJob) Update(db DB) error {
 	}
 	// update with primary key
 	const sqlstr = `UPDATE trackit.aws_account_update_job SET ` +
-		`aws_account_id = ?, completed = ?, worker_id = ?, jobError = ?, rdsError = ?, ec2Error = ?, historyError = ?, esError = ?, monthly_reports_generated = ?, elastiCacheError = ?, lambdaError = ?, riEc2Error = ?, riRdsError = ?, odToRiEc2Error = ?, ebsError = ?, stepFunctionError = ?, s3Error = ?, sqsError = ?, cloudFormationError = ?, route53Error = ? ` +
+		`aws_account_id = ?, completed = ?, worker_id = ?, jobError = ?, rdsError = ?, ec2Error = ?, historyError = ?, esError = ?, monthly_reports_generated = ?, elastiCacheError = ?, lambdaError = ?, riEc2Error = ?, riRdsError = ?, odToRiEc2Error = ?, ebsError = ?, stepFunctionError = ?, s3Error = ?, sqsError = ?, cloudFormationError = ?, route53Error = ?, savingsPlansError = ?, odToRiRdsError = ?, odToRiElastiCacheError = ?, odToRiEsError = ? ` +
 		`WHERE id = ?`
 	// run
-	logf(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.ID)
-	if _, err := db.Exec(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.ID); err != nil {
+	logf(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError, aauj.ID)
+	if _, err := db.Exec(sqlstr, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError, aauj.ID); err != nil {
 		return logerror(err)
 	}
 	return nil
@@ -112,15 +116,15 @@ func (aauj *AwsAccountUpdateJob) Upsert(db DB) error {
 	}
 	// upsert
 	const sqlstr = `INSERT INTO trackit.aws_account_update_job (` +
-		`id, aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error` +
+		`id, aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error, savingsPlansError, odToRiRdsError, odToRiElastiCacheError, odToRiEsError` +
 		`) VALUES (` +
-		`?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?` +
+		`?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?` +
 		`)` +
 		` ON DUPLICATE KEY UPDATE ` +
-		`aws_account_id = VALUES(aws_account_id), completed = VALUES(completed), worker_id = VALUES(worker_id), jobError = VALUES(jobError), rdsError = VALUES(rdsError), ec2Error = VALUES(ec2Error), historyError = VALUES(historyError), esError = VALUES(esError), monthly_reports_generated = VALUES(monthly_reports_generated), elastiCacheError = VALUES(elastiCacheError), lambdaError = VALUES(lambdaError), riEc2Error = VALUES(riEc2Error), riRdsError = VALUES(riRdsError), odToRiEc2Error = VALUES(odToRiEc2Error), ebsError = VALUES(ebsError), stepFunctionError = VALUES(stepFunctionError), s3Error = VALUES(s3Error), sqsError = VALUES(sqsError), cloudFormationError = VALUES(cloudFormationError), route53Error = VALUES(route53Error)`
+		`aws_account_id = VALUES(aws_account_id), completed = VALUES(completed), worker_id = VALUES(worker_id), jobError = VALUES(jobError), rdsError = VALUES(rdsError), ec2Error = VALUES(ec2Error), historyError = VALUES(historyError), esError = VALUES(esError), monthly_reports_generated = VALUES(monthly_reports_generated), elastiCacheError = VALUES(elastiCacheError), lambdaError = VALUES(lambdaError), riEc2Error = VALUES(riEc2Error), riRdsError = VALUES(riRdsError), odToRiEc2Error = VALUES(odToRiEc2Error), ebsError = VALUES(ebsError), stepFunctionError = VALUES(stepFunctionError), s3Error = VALUES(s3Error), sqsError = VALUES(sqsError), cloudFormationError = VALUES(cloudFormationError), route53Error = VALUES(route53Error), savingsPlansError = VALUES(savingsPlansError), odToRiRdsError = VALUES(odToRiRdsError), odToRiElastiCacheError = VALUES(odToRiElastiCacheError), odToRiEsError = VALUES(odToRiEsError)`
 	// run
-	logf(sqlstr, aauj.ID, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error)
-	if _, err := db.Exec(sqlstr, aauj.ID, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error); err != nil {
+	logf(sqlstr, aauj.ID, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError)
+	if _, err := db.Exec(sqlstr, aauj.ID, aauj.AwsAccountID, aauj.Completed, aauj.WorkerID, aauj.JobError, aauj.RdsError, aauj.Ec2error, aauj.HistoryError, aauj.EsError, aauj.MonthlyReportsGenerated, aauj.ElastiCacheError, aauj.LambdaError, aauj.RiEc2error, aauj.RiRdsError, aauj.OdToRiEc2error, aauj.EbsError, aauj.StepFunctionError, aauj.S3error, aauj.SqsError, aauj.CloudFormationError, aauj.Route53error, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError); err != nil {
 		return err
 	}
 	// set exists
@@ -155,7 +159,7 @@ func (aauj *AwsAccountUpdateJob) Delete(db DB) error {
 func AwsAccountUpdateJobByID(db DB, id int) (*AwsAccountUpdateJob, error) {
 	// query
 	const sqlstr = `SELECT ` +
-		`id, aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error ` +
+		`id, aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error, savingsPlansError, odToRiRdsError, odToRiElastiCacheError, odToRiEsError ` +
 		`FROM trackit.aws_account_update_job ` +
 		`WHERE id = ?`
 	// run
@@ -163,7 +167,7 @@ func AwsAccountUpdateJobByID(db DB, id int) (*AwsAccountUpdateJob, error) {
 	aauj := AwsAccountUpdateJob{
 		_exists: true,
 	}
-	if err := db.QueryRow(sqlstr, id).Scan(&aauj.ID, &aauj.AwsAccountID, &aauj.Completed, &aauj.WorkerID, &aauj.JobError, &aauj.RdsError, &aauj.Ec2error, &aauj.HistoryError, &aauj.EsError, &aauj.MonthlyReportsGenerated, &aauj.ElastiCacheError, &aauj.LambdaError, &aauj.RiEc2error, &aauj.RiRdsError, &aauj.OdToRiEc2error, &aauj.EbsError, &aauj.StepFunctionError, &aauj.S3error, &aauj.SqsError, &aauj.CloudFormationError, &aauj.Route53error); err != nil {
+	if err := db.QueryRow(sqlstr, id).Scan(&aauj.ID, &aauj.AwsAccountID, &aauj.Completed, &aauj.WorkerID, &aauj.JobError, &aauj.RdsError, &aauj.Ec2error, &aauj.HistoryError, &aauj.EsError, &aauj.MonthlyReportsGenerated, &aauj.ElastiCacheError, &aauj.LambdaError, &aauj.RiEc2error, &aauj.RiRdsError, &aauj.OdToRiEc2error, &aauj.EbsError, &aauj.StepFunctionError, &aauj.S3error, &aauj.SqsError, &aauj.CloudFormationError, &aauj.Route53error, &aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError, &aauj.OdToRiRdsError, &aauj.OdToRiElastiCacheError, &aauj.OdToRiEsError, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError); err != nil {
 		return nil, logerror(err)
 	}
 	return &aauj, nil
@@ -175,7 +179,7 @@ func AwsAccountUpdateJobByID(db DB, id int) (*AwsAccountUpdateJob, error) {
 func AwsAccountUpdateJobByAwsAccountID(db DB, awsAccountID int) ([]*AwsAccountUpdateJob, error) {
 	// query
 	const sqlstr = `SELECT ` +
-		`id, aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error ` +
+		`id, aws_account_id, completed, worker_id, jobError, rdsError, ec2Error, historyError, esError, monthly_reports_generated, elastiCacheError, lambdaError, riEc2Error, riRdsError, odToRiEc2Error, ebsError, stepFunctionError, s3Error, sqsError, cloudFormationError, route53Error, savingsPlansError, odToRiRdsError, odToRiElastiCacheError, odToRiEsError ` +
 		`FROM trackit.aws_account_update_job ` +
 		`WHERE aws_account_id = ?`
 	// run
@@ -192,7 +196,7 @@ func AwsAccountUpdateJobByAwsAccountID(db DB, awsAccountID int) ([]*AwsAccountUp
 			_exists: true,
 		}
 		// scan
-		if err := rows.Scan(&aauj.ID, &aauj.AwsAccountID, &aauj.Completed, &aauj.WorkerID, &aauj.JobError, &aauj.RdsError, &aauj.Ec2error, &aauj.HistoryError, &aauj.EsError, &aauj.MonthlyReportsGenerated, &aauj.ElastiCacheError, &aauj.LambdaError, &aauj.RiEc2error, &aauj.RiRdsError, &aauj.OdToRiEc2error, &aauj.EbsError, &aauj.StepFunctionError, &aauj.S3error, &aauj.SqsError, &aauj.CloudFormationError, &aauj.Route53error); err != nil {
+		if err := rows.Scan(&aauj.ID, &aauj.AwsAccountID, &aauj.Completed, &aauj.WorkerID, &aauj.JobError, &aauj.RdsError, &aauj.Ec2error, &aauj.HistoryError, &aauj.EsError, &aauj.MonthlyReportsGenerated, &aauj.ElastiCacheError, &aauj.LambdaError, &aauj.RiEc2error, &aauj.RiRdsError, &aauj.OdToRiEc2error, &aauj.EbsError, &aauj.StepFunctionError, &aauj.S3error, &aauj.SqsError, &aauj.CloudFormationError, &aauj.Route53error, &aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError, &aauj.OdToRiRdsError, &aauj.OdToRiElastiCacheError, &aauj.OdToRiEsError, aauj.SavingsPlansError, aauj.OdToRiRdsError, aauj.OdToRiElastiCacheError, aauj.OdToRiEsError); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &aauj)
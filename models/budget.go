@@ -0,0 +1,44 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package models contains the types for schema 'trackit'.
+package models
+
+// AllBudgets retrieves all the rows of 'trackit.budget'.
+func AllBudgets(db DB) (res []*Budget, err error) {
+	const sqlstr = `SELECT ` +
+		`id, user_id, name, period, amount, scope, thresholds ` +
+		`FROM trackit.budget`
+	logf(sqlstr)
+	q, err := db.Query(sqlstr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := q.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	for q.Next() {
+		b := Budget{
+			_exists: true,
+		}
+		err = q.Scan(&b.ID, &b.UserID, &b.Name, &b.Period, &b.Amount, &b.Scope, &b.Thresholds)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &b)
+	}
+	return res, nil
+}
@@ -0,0 +1,60 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ClaimScheduledTask claims the run of a scheduled task planned at a time for
+// a holder, leasing the task until leaseUntil. It fails if a run was claimed
+// after since, or if another holder's lease has not expired. The row of the
+// task is created if needed.
+func ClaimScheduledTask(db DB, name, holder string, planned, since, leaseUntil time.Time) (bool, error) {
+	const insertSqlstr = `INSERT INTO trackit.scheduled_task (name) VALUES (?) ` +
+		`ON DUPLICATE KEY UPDATE name = name`
+	logf(insertSqlstr, name)
+	if _, err := db.Exec(insertSqlstr, name); err != nil {
+		return false, logerror(err)
+	}
+	const sqlstr = `UPDATE trackit.scheduled_task SET ` +
+		`last_run = ?, lease_holder = ?, lease_until = ? ` +
+		`WHERE name = ? AND (last_run IS NULL OR last_run <= ?) ` +
+		`AND (lease_until IS NULL OR lease_until < ? OR lease_holder = ?)`
+	now := time.Now().UTC()
+	logf(sqlstr, planned, holder, leaseUntil, name, since, now, holder)
+	res, err := db.Exec(sqlstr, planned.UTC(), holder, leaseUntil.UTC(), name, since.UTC(), now, holder)
+	if err != nil {
+		return false, logerror(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, logerror(err)
+	}
+	return affected == 1, nil
+}
+
+// ReleaseScheduledTask ends the lease of a holder on a scheduled task,
+// recording when and how its run finished.
+func ReleaseScheduledTask(db DB, name, holder string, finished time.Time, lastError string) error {
+	const sqlstr = `UPDATE trackit.scheduled_task SET ` +
+		`last_finished = ?, last_error = ?, lease_until = NULL ` +
+		`WHERE name = ? AND lease_holder = ?`
+	logf(sqlstr, finished, lastError, name, holder)
+	if _, err := db.Exec(sqlstr, finished.UTC(), lastError, name, holder); err != nil {
+		return logerror(err)
+	}
+	return nil
+}
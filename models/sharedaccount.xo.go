@@ -2,13 +2,18 @@ package models
 
 // Code generated by xo. DO NOT EDIT.
 
+import (
+	"database/sql"
+)
+
 // SharedAccount represents a row from 'trackit.shared_account'.
 type SharedAccount struct {
-	ID              int  `json:"id"`               // id
-	AccountID       int  `json:"account_id"`       // account_id
-	UserID          int  `json:"user_id"`          // user_id
-	UserPermission  int  `json:"user_permission"`  // user_permission
-	SharingAccepted bool `json:"sharing_accepted"` // sharing_accepted
+	ID              int           `json:"id"`               // id
+	AccountID       int           `json:"account_id"`       // account_id
+	UserID          int           `json:"user_id"`          // user_id
+	UserPermission  int           `json:"user_permission"`  // user_permission
+	SharingAccepted bool          `json:"sharing_accepted"` // sharing_accepted
+	RoleID          sql.NullInt64 `json:"role_id"`          // role_id
 	// xo fields
 	_exists, _deleted bool
 }
@@ -34,13 +39,13 @@ func (sa *SharedAccount) Insert(db DB) error {
 	}
 	// insert (primary key generated and returned by database)
 	const sqlstr = `INSERT INTO trackit.shared_account (` +
-		`account_id, user_id, user_permission, sharing_accepted` +
+		`account_id, user_id, user_permission, sharing_accepted, role_id` +
 		`) VALUES (` +
-		`?, ?, ?, ?` +
+		`?, ?, ?, ?, ?` +
 		`)`
 	// run
-	logf(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted)
-	res, err := db.Exec(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted)
+	logf(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.RoleID)
+	res, err := db.Exec(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.RoleID)
 	if err != nil {
 		return err
 	}
@@ -65,11 +70,11 @@ func (sa *SharedAccount) Update(db DB) error {
 	}
 	// update with primary key
 	const sqlstr = `UPDATE trackit.shared_account SET ` +
-		`account_id = ?, user_id = ?, user_permission = ?, sharing_accepted = ? ` +
+		`account_id = ?, user_id = ?, user_permission = ?, sharing_accepted = ?, role_id = ? ` +
 		`WHERE id = ?`
 	// run
-	logf(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.ID)
-	if _, err := db.Exec(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.ID); err != nil {
+	logf(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.RoleID, sa.ID)
+	if _, err := db.Exec(sqlstr, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.RoleID, sa.ID); err != nil {
 		return logerror(err)
 	}
 	return nil
@@ -91,15 +96,15 @@ func (sa *SharedAccount) Upsert(db DB) error {
 	}
 	// upsert
 	const sqlstr = `INSERT INTO trackit.shared_account (` +
-		`id, account_id, user_id, user_permission, sharing_accepted` +
+		`id, account_id, user_id, user_permission, sharing_accepted, role_id` +
 		`) VALUES (` +
-		`?, ?, ?, ?, ?` +
+		`?, ?, ?, ?, ?, ?` +
 		`)` +
 		` ON DUPLICATE KEY UPDATE ` +
-		`account_id = VALUES(account_id), user_id = VALUES(user_id), user_permission = VALUES(user_permission), sharing_accepted = VALUES(sharing_accepted)`
+		`account_id = VALUES(account_id), user_id = VALUES(user_id), user_permission = VALUES(user_permission), sharing_accepted = VALUES(sharing_accepted), role_id = VALUES(role_id)`
 	// run
-	logf(sqlstr, sa.ID, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted)
-	if _, err := db.Exec(sqlstr, sa.ID, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted); err != nil {
+	logf(sqlstr, sa.ID, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.RoleID)
+	if _, err := db.Exec(sqlstr, sa.ID, sa.AccountID, sa.UserID, sa.UserPermission, sa.SharingAccepted, sa.RoleID); err != nil {
 		return err
 	}
 	// set exists
@@ -134,7 +139,7 @@ func (sa *SharedAccount) Delete(db DB) error {
 func SharedAccountByAccountID(db DB, accountID int) ([]*SharedAccount, error) {
 	// query
 	const sqlstr = `SELECT ` +
-		`id, account_id, user_id, user_permission, sharing_accepted ` +
+		`id, account_id, user_id, user_permission, sharing_accepted, role_id ` +
 		`FROM trackit.shared_account ` +
 		`WHERE account_id = ?`
 	// run
@@ -151,7 +156,7 @@ func SharedAccountByAccountID(db DB, accountID int) ([]*SharedAccount, error) {
 			_exists: true,
 		}
 		// scan
-		if err := rows.Scan(&sa.ID, &sa.AccountID, &sa.UserID, &sa.UserPermission, &sa.SharingAccepted); err != nil {
+		if err := rows.Scan(&sa.ID, &sa.AccountID, &sa.UserID, &sa.UserPermission, &sa.SharingAccepted, &sa.RoleID); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &sa)
@@ -168,7 +173,7 @@ func SharedAccountByAccountID(db DB, accountID int) ([]*SharedAccount, error) {
 func SharedAccountByUserID(db DB, userID int) ([]*SharedAccount, error) {
 	// query
 	const sqlstr = `SELECT ` +
-		`id, account_id, user_id, user_permission, sharing_accepted ` +
+		`id, account_id, user_id, user_permission, sharing_accepted, role_id ` +
 		`FROM trackit.shared_account ` +
 		`WHERE user_id = ?`
 	// run
@@ -185,7 +190,7 @@ func SharedAccountByUserID(db DB, userID int) ([]*SharedAccount, error) {
 			_exists: true,
 		}
 		// scan
-		if err := rows.Scan(&sa.ID, &sa.AccountID, &sa.UserID, &sa.UserPermission, &sa.SharingAccepted); err != nil {
+		if err := rows.Scan(&sa.ID, &sa.AccountID, &sa.UserID, &sa.UserPermission, &sa.SharingAccepted, &sa.RoleID); err != nil {
 			return nil, logerror(err)
 		}
 		res = append(res, &sa)
@@ -202,7 +207,7 @@ func SharedAccountByUserID(db DB, userID int) ([]*SharedAccount, error) {
 func SharedAccountByID(db DB, id int) (*SharedAccount, error) {
 	// query
 	const sqlstr = `SELECT ` +
-		`id, account_id, user_id, user_permission, sharing_accepted ` +
+		`id, account_id, user_id, user_permission, sharing_accepted, role_id ` +
 		`FROM trackit.shared_account ` +
 		`WHERE id = ?`
 	// run
@@ -210,7 +215,7 @@ func SharedAccountByID(db DB, id int) (*SharedAccount, error) {
 	sa := SharedAccount{
 		_exists: true,
 	}
-	if err := db.QueryRow(sqlstr, id).Scan(&sa.ID, &sa.AccountID, &sa.UserID, &sa.UserPermission, &sa.SharingAccepted); err != nil {
+	if err := db.QueryRow(sqlstr, id).Scan(&sa.ID, &sa.AccountID, &sa.UserID, &sa.UserPermission, &sa.SharingAccepted, &sa.RoleID); err != nil {
 		return nil, logerror(err)
 	}
 	return &sa, nil
@@ -42,7 +42,7 @@ func init() {
 const TemplateOdToRiEc2Report = `
 {
 	"template": "*-od-to-ri-ec2-reports",
-	"version": 1,
+	"version": 3,
 	"mappings": {
 		"od-to-ri-ec2-report": {
 			"properties": {
@@ -220,6 +220,216 @@ const TemplateOdToRiEc2Report = `
 							}
 						}
 					}
+				},
+				"savingsPlans": {
+					"properties": {
+						"lookbackDays": {
+							"type": "integer"
+						},
+						"estimatedRates": {
+							"type": "boolean"
+						},
+						"error": {
+							"type": "keyword",
+							"norms": false
+						},
+						"compute": {
+							"properties": {
+								"averageHourlyOnDemandCost": {
+									"type": "double"
+								},
+								"minHourlyOnDemandCost": {
+									"type": "double"
+								},
+								"maxHourlyOnDemandCost": {
+									"type": "double"
+								},
+								"monthlyOnDemandCost": {
+									"type": "double"
+								},
+								"oneYear": {
+									"properties": {
+										"discount": {
+											"type": "double"
+										},
+										"breakEvenUtilization": {
+											"type": "double"
+										},
+										"scenarios": {
+											"type": "nested",
+											"properties": {
+												"coverageTarget": {
+													"type": "double"
+												},
+												"optimal": {
+													"type": "boolean"
+												},
+												"hourlyCommitment": {
+													"type": "double"
+												},
+												"coverage": {
+													"type": "double"
+												},
+												"utilization": {
+													"type": "double"
+												},
+												"monthlyCost": {
+													"type": "double"
+												},
+												"monthlySaving": {
+													"type": "double"
+												},
+												"termSaving": {
+													"type": "double"
+												}
+											}
+										}
+									}
+								},
+								"threeYears": {
+									"properties": {
+										"discount": {
+											"type": "double"
+										},
+										"breakEvenUtilization": {
+											"type": "double"
+										},
+										"scenarios": {
+											"type": "nested",
+											"properties": {
+												"coverageTarget": {
+													"type": "double"
+												},
+												"optimal": {
+													"type": "boolean"
+												},
+												"hourlyCommitment": {
+													"type": "double"
+												},
+												"coverage": {
+													"type": "double"
+												},
+												"utilization": {
+													"type": "double"
+												},
+												"monthlyCost": {
+													"type": "double"
+												},
+												"monthlySaving": {
+													"type": "double"
+												},
+												"termSaving": {
+													"type": "double"
+												}
+											}
+										}
+									}
+								}
+							}
+						},
+						"ec2Instance": {
+							"properties": {
+								"region": {
+									"type": "keyword",
+									"norms": false
+								},
+								"instanceFamily": {
+									"type": "keyword",
+									"norms": false
+								},
+								"averageHourlyOnDemandCost": {
+									"type": "double"
+								},
+								"minHourlyOnDemandCost": {
+									"type": "double"
+								},
+								"maxHourlyOnDemandCost": {
+									"type": "double"
+								},
+								"monthlyOnDemandCost": {
+									"type": "double"
+								},
+								"oneYear": {
+									"properties": {
+										"discount": {
+											"type": "double"
+										},
+										"breakEvenUtilization": {
+											"type": "double"
+										},
+										"scenarios": {
+											"type": "nested",
+											"properties": {
+												"coverageTarget": {
+													"type": "double"
+												},
+												"optimal": {
+													"type": "boolean"
+												},
+												"hourlyCommitment": {
+													"type": "double"
+												},
+												"coverage": {
+													"type": "double"
+												},
+												"utilization": {
+													"type": "double"
+												},
+												"monthlyCost": {
+													"type": "double"
+												},
+												"monthlySaving": {
+													"type": "double"
+												},
+												"termSaving": {
+													"type": "double"
+												}
+											}
+										}
+									}
+								},
+								"threeYears": {
+									"properties": {
+										"discount": {
+											"type": "double"
+										},
+										"breakEvenUtilization": {
+											"type": "double"
+										},
+										"scenarios": {
+											"type": "nested",
+											"properties": {
+												"coverageTarget": {
+													"type": "double"
+												},
+												"optimal": {
+													"type": "boolean"
+												},
+												"hourlyCommitment": {
+													"type": "double"
+												},
+												"coverage": {
+													"type": "double"
+												},
+												"utilization": {
+													"type": "double"
+												},
+												"monthlyCost": {
+													"type": "double"
+												},
+												"monthlySaving": {
+													"type": "double"
+												},
+												"termSaving": {
+													"type": "double"
+												}
+											}
+										}
+									}
+								}
+							}
+						}
+					}
 				}
 			},
 			"_all": {
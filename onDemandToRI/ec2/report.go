@@ -90,7 +90,8 @@ type (
 			OneYear   ReservationTotalCost `json:"oneYear"`
 			ThreeYear ReservationTotalCost `json:"threeYears"`
 		} `json:"reservation"`
-		Instances []InstancesSpecs `json:"instances"`
+		Instances    []InstancesSpecs            `json:"instances"`
+		SavingsPlans SavingsPlansRecommendations `json:"savingsPlans"`
 	}
 )
 
@@ -295,7 +296,7 @@ func calculateCosts(ctx context.Context, unreservedIntances []InstancesSpecs, ec
 }
 
 // RunOnDemandToRiEc2 generates a report listing the unreserved instances and the
-// savings that can be done by buying reservations
+// savings that can be done by buying reservations or Savings Plans
 // The result is saved into ES
 func RunOnDemandToRiEc2(ctx context.Context, aa aws.AwsAccount) error {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
@@ -321,6 +322,16 @@ func RunOnDemandToRiEc2(ctx context.Context, aa aws.AwsAccount) error {
 	}
 	unreservedIntances := getUnreservedInstances(instancesReport, reservationsReport)
 	report = calculateCosts(ctx, unreservedIntances, ec2Pricings, report)
+	// The reservations scenarios are still saved if the Savings Plans cannot be sized,
+	// the failure being reported in the Savings Plans recommendations
+	report.SavingsPlans, err = getSavingsPlansRecommendations(ctx, aa, unreservedIntances, ec2Pricings)
+	if err != nil {
+		logger.Error("Failed to size Savings Plans", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		report.SavingsPlans.Error = "Failed to size Savings Plans."
+	}
 	return IngestOdToRiEc2Result(ctx, aa, report)
 }
 
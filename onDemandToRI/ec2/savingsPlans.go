@@ -0,0 +1,446 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRiEc2
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/pricings"
+	"github.com/trackit/trackit/aws/s3"
+	"github.com/trackit/trackit/aws/usageReports/savingsPlans"
+	"github.com/trackit/trackit/es"
+)
+
+const (
+	// savingsPlansLookbackDays is the number of days of on demand spend used to size the commitments
+	savingsPlansLookbackDays = 30
+	// savingsPlansSearchIterations is the number of iterations of the dichotomic search
+	// of the commitment reaching a coverage target
+	savingsPlansSearchIterations = 64
+	// savingsPlansAggregationMaxSize is the maximum size of the aggregations on regions and usage types
+	savingsPlansAggregationMaxSize = 0x7FFFFFFF
+	// savingsPlansFallbackPlatform is the platform whose pricings are used for the instance types
+	// of an instance family without unreserved instances
+	savingsPlansFallbackPlatform = "Linux/UNIX"
+)
+
+var (
+	// SavingsPlansCoverageTargets are the coverage percentages for which a commitment is proposed
+	SavingsPlansCoverageTargets = []float64{50, 70, 80, 90, 100}
+)
+
+type (
+	// SavingsPlanScenario stores the outcome of an hourly commitment replayed
+	// over the on demand spend of the lookback period
+	SavingsPlanScenario struct {
+		CoverageTarget   float64 `json:"coverageTarget"`
+		Optimal          bool    `json:"optimal"`
+		HourlyCommitment float64 `json:"hourlyCommitment"`
+		Coverage         float64 `json:"coverage"`
+		Utilization      float64 `json:"utilization"`
+		MonthlyCost      float64 `json:"monthlyCost"`
+		MonthlySaving    float64 `json:"monthlySaving"`
+		TermSaving       float64 `json:"termSaving"`
+	}
+
+	// SavingsPlanOffer stores the scenarios of a Savings Plan for a given term.
+	// The plan breaks even as long as its utilization stays above BreakEvenUtilization.
+	SavingsPlanOffer struct {
+		Discount             float64               `json:"discount"`
+		BreakEvenUtilization float64               `json:"breakEvenUtilization"`
+		Scenarios            []SavingsPlanScenario `json:"scenarios"`
+	}
+
+	// SavingsPlanRecommendation stores the recommendations for a type of Savings Plan
+	SavingsPlanRecommendation struct {
+		AverageHourlyOnDemandCost float64          `json:"averageHourlyOnDemandCost"`
+		MinHourlyOnDemandCost     float64          `json:"minHourlyOnDemandCost"`
+		MaxHourlyOnDemandCost     float64          `json:"maxHourlyOnDemandCost"`
+		MonthlyOnDemandCost       float64          `json:"monthlyOnDemandCost"`
+		OneYear                   SavingsPlanOffer `json:"oneYear"`
+		ThreeYear                 SavingsPlanOffer `json:"threeYears"`
+	}
+
+	// Ec2InstanceSavingsPlanRecommendation stores the recommendations for the EC2 Instance
+	// Savings Plan of an instance family in a region
+	Ec2InstanceSavingsPlanRecommendation struct {
+		Region         string `json:"region"`
+		InstanceFamily string `json:"instanceFamily"`
+		SavingsPlanRecommendation
+	}
+
+	// SavingsPlansRecommendations stores the Compute and EC2 Instance Savings Plans
+	// recommendations of an account. EC2 Instance Savings Plans are recommended per
+	// instance family and region, which they are restricted to.
+	// The Savings Plans rates are not available in the EC2 pricings: they are estimated
+	// from the reservations rates, which EstimatedRates reminds. Error is set if the
+	// Savings Plans could not be sized.
+	SavingsPlansRecommendations struct {
+		LookbackDays   int                                    `json:"lookbackDays"`
+		EstimatedRates bool                                   `json:"estimatedRates"`
+		Error          string                                 `json:"error,omitempty"`
+		Compute        SavingsPlanRecommendation              `json:"compute"`
+		Ec2Instance    []Ec2InstanceSavingsPlanRecommendation `json:"ec2Instance"`
+	}
+
+	// instanceFamilyRegion identifies the instance family and the region an EC2 Instance
+	// Savings Plan applies to
+	instanceFamilyRegion struct {
+		region string
+		family string
+	}
+
+	// instanceFamilyUsage stores the hourly on demand costs of an instance family in a
+	// region and the instance types it was used with
+	instanceFamilyUsage struct {
+		hourlyCosts   []float64
+		instanceTypes map[string]bool
+	}
+
+	// savingsPlanRates stores the weighted on demand and Savings Plan hourly costs
+	// used to compute the discount of a Savings Plan
+	savingsPlanRates struct {
+		onDemand float64
+		plan     float64
+	}
+)
+
+// discount returns the discount of the Savings Plan over on demand, between 0 and 1
+func (r savingsPlanRates) discount() float64 {
+	if r.onDemand <= 0 || r.plan <= 0 || r.plan >= r.onDemand {
+		return 0
+	}
+	return 1 - r.plan/r.onDemand
+}
+
+// add adds the costs of an instance to the rates if the Savings Plan cost exists
+func (r *savingsPlanRates) add(onDemandCost, planCost float64, count int) {
+	if onDemandCost <= 0 || planCost <= 0 {
+		return
+	}
+	r.onDemand += onDemandCost * float64(count)
+	r.plan += planCost * float64(count)
+}
+
+// getComputeSavingsPlansDiscounts estimates the discounts of the Compute Savings Plans from the
+// pricings of the unreserved instances, weighted by their count. Compute Savings Plans are priced
+// like convertible reservations.
+func getComputeSavingsPlansDiscounts(unreservedInstances []InstancesSpecs, ec2Pricings pricings.EC2Pricing) (discount1yr, discount3yr float64) {
+	var r1, r3 savingsPlanRates
+	for _, spec := range unreservedInstances {
+		pricing, err := getPricingForSpecs(spec.Region, spec.Platform, spec.Type, ec2Pricings)
+		if err != nil {
+			continue
+		}
+		r1.add(pricing.OnDemandHourlyCost, pricing.OneYearConvertibleNoUpfrontHourlyCost, spec.InstanceCount)
+		r3.add(pricing.OnDemandHourlyCost, pricing.ThreeYearsConvertibleNoUpfrontHourlyCost, spec.InstanceCount)
+	}
+	return r1.discount(), r3.discount()
+}
+
+// getInstanceSavingsPlansDiscounts estimates the discounts of the EC2 Instance Savings Plan of an
+// instance family in a region, which is priced like standard reservations. The pricings of the
+// unreserved instances of the family are weighted by their count. Without unreserved instances,
+// the pricings of the instance types of the usage are used for savingsPlansFallbackPlatform.
+func getInstanceSavingsPlansDiscounts(key instanceFamilyRegion, usage instanceFamilyUsage, unreservedInstances []InstancesSpecs, ec2Pricings pricings.EC2Pricing) (discount1yr, discount3yr float64) {
+	var r1, r3 savingsPlanRates
+	for _, spec := range unreservedInstances {
+		if spec.Region != key.region || getInstanceFamily(spec.Type) != key.family {
+			continue
+		} else if pricing, err := getPricingForSpecs(spec.Region, spec.Platform, spec.Type, ec2Pricings); err == nil {
+			r1.add(pricing.OnDemandHourlyCost, pricing.OneYearStandardNoUpfrontHourlyCost, spec.InstanceCount)
+			r3.add(pricing.OnDemandHourlyCost, pricing.ThreeYearsStandardNoUpfrontHourlyCost, spec.InstanceCount)
+		}
+	}
+	if r1.onDemand == 0 && r3.onDemand == 0 {
+		for instanceType := range usage.instanceTypes {
+			if pricing, err := getPricingForSpecs(key.region, savingsPlansFallbackPlatform, instanceType, ec2Pricings); err == nil {
+				r1.add(pricing.OnDemandHourlyCost, pricing.OneYearStandardNoUpfrontHourlyCost, 1)
+				r3.add(pricing.OnDemandHourlyCost, pricing.ThreeYearsStandardNoUpfrontHourlyCost, 1)
+			}
+		}
+	}
+	return r1.discount(), r3.discount()
+}
+
+// getInstanceType returns the instance type of an EC2 usage type, such as m5.large for
+// EUW3-BoxUsage:m5.large. Usage types without instance type are for m1.small instances.
+func getInstanceType(usageType string) string {
+	if i := strings.LastIndex(usageType, ":"); i >= 0 {
+		return usageType[i+1:]
+	}
+	return "m1.small"
+}
+
+// getInstanceFamily returns the family of an instance type, such as m5 for m5.large
+func getInstanceFamily(instanceType string) string {
+	return strings.SplitN(instanceType, ".", 2)[0]
+}
+
+// getElasticSearchHourlyOnDemandParams builds the request summing the on demand spend
+// eligible to Savings Plans of an account for each hour between begin and end
+func getElasticSearchHourlyOnDemandParams(account string, begin, end time.Time, eligibleUsage elastic.Query, client *elastic.Client, index string) *elastic.SearchService {
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewTermQuery("usageAccountId", account)).
+		Filter(elastic.NewRangeQuery("usageStartDate").From(begin).To(end)).
+		Filter(eligibleUsage)
+	search := client.Search().Index(index).Size(0).Query(query)
+	search.Aggregation("hours", elastic.NewDateHistogramAggregation().Field("usageStartDate").Interval("hour").
+		SubAggregation("cost", elastic.NewSumAggregation().Field("unblendedCost")))
+	return search
+}
+
+// getElasticSearchHourlyInstanceCostsParams builds the request summing the on demand spend
+// of the EC2 instances of an account for each region, usage type and hour between begin and end
+func getElasticSearchHourlyInstanceCostsParams(account string, begin, end time.Time, client *elastic.Client, index string) *elastic.SearchService {
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewTermQuery("usageAccountId", account)).
+		Filter(elastic.NewRangeQuery("usageStartDate").From(begin).To(end)).
+		Filter(savingsPlans.EligibleUsageQuery().Filter(elastic.NewTermQuery("productCode", pricings.EC2ServiceCode)))
+	search := client.Search().Index(index).Size(0).Query(query)
+	search.Aggregation("regions", elastic.NewTermsAggregation().Field("region").Size(savingsPlansAggregationMaxSize).
+		SubAggregation("usageTypes", elastic.NewTermsAggregation().Field("usageType").Size(savingsPlansAggregationMaxSize).
+			SubAggregation("hours", elastic.NewDateHistogramAggregation().Field("usageStartDate").Interval("hour").
+				SubAggregation("cost", elastic.NewSumAggregation().Field("unblendedCost")))))
+	return search
+}
+
+// addHourlyCosts adds the costs of an hourly histogram to the costs of the period starting
+// at begin, buckets out of the period are ignored
+func addHourlyCosts(costs []float64, begin time.Time, histogram *elastic.AggregationBucketHistogramItems) {
+	for _, bucket := range histogram.Buckets {
+		hour := int(time.Unix(0, int64(bucket.Key)*int64(time.Millisecond)).UTC().Sub(begin) / time.Hour)
+		if hour < 0 || hour >= len(costs) {
+			continue
+		}
+		if cost, found := bucket.Aggregations.Sum("cost"); found && cost.Value != nil {
+			costs[hour] += *cost.Value
+		}
+	}
+}
+
+// getHourlyOnDemandCosts returns the on demand spend of each hour of the period
+// starting at begin, hours without spend are set to 0
+func getHourlyOnDemandCosts(ctx context.Context, aa aws.AwsAccount, begin time.Time, hours int, eligibleUsage elastic.Query) ([]float64, error) {
+	costs := make([]float64, hours)
+	end := begin.Add(time.Duration(hours) * time.Hour)
+	index := es.IndexNameForUserId(aa.UserId, s3.IndexPrefixLineItem)
+	res, err := getElasticSearchHourlyOnDemandParams(aa.AwsIdentity, begin, end, eligibleUsage, es.Client, index).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if histogram, found := res.Aggregations.DateHistogram("hours"); found {
+		addHourlyCosts(costs, begin, histogram)
+	}
+	return costs, nil
+}
+
+// getHourlyInstanceCosts returns the on demand spend of each hour of the period starting at
+// begin for each instance family and region, hours without spend are set to 0
+func getHourlyInstanceCosts(ctx context.Context, aa aws.AwsAccount, begin time.Time, hours int) (map[instanceFamilyRegion]instanceFamilyUsage, error) {
+	usages := make(map[instanceFamilyRegion]instanceFamilyUsage)
+	end := begin.Add(time.Duration(hours) * time.Hour)
+	index := es.IndexNameForUserId(aa.UserId, s3.IndexPrefixLineItem)
+	res, err := getElasticSearchHourlyInstanceCostsParams(aa.AwsIdentity, begin, end, es.Client, index).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	regions, found := res.Aggregations.Terms("regions")
+	if !found {
+		return usages, nil
+	}
+	for _, region := range regions.Buckets {
+		usageTypes, found := region.Aggregations.Terms("usageTypes")
+		if !found {
+			continue
+		}
+		for _, usageType := range usageTypes.Buckets {
+			histogram, found := usageType.Aggregations.DateHistogram("hours")
+			if !found {
+				continue
+			}
+			instanceType := getInstanceType(usageType.Key.(string))
+			key := instanceFamilyRegion{region.Key.(string), getInstanceFamily(instanceType)}
+			usage, ok := usages[key]
+			if !ok {
+				usage = instanceFamilyUsage{make([]float64, hours), make(map[string]bool)}
+				usages[key] = usage
+			}
+			usage.instanceTypes[instanceType] = true
+			addHourlyCosts(usage.hourlyCosts, begin, histogram)
+		}
+	}
+	return usages, nil
+}
+
+// simulateSavingsPlan replays an hourly commitment over the hourly on demand costs.
+// The commitment is expressed in Savings Plan rates, so it covers commitment/(1-discount)
+// of on demand spend each hour. The costs are scaled to a month.
+func simulateSavingsPlan(hourlyCosts []float64, discount, commitment float64, termMonths int) SavingsPlanScenario {
+	var total, covered float64
+	for _, cost := range hourlyCosts {
+		total += cost
+		covered += math.Min(cost, commitment/(1-discount))
+	}
+	hours := float64(len(hourlyCosts))
+	cost := commitment*hours + total - covered
+	scenario := SavingsPlanScenario{
+		HourlyCommitment: commitment,
+		MonthlyCost:      cost * HoursPerMonth / hours,
+		MonthlySaving:    (total - cost) * HoursPerMonth / hours,
+	}
+	if total > 0 {
+		scenario.Coverage = covered / total * 100
+	}
+	if commitment > 0 {
+		scenario.Utilization = covered * (1 - discount) / (commitment * hours) * 100
+	}
+	scenario.TermSaving = scenario.MonthlySaving * float64(termMonths)
+	return scenario
+}
+
+// getOptimalSavingsPlanScenario returns the scenario with the highest saving. The saving
+// being piecewise linear in the commitment, only the hourly costs need to be tried.
+func getOptimalSavingsPlanScenario(hourlyCosts []float64, discount float64, termMonths int) SavingsPlanScenario {
+	candidates := make([]float64, len(hourlyCosts))
+	copy(candidates, hourlyCosts)
+	sort.Float64s(candidates)
+	best := simulateSavingsPlan(hourlyCosts, discount, 0, termMonths)
+	for i, candidate := range candidates {
+		if i > 0 && candidate == candidates[i-1] {
+			continue
+		}
+		scenario := simulateSavingsPlan(hourlyCosts, discount, candidate*(1-discount), termMonths)
+		if scenario.MonthlySaving > best.MonthlySaving {
+			best = scenario
+		}
+	}
+	best.Optimal = true
+	return best
+}
+
+// getTargetSavingsPlanScenario returns the scenario of the lowest commitment reaching
+// the coverage target, found by dichotomy since the coverage grows with the commitment
+func getTargetSavingsPlanScenario(hourlyCosts []float64, discount, target float64, termMonths int) SavingsPlanScenario {
+	var low, high float64
+	for _, cost := range hourlyCosts {
+		high = math.Max(high, cost*(1-discount))
+	}
+	for i := 0; i < savingsPlansSearchIterations; i++ {
+		middle := (low + high) / 2
+		if simulateSavingsPlan(hourlyCosts, discount, middle, termMonths).Coverage >= target {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+	scenario := simulateSavingsPlan(hourlyCosts, discount, high, termMonths)
+	scenario.CoverageTarget = target
+	return scenario
+}
+
+// getSavingsPlanOffer computes the optimal scenario and the scenarios of each coverage target
+func getSavingsPlanOffer(hourlyCosts []float64, discount float64, termMonths int) SavingsPlanOffer {
+	offer := SavingsPlanOffer{
+		Discount:  discount * 100,
+		Scenarios: []SavingsPlanScenario{},
+	}
+	if discount <= 0 {
+		return offer
+	}
+	offer.BreakEvenUtilization = (1 - discount) * 100
+	offer.Scenarios = append(offer.Scenarios, getOptimalSavingsPlanScenario(hourlyCosts, discount, termMonths))
+	for _, target := range SavingsPlansCoverageTargets {
+		offer.Scenarios = append(offer.Scenarios, getTargetSavingsPlanScenario(hourlyCosts, discount, target, termMonths))
+	}
+	return offer
+}
+
+// getSavingsPlanRecommendation sizes a type of Savings Plan from the hourly on demand costs
+func getSavingsPlanRecommendation(hourlyCosts []float64, discount1yr, discount3yr float64) SavingsPlanRecommendation {
+	recommendation := SavingsPlanRecommendation{
+		OneYear:   SavingsPlanOffer{Scenarios: []SavingsPlanScenario{}},
+		ThreeYear: SavingsPlanOffer{Scenarios: []SavingsPlanScenario{}},
+	}
+	if len(hourlyCosts) == 0 {
+		return recommendation
+	}
+	var total float64
+	recommendation.MinHourlyOnDemandCost = hourlyCosts[0]
+	for _, cost := range hourlyCosts {
+		total += cost
+		recommendation.MinHourlyOnDemandCost = math.Min(recommendation.MinHourlyOnDemandCost, cost)
+		recommendation.MaxHourlyOnDemandCost = math.Max(recommendation.MaxHourlyOnDemandCost, cost)
+	}
+	recommendation.AverageHourlyOnDemandCost = total / float64(len(hourlyCosts))
+	recommendation.MonthlyOnDemandCost = recommendation.AverageHourlyOnDemandCost * HoursPerMonth
+	if total <= 0 {
+		return recommendation
+	}
+	recommendation.OneYear = getSavingsPlanOffer(hourlyCosts, discount1yr, 12)
+	recommendation.ThreeYear = getSavingsPlanOffer(hourlyCosts, discount3yr, 36)
+	return recommendation
+}
+
+// getSavingsPlansRecommendations sizes the Compute and EC2 Instance Savings Plans of an account
+// from its on demand spend over the last savingsPlansLookbackDays days. Compute Savings Plans
+// apply to all the eligible usage while EC2 Instance Savings Plans only apply to the EC2
+// instances of an instance family in a region, for which they are sized separately.
+func getSavingsPlansRecommendations(ctx context.Context, aa aws.AwsAccount, unreservedInstances []InstancesSpecs, ec2Pricings pricings.EC2Pricing) (SavingsPlansRecommendations, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	recommendations := SavingsPlansRecommendations{
+		LookbackDays:   savingsPlansLookbackDays,
+		EstimatedRates: true,
+		Ec2Instance:    []Ec2InstanceSavingsPlanRecommendation{},
+	}
+	now := time.Now().UTC()
+	begin := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -savingsPlansLookbackDays)
+	hours := savingsPlansLookbackDays * 24
+	computeCosts, err := getHourlyOnDemandCosts(ctx, aa, begin, hours, savingsPlans.EligibleUsageQuery())
+	if err != nil {
+		logger.Error("Failed to get on demand spend eligible to Compute Savings Plans", err.Error())
+		return recommendations, err
+	}
+	instanceUsages, err := getHourlyInstanceCosts(ctx, aa, begin, hours)
+	if err != nil {
+		logger.Error("Failed to get on demand spend eligible to EC2 Instance Savings Plans", err.Error())
+		return recommendations, err
+	}
+	compute1yr, compute3yr := getComputeSavingsPlansDiscounts(unreservedInstances, ec2Pricings)
+	recommendations.Compute = getSavingsPlanRecommendation(computeCosts, compute1yr, compute3yr)
+	for key, usage := range instanceUsages {
+		instance1yr, instance3yr := getInstanceSavingsPlansDiscounts(key, usage, unreservedInstances, ec2Pricings)
+		recommendations.Ec2Instance = append(recommendations.Ec2Instance, Ec2InstanceSavingsPlanRecommendation{
+			Region:                    key.region,
+			InstanceFamily:            key.family,
+			SavingsPlanRecommendation: getSavingsPlanRecommendation(usage.hourlyCosts, instance1yr, instance3yr),
+		})
+	}
+	sort.Slice(recommendations.Ec2Instance, func(i, j int) bool {
+		if recommendations.Ec2Instance[i].Region != recommendations.Ec2Instance[j].Region {
+			return recommendations.Ec2Instance[i].Region < recommendations.Ec2Instance[j].Region
+		}
+		return recommendations.Ec2Instance[i].InstanceFamily < recommendations.Ec2Instance[j].InstanceFamily
+	})
+	return recommendations, nil
+}
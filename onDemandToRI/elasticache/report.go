@@ -0,0 +1,119 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRiElastiCache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/pricings"
+	awsElastiCache "github.com/trackit/trackit/aws/usageReports/elasticache"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/onDemandToRI"
+	usageElastiCache "github.com/trackit/trackit/usageReports/elasticache"
+	"github.com/trackit/trackit/users"
+)
+
+// getElastiCacheReport retrieves the latest ElastiCache daily report
+func getElastiCacheReport(ctx context.Context, aa aws.AwsAccount) ([]usageElastiCache.InstanceReport, error) {
+	tx, err := db.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	user, err := users.GetUserWithId(tx, aa.UserId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	_, instances, err := usageElastiCache.GetElastiCacheDailyInstances(ctx, usageElastiCache.ElastiCacheQueryParams{
+		AccountList: []string{aa.AwsIdentity},
+		IndexList:   []string{es.IndexNameForUserId(aa.UserId, awsElastiCache.IndexPrefixElastiCacheReport)},
+		Date:        time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+	}, user, tx)
+	return instances, err
+}
+
+// fetchRegionReservations counts the active reserved cache nodes of a region
+func fetchRegionReservations(sess *session.Session, reservations onDemandToRI.Reservations) error {
+	svc := elasticache.New(sess)
+	return svc.DescribeReservedCacheNodesPages(&elasticache.DescribeReservedCacheNodesInput{},
+		func(page *elasticache.DescribeReservedCacheNodesOutput, lastPage bool) bool {
+			for _, reservation := range page.ReservedCacheNodes {
+				if awsSdk.StringValue(reservation.State) == "active" {
+					reservations.Add(awsSdk.StringValue(sess.Config.Region), strings.ToLower(awsSdk.StringValue(reservation.ProductDescription)),
+						awsSdk.StringValue(reservation.CacheNodeType), int(awsSdk.Int64Value(reservation.CacheNodeCount)))
+				}
+			}
+			return !lastPage
+		})
+}
+
+// getUnreservedInstances returns the list of the cache nodes without reservations
+func getUnreservedInstances(instancesReport []usageElastiCache.InstanceReport, reservations onDemandToRI.Reservations) []onDemandToRI.InstancesSpecs {
+	unreservedInstances := []onDemandToRI.InstancesSpecs{}
+	for _, instanceReport := range instancesReport {
+		instance := instanceReport.Instance
+		if instance.Status != "available" {
+			continue
+		}
+		for _, node := range instance.Nodes {
+			region := node.Region
+			if region == "" {
+				region = instance.Region
+			}
+			unreservedInstances = onDemandToRI.AddUnreservedInstances(unreservedInstances, reservations, onDemandToRI.GetRegionName(region),
+				strings.ToLower(instance.Engine), instance.NodeType, 1)
+		}
+	}
+	return unreservedInstances
+}
+
+// RunOnDemandToRiElastiCache generates a report listing the unreserved ElastiCache nodes and the
+// savings that can be done by buying reservations
+// The result is saved into ES
+func RunOnDemandToRiElastiCache(ctx context.Context, aa aws.AwsAccount) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	report := onDemandToRI.OdToRiReport{
+		Account:    aa.AwsIdentity,
+		ReportDate: time.Now().UTC(),
+		Service:    onDemandToRI.ServiceElastiCache,
+	}
+	logger.Info("Generating on demand to reserved instances ElastiCache report", map[string]interface{}{"awsAccountId": aa.Id})
+	instancesReport, err := getElastiCacheReport(ctx, aa)
+	if err != nil {
+		logger.Error("Unable to retrieve ElastiCache daily report", err.Error())
+		return err
+	}
+	reservations, err := onDemandToRI.FetchReservations(ctx, aa, fetchRegionReservations)
+	if err != nil {
+		return err
+	}
+	elastiCachePricings, err := onDemandToRI.GetInstancePricings(ctx, pricings.ElastiCacheServiceCode)
+	if err != nil {
+		logger.Error("Failed to retrieve ElastiCache pricings from database", err.Error())
+		return err
+	}
+	report = onDemandToRI.CalculateCosts(ctx, getUnreservedInstances(instancesReport, reservations), elastiCachePricings, report)
+	return onDemandToRI.IngestOdToRiResult(ctx, aa, report)
+}
@@ -0,0 +1,110 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRiEs
+
+import (
+	"context"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/pricings"
+	awsEs "github.com/trackit/trackit/aws/usageReports/es"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/onDemandToRI"
+	usageEs "github.com/trackit/trackit/usageReports/es"
+	"github.com/trackit/trackit/users"
+)
+
+// getEsReport retrieves the latest ES daily report
+func getEsReport(ctx context.Context, aa aws.AwsAccount) ([]usageEs.DomainReport, error) {
+	tx, err := db.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	user, err := users.GetUserWithId(tx, aa.UserId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	_, domains, err := usageEs.GetEsDailyDomains(ctx, usageEs.EsQueryParams{
+		AccountList: []string{aa.AwsIdentity},
+		IndexList:   []string{es.IndexNameForUserId(aa.UserId, awsEs.IndexPrefixESReport)},
+		Date:        time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+	}, user, tx)
+	return domains, err
+}
+
+// fetchRegionReservations counts the active reserved ES instances of a region
+func fetchRegionReservations(sess *session.Session, reservations onDemandToRI.Reservations) error {
+	svc := elasticsearchservice.New(sess)
+	return svc.DescribeReservedElasticsearchInstancesPages(&elasticsearchservice.DescribeReservedElasticsearchInstancesInput{},
+		func(page *elasticsearchservice.DescribeReservedElasticsearchInstancesOutput, lastPage bool) bool {
+			for _, reservation := range page.ReservedElasticsearchInstances {
+				if awsSdk.StringValue(reservation.State) == "active" {
+					reservations.Add(awsSdk.StringValue(sess.Config.Region), pricings.EsPricingEngine,
+						pricings.EsPricingType(awsSdk.StringValue(reservation.ElasticsearchInstanceType)),
+						int(awsSdk.Int64Value(reservation.ElasticsearchInstanceCount)))
+				}
+			}
+			return !lastPage
+		})
+}
+
+// getUnreservedInstances returns the list of the ES instances without reservations
+func getUnreservedInstances(domainsReport []usageEs.DomainReport, reservations onDemandToRI.Reservations) []onDemandToRI.InstancesSpecs {
+	unreservedInstances := []onDemandToRI.InstancesSpecs{}
+	for _, domainReport := range domainsReport {
+		domain := domainReport.Domain
+		unreservedInstances = onDemandToRI.AddUnreservedInstances(unreservedInstances, reservations, onDemandToRI.GetRegionName(domain.Region),
+			pricings.EsPricingEngine, pricings.EsPricingType(domain.InstanceType), int(domain.InstanceCount))
+	}
+	return unreservedInstances
+}
+
+// RunOnDemandToRiEs generates a report listing the unreserved ES instances and the
+// savings that can be done by buying reservations
+// The result is saved into ES
+func RunOnDemandToRiEs(ctx context.Context, aa aws.AwsAccount) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	report := onDemandToRI.OdToRiReport{
+		Account:    aa.AwsIdentity,
+		ReportDate: time.Now().UTC(),
+		Service:    onDemandToRI.ServiceEs,
+	}
+	logger.Info("Generating on demand to reserved instances ES report", map[string]interface{}{"awsAccountId": aa.Id})
+	domainsReport, err := getEsReport(ctx, aa)
+	if err != nil {
+		logger.Error("Unable to retrieve ES daily report", err.Error())
+		return err
+	}
+	reservations, err := onDemandToRI.FetchReservations(ctx, aa, fetchRegionReservations)
+	if err != nil {
+		return err
+	}
+	esPricings, err := onDemandToRI.GetInstancePricings(ctx, pricings.ESServiceCode)
+	if err != nil {
+		logger.Error("Failed to retrieve ES pricings from database", err.Error())
+		return err
+	}
+	report = onDemandToRI.CalculateCosts(ctx, getUnreservedInstances(domainsReport, reservations), esPricings, report)
+	return onDemandToRI.IngestOdToRiResult(ctx, aa, report)
+}
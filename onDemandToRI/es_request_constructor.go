@@ -0,0 +1,200 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRI
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	terrors "github.com/trackit/trackit/errors"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/users"
+)
+
+const maxAggregationSize = 0x7FFFFFFF
+
+type (
+	// OdToRiQueryParams will store the parsed query params
+	OdToRiQueryParams struct {
+		AccountList []string
+		IndexList   []string
+		Service     string
+		Date        time.Time
+	}
+
+	// ResponseOdToRiReports allows us to parse ES response for on demand to RI reports
+	ResponseOdToRiReports struct {
+		Accounts struct {
+			Buckets []struct {
+				Reports struct {
+					Hits struct {
+						Hits []struct {
+							Report OdToRiReport `json:"_source"`
+						} `json:"hits"`
+					} `json:"hits"`
+				} `json:"reports"`
+			} `json:"buckets"`
+		} `json:"accounts"`
+	}
+)
+
+// IngestOdToRiResult saves a OdToRiReport into elasticsearch
+func IngestOdToRiResult(ctx context.Context, aa aws.AwsAccount, report OdToRiReport) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	logger.Info("Saving od to ri result for AWS account.", map[string]interface{}{
+		"awsAccount": aa,
+		"service":    report.Service,
+	})
+	ji, err := json.Marshal(struct {
+		Account    string    `json:"account"`
+		Service    string    `json:"service"`
+		ReportDate time.Time `json:"reportDate"`
+	}{
+		report.Account,
+		report.Service,
+		report.ReportDate,
+	})
+	if err != nil {
+		logger.Error("Error when marshaling report var", err.Error())
+		return err
+	}
+	hash := md5.Sum(ji)
+	hash64 := base64.URLEncoding.EncodeToString(hash[:])
+	index := es.IndexNameForUserId(aa.UserId, IndexPrefixOdToRiReport)
+	if res, err := es.Client.
+		Index().
+		Index(index).
+		Type(TypeOdToRiReport).
+		BodyJson(report).
+		Id(hash64).
+		Do(ctx); err != nil {
+		logger.Error("Error when putting od to ri result in ES", err.Error())
+		return err
+	} else {
+		logger.Info("od to ri result put in ES", *res)
+	}
+	return nil
+}
+
+// getDateRange returns the begin and the end of the month of a date
+// the end is now if the date is in the current month
+func getDateRange(date time.Time) (begin, end time.Time) {
+	now := time.Now().UTC()
+	begin = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if date.Year() == now.Year() && date.Month() == now.Month() {
+		end = now
+	} else {
+		end = begin.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	}
+	return
+}
+
+// getElasticSearchOdToRiParams is used to construct an ElasticSearch *elastic.SearchService used to perform a request on ES
+// It keeps the latest report of the service of each account during the month of the date
+func getElasticSearchOdToRiParams(params OdToRiQueryParams, client *elastic.Client, index string) *elastic.SearchService {
+	query := elastic.NewBoolQuery()
+	if len(params.AccountList) > 0 {
+		accountList := make([]interface{}, len(params.AccountList))
+		for i, v := range params.AccountList {
+			accountList[i] = v
+		}
+		query = query.Filter(elastic.NewTermsQuery("account", accountList...))
+	}
+	query = query.Filter(elastic.NewTermQuery("service", params.Service))
+	dateBegin, dateEnd := getDateRange(params.Date)
+	query = query.Filter(elastic.NewRangeQuery("reportDate").From(dateBegin).To(dateEnd))
+	search := client.Search().Index(index).Size(0).Query(query)
+	search.Aggregation("accounts", elastic.NewTermsAggregation().Field("account").Size(maxAggregationSize).
+		SubAggregation("reports", elastic.NewTopHitsAggregation().Sort("reportDate", false).Size(1)))
+	return search
+}
+
+// makeElasticSearchRequest prepares and run an ES request based on the OdToRiQueryParams
+// It will return the data, an http status code (as int) and an error.
+// If the index does not exist yet, the error is returned with a 200 status code and no data.
+func makeElasticSearchRequest(ctx context.Context, parsedParams OdToRiQueryParams) (*elastic.SearchResult, int, error) {
+	l := jsonlog.LoggerFromContextOrDefault(ctx)
+	index := strings.Join(parsedParams.IndexList, ",")
+	res, err := getElasticSearchOdToRiParams(parsedParams, es.Client, index).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			l.Warning("Query execution failed, ES index does not exists", map[string]interface{}{
+				"index": index,
+				"error": err.Error(),
+			})
+			return nil, http.StatusOK, terrors.GetErrorMessage(ctx, err)
+		} else if cast, ok := err.(*elastic.Error); ok && cast.Details != nil && cast.Details.Type == "search_phase_execution_exception" {
+			l.Error("Error while getting data from ES", map[string]interface{}{
+				"type":  fmt.Sprintf("%T", err),
+				"error": err,
+			})
+		} else {
+			l.Error("Query execution failed", map[string]interface{}{"error": err.Error()})
+		}
+		return nil, http.StatusInternalServerError, terrors.GetErrorMessage(ctx, err)
+	}
+	return res, http.StatusOK, nil
+}
+
+// prepareResponseOdToRi parses the results from elasticsearch and returns an array of on demand to RI reports
+func prepareResponseOdToRi(ctx context.Context, res *elastic.SearchResult) ([]OdToRiReport, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	var response ResponseOdToRiReports
+	reports := make([]OdToRiReport, 0)
+	err := json.Unmarshal(*res.Aggregations["accounts"], &response.Accounts)
+	if err != nil {
+		logger.Error("Error while unmarshaling ES od to ri response", err)
+		return nil, terrors.GetErrorMessage(ctx, err)
+	}
+	for _, account := range response.Accounts.Buckets {
+		for _, report := range account.Reports.Hits.Hits {
+			reports = append(reports, report.Report)
+		}
+	}
+	return reports, nil
+}
+
+// GetOdToRiReports gets the on demand to RI reports of a service based on query params
+func GetOdToRiReports(ctx context.Context, parsedParams OdToRiQueryParams, user users.User, tx *sql.Tx) (int, []OdToRiReport, error) {
+	accountsAndIndexes, returnCode, err := es.GetAccountsAndIndexes(parsedParams.AccountList, user, tx, IndexPrefixOdToRiReport)
+	if err != nil {
+		return returnCode, nil, err
+	}
+	parsedParams.AccountList = accountsAndIndexes.Accounts
+	parsedParams.IndexList = accountsAndIndexes.Indexes
+	res, returnCode, err := makeElasticSearchRequest(ctx, parsedParams)
+	if err != nil {
+		return returnCode, nil, err
+	} else if res == nil {
+		return http.StatusInternalServerError, nil, errors.New("Error while getting data. Please check again in few hours.")
+	}
+	reports, err := prepareResponseOdToRi(ctx, res)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return http.StatusOK, reports, nil
+}
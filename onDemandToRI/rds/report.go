@@ -0,0 +1,121 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRiRds
+
+import (
+	"context"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/pricings"
+	awsRds "github.com/trackit/trackit/aws/usageReports/rds"
+	awsRiRds "github.com/trackit/trackit/aws/usageReports/riRdS"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/onDemandToRI"
+	"github.com/trackit/trackit/usageReports/rds"
+	"github.com/trackit/trackit/usageReports/riRds"
+	"github.com/trackit/trackit/users"
+)
+
+// rdsEngines maps the engines of the RDS API to the engines of the pricings
+// The engines whose price depends on a license edition are not supported
+var rdsEngines = map[string]string{
+	"mysql":             "MySQL",
+	"mariadb":           "MariaDB",
+	"postgres":          "PostgreSQL",
+	"postgresql":        "PostgreSQL",
+	"aurora":            "Aurora MySQL",
+	"aurora-mysql":      "Aurora MySQL",
+	"aurora-postgresql": "Aurora PostgreSQL",
+}
+
+// getReports retrieves the latest RDS and RDS reservations daily reports
+func getReports(ctx context.Context, aa aws.AwsAccount) ([]rds.InstanceReport, []riRds.ReservationReport, error) {
+	tx, err := db.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+	user, err := users.GetUserWithId(tx, aa.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now().UTC()
+	currentMonthBeginning := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	_, instances, err := rds.GetRdsDailyInstances(ctx, rds.RdsQueryParams{
+		AccountList: []string{aa.AwsIdentity},
+		IndexList:   []string{es.IndexNameForUserId(aa.UserId, awsRds.IndexPrefixRDSReport)},
+		Date:        currentMonthBeginning,
+	}, user, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, reservations, err := riRds.GetReservedInstancesDaily(ctx, riRds.ReservedInstancesQueryParams{
+		AccountList: []string{aa.AwsIdentity},
+		IndexList:   []string{es.IndexNameForUserId(aa.UserId, awsRiRds.IndexPrefixReservedRDSReport)},
+		Date:        currentMonthBeginning,
+	}, user, tx)
+	return instances, reservations, err
+}
+
+// getUnreservedInstances takes a list of instance reports and a list of reservation reports
+// It returns the list of instances without reservations
+func getUnreservedInstances(instancesReport []rds.InstanceReport, reservationsReport []riRds.ReservationReport) []onDemandToRI.InstancesSpecs {
+	reservations := onDemandToRI.Reservations{}
+	for _, reservationReport := range reservationsReport {
+		reservation := reservationReport.Reservation
+		if engine, ok := rdsEngines[reservation.ProductDescription]; ok && reservation.State == "active" {
+			reservations.Add(onDemandToRI.GetRegionName(reservation.AvailabilityZone), pricings.RdsPricingEngine(engine, reservation.MultiAZ),
+				reservation.DBInstanceClass, int(reservation.DBInstanceCount))
+		}
+	}
+	unreservedInstances := []onDemandToRI.InstancesSpecs{}
+	for _, instanceReport := range instancesReport {
+		instance := instanceReport.Instance
+		if engine, ok := rdsEngines[instance.Engine]; ok {
+			unreservedInstances = onDemandToRI.AddUnreservedInstances(unreservedInstances, reservations, onDemandToRI.GetRegionName(instance.AvailabilityZone),
+				pricings.RdsPricingEngine(engine, instance.MultiAZ), instance.DBInstanceClass, 1)
+		}
+	}
+	return unreservedInstances
+}
+
+// RunOnDemandToRiRds generates a report listing the unreserved RDS instances and the
+// savings that can be done by buying reservations
+// The result is saved into ES
+func RunOnDemandToRiRds(ctx context.Context, aa aws.AwsAccount) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	report := onDemandToRI.OdToRiReport{
+		Account:    aa.AwsIdentity,
+		ReportDate: time.Now().UTC(),
+		Service:    onDemandToRI.ServiceRds,
+	}
+	logger.Info("Generating on demand to reserved instances RDS report", map[string]interface{}{"awsAccountId": aa.Id})
+	instancesReport, reservationsReport, err := getReports(ctx, aa)
+	if err != nil {
+		logger.Error("Unable to retrieve RDS daily reports", err.Error())
+		return err
+	}
+	rdsPricings, err := onDemandToRI.GetInstancePricings(ctx, pricings.RDSServiceCode)
+	if err != nil {
+		logger.Error("Failed to retrieve RDS pricings from database", err.Error())
+		return err
+	}
+	report = onDemandToRI.CalculateCosts(ctx, getUnreservedInstances(instancesReport, reservationsReport), rdsPricings, report)
+	return onDemandToRI.IngestOdToRiResult(ctx, aa, report)
+}
@@ -0,0 +1,236 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package onDemandToRI contains what is shared by the on demand to reserved
+// instances reports of the managed services (RDS, ElastiCache and ES).
+package onDemandToRI
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws/pricings"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+)
+
+var (
+	HoursPerMonth = 730.0
+)
+
+type (
+	Cost struct {
+		PerUnit float64 `json:"perUnit"`
+		Total   float64 `json:"total"`
+	}
+
+	OnDemandCost struct {
+		Monthly    Cost `json:"monthly"`
+		OneYear    Cost `json:"oneYear"`
+		ThreeYears Cost `json:"threeYears"`
+	}
+
+	ReservationCost struct {
+		Monthly Cost `json:"monthly"`
+		Global  Cost `json:"global"`
+		Saving  Cost `json:"saving"`
+	}
+
+	OnDemandTotalCost struct {
+		MonthlyTotal    float64 `json:"monthly"`
+		OneYearTotal    float64 `json:"oneYear"`
+		ThreeYearsTotal float64 `json:"threeYears"`
+	}
+
+	ReservationTotalCost struct {
+		MonthlyTotal float64 `json:"monthly"`
+		GlobalTotal  float64 `json:"global"`
+		SavingTotal  float64 `json:"saving"`
+	}
+
+	// InstancesSpecs stores the costs calculated for a given region/engine/type
+	// combination
+	InstancesSpecs struct {
+		Region        string       `json:"region"`
+		Engine        string       `json:"engine"`
+		Type          string       `json:"instanceType"`
+		InstanceCount int          `json:"instanceCount"`
+		OnDemand      OnDemandCost `json:"onDemand"`
+		Reservation   struct {
+			Type      string          `json:"type"`
+			OneYear   ReservationCost `json:"oneYear"`
+			ThreeYear ReservationCost `json:"threeYears"`
+		} `json:"reservation"`
+	}
+
+	// OdToRiReport stores all the on demand to RI report infos of a service
+	OdToRiReport struct {
+		Account     string            `json:"account"`
+		ReportDate  time.Time         `json:"reportDate"`
+		Service     string            `json:"service"`
+		OnDemand    OnDemandTotalCost `json:"onDemand"`
+		Reservation struct {
+			OneYear   ReservationTotalCost `json:"oneYear"`
+			ThreeYear ReservationTotalCost `json:"threeYears"`
+		} `json:"reservation"`
+		Instances []InstancesSpecs `json:"instances"`
+	}
+
+	// Reservations counts the reserved instances by region, engine and type
+	Reservations map[string]int
+)
+
+// GetRegionName takes an availability zone or a region name and returns a region name
+func GetRegionName(az string) string {
+	if az == "" {
+		return az
+	} else if _, err := strconv.Atoi(string(az[len(az)-1])); err == nil {
+		// The "az" finishes by a number, so it's a region name
+		return az
+	}
+	return az[:len(az)-1]
+}
+
+// reservationKey returns the key of a region/engine/type combination in Reservations
+func reservationKey(region, engine, instanceType string) string {
+	return region + "|" + engine + "|" + instanceType
+}
+
+// Add adds count reserved instances for a region/engine/type combination
+func (r Reservations) Add(region, engine, instanceType string, count int) {
+	r[reservationKey(region, engine, instanceType)] += count
+}
+
+// take uses up to count reserved instances for a region/engine/type combination
+// and returns the number of instances which are not covered
+func (r Reservations) take(region, engine, instanceType string, count int) int {
+	key := reservationKey(region, engine, instanceType)
+	if r[key] >= count {
+		r[key] -= count
+		return 0
+	}
+	count -= r[key]
+	r[key] = 0
+	return count
+}
+
+// AddUnreservedInstances adds count running instances to the list of unreserved instances
+// once the matching reservations have been used up
+func AddUnreservedInstances(unreservedInstances []InstancesSpecs, reservations Reservations, region, engine, instanceType string, count int) []InstancesSpecs {
+	count = reservations.take(region, engine, instanceType, count)
+	if count <= 0 {
+		return unreservedInstances
+	}
+	for i, unreservedInstance := range unreservedInstances {
+		if unreservedInstance.Region == region && unreservedInstance.Engine == engine && unreservedInstance.Type == instanceType {
+			unreservedInstances[i].InstanceCount += count
+			return unreservedInstances
+		}
+	}
+	return append(unreservedInstances, InstancesSpecs{
+		Region:        region,
+		Engine:        engine,
+		Type:          instanceType,
+		InstanceCount: count,
+	})
+}
+
+// GetInstancePricings retrieves the pricings of a service from the database
+func GetInstancePricings(ctx context.Context, product string) (pricings.InstancePricing, error) {
+	instancePricings := pricings.InstancePricing{}
+	tx, err := db.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return instancePricings, err
+	}
+	defer tx.Rollback()
+	pricingDb, err := models.AwsPricingByProduct(tx, product)
+	if err != nil {
+		return instancePricings, err
+	}
+	err = json.Unmarshal(pricingDb.Pricing, &instancePricings)
+	return instancePricings, err
+}
+
+// getMonthlyCostPerUnit returns the monthly cost based on the hourlyCost
+// it returns 0.0 if the hourlyCost is -1.0 (which means the pricing term does not exist)
+func getMonthlyCostPerUnit(hourlyCost float64) float64 {
+	if hourlyCost != -1.0 {
+		return hourlyCost * HoursPerMonth
+	}
+	return 0.0
+}
+
+// getReservationCost returns the cost of a reservation and the saving compared to on demand
+// over a term of termMonths months
+func getReservationCost(odMonthly Cost, riMonthlyCostPerUnit float64, count int, termMonths float64) ReservationCost {
+	riMonthlyCostTotal := riMonthlyCostPerUnit * float64(count)
+	return ReservationCost{
+		Monthly: Cost{riMonthlyCostPerUnit, riMonthlyCostTotal},
+		Global:  Cost{riMonthlyCostPerUnit * termMonths, riMonthlyCostTotal * termMonths},
+		Saving:  Cost{(odMonthly.PerUnit - riMonthlyCostPerUnit) * termMonths, (odMonthly.Total - riMonthlyCostTotal) * termMonths},
+	}
+}
+
+// CalculateCosts calculates the on demand cost and the savings by switching to RI
+// Instances without a no upfront reservation for both terms are left out of the report
+func CalculateCosts(ctx context.Context, unreservedInstances []InstancesSpecs, instancePricings pricings.InstancePricing, report OdToRiReport) OdToRiReport {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	report.Instances = []InstancesSpecs{}
+	for _, unreservedSpec := range unreservedInstances {
+		pricing, err := instancePricings.GetSpecs(unreservedSpec.Region, unreservedSpec.Engine, unreservedSpec.Type)
+		if err != nil {
+			logger.Warning("Pricing not found", map[string]interface{}{
+				"error":   err.Error(),
+				"service": report.Service,
+				"region":  unreservedSpec.Region,
+				"engine":  unreservedSpec.Engine,
+				"type":    unreservedSpec.Type,
+			})
+			continue
+		} else if pricing.OneYearNoUpfrontHourlyCost == -1.0 && pricing.ThreeYearsNoUpfrontHourlyCost == -1.0 {
+			continue
+		}
+		count := float64(unreservedSpec.InstanceCount)
+		odMonthlyPerUnit := getMonthlyCostPerUnit(pricing.OnDemandHourlyCost)
+		odMonthly := Cost{odMonthlyPerUnit, odMonthlyPerUnit * count}
+		unreservedSpec.OnDemand = OnDemandCost{
+			Monthly:    odMonthly,
+			OneYear:    Cost{odMonthly.PerUnit * 12.0, odMonthly.Total * 12.0},
+			ThreeYears: Cost{odMonthly.PerUnit * 36.0, odMonthly.Total * 36.0},
+		}
+		report.OnDemand.MonthlyTotal += unreservedSpec.OnDemand.Monthly.Total
+		report.OnDemand.OneYearTotal += unreservedSpec.OnDemand.OneYear.Total
+		report.OnDemand.ThreeYearsTotal += unreservedSpec.OnDemand.ThreeYears.Total
+
+		unreservedSpec.Reservation.Type = unreservedSpec.Type
+		if pricing.OneYearNoUpfrontHourlyCost != -1.0 {
+			unreservedSpec.Reservation.OneYear = getReservationCost(odMonthly, getMonthlyCostPerUnit(pricing.OneYearNoUpfrontHourlyCost), unreservedSpec.InstanceCount, 12.0)
+			report.Reservation.OneYear.MonthlyTotal += unreservedSpec.Reservation.OneYear.Monthly.Total
+			report.Reservation.OneYear.GlobalTotal += unreservedSpec.Reservation.OneYear.Global.Total
+			report.Reservation.OneYear.SavingTotal += unreservedSpec.Reservation.OneYear.Saving.Total
+		}
+		if pricing.ThreeYearsNoUpfrontHourlyCost != -1.0 {
+			unreservedSpec.Reservation.ThreeYear = getReservationCost(odMonthly, getMonthlyCostPerUnit(pricing.ThreeYearsNoUpfrontHourlyCost), unreservedSpec.InstanceCount, 36.0)
+			report.Reservation.ThreeYear.MonthlyTotal += unreservedSpec.Reservation.ThreeYear.Monthly.Total
+			report.Reservation.ThreeYear.GlobalTotal += unreservedSpec.Reservation.ThreeYear.Global.Total
+			report.Reservation.ThreeYear.SavingTotal += unreservedSpec.Reservation.ThreeYear.Saving.Total
+		}
+		report.Instances = append(report.Instances, unreservedSpec)
+	}
+	return report
+}
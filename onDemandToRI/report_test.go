@@ -0,0 +1,218 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRI
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/trackit/trackit/aws/pricings"
+)
+
+type runningInstances struct {
+	region       string
+	engine       string
+	instanceType string
+	count        int
+}
+
+func TestAddUnreservedInstances(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		reservations Reservations
+		running      []runningInstances
+		expected     []InstancesSpecs
+		remaining    Reservations
+	}{
+		{
+			"no reservation",
+			Reservations{},
+			[]runningInstances{{"us-east-1", "mysql", "db.m5.large", 2}},
+			[]InstancesSpecs{{Region: "us-east-1", Engine: "mysql", Type: "db.m5.large", InstanceCount: 2}},
+			Reservations{},
+		},
+		{
+			"fully reserved",
+			Reservations{"us-east-1|mysql|db.m5.large": 3},
+			[]runningInstances{{"us-east-1", "mysql", "db.m5.large", 2}},
+			nil,
+			Reservations{"us-east-1|mysql|db.m5.large": 1},
+		},
+		{
+			"partially reserved",
+			Reservations{"us-east-1|mysql|db.m5.large": 1},
+			[]runningInstances{{"us-east-1", "mysql", "db.m5.large", 3}},
+			[]InstancesSpecs{{Region: "us-east-1", Engine: "mysql", Type: "db.m5.large", InstanceCount: 2}},
+			Reservations{"us-east-1|mysql|db.m5.large": 0},
+		},
+		{
+			"reservations used up across calls",
+			Reservations{"us-east-1|mysql|db.m5.large": 2},
+			[]runningInstances{
+				{"us-east-1", "mysql", "db.m5.large", 1},
+				{"us-east-1", "mysql", "db.m5.large", 2},
+				{"us-east-1", "mysql", "db.m5.large", 4},
+			},
+			[]InstancesSpecs{{Region: "us-east-1", Engine: "mysql", Type: "db.m5.large", InstanceCount: 5}},
+			Reservations{"us-east-1|mysql|db.m5.large": 0},
+		},
+		{
+			"reservations only cover their region, engine and type",
+			Reservations{"us-east-1|mysql|db.m5.large": 5},
+			[]runningInstances{
+				{"eu-west-1", "mysql", "db.m5.large", 1},
+				{"us-east-1", "postgres", "db.m5.large", 1},
+				{"us-east-1", "mysql", "db.r5.large", 1},
+			},
+			[]InstancesSpecs{
+				{Region: "eu-west-1", Engine: "mysql", Type: "db.m5.large", InstanceCount: 1},
+				{Region: "us-east-1", Engine: "postgres", Type: "db.m5.large", InstanceCount: 1},
+				{Region: "us-east-1", Engine: "mysql", Type: "db.r5.large", InstanceCount: 1},
+			},
+			Reservations{"us-east-1|mysql|db.m5.large": 5},
+		},
+	} {
+		var unreservedInstances []InstancesSpecs
+		for _, running := range c.running {
+			unreservedInstances = AddUnreservedInstances(unreservedInstances, c.reservations, running.region, running.engine, running.instanceType, running.count)
+		}
+		if !reflect.DeepEqual(unreservedInstances, c.expected) {
+			t.Errorf("%s: unreserved instances should be %v, are %v instead.", c.name, c.expected, unreservedInstances)
+		}
+		for key, count := range c.remaining {
+			if c.reservations[key] != count {
+				t.Errorf("%s: %d reservations should remain for %s, %d remain instead.", c.name, count, key, c.reservations[key])
+			}
+		}
+	}
+}
+
+func testInstancePricing() pricings.InstancePricing {
+	return pricings.InstancePricing{Region: map[string]pricings.InstanceEngine{
+		"us-east-1": {Engine: map[string]pricings.InstanceType{
+			"mysql": {Type: map[string]*pricings.InstanceSpecs{
+				"db.m5.large": {OnDemandHourlyCost: 0.1, OneYearNoUpfrontHourlyCost: 0.06, ThreeYearsNoUpfrontHourlyCost: 0.04},
+				"db.r5.large": {OnDemandHourlyCost: 0.2, OneYearNoUpfrontHourlyCost: 0.15, ThreeYearsNoUpfrontHourlyCost: -1.0},
+				"db.t2.micro": {OnDemandHourlyCost: 0.02, OneYearNoUpfrontHourlyCost: -1.0, ThreeYearsNoUpfrontHourlyCost: -1.0},
+			}},
+		}},
+	}}
+}
+
+func TestCalculateCosts(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		instanceType string
+		count        int
+		included     bool
+		odMonthly    Cost
+		oneYear      ReservationCost
+		threeYears   ReservationCost
+	}{
+		{
+			"both terms", "db.m5.large", 2, true,
+			Cost{73, 146},
+			ReservationCost{Cost{43.8, 87.6}, Cost{525.6, 1051.2}, Cost{350.4, 700.8}},
+			ReservationCost{Cost{29.2, 58.4}, Cost{1051.2, 2102.4}, Cost{1576.8, 3153.6}},
+		},
+		{
+			"one year term only", "db.r5.large", 1, true,
+			Cost{146, 146},
+			ReservationCost{Cost{109.5, 109.5}, Cost{1314, 1314}, Cost{438, 438}},
+			ReservationCost{},
+		},
+		{"no reservation", "db.t2.micro", 4, false, Cost{}, ReservationCost{}, ReservationCost{}},
+		{"no pricing", "db.x1.large", 1, false, Cost{}, ReservationCost{}, ReservationCost{}},
+	} {
+		unreservedInstances := []InstancesSpecs{{Region: "us-east-1", Engine: "mysql", Type: c.instanceType, InstanceCount: c.count}}
+		report := CalculateCosts(context.Background(), unreservedInstances, testInstancePricing(), OdToRiReport{Service: "RDS"})
+		if !c.included {
+			if len(report.Instances) != 0 || report.OnDemand != (OnDemandTotalCost{}) {
+				t.Errorf("%s: instance should be left out of the report, report is %v.", c.name, report)
+			}
+			continue
+		} else if len(report.Instances) != 1 {
+			t.Errorf("%s: report should have 1 instance, has %d instead.", c.name, len(report.Instances))
+			continue
+		}
+		instance := report.Instances[0]
+		for _, cost := range []struct {
+			name     string
+			actual   Cost
+			expected Cost
+		}{
+			{"on demand monthly", instance.OnDemand.Monthly, c.odMonthly},
+			{"on demand one year", instance.OnDemand.OneYear, Cost{c.odMonthly.PerUnit * 12, c.odMonthly.Total * 12}},
+			{"on demand three years", instance.OnDemand.ThreeYears, Cost{c.odMonthly.PerUnit * 36, c.odMonthly.Total * 36}},
+			{"one year monthly", instance.Reservation.OneYear.Monthly, c.oneYear.Monthly},
+			{"one year global", instance.Reservation.OneYear.Global, c.oneYear.Global},
+			{"one year saving", instance.Reservation.OneYear.Saving, c.oneYear.Saving},
+			{"three years monthly", instance.Reservation.ThreeYear.Monthly, c.threeYears.Monthly},
+			{"three years global", instance.Reservation.ThreeYear.Global, c.threeYears.Global},
+			{"three years saving", instance.Reservation.ThreeYear.Saving, c.threeYears.Saving},
+		} {
+			if !costEquals(cost.actual, cost.expected) {
+				t.Errorf("%s: %s cost should be %v, is %v instead.", c.name, cost.name, cost.expected, cost.actual)
+			}
+		}
+		if instance.Reservation.Type != c.instanceType {
+			t.Errorf("%s: reservation type should be %s, is %s instead.", c.name, c.instanceType, instance.Reservation.Type)
+		}
+		if !floatEquals(report.OnDemand.MonthlyTotal, c.odMonthly.Total) ||
+			!floatEquals(report.Reservation.OneYear.SavingTotal, c.oneYear.Saving.Total) ||
+			!floatEquals(report.Reservation.ThreeYear.SavingTotal, c.threeYears.Saving.Total) {
+			t.Errorf("%s: report totals do not match the instance costs: %v.", c.name, report)
+		}
+	}
+}
+
+func TestCalculateCostsTotals(t *testing.T) {
+	unreservedInstances := []InstancesSpecs{
+		{Region: "us-east-1", Engine: "mysql", Type: "db.m5.large", InstanceCount: 2},
+		{Region: "us-east-1", Engine: "mysql", Type: "db.r5.large", InstanceCount: 1},
+		{Region: "us-east-1", Engine: "mysql", Type: "db.t2.micro", InstanceCount: 4},
+	}
+	report := CalculateCosts(context.Background(), unreservedInstances, testInstancePricing(), OdToRiReport{Service: "RDS"})
+	if len(report.Instances) != 2 {
+		t.Fatalf("Report should have 2 instances, has %d instead.", len(report.Instances))
+	}
+	for _, c := range []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"on demand monthly", report.OnDemand.MonthlyTotal, 146 + 146},
+		{"on demand one year", report.OnDemand.OneYearTotal, (146 + 146) * 12},
+		{"on demand three years", report.OnDemand.ThreeYearsTotal, (146 + 146) * 36},
+		{"one year monthly", report.Reservation.OneYear.MonthlyTotal, 87.6 + 109.5},
+		{"one year saving", report.Reservation.OneYear.SavingTotal, 700.8 + 438},
+		{"three years global", report.Reservation.ThreeYear.GlobalTotal, 2102.4},
+		{"three years saving", report.Reservation.ThreeYear.SavingTotal, 3153.6},
+	} {
+		if !floatEquals(c.actual, c.expected) {
+			t.Errorf("Total %s should be %f, is %f instead.", c.name, c.expected, c.actual)
+		}
+	}
+}
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func costEquals(a, b Cost) bool {
+	return floatEquals(a.PerUnit, b.PerUnit) && floatEquals(a.Total, b.Total)
+}
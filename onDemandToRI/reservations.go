@@ -0,0 +1,64 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRI
+
+import (
+	"context"
+
+	awsSdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/usageReports"
+	"github.com/trackit/trackit/config"
+)
+
+const MonitorReservationStsSessionName = "monitor-reservation"
+
+// FetchReservations calls fetchRegion for each region of an AwsAccount to count its active
+// reservations, for the services whose reservations are not already saved in ES
+func FetchReservations(ctx context.Context, aa aws.AwsAccount, fetchRegion func(sess *session.Session, reservations Reservations) error) (Reservations, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	reservations := Reservations{}
+	creds, err := aws.GetTemporaryCredentials(aa, MonitorReservationStsSessionName)
+	if err != nil {
+		logger.Error("Error when getting temporary credentials", err.Error())
+		return reservations, err
+	}
+	defaultSession := session.Must(session.NewSession(&awsSdk.Config{
+		Credentials: creds,
+		Region:      awsSdk.String(config.AwsRegion),
+	}))
+	regions, err := utils.FetchRegionsList(ctx, defaultSession)
+	if err != nil {
+		logger.Error("Error when fetching regions list", err.Error())
+		return reservations, err
+	}
+	for _, region := range regions {
+		sess := session.Must(session.NewSession(&awsSdk.Config{
+			Credentials: creds,
+			Region:      awsSdk.String(region),
+		}))
+		if err := fetchRegion(sess, reservations); err != nil {
+			logger.Error("Error when describing reservations", map[string]interface{}{
+				"region": region,
+				"error":  err.Error(),
+			})
+			return reservations, err
+		}
+	}
+	return reservations, nil
+}
@@ -0,0 +1,86 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package onDemandToRI
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/trackit/trackit/cache"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+const (
+	ServiceRds         = "rds"
+	ServiceElastiCache = "elasticache"
+	ServiceEs          = "es"
+)
+
+var (
+	// odToRiQueryArgs allows to get required queryArgs params
+	odToRiQueryArgs = []routes.QueryArg{
+		routes.AwsAccountsOptionalQueryArg,
+		routes.DateQueryArg,
+	}
+
+	// serviceNames are the names of the services in the documentation of the routes
+	serviceNames = map[string]string{
+		ServiceRds:         "RDS",
+		ServiceElastiCache: "ElastiCache",
+		ServiceEs:          "ES",
+	}
+)
+
+func init() {
+	for _, service := range []string{ServiceRds, ServiceElastiCache, ServiceEs} {
+		routes.MethodMuxer{
+			http.MethodGet: routes.H(getOdToRiReports(service)).With(
+				db.RequestTransaction{Db: db.Db},
+				users.RequireAuthenticatedUser{users.ViewerAsParent},
+				routes.QueryArgs(odToRiQueryArgs),
+				users.RequirePermission{users.PermissionViewCosts},
+				cache.UsersCache{},
+				routes.Documentation{
+					Summary:     "get the " + serviceNames[service] + " reservation recommendations",
+					Description: "Responds with the unreserved " + serviceNames[service] + " instances and the savings that can be done by reserving them, for the month of the date passed as query param",
+				},
+			),
+		}.H().Register("/ri/" + service + "/recommendations")
+	}
+}
+
+// getOdToRiReports returns a handler responding with the on demand to RI reports of a service, in JSON format.
+func getOdToRiReports(service string) routes.SimpleHandler {
+	return func(request *http.Request, a routes.Arguments) (int, interface{}) {
+		user := a[users.AuthenticatedUser].(users.User)
+		tx := a[db.Transaction].(*sql.Tx)
+		parsedParams := OdToRiQueryParams{
+			AccountList: []string{},
+			Service:     service,
+			Date:        a[routes.DateQueryArg].(time.Time),
+		}
+		if a[routes.AwsAccountsOptionalQueryArg] != nil {
+			parsedParams.AccountList = a[routes.AwsAccountsOptionalQueryArg].([]string)
+		}
+		returnCode, reports, err := GetOdToRiReports(request.Context(), parsedParams, user, tx)
+		if err != nil {
+			return returnCode, err
+		}
+		return returnCode, reports
+	}
+}
@@ -0,0 +1,242 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package periodic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule tells when a task runs.
+type Schedule interface {
+	// Next returns the first time the task runs after t, or the zero time
+	// if it never does.
+	Next(t time.Time) time.Time
+	String() string
+}
+
+// Every is a Schedule running a task at a fixed period from the time its
+// Scheduler starts.
+type Every time.Duration
+
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+func (e Every) String() string {
+	return fmt.Sprintf("@every %s", time.Duration(e))
+}
+
+// cronField is the range of values of a field of a cron expression, and the
+// names they can be given.
+type cronField struct {
+	name  string
+	min   uint
+	max   uint
+	names map[string]uint
+}
+
+var (
+	cronMinute     = cronField{"minute", 0, 59, nil}
+	cronHour       = cronField{"hour", 0, 23, nil}
+	cronDayOfMonth = cronField{"day of month", 1, 31, nil}
+	cronMonth      = cronField{"month", 1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// cronDayOfWeek goes up to 7 since both 0 and 7 are Sunday.
+	cronDayOfWeek = cronField{"day of week", 0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronDescriptors are the shorthands for common cron expressions.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule is a Schedule running a task at the minutes matching a cron
+// expression in a time zone. Each field is a set of values stored as bits.
+type cronSchedule struct {
+	expression string
+	location   *time.Location
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// anyDay is true if either day field is a star. Otherwise, days
+	// matching either field match, as in cron.
+	anyDay bool
+}
+
+// ParseCron parses a cron expression evaluated in a time zone, nil meaning
+// UTC. The expression has the five usual fields (minute, hour, day of month,
+// month and day of week), each being a star or a list of values and ranges
+// with optional steps. Months and days of week can be given by their three
+// letter English names. The @yearly, @monthly, @weekly, @daily and @hourly
+// shorthands are supported, as well as "@every <duration>" which gives an
+// Every schedule. Times skipped by daylight saving time changes never match.
+func ParseCron(expression string, location *time.Location) (Schedule, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	spec := strings.TrimSpace(expression)
+	if strings.HasPrefix(spec, "@every ") {
+		period, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s", expression, err.Error())
+		} else if period <= 0 {
+			return nil, fmt.Errorf("invalid cron expression %q: period must be positive", expression)
+		}
+		return Every(period), nil
+	}
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expression, len(fields))
+	}
+	schedule := cronSchedule{
+		expression: expression,
+		location:   location,
+		anyDay:     strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*"),
+	}
+	var err error
+	for i, f := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{cronMinute, &schedule.minute},
+		{cronHour, &schedule.hour},
+		{cronDayOfMonth, &schedule.dayOfMonth},
+		{cronMonth, &schedule.month},
+		{cronDayOfWeek, &schedule.dayOfWeek},
+	} {
+		if *f.bits, err = f.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s", expression, err.Error())
+		}
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	return schedule, nil
+}
+
+// parse parses a field of a cron expression to the set of its values.
+func (f cronField) parse(spec string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(spec, ",") {
+		rangeSpec, step := part, uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangeSpec = part[:i]
+			s, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || s == 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, part)
+			}
+			step = uint(s)
+		}
+		var first, last uint
+		if rangeSpec == "*" {
+			first, last = f.min, f.max
+		} else if i := strings.Index(rangeSpec, "-"); i >= 0 {
+			var err error
+			if first, err = f.value(rangeSpec[:i]); err != nil {
+				return 0, err
+			} else if last, err = f.value(rangeSpec[i+1:]); err != nil {
+				return 0, err
+			} else if first > last {
+				return 0, fmt.Errorf("invalid range in %s field %q", f.name, part)
+			}
+		} else {
+			var err error
+			if first, err = f.value(rangeSpec); err != nil {
+				return 0, err
+			}
+			last = first
+			if step > 1 {
+				last = f.max
+			}
+		}
+		for v := first; v <= last; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value of a field of a cron expression.
+func (f cronField) value(spec string) (uint, error) {
+	if v, ok := f.names[strings.ToLower(spec)]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(spec, 10, 8)
+	if err != nil || uint(v) < f.min || uint(v) > f.max {
+		return 0, fmt.Errorf("invalid value in %s field %q, must be between %d and %d", f.name, spec, f.min, f.max)
+	}
+	return uint(v), nil
+}
+
+// cronSearchYears is how far in the future Next looks for a matching time,
+// so that expressions which never match, such as February 30th, end.
+const cronSearchYears = 5
+
+func (c cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(c.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronSearchYears
+	for t.Year() <= limit {
+		var next time.Time
+		if c.month&(1<<uint(t.Month())) == 0 {
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.location)
+		} else if !c.matchesDay(t) {
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.location)
+		} else if c.hour&(1<<uint(t.Hour())) == 0 {
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.location)
+		} else if c.minute&(1<<uint(t.Minute())) == 0 {
+			next = t.Add(time.Minute)
+		} else {
+			return t
+		}
+		// Daylight saving time changes can make the computed time go back.
+		if !next.After(t) {
+			next = t.Add(time.Minute)
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// matchesDay tells whether the day of t matches the day fields.
+func (c cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if c.anyDay {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func (c cronSchedule) String() string {
+	return fmt.Sprintf("%s (%s)", c.expression, c.location)
+}
@@ -0,0 +1,82 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package periodic
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("Time zone %s is unavailable: %s", name, err.Error())
+	}
+	return location
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@every -1m",
+		"@every soon",
+	} {
+		if _, err := ParseCron(expression, nil); err == nil {
+			t.Errorf("Cron expression %q should be invalid, is valid.", expression)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	paris := mustLoadLocation(t, "Europe/Paris")
+	for _, c := range []struct {
+		expression string
+		location   *time.Location
+		from       time.Time
+		expected   time.Time
+	}{
+		{"*/10 * * * *", nil, time.Date(2021, 3, 1, 10, 3, 20, 0, time.UTC), time.Date(2021, 3, 1, 10, 10, 0, 0, time.UTC)},
+		{"*/10 * * * *", nil, time.Date(2021, 3, 1, 10, 10, 0, 0, time.UTC), time.Date(2021, 3, 1, 10, 20, 0, 0, time.UTC)},
+		{"0 3 * * *", nil, time.Date(2021, 3, 1, 3, 0, 0, 0, time.UTC), time.Date(2021, 3, 2, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * *", paris, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)},
+		{"0 3 * * *", paris, time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 7, 1, 1, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", paris, time.Date(2021, 3, 27, 12, 0, 0, 0, time.UTC), time.Date(2021, 3, 29, 0, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * mon-fri", nil, time.Date(2021, 3, 5, 18, 0, 0, 0, time.UTC), time.Date(2021, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * 0", nil, time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", nil, time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", nil, time.Date(2021, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", nil, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 feb *", nil, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+		{"@every 90m", nil, time.Date(2021, 3, 1, 10, 3, 0, 0, time.UTC), time.Date(2021, 3, 1, 11, 33, 0, 0, time.UTC)},
+	} {
+		schedule, err := ParseCron(c.expression, c.location)
+		if err != nil {
+			t.Errorf("Failed to parse cron expression %q: %s", c.expression, err.Error())
+			continue
+		}
+		if next := schedule.Next(c.from); !next.Equal(c.expected) {
+			t.Errorf("Next run of %q after %s should be %s, is %s.", c.expression, c.from, c.expected, next)
+		}
+	}
+}
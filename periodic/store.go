@@ -0,0 +1,52 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package periodic
+
+import (
+	"time"
+)
+
+// DefaultLeaseDuration is the lease duration of a Scheduler which doesn't set
+// one.
+const DefaultLeaseDuration = 1 * time.Hour
+
+// TaskState is the state of a task shared between the replicas of a
+// Scheduler.
+type TaskState struct {
+	// LastRun is the planned time of the last claimed run.
+	LastRun time.Time
+	// LastFinished is the time the last run finished.
+	LastFinished time.Time
+	// LastError is the error of the last run, empty if it succeeded.
+	LastError string
+	// Holder is the replica holding the lease of the task, if LeaseUntil
+	// has not passed.
+	Holder     string
+	LeaseUntil time.Time
+}
+
+// Store shares the state of tasks between the replicas of a Scheduler, so
+// that each run of a task is made by a single replica.
+type Store interface {
+	// Claim claims the run of a task planned at a time for a holder, which
+	// leases the task until leaseUntil. The claim fails if a run was claimed
+	// after since, or if another holder has an unexpired lease.
+	Claim(name, holder string, planned, since, leaseUntil time.Time) (bool, error)
+	// Release ends the lease of a task, recording when and how its run
+	// finished.
+	Release(name, holder string, finished time.Time, runErr error) error
+	// State returns the state of a task, which is zero if it never ran.
+	State(name string) (TaskState, error)
+}
@@ -50,19 +50,47 @@ type Task func(context.Context) error
 
 // taskRegistration is a task registration that may or may not be ticking.
 type taskRegistration struct {
-	Name    string `json:"name"`
-	task    Task
-	Period  time.Duration
-	ticker  *time.Ticker
-	control chan taskSignal
+	Name      string `json:"name"`
+	task      Task
+	schedule  Schedule
+	scheduler *Scheduler
+	control   chan taskSignal
+	status    TaskStatus
+	mutex     sync.Mutex
+}
+
+// TaskStatus is the status of a registered task.
+type TaskStatus struct {
+	Name         string     `json:"name"`
+	Schedule     string     `json:"schedule"`
+	Running      bool       `json:"running"`
+	NextRun      *time.Time `json:"nextRun"`
+	LastRun      *time.Time `json:"lastRun"`
+	LastFinished *time.Time `json:"lastFinished"`
+	LastError    string     `json:"lastError"`
+	// Holder is the replica running the task, if a Store is used.
+	Holder string `json:"holder,omitempty"`
 }
 
 // Scheduler runs registered periodic tasks. Its zero value is a valid
 // Scheduler that doesn't tick and has no registered task. It may be used in
 // parallel.
+//
+// If several replicas of a Scheduler run the same tasks, they share a Store
+// so that each run is made by a single replica. Tasks with a Store also catch
+// up on a missed run when the Scheduler starts. The Store, Holder and
+// LeaseDuration fields must be set before tasks are registered.
 type Scheduler struct {
+	// Store, if set, shares the state of tasks between replicas.
+	Store Store
+	// Holder identifies the replica in the Store.
+	Holder string
+	// LeaseDuration is how long a replica can run a task before others may
+	// run it again. It defaults to DefaultLeaseDuration.
+	LeaseDuration time.Duration
+
 	running       bool
-	registrations []taskRegistration
+	registrations []*taskRegistration
 	mutex         sync.RWMutex
 }
 
@@ -76,15 +104,34 @@ func (s *Scheduler) Ticking() bool {
 // Register registers a Task to the Scheduler to be run at period p. If the
 // Scheduler is ticking, the task starts ticking immediately.
 func (s *Scheduler) Register(t Task, p time.Duration, n string) {
-	r := taskRegistration{
-		task:   t,
-		Period: p,
-		Name:   n,
+	s.RegisterSchedule(t, Every(p), n)
+}
+
+// RegisterCron registers a Task to the Scheduler to be run at the times
+// matching a cron expression in a time zone, as parsed by ParseCron.
+func (s *Scheduler) RegisterCron(t Task, expression string, location *time.Location, n string) error {
+	schedule, err := ParseCron(expression, location)
+	if err != nil {
+		return err
+	}
+	s.RegisterSchedule(t, schedule, n)
+	return nil
+}
+
+// RegisterSchedule registers a Task to the Scheduler to be run according to
+// a Schedule. If the Scheduler is ticking, the task starts ticking
+// immediately.
+func (s *Scheduler) RegisterSchedule(t Task, schedule Schedule, n string) {
+	r := &taskRegistration{
+		task:      t,
+		schedule:  schedule,
+		scheduler: s,
+		Name:      n,
 	}
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	if s.running {
-		r.start()
+		r.start(time.Now())
 	}
 	s.registrations = append(s.registrations, r)
 }
@@ -97,8 +144,9 @@ func (s *Scheduler) Start() {
 	if s.running {
 		jsonlog.Error("Attempt to start already started scheduler. Ignoring.", nil)
 	} else {
-		for i := range s.registrations {
-			s.registrations[i].start()
+		now := time.Now()
+		for _, r := range s.registrations {
+			r.start(now)
 		}
 		s.running = true
 	}
@@ -110,25 +158,87 @@ func (s *Scheduler) Stop() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	if s.running {
-		for i := range s.registrations {
-			s.registrations[i].stop()
+		for _, r := range s.registrations {
+			r.stop()
 		}
 		s.running = false
 	}
 }
 
+// Tasks returns the status of the registered tasks. If the Scheduler has a
+// Store, the last runs are the ones of all replicas.
+func (s *Scheduler) Tasks() ([]TaskStatus, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	statuses := make([]TaskStatus, 0, len(s.registrations))
+	for _, r := range s.registrations {
+		status := r.getStatus()
+		if s.Store != nil {
+			state, err := s.Store.State(r.Name)
+			if err != nil {
+				return nil, err
+			}
+			status.setState(state)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// setState sets the last run of a TaskStatus from the state of the task in
+// a Store.
+func (ts *TaskStatus) setState(state TaskState) {
+	ts.LastRun, ts.LastFinished, ts.LastError, ts.Holder, ts.Running = nil, nil, state.LastError, "", false
+	if !state.LastRun.IsZero() {
+		ts.LastRun = &state.LastRun
+	}
+	if !state.LastFinished.IsZero() {
+		ts.LastFinished = &state.LastFinished
+	}
+	if state.LeaseUntil.After(time.Now()) {
+		ts.Holder = state.Holder
+		ts.Running = true
+	}
+}
+
 // start starts a taskRegistration, having it tick and run its task
-// periodically.
-func (t *taskRegistration) start() {
+// periodically from now. If the task missed a run while no replica was
+// ticking, it is run immediately.
+func (t *taskRegistration) start(now time.Time) {
 	if t.control == nil {
-		t.ticker = time.NewTicker(t.Period)
 		t.control = make(chan taskSignal)
-		go t.tick()
+		t.catchUp(now)
+		go t.tick(now)
 	} else {
 		jsonlog.Error("Attempt to start already started task. Ignoring.", t)
 	}
 }
 
+// catchUp runs the task now if, according to the Store, its schedule planned
+// a run after the last one which should have already happened. Several
+// missed runs result in a single one.
+func (t *taskRegistration) catchUp(now time.Time) {
+	if t.scheduler.Store == nil {
+		return
+	}
+	state, err := t.scheduler.Store.State(t.Name)
+	if err != nil {
+		jsonlog.DefaultLogger.Error("Failed to get periodic task state", map[string]interface{}{
+			"task":  t.Name,
+			"error": err.Error(),
+		})
+	} else if state.LastRun.IsZero() {
+		return
+	} else if next := t.schedule.Next(state.LastRun); !next.IsZero() && next.Before(now) {
+		jsonlog.DefaultLogger.Info("Catching up on missed periodic task run", map[string]interface{}{
+			"task":    t.Name,
+			"lastRun": state.LastRun,
+			"missed":  next,
+		})
+		go t.runScheduled(now, state.LastRun)
+	}
+}
+
 // run runs the taskRegistration's task in the current goroutine.
 func (t *taskRegistration) run(d time.Time) error {
 	ctx := context.Background()
@@ -136,38 +246,79 @@ func (t *taskRegistration) run(d time.Time) error {
 	return t.task(ctx)
 }
 
-// getTickerChan gets the taskRegistration's ticker channel, creating it if it
-// doesn’t exist yet. This is the channel where a time.Ticker outputs to run
-// the periodic tasks.
-func (t *taskRegistration) getTickerChan() <-chan time.Time {
-	c := t.ticker
-	if c == nil {
-		return nil
-	} else {
-		return c.C
+// runScheduled runs the task for its run planned at a time, after its
+// previous planned run at since. If the Scheduler has a Store, the run is
+// first claimed so that no other replica makes it.
+func (t *taskRegistration) runScheduled(planned, since time.Time) {
+	logger := jsonlog.DefaultLogger
+	store := t.scheduler.Store
+	if store != nil {
+		leaseDuration := t.scheduler.LeaseDuration
+		if leaseDuration == 0 {
+			leaseDuration = DefaultLeaseDuration
+		}
+		claimed, err := store.Claim(t.Name, t.scheduler.Holder, planned, since, time.Now().Add(leaseDuration))
+		if err != nil {
+			logger.Error("Failed to claim periodic task run", map[string]interface{}{
+				"task":  t.Name,
+				"error": err.Error(),
+			})
+			return
+		} else if !claimed {
+			logger.Debug("Periodic task run claimed by another replica", map[string]interface{}{
+				"task":    t.Name,
+				"planned": planned,
+			})
+			return
+		}
+	}
+	t.setRunning(planned)
+	err := t.run(planned)
+	finished := time.Now()
+	t.setFinished(finished, err)
+	if err != nil {
+		logger.Error("Error while running periodic task", map[string]interface{}{
+			"task":  t.Name,
+			"error": err.Error(),
+		})
+	}
+	if store != nil {
+		if err := store.Release(t.Name, t.scheduler.Holder, finished, err); err != nil {
+			logger.Error("Failed to release periodic task", map[string]interface{}{
+				"task":  t.Name,
+				"error": err.Error(),
+			})
+		}
 	}
 }
 
-// tick starts periodic tasks in response to ticks from t.ticker. The tasks are
-// started in their own goroutine using t.run.
-func (t *taskRegistration) tick() {
+// tick starts periodic tasks at the times planned by the schedule, starting
+// from start. The tasks are started in their own goroutine using
+// t.runScheduled.
+func (t *taskRegistration) tick(start time.Time) {
+	previous := start
 	for {
+		next := t.schedule.Next(previous)
+		t.setNextRun(next)
+		var timer *time.Timer
+		var timerChan <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			timerChan = timer.C
+		}
 		select {
-		case d := <-t.getTickerChan():
-			go func() {
-				if err := t.run(d); err != nil {
-					jsonlog.DefaultLogger.Error("Error while running periodic task", map[string]interface{}{
-						"error": err.Error(),
-					})
-				}
-			}()
+		case <-timerChan:
+			go t.runScheduled(next, previous)
+			previous = next
 		case s := <-t.control:
 			switch s {
 			case taskStop:
+				if timer != nil {
+					timer.Stop()
+				}
 				close(t.control)
 				t.control = nil
-				t.ticker.Stop()
-				t.ticker = nil
+				t.setNextRun(time.Time{})
 				return
 			}
 		}
@@ -183,3 +334,40 @@ func (t *taskRegistration) stop() {
 		jsonlog.Error("Attempt to stop an already stopped task. Ignoring.", t)
 	}
 }
+
+// getStatus returns the status of the task as seen by the current replica.
+func (t *taskRegistration) getStatus() TaskStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	status := t.status
+	status.Name = t.Name
+	status.Schedule = t.schedule.String()
+	return status
+}
+
+func (t *taskRegistration) setNextRun(next time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.status.NextRun = nil
+	if !next.IsZero() {
+		t.status.NextRun = &next
+	}
+}
+
+func (t *taskRegistration) setRunning(planned time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.status.Running = true
+	t.status.LastRun = &planned
+}
+
+func (t *taskRegistration) setFinished(finished time.Time, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.status.Running = false
+	t.status.LastFinished = &finished
+	t.status.LastError = ""
+	if err != nil {
+		t.status.LastError = err.Error()
+	}
+}
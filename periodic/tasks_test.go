@@ -17,6 +17,7 @@ package periodic
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -110,3 +111,99 @@ out:
 		t.Errorf("Expected task count to be %#v, is %#v.", be, b)
 	}
 }
+
+// memoryStore is a Store shared by Schedulers in the same process.
+type memoryStore struct {
+	states map[string]TaskState
+	mutex  sync.Mutex
+}
+
+func (m *memoryStore) Claim(name, holder string, planned, since, leaseUntil time.Time) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	state := m.states[name]
+	if state.LastRun.After(since) || (state.Holder != holder && state.LeaseUntil.After(time.Now())) {
+		return false, nil
+	}
+	state.LastRun, state.Holder, state.LeaseUntil = planned, holder, leaseUntil
+	m.states[name] = state
+	return true, nil
+}
+
+func (m *memoryStore) Release(name, holder string, finished time.Time, runErr error) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	state := m.states[name]
+	state.LastFinished, state.LeaseUntil, state.LastError = finished, time.Time{}, ""
+	if runErr != nil {
+		state.LastError = runErr.Error()
+	}
+	m.states[name] = state
+	return nil
+}
+
+func (m *memoryStore) State(name string) (TaskState, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.states[name], nil
+}
+
+func TestSharedStoreRunsTaskOnce(t *testing.T) {
+	store := &memoryStore{states: map[string]TaskState{}}
+	var a int
+	c := make(chan int)
+	s := [2]Scheduler{{Store: store, Holder: "first"}, {Store: store, Holder: "second"}}
+	for i := range s {
+		s[i].Register(messageTask(c, i), 100*time.Millisecond, "Tenth of a second")
+		s[i].Start()
+		time.Sleep(20 * time.Millisecond)
+	}
+	e := time.After(330 * time.Millisecond)
+out:
+	for {
+		select {
+		case <-c:
+			a++
+		case <-e:
+			break out
+		}
+	}
+	for i := range s {
+		s[i].Stop()
+	}
+	if a != 3 {
+		t.Errorf("Task should run %d times, ran %d times.", 3, a)
+	}
+}
+
+func TestSharedStoreCatchesUp(t *testing.T) {
+	store := &memoryStore{states: map[string]TaskState{
+		"Hourly": {LastRun: time.Now().Add(-2 * time.Hour)},
+		"Daily":  {LastRun: time.Now().Add(-2 * time.Hour)},
+	}}
+	c := make(chan int)
+	s := Scheduler{Store: store, Holder: "first"}
+	s.Register(messageTask(c, 0), Hourly, "Hourly")
+	s.Register(messageTask(c, 1), Daily, "Daily")
+	s.Start()
+	defer s.Stop()
+	select {
+	case i := <-c:
+		if i != 0 {
+			t.Errorf("Only the task which missed a run should catch up.")
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Task which missed a run should catch up, did not.")
+	}
+	select {
+	case <-c:
+		t.Errorf("Several missed runs should result in a single one.")
+	case <-time.After(100 * time.Millisecond):
+	}
+	tasks, err := s.Tasks()
+	if err != nil {
+		t.Fatalf("Failed to get tasks: %s", err.Error())
+	} else if len(tasks) != 2 || tasks[0].LastFinished == nil || tasks[0].NextRun == nil {
+		t.Errorf("Caught up task should have a last and a next run, got %#v.", tasks)
+	}
+}
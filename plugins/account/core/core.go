@@ -33,6 +33,10 @@ type AccountPlugin struct {
 	Label           string
 	Func            PluginFunc
 	BillingDataOnly bool
+	// PrepareResult sets the Result and Status of a PluginResult from its counts,
+	// and the Details of the plugins which build them from the findings
+	// It is called again by ApplySuppressions once the suppressed findings are passed
+	PrepareResult func(*PluginResult)
 }
 
 // PluginParams is the struct that is passed as a parameter for each plugin
@@ -45,14 +49,48 @@ type PluginParams struct {
 	ESClient           *elastic.Client
 }
 
+// Severities of a Finding
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// Finding is a resource reported by a plugin
+type Finding struct {
+	ResourceId              string  `json:"resourceId"`
+	Region                  string  `json:"region"`
+	ResourceType            string  `json:"resourceType"`
+	Severity                string  `json:"severity"`
+	EstimatedMonthlySavings float64 `json:"estimatedMonthlySavings"`
+	Remediation             string  `json:"remediation"`
+	// AvailabilityZone is only set for zonal resources
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	// Detail is the line of the Details of the result naming the resource,
+	// removed from them when the finding is suppressed
+	Detail string `json:"detail,omitempty"`
+}
+
 // PluginResult is the struct that each plugin should return
 type PluginResult struct {
-	Result  string
-	Status  string
-	Details []string
-	Error   string
-	Checked int
-	Passed  int
+	Result   string
+	Status   string
+	Details  []string
+	Error    string
+	Checked  int
+	Passed   int
+	Findings []Finding
+	// SuppressedFindings is set by ApplySuppressions
+	SuppressedFindings []Finding
+}
+
+// EstimatedMonthlySavings returns the sum of the estimated monthly savings of the findings
+func (pr PluginResult) EstimatedMonthlySavings() float64 {
+	savings := 0.0
+	for _, finding := range pr.Findings {
+		savings += finding.EstimatedMonthlySavings
+	}
+	return savings
 }
 
 // PluginResultES is the struct used to save a plugin result into elaticsearch
@@ -69,6 +107,11 @@ type PluginResultES struct {
 	Error            string    `json:"error"`
 	Checked          int       `json:"checked"`
 	Passed           int       `json:"passed"`
+	// Findings and EstimatedMonthlySavings are not set by plugins which
+	// only report Details
+	Findings                []Finding `json:"findings"`
+	EstimatedMonthlySavings float64   `json:"estimatedMonthlySavings"`
+	SuppressedFindings      []Finding `json:"suppressedFindings"`
 }
 
 // PluginFunc is the type that should be implemented by the plugin's function
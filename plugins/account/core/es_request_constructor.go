@@ -15,6 +15,8 @@
 package plugins_account_core
 
 import (
+	"time"
+
 	"github.com/olivere/elastic"
 )
 
@@ -49,3 +51,28 @@ func GetElasticSearchPluginsParams(accountList []string, client *elastic.Client,
 		SubAggregation("top_reports_hits", elastic.NewTopHitsAggregation().Sort("reportDate", false).Size(1)))
 	return search
 }
+
+// GetElasticSearchPluginsHistoryParams is used to construct an ElasticSearch *elastic.SearchService retrieving
+// the results of every plugin between begin and end, grouped by interval
+// In each interval, only the latest result of each account is kept
+// It takes the same parameters as GetElasticSearchPluginsParams, along with:
+//	- begin time.Time : the beginning of the date range
+//	- end time.Time : the end of the date range
+//	- interval string : the size of the buckets of the date histogram (day, week or month)
+func GetElasticSearchPluginsHistoryParams(accountList []string, client *elastic.Client, index string,
+	begin, end time.Time, interval string) *elastic.SearchService {
+	query := elastic.NewBoolQuery()
+	if len(accountList) > 0 {
+		query = query.Filter(createQueryAccountFilterPlugins(accountList))
+	}
+	query = query.Filter(elastic.NewRangeQuery("reportDate").
+		From(begin).To(end))
+	search := client.Search().Index(index).Size(0).Query(query)
+	search.Aggregation("plugins", elastic.NewTermsAggregation().Field("pluginName").Size(maxAggregationSize).
+		SubAggregation("dates", elastic.NewDateHistogramAggregation().Field("reportDate").Interval(interval).
+			SubAggregation("accounts", elastic.NewTermsAggregation().Field("account").Size(maxAggregationSize).
+				SubAggregation("latest_result", elastic.NewTopHitsAggregation().Sort("reportDate", false).Size(1).
+					FetchSourceContext(elastic.NewFetchSourceContext(true).
+						Include("pluginName", "category", "label", "checked", "passed", "estimatedMonthlySavings"))))))
+	return search
+}
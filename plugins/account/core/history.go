@@ -0,0 +1,182 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/users"
+)
+
+// HistoryIntervals are the intervals supported by the plugins history
+var HistoryIntervals = []string{"day", "week", "month"}
+
+type (
+	// PluginsHistoryQueryParams are the parameters of a plugins history request
+	PluginsHistoryQueryParams struct {
+		AccountList []string
+		IndexList   []string
+		Begin       time.Time
+		End         time.Time
+		Interval    string
+	}
+
+	// PluginHistoryPoint sums the latest results of the accounts during an interval
+	PluginHistoryPoint struct {
+		Date                    time.Time `json:"date"`
+		Checked                 int       `json:"checked"`
+		Passed                  int       `json:"passed"`
+		EstimatedMonthlySavings float64   `json:"estimatedMonthlySavings"`
+	}
+
+	// PluginHistory is the evolution of the results of a plugin
+	PluginHistory struct {
+		PluginName string               `json:"pluginName"`
+		Category   string               `json:"category"`
+		Label      string               `json:"label"`
+		Points     []PluginHistoryPoint `json:"points"`
+	}
+
+	// responsePluginsHistory is used to parse the ES response of a plugins history request
+	responsePluginsHistory struct {
+		Buckets []struct {
+			Key   string `json:"key"`
+			Dates struct {
+				Buckets []struct {
+					Key      float64 `json:"key"`
+					Accounts struct {
+						Buckets []struct {
+							LatestResult struct {
+								Hits struct {
+									Hits []struct {
+										Result PluginResultES `json:"_source"`
+									} `json:"hits"`
+								} `json:"hits"`
+							} `json:"latest_result"`
+						} `json:"buckets"`
+					} `json:"accounts"`
+				} `json:"buckets"`
+			} `json:"dates"`
+		} `json:"buckets"`
+	}
+)
+
+// isValidHistoryInterval returns true if the interval is one of HistoryIntervals
+func isValidHistoryInterval(interval string) bool {
+	for _, validInterval := range HistoryIntervals {
+		if interval == validInterval {
+			return true
+		}
+	}
+	return false
+}
+
+// makeElasticSearchPluginsHistoryRequest prepares and runs the request to retrieve the plugins history
+// It will return the data, an http status code (as int) and an error.
+// If the index does not exist, a nil result is returned with a 200 status code
+func makeElasticSearchPluginsHistoryRequest(ctx context.Context, params PluginsHistoryQueryParams) (*elastic.SearchResult, int, error) {
+	l := jsonlog.LoggerFromContextOrDefault(ctx)
+	index := strings.Join(params.IndexList, ",")
+	searchService := GetElasticSearchPluginsHistoryParams(
+		params.AccountList,
+		es.Client,
+		index,
+		params.Begin,
+		params.End,
+		params.Interval,
+	)
+	res, err := searchService.Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			l.Warning("Query execution failed, ES index does not exists : "+index, err)
+			return nil, http.StatusOK, nil
+		}
+		l.Error("Query execution failed : "+err.Error(), nil)
+		return nil, http.StatusInternalServerError, fmt.Errorf("could not execute the ElasticSearch query")
+	}
+	return res, http.StatusOK, nil
+}
+
+// parsePluginsHistory parses the ES response of a plugins history request
+// The intervals without any result are skipped
+func parsePluginsHistory(res *elastic.SearchResult) ([]PluginHistory, error) {
+	history := []PluginHistory{}
+	var parsed responsePluginsHistory
+	if res == nil || res.Aggregations["plugins"] == nil {
+		return history, nil
+	} else if err := json.Unmarshal(*res.Aggregations["plugins"], &parsed); err != nil {
+		return nil, err
+	}
+	for _, plugin := range parsed.Buckets {
+		pluginHistory := PluginHistory{
+			PluginName: plugin.Key,
+			Points:     []PluginHistoryPoint{},
+		}
+		for _, date := range plugin.Dates.Buckets {
+			if len(date.Accounts.Buckets) == 0 {
+				continue
+			}
+			point := PluginHistoryPoint{Date: time.Unix(0, int64(date.Key)*int64(time.Millisecond)).UTC()}
+			for _, account := range date.Accounts.Buckets {
+				for _, hit := range account.LatestResult.Hits.Hits {
+					pluginHistory.Category = hit.Result.Category
+					pluginHistory.Label = hit.Result.Label
+					point.Checked += hit.Result.Checked
+					point.Passed += hit.Result.Passed
+					point.EstimatedMonthlySavings += hit.Result.EstimatedMonthlySavings
+				}
+			}
+			pluginHistory.Points = append(pluginHistory.Points, point)
+		}
+		history = append(history, pluginHistory)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].PluginName < history[j].PluginName })
+	return history, nil
+}
+
+// GetPluginsHistory returns the evolution of the results of every plugin between
+// params.Begin and params.End, for the accounts of params.AccountList
+func GetPluginsHistory(ctx context.Context, params PluginsHistoryQueryParams, user users.User, tx *sql.Tx) (int, []PluginHistory, error) {
+	if !isValidHistoryInterval(params.Interval) {
+		return http.StatusBadRequest, nil, fmt.Errorf("interval must be one of %s", strings.Join(HistoryIntervals, ", "))
+	}
+	accountsAndIndexes, returnCode, err := es.GetAccountsAndIndexes(params.AccountList, user, tx, IndexPrefixAccountPlugin)
+	if err != nil {
+		return returnCode, nil, err
+	}
+	params.AccountList = accountsAndIndexes.Accounts
+	params.IndexList = accountsAndIndexes.Indexes
+	res, returnCode, err := makeElasticSearchPluginsHistoryRequest(ctx, params)
+	if err != nil {
+		return returnCode, nil, err
+	}
+	history, err := parsePluginsHistory(res)
+	if err != nil {
+		jsonlog.LoggerFromContextOrDefault(ctx).Error("Failed to parse elasticsearch document.", err.Error())
+		return http.StatusInternalServerError, nil, err
+	}
+	return http.StatusOK, history, nil
+}
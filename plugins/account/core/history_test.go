@@ -0,0 +1,118 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+)
+
+func TestIsValidHistoryInterval(t *testing.T) {
+	for _, c := range []struct {
+		interval string
+		valid    bool
+	}{
+		{"day", true},
+		{"week", true},
+		{"month", true},
+		{"", false},
+		{"year", false},
+		{"Week", false},
+		{"1w", false},
+	} {
+		if valid := isValidHistoryInterval(c.interval); valid != c.valid {
+			t.Errorf("Interval %q should be valid: %v, is %v instead.", c.interval, c.valid, valid)
+		}
+	}
+}
+
+// getPluginsHistorySearchResult returns the response of elasticsearch to the
+// plugins history request, with the given "plugins" aggregation
+func getPluginsHistorySearchResult(plugins string) *elastic.SearchResult {
+	message := json.RawMessage(plugins)
+	return &elastic.SearchResult{Aggregations: elastic.Aggregations{"plugins": &message}}
+}
+
+func TestParsePluginsHistoryWithoutResult(t *testing.T) {
+	for _, res := range []*elastic.SearchResult{
+		nil,
+		{},
+		{Aggregations: elastic.Aggregations{}},
+		getPluginsHistorySearchResult(`{"buckets": []}`),
+	} {
+		history, err := parsePluginsHistory(res)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if history == nil || len(history) != 0 {
+			t.Errorf("History should be empty, is %v instead.", history)
+		}
+	}
+}
+
+func TestParsePluginsHistoryInvalidResult(t *testing.T) {
+	if _, err := parsePluginsHistory(getPluginsHistorySearchResult(`{"buckets": "invalid"}`)); err == nil {
+		t.Errorf("An invalid aggregation should return an error.")
+	}
+}
+
+func TestParsePluginsHistory(t *testing.T) {
+	res := getPluginsHistorySearchResult(`{"buckets": [
+		{"key": "Unused EBS", "dates": {"buckets": [
+			{"key": 1609718400000, "accounts": {"buckets": [
+				{"latest_result": {"hits": {"hits": [{"_source": {"pluginName": "Unused EBS", "category": "EC2", "label": "volume(s) in use", "checked": 10, "passed": 6, "estimatedMonthlySavings": 40}}]}}},
+				{"latest_result": {"hits": {"hits": [{"_source": {"pluginName": "Unused EBS", "category": "EC2", "label": "volume(s) in use", "checked": 5, "passed": 5}}]}}}
+			]}},
+			{"key": 1610323200000, "accounts": {"buckets": []}},
+			{"key": 1610928000000, "accounts": {"buckets": [
+				{"latest_result": {"hits": {"hits": [{"_source": {"pluginName": "Unused EBS", "category": "EC2", "label": "volume(s) in use", "checked": 12, "passed": 10, "estimatedMonthlySavings": 15.5}}]}}}
+			]}}
+		]}},
+		{"key": "Idle ELB", "dates": {"buckets": [
+			{"key": 1609718400000, "accounts": {"buckets": [
+				{"latest_result": {"hits": {"hits": [{"_source": {"pluginName": "Idle ELB", "category": "ELB", "label": "active load balancer(s)", "checked": 2, "passed": 1, "estimatedMonthlySavings": 18.25}}]}}}
+			]}}
+		]}}
+	]}`)
+	history, err := parsePluginsHistory(res)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	expected := []PluginHistory{
+		{
+			PluginName: "Idle ELB",
+			Category:   "ELB",
+			Label:      "active load balancer(s)",
+			Points: []PluginHistoryPoint{
+				{Date: time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC), Checked: 2, Passed: 1, EstimatedMonthlySavings: 18.25},
+			},
+		},
+		{
+			PluginName: "Unused EBS",
+			Category:   "EC2",
+			Label:      "volume(s) in use",
+			Points: []PluginHistoryPoint{
+				{Date: time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC), Checked: 15, Passed: 11, EstimatedMonthlySavings: 40},
+				{Date: time.Date(2021, time.January, 18, 0, 0, 0, 0, time.UTC), Checked: 12, Passed: 10, EstimatedMonthlySavings: 15.5},
+			},
+		},
+	}
+	if !reflect.DeepEqual(history, expected) {
+		t.Errorf("History should be %v, is %v instead.", expected, history)
+	}
+}
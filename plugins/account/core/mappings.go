@@ -42,7 +42,7 @@ func init() {
 const TemplateAccountPlugin = `
 {
   "template": "*-account-plugins",
-  "version": 3,
+  "version": 6,
   "mappings": {
     "account-plugin": {
       "properties": {
@@ -81,6 +81,67 @@ const TemplateAccountPlugin = `
         },
         "passed": {
           "type": "integer"
+        },
+        "findings": {
+          "type": "nested",
+          "properties": {
+            "resourceId": {
+              "type": "keyword"
+            },
+            "region": {
+              "type": "keyword"
+            },
+            "resourceType": {
+              "type": "keyword"
+            },
+            "severity": {
+              "type": "keyword"
+            },
+            "estimatedMonthlySavings": {
+              "type": "double"
+            },
+            "remediation": {
+              "type": "keyword"
+            },
+            "availabilityZone": {
+              "type": "keyword"
+            },
+            "detail": {
+              "type": "keyword"
+            }
+          }
+        },
+        "estimatedMonthlySavings": {
+          "type": "double"
+        },
+        "suppressedFindings": {
+          "type": "nested",
+          "properties": {
+            "resourceId": {
+              "type": "keyword"
+            },
+            "region": {
+              "type": "keyword"
+            },
+            "resourceType": {
+              "type": "keyword"
+            },
+            "severity": {
+              "type": "keyword"
+            },
+            "estimatedMonthlySavings": {
+              "type": "double"
+            },
+            "remediation": {
+              "type": "keyword"
+            },
+            "availabilityZone": {
+              "type": "keyword"
+            },
+            "detail": {
+              "type": "keyword"
+            }
+          }
         }
       },
       "_all": {
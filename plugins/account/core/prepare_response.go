@@ -17,6 +17,7 @@ package plugins_account_core
 import (
 	"context"
 	"encoding/json"
+	"sort"
 
 	"github.com/olivere/elastic"
 
@@ -63,3 +64,80 @@ func prepareResponse(ctx context.Context, res *elastic.SearchResult) (interface{
 	}
 	return reports, nil
 }
+
+// PluginFinding is a finding with the plugin which reported it
+type PluginFinding struct {
+	PluginName string `json:"pluginName"`
+	Category   string `json:"category"`
+	Finding
+}
+
+// AccountFindings are the findings of the latest plugins results of an account
+type AccountFindings struct {
+	Account                 string             `json:"account"`
+	EstimatedMonthlySavings float64            `json:"estimatedMonthlySavings"`
+	SavingsByPlugin         map[string]float64 `json:"savingsByPlugin"`
+	Findings                []PluginFinding    `json:"findings"`
+}
+
+// FindingsResponse is the response of the findings route
+type FindingsResponse struct {
+	EstimatedMonthlySavings float64           `json:"estimatedMonthlySavings"`
+	Accounts                []AccountFindings `json:"accounts"`
+}
+
+// parseLatestResults parses the latest plugins results of an *elastic.SearchResult
+func parseLatestResults(ctx context.Context, res *elastic.SearchResult) ([]PluginResultES, error) {
+	reports, err := parseESResult(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(reports)
+	if err != nil {
+		return nil, err
+	}
+	var results []PluginResultES
+	err = json.Unmarshal(raw, &results)
+	return results, err
+}
+
+// prepareFindingsResponse parses the results from elasticsearch and aggregates their findings per account
+// Findings are sorted by decreasing estimated monthly savings
+func prepareFindingsResponse(ctx context.Context, res *elastic.SearchResult) (FindingsResponse, error) {
+	response := FindingsResponse{Accounts: []AccountFindings{}}
+	if res == nil {
+		return response, nil
+	}
+	results, err := parseLatestResults(ctx, res)
+	if err != nil {
+		return response, err
+	}
+	accounts := make(map[string]*AccountFindings)
+	for _, result := range results {
+		account, ok := accounts[result.Account]
+		if !ok {
+			account = &AccountFindings{
+				Account:         result.Account,
+				SavingsByPlugin: make(map[string]float64),
+				Findings:        []PluginFinding{},
+			}
+			accounts[result.Account] = account
+		}
+		for _, finding := range result.Findings {
+			account.Findings = append(account.Findings, PluginFinding{result.PluginName, result.Category, finding})
+			account.SavingsByPlugin[result.PluginName] += finding.EstimatedMonthlySavings
+			account.EstimatedMonthlySavings += finding.EstimatedMonthlySavings
+		}
+	}
+	for _, account := range accounts {
+		sort.SliceStable(account.Findings, func(i, j int) bool {
+			return account.Findings[i].EstimatedMonthlySavings > account.Findings[j].EstimatedMonthlySavings
+		})
+		response.EstimatedMonthlySavings += account.EstimatedMonthlySavings
+		response.Accounts = append(response.Accounts, *account)
+	}
+	sort.Slice(response.Accounts, func(i, j int) bool {
+		return response.Accounts[i].Account < response.Accounts[j].Account
+	})
+	return response, nil
+}
@@ -0,0 +1,134 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_core
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/olivere/elastic"
+)
+
+func TestEstimatedMonthlySavings(t *testing.T) {
+	for _, c := range []struct {
+		findings []Finding
+		savings  float64
+	}{
+		{nil, 0},
+		{[]Finding{{ResourceId: "vol-1"}}, 0},
+		{[]Finding{{ResourceId: "vol-1", EstimatedMonthlySavings: 8}, {ResourceId: "vol-2", EstimatedMonthlySavings: 2.5}}, 10.5},
+	} {
+		pr := PluginResult{Findings: c.findings}
+		if savings := pr.EstimatedMonthlySavings(); math.Abs(savings-c.savings) > 1e-9 {
+			t.Errorf("Savings of %v should be %f, are %f instead.", c.findings, c.savings, savings)
+		}
+	}
+}
+
+// getLatestResultsSearchResult returns the response of elasticsearch to the
+// latest plugins results request, with the top hit of each bucket
+func getLatestResultsSearchResult(t *testing.T, results ...PluginResultES) *elastic.SearchResult {
+	buckets := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		buckets = append(buckets, map[string]interface{}{
+			"top_reports_hits": map[string]interface{}{
+				"hits": map[string]interface{}{
+					"hits": []interface{}{map[string]interface{}{"_source": result}},
+				},
+			},
+		})
+	}
+	raw, err := json.Marshal(map[string]interface{}{"buckets": buckets})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	message := json.RawMessage(raw)
+	return &elastic.SearchResult{Aggregations: elastic.Aggregations{"top_plugins_account": &message}}
+}
+
+func TestPrepareFindingsResponseWithoutIndex(t *testing.T) {
+	response, err := prepareFindingsResponse(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	} else if len(response.Accounts) != 0 || response.EstimatedMonthlySavings != 0 {
+		t.Errorf("Response should be empty, is %v instead.", response)
+	}
+}
+
+func TestPrepareFindingsResponse(t *testing.T) {
+	res := getLatestResultsSearchResult(t,
+		PluginResultES{
+			Account:    "222222222222",
+			PluginName: "Unused EBS",
+			Category:   "EC2",
+			Findings: []Finding{
+				{ResourceId: "vol-1", EstimatedMonthlySavings: 10},
+				{ResourceId: "vol-2", EstimatedMonthlySavings: 30},
+			},
+		},
+		PluginResultES{
+			Account:    "222222222222",
+			PluginName: "Unattached EIP",
+			Category:   "EC2",
+			Findings:   []Finding{{ResourceId: "eipalloc-1", EstimatedMonthlySavings: 20}},
+		},
+		PluginResultES{
+			Account:    "111111111111",
+			PluginName: "Unattached EIP",
+			Category:   "EC2",
+			Findings:   []Finding{{ResourceId: "eipalloc-2", EstimatedMonthlySavings: 5}},
+		},
+		PluginResultES{
+			Account:    "111111111111",
+			PluginName: "S3 Traffic",
+			Category:   "S3",
+			Details:    []string{"bucket"},
+		},
+	)
+	response, err := prepareFindingsResponse(context.Background(), res)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if response.EstimatedMonthlySavings != 65 {
+		t.Errorf("Total savings should be 65, are %f instead.", response.EstimatedMonthlySavings)
+	}
+	if len(response.Accounts) != 2 {
+		t.Fatalf("Response should have 2 accounts, has %d instead.", len(response.Accounts))
+	}
+	first, second := response.Accounts[0], response.Accounts[1]
+	if first.Account != "111111111111" || second.Account != "222222222222" {
+		t.Errorf("Accounts should be sorted, are %s and %s instead.", first.Account, second.Account)
+	}
+	if first.EstimatedMonthlySavings != 5 || second.EstimatedMonthlySavings != 60 {
+		t.Errorf("Account savings should be 5 and 60, are %f and %f instead.", first.EstimatedMonthlySavings, second.EstimatedMonthlySavings)
+	}
+	expectedSavings := map[string]float64{"Unused EBS": 40, "Unattached EIP": 20}
+	if !reflect.DeepEqual(second.SavingsByPlugin, expectedSavings) {
+		t.Errorf("Savings by plugin should be %v, are %v instead.", expectedSavings, second.SavingsByPlugin)
+	}
+	resourceIds := make([]string, 0, len(second.Findings))
+	for _, finding := range second.Findings {
+		resourceIds = append(resourceIds, finding.ResourceId)
+	}
+	if expected := []string{"vol-2", "eipalloc-1", "vol-1"}; !reflect.DeepEqual(resourceIds, expected) {
+		t.Errorf("Findings should be sorted by decreasing savings as %v, are %v instead.", expected, resourceIds)
+	}
+	if second.Findings[1].PluginName != "Unattached EIP" || second.Findings[1].Category != "EC2" {
+		t.Errorf("Findings should keep their plugin, got %v.", second.Findings[1])
+	}
+}
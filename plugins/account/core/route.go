@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/olivere/elastic"
 	"github.com/trackit/jsonlog"
@@ -42,12 +43,26 @@ var pluginsQueryArgs = []routes.QueryArg{
 	routes.AwsAccountsOptionalQueryArg,
 }
 
+// pluginsHistoryQueryArgs allows to get required queryArgs params of the history route
+var pluginsHistoryQueryArgs = []routes.QueryArg{
+	routes.AwsAccountsOptionalQueryArg,
+	routes.DateBeginQueryArg,
+	routes.DateEndQueryArg,
+	routes.QueryArg{
+		Name:        "interval",
+		Type:        routes.QueryArgString{},
+		Description: "Size of the intervals of the history: day, week (default) or month",
+		Optional:    true,
+	},
+}
+
 func init() {
 	routes.MethodMuxer{
 		http.MethodGet: routes.H(getPluginsResults).With(
 			db.RequestTransaction{Db: db.Db},
 			users.RequireAuthenticatedUser{users.ViewerAsParent},
 			routes.QueryArgs(pluginsQueryArgs),
+			users.RequirePermission{users.PermissionViewCosts},
 			cache.UsersCache{},
 			routes.Documentation{
 				Summary:     "get the latests plugins results",
@@ -55,6 +70,32 @@ func init() {
 			},
 		),
 	}.H().Register("/plugins/results")
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getPluginsFindings).With(
+			db.RequestTransaction{Db: db.Db},
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.QueryArgs(pluginsQueryArgs),
+			users.RequirePermission{users.PermissionViewCosts},
+			cache.UsersCache{},
+			routes.Documentation{
+				Summary:     "get the findings of the latests plugins results",
+				Description: "Responds with the findings and the estimated monthly savings of the latests plugins results, aggregated per account",
+			},
+		),
+	}.H().Register("/plugins/findings")
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getPluginsHistory).With(
+			db.RequestTransaction{Db: db.Db},
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.QueryArgs(pluginsHistoryQueryArgs),
+			users.RequirePermission{users.PermissionViewCosts},
+			cache.UsersCache{},
+			routes.Documentation{
+				Summary:     "get the history of the plugins results",
+				Description: "Responds with the checked and passed checks and the estimated monthly savings of each plugin over the date range, summing the latest result of each account per interval",
+			},
+		),
+	}.H().Register("/plugins/history")
 }
 
 // makeElasticSearchPluginsRequest prepares and run the request to retrieve the latest plugins results
@@ -84,8 +125,9 @@ func makeElasticSearchPluginsRequest(ctx context.Context, parsedParams pluginsQu
 	return res, http.StatusOK, nil
 }
 
-// getPluginsResults returns the list of plugins results based on the query params, in JSON format.
-func getPluginsResults(request *http.Request, a routes.Arguments) (int, interface{}) {
+// searchLatestPluginsResults runs the request retrieving the latest plugins results of the accounts
+// passed in the query args
+func searchLatestPluginsResults(request *http.Request, a routes.Arguments) (*elastic.SearchResult, int, error) {
 	user := a[users.AuthenticatedUser].(users.User)
 	parsedParams := pluginsQueryParams{
 		accountList: []string{},
@@ -96,11 +138,16 @@ func getPluginsResults(request *http.Request, a routes.Arguments) (int, interfac
 	tx := a[db.Transaction].(*sql.Tx)
 	accountsAndIndexes, returnCode, err := es.GetAccountsAndIndexes(parsedParams.accountList, user, tx, IndexPrefixAccountPlugin)
 	if err != nil {
-		return returnCode, err
+		return nil, returnCode, err
 	}
 	parsedParams.accountList = accountsAndIndexes.Accounts
 	parsedParams.indexList = accountsAndIndexes.Indexes
-	pluginsResult, returnCode, err := makeElasticSearchPluginsRequest(request.Context(), parsedParams)
+	return makeElasticSearchPluginsRequest(request.Context(), parsedParams)
+}
+
+// getPluginsResults returns the list of plugins results based on the query params, in JSON format.
+func getPluginsResults(request *http.Request, a routes.Arguments) (int, interface{}) {
+	pluginsResult, returnCode, err := searchLatestPluginsResults(request, a)
 	if err != nil {
 		return returnCode, err
 	}
@@ -110,3 +157,40 @@ func getPluginsResults(request *http.Request, a routes.Arguments) (int, interfac
 	}
 	return http.StatusOK, res
 }
+
+// getPluginsFindings returns the findings of the latest plugins results based on the query params,
+// aggregated per account, in JSON format.
+func getPluginsFindings(request *http.Request, a routes.Arguments) (int, interface{}) {
+	pluginsResult, returnCode, err := searchLatestPluginsResults(request, a)
+	if err != nil {
+		return returnCode, err
+	}
+	res, err := prepareFindingsResponse(request.Context(), pluginsResult)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, res
+}
+
+// getPluginsHistory returns the history of the plugins results based on the query params, in JSON format.
+func getPluginsHistory(request *http.Request, a routes.Arguments) (int, interface{}) {
+	user := a[users.AuthenticatedUser].(users.User)
+	tx := a[db.Transaction].(*sql.Tx)
+	params := PluginsHistoryQueryParams{
+		AccountList: []string{},
+		Begin:       a[pluginsHistoryQueryArgs[1]].(time.Time),
+		End:         a[pluginsHistoryQueryArgs[2]].(time.Time).Add(time.Hour*time.Duration(23) + time.Minute*time.Duration(59) + time.Second*time.Duration(59)),
+		Interval:    "week",
+	}
+	if a[pluginsHistoryQueryArgs[0]] != nil {
+		params.AccountList = a[pluginsHistoryQueryArgs[0]].([]string)
+	}
+	if a[pluginsHistoryQueryArgs[3]] != nil {
+		params.Interval = a[pluginsHistoryQueryArgs[3]].(string)
+	}
+	returnCode, history, err := GetPluginsHistory(request.Context(), params, user, tx)
+	if err != nil {
+		return returnCode, err
+	}
+	return http.StatusOK, history
+}
@@ -0,0 +1,224 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/trackit/trackit/models"
+)
+
+// Suppression marks the resources matching a pattern as intentionally kept
+// Their findings count as passed checks until the suppression expires
+type Suppression struct {
+	Id              int        `json:"id"`
+	AwsAccountId    int        `json:"awsAccountId"`
+	PluginName      string     `json:"pluginName"`
+	ResourcePattern string     `json:"resourcePattern"`
+	Reason          string     `json:"reason"`
+	Expiry          *time.Time `json:"expiry"`
+	Author          string     `json:"author"`
+	Created         time.Time  `json:"created"`
+	// pattern is the compiled ResourcePattern, nil if it is invalid
+	pattern *regexp.Regexp
+}
+
+// suppressionFromDbSuppression builds a Suppression from its database row
+func suppressionFromDbSuppression(dbSuppression models.PluginFindingSuppression) Suppression {
+	suppression := Suppression{
+		Id:              dbSuppression.ID,
+		AwsAccountId:    dbSuppression.AwsAccountID,
+		PluginName:      dbSuppression.PluginName,
+		ResourcePattern: dbSuppression.ResourcePattern,
+		Reason:          dbSuppression.Reason,
+		Author:          dbSuppression.Author,
+		Created:         dbSuppression.Created,
+	}
+	if dbSuppression.Expiry.Valid {
+		expiry := dbSuppression.Expiry.Time
+		suppression.Expiry = &expiry
+	}
+	// Stored patterns have been validated, an invalid one matches nothing
+	suppression.Compile()
+	return suppression
+}
+
+// dbSuppressionFromSuppression builds the database row of a Suppression
+func dbSuppressionFromSuppression(suppression Suppression) models.PluginFindingSuppression {
+	dbSuppression := models.PluginFindingSuppression{
+		AwsAccountID:    suppression.AwsAccountId,
+		PluginName:      suppression.PluginName,
+		ResourcePattern: suppression.ResourcePattern,
+		Reason:          suppression.Reason,
+		Author:          suppression.Author,
+		Created:         suppression.Created,
+	}
+	if suppression.Expiry != nil {
+		dbSuppression.Expiry = mysql.NullTime{Time: *suppression.Expiry, Valid: true}
+	}
+	return dbSuppression
+}
+
+// Compile compiles the resource pattern of a suppression once, so that it
+// can be matched against every finding
+func (s *Suppression) Compile() (err error) {
+	s.pattern, err = compileResourcePattern(s.ResourcePattern)
+	return
+}
+
+// validate checks that the pattern of a suppression is valid and compiles it
+func (s *Suppression) validate() error {
+	if s.ResourcePattern == "" {
+		return fmt.Errorf("resourcePattern is required")
+	} else if err := s.Compile(); err != nil {
+		return fmt.Errorf("resourcePattern is invalid: %s", err.Error())
+	} else if s.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	for _, plugin := range RegisteredAccountPlugins {
+		if s.PluginName == "" || plugin.Name == s.PluginName {
+			return nil
+		}
+	}
+	return fmt.Errorf("plugin %s does not exist", s.PluginName)
+}
+
+// IsActive returns true if the suppression has not expired at the given date
+func (s Suppression) IsActive(date time.Time) bool {
+	return s.Expiry == nil || s.Expiry.After(date)
+}
+
+// compileResourcePattern compiles a resource pattern to a regular expression
+// In the pattern, '*' matches any sequence of characters, including the '/' and ':' of
+// ARNs, '?' matches any single character and a backslash escapes the next character
+func compileResourcePattern(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^(?s)")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			expr.WriteString(".*")
+		case r == '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// Matches returns true if a finding of a plugin is suppressed
+// An empty plugin name matches every plugin, and the pattern uses the syntax of compileResourcePattern
+// The pattern must have been compiled, by loading or validating the suppression
+func (s Suppression) Matches(pluginName string, finding Finding) bool {
+	if s.PluginName != "" && s.PluginName != pluginName {
+		return false
+	}
+	return s.pattern != nil && s.pattern.MatchString(finding.ResourceId)
+}
+
+// GetSuppressions returns the suppressions of an AWS account
+func GetSuppressions(db models.DB, aaId int) ([]Suppression, error) {
+	dbSuppressions, err := models.PluginFindingSuppressionByAwsAccountID(db, aaId)
+	if err != nil {
+		return nil, err
+	}
+	suppressions := make([]Suppression, len(dbSuppressions))
+	for i, dbSuppression := range dbSuppressions {
+		suppressions[i] = suppressionFromDbSuppression(*dbSuppression)
+	}
+	return suppressions, nil
+}
+
+// GetActiveSuppressions returns the suppressions of an AWS account which have not
+// expired at the given date
+func GetActiveSuppressions(db models.DB, aaId int, date time.Time) ([]Suppression, error) {
+	suppressions, err := GetSuppressions(db, aaId)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]Suppression, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		if suppression.IsActive(date) {
+			active = append(active, suppression)
+		}
+	}
+	return active, nil
+}
+
+// isSuppressed returns true if a finding of a plugin matches one of the suppressions
+func isSuppressed(pluginName string, finding Finding, suppressions []Suppression) bool {
+	for _, suppression := range suppressions {
+		if suppression.Matches(pluginName, finding) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplySuppressions moves the suppressed findings of a plugin to SuppressedFindings
+// and counts them as passed checks. The Detail of each suppressed finding is removed
+// from the details, and the Result and Status are computed again with the PrepareResult
+// of the plugin, which can also rebuild the details from the remaining findings.
+func (pr *PluginResult) ApplySuppressions(plugin AccountPlugin, suppressions []Suppression) {
+	findings := make([]Finding, 0, len(pr.Findings))
+	suppressed := make(map[string]int)
+	for _, finding := range pr.Findings {
+		if isSuppressed(plugin.Name, finding, suppressions) {
+			pr.SuppressedFindings = append(pr.SuppressedFindings, finding)
+			if finding.Detail != "" {
+				suppressed[finding.Detail]++
+			}
+		} else {
+			findings = append(findings, finding)
+		}
+	}
+	if len(pr.SuppressedFindings) == 0 {
+		return
+	}
+	pr.Findings = findings
+	pr.Passed += len(pr.SuppressedFindings)
+	details := make([]string, 0, len(pr.Details)+1)
+	for _, detail := range pr.Details {
+		if suppressed[detail] > 0 {
+			suppressed[detail]--
+		} else {
+			details = append(details, detail)
+		}
+	}
+	pr.Details = details
+	if pr.Error == "" && plugin.PrepareResult != nil {
+		plugin.PrepareResult(pr)
+	} else if pr.Error == "" && pr.Passed >= pr.Checked {
+		pr.Status = "green"
+	}
+	pr.Details = append(pr.Details, fmt.Sprintf("%d suppressed resource(s)", len(pr.SuppressedFindings)))
+}
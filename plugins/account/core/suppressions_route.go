@@ -0,0 +1,158 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_core
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+// suppressionIdQueryArg allows to get the ID of a suppression in the URL parameters.
+var suppressionIdQueryArg = routes.QueryArg{
+	Name:        "suppression",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of a suppression.",
+}
+
+// suppressionBody is the expected body to create a suppression.
+type suppressionBody struct {
+	PluginName      string     `json:"pluginName"`
+	ResourcePattern string     `json:"resourcePattern" req:"nonzero"`
+	Reason          string     `json:"reason"          req:"nonzero"`
+	Expiry          *time.Time `json:"expiry"`
+}
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getSuppressions).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			aws.RequireAwsAccountId{},
+			routes.Documentation{
+				Summary:     "get the suppressions of an aws account",
+				Description: "Responds with the list of the plugins findings suppressions of an AWS account, including expired ones.",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+		http.MethodPost: routes.H(postSuppression).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			aws.RequireAwsAccountId{},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{suppressionBody{
+				PluginName:      "Unattached EIP",
+				ResourcePattern: "eipalloc-*",
+				Reason:          "Reserved for the failover of the production",
+			}},
+			routes.Documentation{
+				Summary:     "suppress plugins findings",
+				Description: "Suppresses the findings of the resources whose ID matches the pattern, in which * matches any characters, including the / and : of ARNs, and ? matches any single character. An empty plugin name matches every plugin, and a missing expiry never expires.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+		http.MethodDelete: routes.H(deleteSuppression).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			aws.RequireAwsAccountId{},
+			routes.QueryArgs{suppressionIdQueryArg},
+			routes.Documentation{
+				Summary:     "delete a suppression",
+				Description: "Deletes a suppression, its resources will be reported again by the next plugins run.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.QueryArgs{routes.AwsAccountIdQueryArg},
+		routes.Documentation{
+			Summary:     "interact with the plugins findings suppressions",
+			Description: "A suppression marks resources as intentionally kept: their findings count as passed checks from the next plugins run until the suppression expires.",
+		},
+	).Register("/plugins/suppressions")
+}
+
+// getSuppressions is a route handler which returns the suppressions of an AWS account.
+func getSuppressions(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	suppressions, err := GetSuppressions(tx, aa.Id)
+	if err != nil {
+		l.Error("Failed to get suppressions.", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve suppressions.")
+	}
+	return http.StatusOK, suppressions
+}
+
+// postSuppression is a route handler which creates a suppression.
+func postSuppression(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	var body suppressionBody
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	suppression := Suppression{
+		AwsAccountId:    aa.Id,
+		PluginName:      body.PluginName,
+		ResourcePattern: body.ResourcePattern,
+		Reason:          body.Reason,
+		Expiry:          body.Expiry,
+		Author:          user.Email,
+		Created:         time.Now().UTC(),
+	}
+	if err := suppression.validate(); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	dbSuppression := dbSuppressionFromSuppression(suppression)
+	if err := dbSuppression.Insert(tx); err != nil {
+		l.Error("Failed to insert suppression.", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save suppression.")
+	}
+	return http.StatusOK, suppressionFromDbSuppression(dbSuppression)
+}
+
+// deleteSuppression is a route handler which deletes a suppression.
+func deleteSuppression(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	dbSuppression, err := models.PluginFindingSuppressionByID(tx, a[suppressionIdQueryArg].(int))
+	if err != nil || dbSuppression.AwsAccountID != aa.Id {
+		return http.StatusNotFound, errors.New("Suppression not found.")
+	}
+	if err := dbSuppression.Delete(tx); err != nil {
+		l.Error("Failed to delete suppression.", map[string]interface{}{
+			"suppressionId": dbSuppression.ID,
+			"error":         err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to delete suppression.")
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,161 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_core
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const elbv2Arn = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/production-lb/50dc6c495c0c9188"
+
+func TestSuppressionMatches(t *testing.T) {
+	for _, c := range []struct {
+		pattern    string
+		pluginName string
+		resourceId string
+		matches    bool
+	}{
+		{"eipalloc-*", "", "eipalloc-0123456789abcdef0", true},
+		{"eipalloc-*", "", "vol-0123456789abcdef0", false},
+		{elbv2Arn, "", elbv2Arn, true},
+		{"arn:aws:elasticloadbalancing:*:loadbalancer/app/production-lb/*", "", elbv2Arn, true},
+		{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/*", "", elbv2Arn, true},
+		{"*production-lb*", "", elbv2Arn, true},
+		{"arn:aws:elasticloadbalancing:*:loadbalancer/net/*", "", elbv2Arn, false},
+		{"arn:aws:elasticloadbalancing:us-east-?:*", "", elbv2Arn, true},
+		{"arn:aws:elasticloadbalancing:us-east-?:*", "", "arn:aws:elasticloadbalancing:us-east-10:123456789012:loadbalancer/app/lb/1", false},
+		{"bucket.name", "", "bucketname", false},
+		{"[a-z]*", "", "abc", false},
+		{"[a-z]*", "", "[a-z]bucket", true},
+		{"weird\\*name", "", "weird*name", true},
+		{"weird\\*name", "", "weird-name", false},
+		{"*", "Idle load balancers", elbv2Arn, true},
+		{"*", "Unattached EIP", elbv2Arn, false},
+	} {
+		suppression := Suppression{PluginName: c.pluginName, ResourcePattern: c.pattern}
+		if err := suppression.Compile(); err != nil {
+			t.Fatalf("Pattern %q should be valid: %s", c.pattern, err.Error())
+		}
+		if matches := suppression.Matches("Idle load balancers", Finding{ResourceId: c.resourceId}); matches != c.matches {
+			t.Errorf("Pattern %q of plugin %q matching %q should be %t, is %t instead.", c.pattern, c.pluginName, c.resourceId, c.matches, matches)
+		}
+	}
+}
+
+func TestCompileResourcePatternInvalid(t *testing.T) {
+	if _, err := compileResourcePattern("vol-\\"); err == nil {
+		t.Errorf("A pattern ending with a backslash should be invalid.")
+	}
+	suppression := Suppression{ResourcePattern: "vol-\\", Reason: "Invalid"}
+	if err := suppression.validate(); err == nil {
+		t.Errorf("A suppression with an invalid pattern should be invalid.")
+	} else if suppression.Matches("Unused EBS", Finding{ResourceId: "vol-\\"}) {
+		t.Errorf("A suppression with an invalid pattern should not match anything.")
+	}
+}
+
+// suppressions compiles the patterns of suppressions like loading them does
+func suppressions(t *testing.T, patterns ...string) []Suppression {
+	t.Helper()
+	suppressions := make([]Suppression, len(patterns))
+	for i, pattern := range patterns {
+		suppressions[i] = Suppression{ResourcePattern: pattern}
+		if err := suppressions[i].Compile(); err != nil {
+			t.Fatalf("Pattern %q should be valid: %s", pattern, err.Error())
+		}
+	}
+	return suppressions
+}
+
+func prepareTestResult(pluginRes *PluginResult) {
+	if pluginRes.Checked == pluginRes.Passed {
+		pluginRes.Status = "green"
+		pluginRes.Result = "All your load balancers are active"
+	} else {
+		pluginRes.Status = "orange"
+		pluginRes.Result = fmt.Sprintf("You have %d idle load balancer(s)", pluginRes.Checked-pluginRes.Passed)
+	}
+}
+
+func TestApplySuppressionsPreparesResult(t *testing.T) {
+	plugin := AccountPlugin{Name: "Idle load balancers", PrepareResult: prepareTestResult}
+	other := "arn:aws:elasticloadbalancing:eu-west-3:123456789012:loadbalancer/app/staging-lb/1234"
+	// The details use the "name (region)" format of the idle load balancers plugin
+	newResult := func() PluginResult {
+		pr := PluginResult{
+			Checked: 3,
+			Passed:  1,
+			Details: []string{"production-lb (us-east-1)", "staging-lb (eu-west-3)"},
+			Findings: []Finding{
+				{ResourceId: elbv2Arn, Region: "us-east-1", Detail: "production-lb (us-east-1)"},
+				{ResourceId: other, Region: "eu-west-3", Detail: "staging-lb (eu-west-3)"},
+			},
+		}
+		prepareTestResult(&pr)
+		return pr
+	}
+
+	pr := newResult()
+	pr.ApplySuppressions(plugin, suppressions(t, "arn:aws:elasticloadbalancing:*:loadbalancer/app/production-lb/*"))
+	if pr.Passed != 2 || len(pr.Findings) != 1 || len(pr.SuppressedFindings) != 1 {
+		t.Errorf("One finding should be suppressed, got %d passed, %d findings and %d suppressed.", pr.Passed, len(pr.Findings), len(pr.SuppressedFindings))
+	}
+	if pr.Result != "You have 1 idle load balancer(s)" || pr.Status != "orange" {
+		t.Errorf("Result should be computed again, is %q with status %s instead.", pr.Result, pr.Status)
+	}
+	if len(pr.Details) != 2 || pr.Details[0] != "staging-lb (eu-west-3)" || pr.Details[1] != "1 suppressed resource(s)" {
+		t.Errorf("Details of suppressed resources should be removed, got %v.", pr.Details)
+	}
+
+	pr = newResult()
+	pr.ApplySuppressions(plugin, suppressions(t, "arn:aws:elasticloadbalancing:*"))
+	if pr.Result != "All your load balancers are active" || pr.Status != "green" {
+		t.Errorf("Result should be green once all findings are suppressed, is %q with status %s instead.", pr.Result, pr.Status)
+	}
+
+	pr = newResult()
+	pr.Error = "Unable to describe load balancers"
+	pr.Status = "red"
+	pr.ApplySuppressions(plugin, suppressions(t, "*"))
+	if pr.Status != "red" {
+		t.Errorf("Status of a result with an error should not change, is %s instead.", pr.Status)
+	}
+}
+
+func TestApplySuppressionsRemovesDetailOfSuppressedFinding(t *testing.T) {
+	plugin := AccountPlugin{Name: "Unattached EIP"}
+	// The unattached EIP plugin names the public IP in its details, and reports
+	// the allocation ID as the resource ID
+	pr := PluginResult{
+		Checked: 3,
+		Passed:  0,
+		Details: []string{"203.0.113.1 (us-east-1)", "203.0.113.2 (us-east-1)", "203.0.113.3 (eu-west-1)"},
+		Findings: []Finding{
+			{ResourceId: "eipalloc-0000000000000001", Detail: "203.0.113.1 (us-east-1)"},
+			{ResourceId: "eipalloc-0000000000000002", Detail: "203.0.113.2 (us-east-1)"},
+			{ResourceId: "eipalloc-0000000000000003", Detail: "203.0.113.3 (eu-west-1)"},
+		},
+	}
+	pr.ApplySuppressions(plugin, suppressions(t, "eipalloc-0000000000000001", "eipalloc-0000000000000003"))
+	expected := []string{"203.0.113.2 (us-east-1)", "2 suppressed resource(s)"}
+	if !reflect.DeepEqual(pr.Details, expected) {
+		t.Errorf("Details should be %v, are %v instead.", expected, pr.Details)
+	}
+	if pr.Passed != 2 || pr.Status == "green" {
+		t.Errorf("Two checks should pass and the status should not be green, got %d passed and status %s.", pr.Passed, pr.Status)
+	}
+}
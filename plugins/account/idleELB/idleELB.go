@@ -0,0 +1,200 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_idle_elb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+)
+
+const (
+	// classicElbHourlyCost is the hourly cost of a classic load balancer, excluding the data processed
+	classicElbHourlyCost = 0.025
+	// elbv2HourlyCost is the hourly cost of an application or network load balancer, excluding the capacity units
+	elbv2HourlyCost = 0.0225
+)
+
+// elbv2Metrics maps the types of load balancers to the metric counting their activity
+// Gateway load balancers are not checked
+var elbv2Metrics = map[string]utils.MetricQuery{
+	elbv2.LoadBalancerTypeEnumApplication: {
+		Namespace:  "AWS/ApplicationELB",
+		MetricName: "RequestCount",
+		Statistic:  cloudwatch.StatisticSum,
+	},
+	elbv2.LoadBalancerTypeEnumNetwork: {
+		Namespace:  "AWS/NetworkELB",
+		MetricName: "NewFlowCount",
+		Statistic:  cloudwatch.StatisticSum,
+	},
+}
+
+func init() {
+	// Register the plugin
+	core.AccountPlugin{
+		Name:          "Idle load balancers",
+		Description:   "Returns the list of load balancers without targets or without traffic during the last week",
+		Category:      utils.PluginsCategories["ELB"],
+		Label:         "active load balancer(s)",
+		Func:          processIdleElb,
+		PrepareResult: prepareResult,
+	}.Register()
+}
+
+// prepareResult sets the Result and Status in the pluginRes struct
+func prepareResult(pluginRes *core.PluginResult) {
+	if pluginRes.Checked == pluginRes.Passed {
+		pluginRes.Status = "green"
+		pluginRes.Result = "All your load balancers are active"
+	} else {
+		pluginRes.Result = fmt.Sprintf("You have %d idle load balancer(s)", pluginRes.Checked-pluginRes.Passed)
+		pluginRes.Status = utils.StatusPercentSteps{50, 80}.GetStatus(pluginRes.Checked, pluginRes.Passed)
+	}
+}
+
+// addIdleElb adds an idle load balancer to the details and findings of pluginRes
+func addIdleElb(pluginRes *core.PluginResult, name, resourceId, resourceType, region string, hourlyCost float64) {
+	detail := fmt.Sprintf("%s (%s)", name, region)
+	pluginRes.Details = append(pluginRes.Details, detail)
+	pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+		ResourceId:              resourceId,
+		Region:                  region,
+		ResourceType:            resourceType,
+		Severity:                core.SeverityMedium,
+		EstimatedMonthlySavings: hourlyCost * utils.HoursPerMonth,
+		Remediation:             "Delete the load balancer if it is not needed anymore",
+		Detail:                  detail,
+	})
+}
+
+// checkClassicElbs checks the classic load balancers of a region
+// A classic load balancer is idle if it has no instance or if it did not receive any request
+func checkClassicElbs(creds *credentials.Credentials, region string, date time.Time, pluginRes *core.PluginResult) error {
+	svc := utils.GetElbClientSession(creds, aws.String(region))
+	cw := utils.GetCloudWatchClientSession(creds, aws.String(region))
+	var metricErr error
+	err := svc.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, loadBalancer := range page.LoadBalancerDescriptions {
+				name := aws.StringValue(loadBalancer.LoadBalancerName)
+				idle := len(loadBalancer.Instances) == 0
+				if !idle {
+					idle, metricErr = utils.IsIdle(cw, utils.MetricQuery{
+						Namespace:  "AWS/ELB",
+						MetricName: "RequestCount",
+						Statistic:  cloudwatch.StatisticSum,
+						Dimensions: map[string]string{"LoadBalancerName": name},
+					}, date)
+					if metricErr != nil {
+						return false
+					}
+				}
+				pluginRes.Checked++
+				if idle {
+					addIdleElb(pluginRes, name, name, "AWS::ElasticLoadBalancing::LoadBalancer", region, classicElbHourlyCost)
+				} else {
+					pluginRes.Passed++
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+	return metricErr
+}
+
+// getElbv2MetricDimension returns the value of the LoadBalancer dimension of a
+// load balancer's metrics, which is the end of its ARN
+func getElbv2MetricDimension(arn string) string {
+	split := strings.SplitN(arn, ":loadbalancer/", 2)
+	return split[len(split)-1]
+}
+
+// checkElbv2s checks the application and network load balancers of a region
+// A load balancer is idle if it did not receive any request or connection
+func checkElbv2s(creds *credentials.Credentials, region string, date time.Time, pluginRes *core.PluginResult) error {
+	svc := utils.GetElbv2ClientSession(creds, aws.String(region))
+	cw := utils.GetCloudWatchClientSession(creds, aws.String(region))
+	var metricErr error
+	err := svc.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, loadBalancer := range page.LoadBalancers {
+				query, ok := elbv2Metrics[aws.StringValue(loadBalancer.Type)]
+				if !ok {
+					continue
+				}
+				arn := aws.StringValue(loadBalancer.LoadBalancerArn)
+				query.Dimensions = map[string]string{"LoadBalancer": getElbv2MetricDimension(arn)}
+				var idle bool
+				if idle, metricErr = utils.IsIdle(cw, query, date); metricErr != nil {
+					return false
+				}
+				pluginRes.Checked++
+				if idle {
+					addIdleElb(pluginRes, aws.StringValue(loadBalancer.LoadBalancerName), arn,
+						"AWS::ElasticLoadBalancingV2::LoadBalancer", region, elbv2HourlyCost)
+				} else {
+					pluginRes.Passed++
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+	return metricErr
+}
+
+// getIdleElbRecommendation searches for idle load balancers in every region available
+// It takes a core.PluginParams struct and a *core.PluginResult as parameters
+func getIdleElbRecommendation(pluginParams core.PluginParams, pluginRes *core.PluginResult) {
+	regions, err := utils.GetRegions(pluginParams.AccountCredentials)
+	if err != nil {
+		pluginRes.Status = "red"
+		pluginRes.Error = fmt.Sprintf("Unable to retrieve the list of regions: %s", err.Error())
+		return
+	}
+	date := time.Now().UTC()
+	for _, region := range regions {
+		if err = checkClassicElbs(pluginParams.AccountCredentials, region, date, pluginRes); err == nil {
+			err = checkElbv2s(pluginParams.AccountCredentials, region, date, pluginRes)
+		}
+		if err != nil {
+			pluginRes.Status = "red"
+			pluginRes.Error = fmt.Sprintf("Unable to check load balancers: %s", err.Error())
+			return
+		}
+	}
+	prepareResult(pluginRes)
+}
+
+// processIdleElb is the handler function for the Idle load balancers plugin
+// it takes a core.PluginParams struct and returns a core.PluginResult struct
+func processIdleElb(params core.PluginParams) core.PluginResult {
+	res := core.PluginResult{}
+	getIdleElbRecommendation(params, &res)
+	return res
+}
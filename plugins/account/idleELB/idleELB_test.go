@@ -0,0 +1,98 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_idle_elb
+
+import (
+	"reflect"
+	"testing"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+)
+
+func TestPrepareResult(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		checked int
+		passed  int
+		status  string
+		result  string
+	}{
+		{"No load balancer", 0, 0, "green", "All your load balancers are active"},
+		{"All active", 4, 4, "green", "All your load balancers are active"},
+		{"Green threshold", 10, 8, "green", "You have 2 idle load balancer(s)"},
+		{"Below green threshold", 10, 7, "orange", "You have 3 idle load balancer(s)"},
+		{"Orange threshold", 10, 5, "orange", "You have 5 idle load balancer(s)"},
+		{"Below orange threshold", 10, 4, "red", "You have 6 idle load balancer(s)"},
+		{"All idle", 3, 0, "red", "You have 3 idle load balancer(s)"},
+	} {
+		pr := core.PluginResult{Checked: c.checked, Passed: c.passed}
+		prepareResult(&pr)
+		if pr.Status != c.status {
+			t.Errorf("%s: Status should be %q, is %q instead.", c.name, c.status, pr.Status)
+		}
+		if pr.Result != c.result {
+			t.Errorf("%s: Result should be %q, is %q instead.", c.name, c.result, pr.Result)
+		}
+	}
+}
+
+func TestAddIdleElb(t *testing.T) {
+	pr := core.PluginResult{}
+	addIdleElb(&pr, "classic", "classic", "AWS::ElasticLoadBalancing::LoadBalancer", "us-east-1", classicElbHourlyCost)
+	addIdleElb(&pr, "alb", "arn:aws:elasticloadbalancing:eu-west-1:000000000000:loadbalancer/app/alb/0123456789abcdef",
+		"AWS::ElasticLoadBalancingV2::LoadBalancer", "eu-west-1", elbv2HourlyCost)
+	expected := []core.Finding{
+		{
+			ResourceId:              "classic",
+			Region:                  "us-east-1",
+			ResourceType:            "AWS::ElasticLoadBalancing::LoadBalancer",
+			Severity:                core.SeverityMedium,
+			EstimatedMonthlySavings: classicElbHourlyCost * utils.HoursPerMonth,
+			Remediation:             "Delete the load balancer if it is not needed anymore",
+			Detail:                  "classic (us-east-1)",
+		},
+		{
+			ResourceId:              "arn:aws:elasticloadbalancing:eu-west-1:000000000000:loadbalancer/app/alb/0123456789abcdef",
+			Region:                  "eu-west-1",
+			ResourceType:            "AWS::ElasticLoadBalancingV2::LoadBalancer",
+			Severity:                core.SeverityMedium,
+			EstimatedMonthlySavings: elbv2HourlyCost * utils.HoursPerMonth,
+			Remediation:             "Delete the load balancer if it is not needed anymore",
+			Detail:                  "alb (eu-west-1)",
+		},
+	}
+	if !reflect.DeepEqual(pr.Findings, expected) {
+		t.Errorf("Findings should be %v, are %v instead.", expected, pr.Findings)
+	}
+	if details := []string{"classic (us-east-1)", "alb (eu-west-1)"}; !reflect.DeepEqual(pr.Details, details) {
+		t.Errorf("Details should be %v, are %v instead.", details, pr.Details)
+	}
+}
+
+func TestGetElbv2MetricDimension(t *testing.T) {
+	for _, c := range []struct {
+		arn       string
+		dimension string
+	}{
+		{"arn:aws:elasticloadbalancing:us-east-1:000000000000:loadbalancer/app/alb/0123456789abcdef", "app/alb/0123456789abcdef"},
+		{"arn:aws:elasticloadbalancing:us-east-1:000000000000:loadbalancer/net/nlb/0123456789abcdef", "net/nlb/0123456789abcdef"},
+		{"app/alb/0123456789abcdef", "app/alb/0123456789abcdef"},
+	} {
+		if dimension := getElbv2MetricDimension(c.arn); dimension != c.dimension {
+			t.Errorf("Dimension of %q should be %q, is %q instead.", c.arn, c.dimension, dimension)
+		}
+	}
+}
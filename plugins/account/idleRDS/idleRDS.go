@@ -0,0 +1,157 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_idle_rds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/db"
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+	rdsReports "github.com/trackit/trackit/usageReports/rds"
+)
+
+func init() {
+	// Register the plugin
+	core.AccountPlugin{
+		Name:          "Idle RDS instances",
+		Description:   fmt.Sprintf("Returns the list of RDS instances with at most %g connection(s) during the last week", config.IdleRdsMaxConnections),
+		Category:      utils.PluginsCategories["RDS"],
+		Label:         "RDS instance(s) with connections",
+		Func:          processIdleRds,
+		PrepareResult: prepareResult,
+	}.Register()
+}
+
+// prepareResult sets the Result and Status in the pluginRes struct
+func prepareResult(pluginRes *core.PluginResult) {
+	if pluginRes.Checked == pluginRes.Passed {
+		pluginRes.Status = "green"
+		pluginRes.Result = "All your RDS instances have connections"
+	} else {
+		pluginRes.Result = fmt.Sprintf("You have %d idle RDS instance(s)", pluginRes.Checked-pluginRes.Passed)
+		pluginRes.Status = utils.StatusPercentSteps{50, 80}.GetStatus(pluginRes.Checked, pluginRes.Passed)
+	}
+}
+
+// isIdle returns true if the maximum number of connections of an instance during
+// the last utils.IdleDays days did not exceed config.IdleRdsMaxConnections
+func isIdle(maxConnections float64) bool {
+	return maxConnections <= config.IdleRdsMaxConnections
+}
+
+// getInstancesMonthlyCost returns the estimated monthly cost of each RDS instance
+// of the account, based on the RDS usage report
+// Without usage report the instances are still reported, so an error is only logged
+func getInstancesMonthlyCost(params core.PluginParams, date time.Time) map[string]float64 {
+	logger := jsonlog.LoggerFromContextOrDefault(params.Context)
+	costs := make(map[string]float64)
+	tx, err := db.Db.Begin()
+	if err != nil {
+		logger.Warning("Unable to retrieve RDS instances costs", err.Error())
+		return costs
+	}
+	defer tx.Rollback()
+	_, instances, err := rdsReports.GetRdsData(params.Context,
+		rdsReports.RdsQueryParams{AccountList: []string{params.AccountId}, Date: date},
+		params.User, tx)
+	if err != nil {
+		logger.Warning("Unable to retrieve RDS instances costs", err.Error())
+		return costs
+	}
+	for _, instance := range instances {
+		costs[instance.Instance.DBInstanceIdentifier] = utils.EstimateMonthlyCost(instance.Instance.Costs["instance"], date)
+	}
+	return costs
+}
+
+// getIdleRdsRecommendation searches for RDS instances whose maximum number of connections
+// did not exceed config.IdleRdsMaxConnections in every region available
+// It takes a core.PluginParams struct and a *core.PluginResult as parameters
+func getIdleRdsRecommendation(pluginParams core.PluginParams, pluginRes *core.PluginResult) {
+	regions, err := utils.GetRegions(pluginParams.AccountCredentials)
+	if err != nil {
+		pluginRes.Status = "red"
+		pluginRes.Error = fmt.Sprintf("Unable to retrieve the list of regions: %s", err.Error())
+		return
+	}
+	date := time.Now().UTC()
+	costs := getInstancesMonthlyCost(pluginParams, date)
+	for _, region := range regions {
+		svc := utils.GetRdsClientSession(pluginParams.AccountCredentials, aws.String(region))
+		cw := utils.GetCloudWatchClientSession(pluginParams.AccountCredentials, aws.String(region))
+		var metricErr error
+		err = svc.DescribeDBInstancesPages(&rds.DescribeDBInstancesInput{},
+			func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+				for _, instance := range page.DBInstances {
+					if aws.StringValue(instance.DBInstanceStatus) != "available" {
+						continue
+					}
+					id := aws.StringValue(instance.DBInstanceIdentifier)
+					var maxConnections float64
+					maxConnections, metricErr = utils.GetMetricValue(cw, utils.MetricQuery{
+						Namespace:  "AWS/RDS",
+						MetricName: "DatabaseConnections",
+						Statistic:  cloudwatch.StatisticMaximum,
+						Dimensions: map[string]string{"DBInstanceIdentifier": id},
+					}, date.AddDate(0, 0, -utils.IdleDays), date)
+					if metricErr != nil {
+						return false
+					}
+					pluginRes.Checked++
+					if !isIdle(maxConnections) {
+						pluginRes.Passed++
+						continue
+					}
+					detail := fmt.Sprintf("%s (%s)", id, region)
+					pluginRes.Details = append(pluginRes.Details, detail)
+					pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+						ResourceId:              id,
+						Region:                  region,
+						ResourceType:            "AWS::RDS::DBInstance",
+						Severity:                core.SeverityHigh,
+						EstimatedMonthlySavings: costs[id],
+						Remediation:             "Snapshot the instance and delete it if it is not needed anymore",
+						Detail:                  detail,
+					})
+				}
+				return true
+			})
+		if err == nil {
+			err = metricErr
+		}
+		if err != nil {
+			pluginRes.Status = "red"
+			pluginRes.Error = fmt.Sprintf("Unable to check RDS instances: %s", err.Error())
+			return
+		}
+	}
+	prepareResult(pluginRes)
+}
+
+// processIdleRds is the handler function for the Idle RDS instances plugin
+// it takes a core.PluginParams struct and returns a core.PluginResult struct
+func processIdleRds(params core.PluginParams) core.PluginResult {
+	res := core.PluginResult{}
+	getIdleRdsRecommendation(params, &res)
+	return res
+}
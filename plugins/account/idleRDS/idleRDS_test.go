@@ -0,0 +1,69 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_idle_rds
+
+import (
+	"testing"
+
+	"github.com/trackit/trackit/config"
+	core "github.com/trackit/trackit/plugins/account/core"
+)
+
+func TestPrepareResult(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		checked int
+		passed  int
+		status  string
+		result  string
+	}{
+		{"No instance", 0, 0, "green", "All your RDS instances have connections"},
+		{"All used", 3, 3, "green", "All your RDS instances have connections"},
+		{"Green threshold", 10, 8, "green", "You have 2 idle RDS instance(s)"},
+		{"Orange threshold", 10, 5, "orange", "You have 5 idle RDS instance(s)"},
+		{"Below orange threshold", 10, 4, "red", "You have 6 idle RDS instance(s)"},
+	} {
+		pr := core.PluginResult{Checked: c.checked, Passed: c.passed}
+		prepareResult(&pr)
+		if pr.Status != c.status {
+			t.Errorf("%s: Status should be %q, is %q instead.", c.name, c.status, pr.Status)
+		}
+		if pr.Result != c.result {
+			t.Errorf("%s: Result should be %q, is %q instead.", c.name, c.result, pr.Result)
+		}
+	}
+}
+
+func TestIsIdle(t *testing.T) {
+	defer func(maxConnections float64) { config.IdleRdsMaxConnections = maxConnections }(config.IdleRdsMaxConnections)
+	for _, c := range []struct {
+		maxConnections float64
+		connections    float64
+		idle           bool
+	}{
+		{0, 0, true},
+		{0, 1, false},
+		{1, 0, true},
+		{1, 1, true},
+		{1, 2, false},
+		{5, 4.5, true},
+		{5, 5.5, false},
+	} {
+		config.IdleRdsMaxConnections = c.maxConnections
+		if idle := isIdle(c.connections); idle != c.idle {
+			t.Errorf("isIdle(%v) with %v max connections should be %v, is %v instead.", c.connections, c.maxConnections, c.idle, idle)
+		}
+	}
+}
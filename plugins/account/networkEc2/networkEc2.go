@@ -32,11 +32,12 @@ const (
 func init() {
 	// Register the plugin
 	core.AccountPlugin{
-		Name:        "EC2 Network",
-		Description: "Get the list of EC2 instances with low network activity",
-		Category:    utils.PluginsCategories["EC2"],
-		Label:       "EC2 instance(s) with network activity",
-		Func:        processNetworkEc2,
+		Name:          "EC2 Network",
+		Description:   "Get the list of EC2 instances with low network activity",
+		Category:      utils.PluginsCategories["EC2"],
+		Label:         "EC2 instance(s) with network activity",
+		Func:          processNetworkEc2,
+		PrepareResult: prepareResult,
 	}.Register()
 }
 
@@ -53,7 +54,7 @@ func prepareResult(pluginRes *core.PluginResult) {
 
 // getUnusedEc2Recommendation searches for unused ec2 network in usage report
 // It takes a *core.PluginResult and an array of instances as parameters
-func getUnusedEc2Recommendation(pluginRes *core.PluginResult, instances []ec2.InstanceReport) {
+func getUnusedEc2Recommendation(pluginRes *core.PluginResult, instances []ec2.InstanceReport, date time.Time) {
 	pluginRes.Details = make([]string, 0)
 	for _, instance := range instances {
 		if instance.Instance.Stats.Network.In == -1 || instance.Instance.Stats.Network.Out == -1 {
@@ -64,7 +65,17 @@ func getUnusedEc2Recommendation(pluginRes *core.PluginResult, instances []ec2.In
 		if network > networkLimit {
 			pluginRes.Passed++
 		} else {
-			pluginRes.Details = append(pluginRes.Details, fmt.Sprintf("%s %s", instance.Instance.Id, instance.Instance.Tags["Name"]))
+			detail := fmt.Sprintf("%s %s", instance.Instance.Id, instance.Instance.Tags["Name"])
+			pluginRes.Details = append(pluginRes.Details, detail)
+			pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+				ResourceId:              instance.Instance.Id,
+				Region:                  instance.Instance.Region,
+				ResourceType:            "AWS::EC2::Instance",
+				Severity:                core.SeverityMedium,
+				EstimatedMonthlySavings: utils.EstimateMonthlyCost(instance.Instance.Costs["instance"], date),
+				Remediation:             "Stop or terminate the instance if it is idle",
+				Detail:                  detail,
+			})
 		}
 	}
 	prepareResult(pluginRes)
@@ -79,14 +90,15 @@ func processNetworkEc2(params core.PluginParams) (res core.PluginResult) {
 		res.Error = fmt.Sprintln("Unable to retrieve EC2 instances : ", err.Error())
 		return
 	}
+	date := time.Now().UTC()
 	_, instances, err := ec2.GetEc2Data(params.Context,
-		ec2.Ec2QueryParams{[]string{params.AccountId}, nil, time.Now().UTC()},
+		ec2.Ec2QueryParams{[]string{params.AccountId}, nil, date},
 		params.User, tx)
 	if err != nil {
 		res.Status = "red"
 		res.Error = fmt.Sprintln("Unable to retrieve EC2 instances : ", err.Error())
 		return
 	}
-	getUnusedEc2Recommendation(&res, instances)
+	getUnusedEc2Recommendation(&res, instances, date)
 	return
 }
@@ -0,0 +1,71 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_network_ec2
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+	"github.com/trackit/trackit/usageReports/ec2"
+)
+
+// getTestInstance returns the report of an instance with its network usage and its cost since the beginning of the month
+func getTestInstance(id string, networkIn, networkOut, cost float64) ec2.InstanceReport {
+	instance := ec2.InstanceReport{}
+	instance.Instance.Id = id
+	instance.Instance.Region = "us-east-1"
+	instance.Instance.Stats.Network.In = networkIn
+	instance.Instance.Stats.Network.Out = networkOut
+	instance.Instance.Costs = map[string]float64{"instance": cost}
+	return instance
+}
+
+func TestGetUnusedEc2Recommendation(t *testing.T) {
+	date := time.Date(2021, time.April, 10, 0, 0, 0, 0, time.UTC)
+	pr := core.PluginResult{}
+	getUnusedEc2Recommendation(&pr, []ec2.InstanceReport{
+		getTestInstance("i-active", networkLimit, 1, 100),
+		getTestInstance("i-idle", 1000, 1000, 20),
+		getTestInstance("i-limit", networkLimit/2, networkLimit/2, 5),
+		getTestInstance("i-nostats", -1, 0, 50),
+	}, date)
+	if pr.Checked != 3 || pr.Passed != 1 {
+		t.Errorf("Checked and Passed should be 3 and 1, are %d and %d instead.", pr.Checked, pr.Passed)
+	}
+	if len(pr.Findings) != 2 {
+		t.Fatalf("There should be 2 findings, there are %d instead.", len(pr.Findings))
+	}
+	for i, c := range []struct {
+		resourceId string
+		savings    float64
+	}{
+		{"i-idle", 60},
+		{"i-limit", 15},
+	} {
+		finding := pr.Findings[i]
+		if finding.ResourceId != c.resourceId {
+			t.Errorf("Finding %d should be %s, is %s instead.", i, c.resourceId, finding.ResourceId)
+		} else if math.Abs(finding.EstimatedMonthlySavings-c.savings) > 1e-9 {
+			t.Errorf("Savings of %s should be %f, are %f instead.", c.resourceId, c.savings, finding.EstimatedMonthlySavings)
+		} else if finding.Region != "us-east-1" || finding.ResourceType != "AWS::EC2::Instance" || finding.Severity != core.SeverityMedium {
+			t.Errorf("Unexpected finding %v.", finding)
+		}
+	}
+	if pr.Status != "red" || pr.Result != "You have 2 EC2 instance with low network activity" {
+		t.Errorf("Unexpected status %q and result %q.", pr.Status, pr.Result)
+	}
+}
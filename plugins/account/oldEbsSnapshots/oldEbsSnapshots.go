@@ -0,0 +1,111 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_old_ebs_snapshots
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/trackit/trackit/aws/pricings"
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+)
+
+// snapshotMaxAgeDays is the age in days after which a snapshot is considered old
+const snapshotMaxAgeDays = 90
+
+func init() {
+	// Register the plugin
+	core.AccountPlugin{
+		Name:          "Old EBS snapshots",
+		Description:   fmt.Sprintf("Returns the list of EBS snapshots older than %d days", snapshotMaxAgeDays),
+		Category:      utils.PluginsCategories["EC2"],
+		Label:         "recent EBS snapshot(s)",
+		Func:          processOldEbsSnapshots,
+		PrepareResult: prepareResult,
+	}.Register()
+}
+
+// prepareResult sets the Result and Status in the pluginRes struct
+func prepareResult(pluginRes *core.PluginResult) {
+	if pluginRes.Checked == pluginRes.Passed {
+		pluginRes.Status = "green"
+		pluginRes.Result = "You don't have any old EBS snapshot"
+	} else {
+		pluginRes.Result = fmt.Sprintf("You have %d EBS snapshot(s) older than %d days", pluginRes.Checked-pluginRes.Passed, snapshotMaxAgeDays)
+		pluginRes.Status = utils.StatusPercentSteps{50, 80}.GetStatus(pluginRes.Checked, pluginRes.Passed)
+	}
+}
+
+// checkSnapshot counts a snapshot in pluginRes and adds it to the findings
+// if it was started before maxDate
+// Since snapshots are incremental, the savings are estimated from the size of the
+// volume and are an upper bound
+func checkSnapshot(pluginRes *core.PluginResult, catalog pricings.ServiceCatalog, region string, snapshot *ec2.Snapshot, maxDate time.Time) {
+	pluginRes.Checked++
+	if aws.TimeValue(snapshot.StartTime).After(maxDate) {
+		pluginRes.Passed++
+		return
+	}
+	pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+		ResourceId:              aws.StringValue(snapshot.SnapshotId),
+		Region:                  region,
+		ResourceType:            "AWS::EC2::Snapshot",
+		Severity:                core.SeverityLow,
+		EstimatedMonthlySavings: utils.GetSnapshotMonthlyCost(catalog, region, aws.Int64Value(snapshot.VolumeSize)),
+		Remediation:             "Delete the snapshot or move it to the archive tier if it is not needed anymore",
+	})
+}
+
+// getOldEbsSnapshotsRecommendation searches for old EBS snapshots in every region available
+// It takes a core.PluginParams struct and a *core.PluginResult as parameters
+func getOldEbsSnapshotsRecommendation(pluginParams core.PluginParams, pluginRes *core.PluginResult) {
+	regions, err := utils.GetRegions(pluginParams.AccountCredentials)
+	if err != nil {
+		pluginRes.Status = "red"
+		pluginRes.Error = fmt.Sprintf("Unable to retrieve the list of regions: %s", err.Error())
+		return
+	}
+	catalog := utils.GetEbsCatalog(pluginParams.Context)
+	maxDate := time.Now().UTC().AddDate(0, 0, -snapshotMaxAgeDays)
+	for _, region := range regions {
+		svc := utils.GetEc2ClientSession(pluginParams.AccountCredentials, aws.String(region))
+		err = svc.DescribeSnapshotsPages(&ec2.DescribeSnapshotsInput{
+			OwnerIds: []*string{aws.String("self")},
+		}, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+			for _, snapshot := range page.Snapshots {
+				checkSnapshot(pluginRes, catalog, region, snapshot, maxDate)
+			}
+			return true
+		})
+		if err != nil {
+			pluginRes.Status = "red"
+			pluginRes.Error = fmt.Sprintf("Unable to list snapshots: %s", err.Error())
+			return
+		}
+	}
+	prepareResult(pluginRes)
+}
+
+// processOldEbsSnapshots is the handler function for the Old EBS snapshots plugin
+// it takes a core.PluginParams struct and returns a core.PluginResult struct
+func processOldEbsSnapshots(params core.PluginParams) core.PluginResult {
+	res := core.PluginResult{}
+	getOldEbsSnapshotsRecommendation(params, &res)
+	return res
+}
@@ -0,0 +1,106 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_old_ebs_snapshots
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/trackit/trackit/aws/pricings"
+	core "github.com/trackit/trackit/plugins/account/core"
+)
+
+func TestPrepareResult(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		checked int
+		passed  int
+		status  string
+		result  string
+	}{
+		{"No snapshot", 0, 0, "green", "You don't have any old EBS snapshot"},
+		{"All recent", 3, 3, "green", "You don't have any old EBS snapshot"},
+		{"Green threshold", 10, 8, "green", "You have 2 EBS snapshot(s) older than 90 days"},
+		{"Orange threshold", 10, 5, "orange", "You have 5 EBS snapshot(s) older than 90 days"},
+		{"Below orange threshold", 10, 4, "red", "You have 6 EBS snapshot(s) older than 90 days"},
+	} {
+		pr := core.PluginResult{Checked: c.checked, Passed: c.passed}
+		prepareResult(&pr)
+		if pr.Status != c.status {
+			t.Errorf("%s: Status should be %q, is %q instead.", c.name, c.status, pr.Status)
+		}
+		if pr.Result != c.result {
+			t.Errorf("%s: Result should be %q, is %q instead.", c.name, c.result, pr.Result)
+		}
+	}
+}
+
+func TestCheckSnapshot(t *testing.T) {
+	catalog := pricings.ServiceCatalog{
+		Product: pricings.EBSProduct,
+		Skus: map[string]*pricings.SkuPricing{
+			"SNAPSHOT": {
+				Sku:           "SNAPSHOT",
+				ProductFamily: "Storage Snapshot",
+				Region:        "us-east-1",
+				Attributes:    map[string]string{"usagetype": "EBS:SnapshotUsage"},
+				OnDemand:      []pricings.PriceDimension{{Unit: "GB-Mo", BeginRange: 0, EndRange: -1, PricePerUnit: 0.05}},
+			},
+		},
+	}
+	maxDate := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		name    string
+		region  string
+		started time.Time
+		passed  int
+		savings float64
+	}{
+		{"Recent snapshot", "us-east-1", maxDate.Add(time.Hour), 1, 0},
+		{"Snapshot started at the max date", "us-east-1", maxDate, 0, 5},
+		{"Old snapshot", "us-east-1", maxDate.AddDate(-1, 0, 0), 0, 5},
+		{"Region without pricing", "eu-west-1", maxDate.AddDate(-1, 0, 0), 0, 0},
+	} {
+		pr := core.PluginResult{}
+		checkSnapshot(&pr, catalog, c.region, &ec2.Snapshot{
+			SnapshotId: aws.String("snap-0123456789abcdef0"),
+			StartTime:  aws.Time(c.started),
+			VolumeSize: aws.Int64(100),
+		}, maxDate)
+		if pr.Checked != 1 || pr.Passed != c.passed {
+			t.Errorf("%s: Checked and Passed should be 1 and %d, are %d and %d instead.", c.name, c.passed, pr.Checked, pr.Passed)
+		}
+		if c.passed == 1 {
+			if len(pr.Findings) != 0 {
+				t.Errorf("%s: Findings should be empty, are %v instead.", c.name, pr.Findings)
+			}
+			continue
+		}
+		if len(pr.Findings) != 1 {
+			t.Errorf("%s: Findings should have 1 element, have %d instead.", c.name, len(pr.Findings))
+			continue
+		}
+		finding := pr.Findings[0]
+		if finding.ResourceId != "snap-0123456789abcdef0" || finding.Region != c.region || finding.Severity != core.SeverityLow {
+			t.Errorf("%s: Finding should be about snap-0123456789abcdef0 in %s, is %v instead.", c.name, c.region, finding)
+		}
+		if finding.EstimatedMonthlySavings != c.savings {
+			t.Errorf("%s: EstimatedMonthlySavings should be %v, is %v instead.", c.name, c.savings, finding.EstimatedMonthlySavings)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_old_rds_snapshots
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+)
+
+const (
+	// snapshotMaxAgeDays is the age in days after which a snapshot is considered old
+	snapshotMaxAgeDays = 90
+	// snapshotGBMonthlyCost is the monthly cost of a GB of backup storage beyond
+	// the free storage of the instances
+	snapshotGBMonthlyCost = 0.095
+)
+
+func init() {
+	// Register the plugin
+	core.AccountPlugin{
+		Name:          "Old RDS snapshots",
+		Description:   fmt.Sprintf("Returns the list of manual RDS snapshots older than %d days", snapshotMaxAgeDays),
+		Category:      utils.PluginsCategories["RDS"],
+		Label:         "recent RDS snapshot(s)",
+		Func:          processOldRdsSnapshots,
+		PrepareResult: prepareResult,
+	}.Register()
+}
+
+// prepareResult sets the Result and Status in the pluginRes struct
+func prepareResult(pluginRes *core.PluginResult) {
+	if pluginRes.Checked == pluginRes.Passed {
+		pluginRes.Status = "green"
+		pluginRes.Result = "You don't have any old RDS snapshot"
+	} else {
+		pluginRes.Result = fmt.Sprintf("You have %d RDS snapshot(s) older than %d days", pluginRes.Checked-pluginRes.Passed, snapshotMaxAgeDays)
+		pluginRes.Status = utils.StatusPercentSteps{50, 80}.GetStatus(pluginRes.Checked, pluginRes.Passed)
+	}
+}
+
+// checkSnapshot counts a snapshot in pluginRes and adds it to the findings
+// if it was created before maxDate
+func checkSnapshot(pluginRes *core.PluginResult, region string, snapshot *rds.DBSnapshot, maxDate time.Time) {
+	pluginRes.Checked++
+	if aws.TimeValue(snapshot.SnapshotCreateTime).After(maxDate) {
+		pluginRes.Passed++
+		return
+	}
+	pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+		ResourceId:              aws.StringValue(snapshot.DBSnapshotIdentifier),
+		Region:                  region,
+		ResourceType:            "AWS::RDS::DBSnapshot",
+		Severity:                core.SeverityLow,
+		EstimatedMonthlySavings: float64(aws.Int64Value(snapshot.AllocatedStorage)) * snapshotGBMonthlyCost,
+		Remediation:             "Delete the snapshot if it is not needed anymore",
+	})
+}
+
+// getOldRdsSnapshotsRecommendation searches for old manual RDS snapshots in every region available
+// It takes a core.PluginParams struct and a *core.PluginResult as parameters
+// Automated snapshots are deleted at the end of their retention period and are not checked
+func getOldRdsSnapshotsRecommendation(pluginParams core.PluginParams, pluginRes *core.PluginResult) {
+	regions, err := utils.GetRegions(pluginParams.AccountCredentials)
+	if err != nil {
+		pluginRes.Status = "red"
+		pluginRes.Error = fmt.Sprintf("Unable to retrieve the list of regions: %s", err.Error())
+		return
+	}
+	maxDate := time.Now().UTC().AddDate(0, 0, -snapshotMaxAgeDays)
+	for _, region := range regions {
+		svc := utils.GetRdsClientSession(pluginParams.AccountCredentials, aws.String(region))
+		err = svc.DescribeDBSnapshotsPages(&rds.DescribeDBSnapshotsInput{
+			SnapshotType: aws.String("manual"),
+		}, func(page *rds.DescribeDBSnapshotsOutput, lastPage bool) bool {
+			for _, snapshot := range page.DBSnapshots {
+				checkSnapshot(pluginRes, region, snapshot, maxDate)
+			}
+			return true
+		})
+		if err != nil {
+			pluginRes.Status = "red"
+			pluginRes.Error = fmt.Sprintf("Unable to list snapshots: %s", err.Error())
+			return
+		}
+	}
+	prepareResult(pluginRes)
+}
+
+// processOldRdsSnapshots is the handler function for the Old RDS snapshots plugin
+// it takes a core.PluginParams struct and returns a core.PluginResult struct
+func processOldRdsSnapshots(params core.PluginParams) core.PluginResult {
+	res := core.PluginResult{}
+	getOldRdsSnapshotsRecommendation(params, &res)
+	return res
+}
@@ -0,0 +1,87 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_old_rds_snapshots
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+)
+
+func TestPrepareResult(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		checked int
+		passed  int
+		status  string
+		result  string
+	}{
+		{"No snapshot", 0, 0, "green", "You don't have any old RDS snapshot"},
+		{"All recent", 3, 3, "green", "You don't have any old RDS snapshot"},
+		{"Green threshold", 10, 8, "green", "You have 2 RDS snapshot(s) older than 90 days"},
+		{"Orange threshold", 10, 5, "orange", "You have 5 RDS snapshot(s) older than 90 days"},
+		{"Below orange threshold", 10, 4, "red", "You have 6 RDS snapshot(s) older than 90 days"},
+	} {
+		pr := core.PluginResult{Checked: c.checked, Passed: c.passed}
+		prepareResult(&pr)
+		if pr.Status != c.status {
+			t.Errorf("%s: Status should be %q, is %q instead.", c.name, c.status, pr.Status)
+		}
+		if pr.Result != c.result {
+			t.Errorf("%s: Result should be %q, is %q instead.", c.name, c.result, pr.Result)
+		}
+	}
+}
+
+func TestCheckSnapshot(t *testing.T) {
+	maxDate := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	pr := core.PluginResult{}
+	for _, snapshot := range []*rds.DBSnapshot{
+		{DBSnapshotIdentifier: aws.String("recent"), SnapshotCreateTime: aws.Time(maxDate.Add(time.Hour)), AllocatedStorage: aws.Int64(20)},
+		{DBSnapshotIdentifier: aws.String("max-date"), SnapshotCreateTime: aws.Time(maxDate), AllocatedStorage: aws.Int64(100)},
+		{DBSnapshotIdentifier: aws.String("old"), SnapshotCreateTime: aws.Time(maxDate.AddDate(-1, 0, 0)), AllocatedStorage: aws.Int64(200)},
+	} {
+		checkSnapshot(&pr, "us-east-1", snapshot, maxDate)
+	}
+	if pr.Checked != 3 || pr.Passed != 1 {
+		t.Errorf("Checked and Passed should be 3 and 1, are %d and %d instead.", pr.Checked, pr.Passed)
+	}
+	expected := []core.Finding{
+		{
+			ResourceId:              "max-date",
+			Region:                  "us-east-1",
+			ResourceType:            "AWS::RDS::DBSnapshot",
+			Severity:                core.SeverityLow,
+			EstimatedMonthlySavings: 100 * snapshotGBMonthlyCost,
+			Remediation:             "Delete the snapshot if it is not needed anymore",
+		},
+		{
+			ResourceId:              "old",
+			Region:                  "us-east-1",
+			ResourceType:            "AWS::RDS::DBSnapshot",
+			Severity:                core.SeverityLow,
+			EstimatedMonthlySavings: 200 * snapshotGBMonthlyCost,
+			Remediation:             "Delete the snapshot if it is not needed anymore",
+		},
+	}
+	if !reflect.DeepEqual(pr.Findings, expected) {
+		t.Errorf("Findings should be %v, are %v instead.", expected, pr.Findings)
+	}
+}
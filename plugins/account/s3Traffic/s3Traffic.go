@@ -33,6 +33,7 @@ func init() {
 		Label:           "bucket(s) with traffic",
 		Func:            handlerS3Traffic,
 		BillingDataOnly: true,
+		PrepareResult:   prepareResult,
 	}.Register()
 }
 
@@ -54,7 +55,16 @@ func getBucketsWithNoTraffic(pluginRes *core.PluginResult, storage, bandwidth bu
 		if _, ok := bandwidth[bucketName]; ok {
 			pluginRes.Passed++
 		} else {
-			pluginRes.Details = append(pluginRes.Details, bucketName)
+			detail := bucketName
+			pluginRes.Details = append(pluginRes.Details, detail)
+			// The savings depend on whether the bucket can be deleted or archived, so they are not estimated
+			pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+				ResourceId:   bucketName,
+				ResourceType: "AWS::S3::Bucket",
+				Severity:     core.SeverityLow,
+				Remediation:  "Delete the bucket or move its objects to an infrequent access storage class",
+				Detail:       detail,
+			})
 		}
 	}
 	prepareResult(pluginRes)
@@ -0,0 +1,165 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_stopped_ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/trackit/trackit/aws/pricings"
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+)
+
+func init() {
+	// Register the plugin
+	core.AccountPlugin{
+		Name:          "Stopped EC2 instances",
+		Description:   "Returns the list of stopped EC2 instances whose attached volumes are still billed",
+		Category:      utils.PluginsCategories["EC2"],
+		Label:         "EC2 instance(s) running or without volume",
+		Func:          processStoppedEc2,
+		PrepareResult: prepareResult,
+	}.Register()
+}
+
+// prepareResult sets the Result and Status in the pluginRes struct
+func prepareResult(pluginRes *core.PluginResult) {
+	if pluginRes.Checked == pluginRes.Passed {
+		pluginRes.Status = "green"
+		pluginRes.Result = "You don't have any stopped EC2 instance with attached volumes"
+	} else {
+		pluginRes.Result = fmt.Sprintf("You have %d stopped EC2 instance(s) with attached volumes", pluginRes.Checked-pluginRes.Passed)
+		pluginRes.Status = utils.StatusPercentSteps{50, 80}.GetStatus(pluginRes.Checked, pluginRes.Passed)
+	}
+}
+
+// filterStoppedInstances returns the stopped instances of reservations which have EBS
+// volumes attached along with the number of instances checked
+// Terminated instances are not checked
+func filterStoppedInstances(reservations []*ec2.Reservation) ([]*ec2.Instance, int) {
+	stoppedInstances := []*ec2.Instance{}
+	checked := 0
+	for _, reservation := range reservations {
+		for _, instance := range reservation.Instances {
+			state := aws.StringValue(instance.State.Name)
+			if state == ec2.InstanceStateNameTerminated || state == ec2.InstanceStateNameShuttingDown {
+				continue
+			}
+			checked++
+			if state == ec2.InstanceStateNameStopped && len(instance.BlockDeviceMappings) > 0 {
+				stoppedInstances = append(stoppedInstances, instance)
+			}
+		}
+	}
+	return stoppedInstances, checked
+}
+
+// getStoppedInstances returns the stopped instances of a region which have EBS volumes attached
+// along with the number of instances checked
+func getStoppedInstances(svc *ec2.EC2) ([]*ec2.Instance, int, error) {
+	stoppedInstances := []*ec2.Instance{}
+	checked := 0
+	err := svc.DescribeInstancesPages(&ec2.DescribeInstancesInput{},
+		func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			pageInstances, pageChecked := filterStoppedInstances(page.Reservations)
+			stoppedInstances = append(stoppedInstances, pageInstances...)
+			checked += pageChecked
+			return true
+		})
+	return stoppedInstances, checked, err
+}
+
+// addVolumesCost adds the monthly cost of volumes to the instances they are attached to
+func addVolumesCost(costs map[string]float64, catalog pricings.ServiceCatalog, region string, volumes []*ec2.Volume) {
+	for _, volume := range volumes {
+		cost := utils.GetVolumeMonthlyCost(catalog, region, aws.StringValue(volume.VolumeType), aws.Int64Value(volume.Size))
+		for _, attachment := range volume.Attachments {
+			costs[aws.StringValue(attachment.InstanceId)] += cost
+		}
+	}
+}
+
+// getVolumesCostByInstance returns the monthly cost of the volumes attached to each instance of a region
+func getVolumesCostByInstance(svc *ec2.EC2, catalog pricings.ServiceCatalog, region string) (map[string]float64, error) {
+	costs := make(map[string]float64)
+	err := svc.DescribeVolumesPages(&ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("attachment.status"),
+			Values: []*string{aws.String(ec2.AttachmentStatusAttached)},
+		}},
+	}, func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
+		addVolumesCost(costs, catalog, region, page.Volumes)
+		return true
+	})
+	return costs, err
+}
+
+// getStoppedEc2Recommendation searches for stopped instances with attached volumes in every region available
+// It takes a core.PluginParams struct and a *core.PluginResult as parameters
+func getStoppedEc2Recommendation(pluginParams core.PluginParams, pluginRes *core.PluginResult) {
+	regions, err := utils.GetRegions(pluginParams.AccountCredentials)
+	if err != nil {
+		pluginRes.Status = "red"
+		pluginRes.Error = fmt.Sprintf("Unable to retrieve the list of regions: %s", err.Error())
+		return
+	}
+	catalog := utils.GetEbsCatalog(pluginParams.Context)
+	for _, region := range regions {
+		svc := utils.GetEc2ClientSession(pluginParams.AccountCredentials, aws.String(region))
+		stoppedInstances, checked, err := getStoppedInstances(svc)
+		if err != nil {
+			pluginRes.Status = "red"
+			pluginRes.Error = fmt.Sprintf("Unable to list instances: %s", err.Error())
+			return
+		}
+		pluginRes.Checked += checked
+		pluginRes.Passed += checked - len(stoppedInstances)
+		if len(stoppedInstances) == 0 {
+			continue
+		}
+		costs, err := getVolumesCostByInstance(svc, catalog, region)
+		if err != nil {
+			pluginRes.Status = "red"
+			pluginRes.Error = fmt.Sprintf("Unable to list volumes: %s", err.Error())
+			return
+		}
+		for _, instance := range stoppedInstances {
+			instanceId := aws.StringValue(instance.InstanceId)
+			detail := fmt.Sprintf("%s (%s)", instanceId, region)
+			pluginRes.Details = append(pluginRes.Details, detail)
+			pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+				ResourceId:              instanceId,
+				Region:                  region,
+				ResourceType:            "AWS::EC2::Instance",
+				Severity:                core.SeverityMedium,
+				EstimatedMonthlySavings: costs[instanceId],
+				Remediation:             "Snapshot the volumes and terminate the instance if it is not needed anymore",
+				Detail:                  detail,
+			})
+		}
+	}
+	prepareResult(pluginRes)
+}
+
+// processStoppedEc2 is the handler function for the Stopped EC2 instances plugin
+// it takes a core.PluginParams struct and returns a core.PluginResult struct
+func processStoppedEc2(params core.PluginParams) core.PluginResult {
+	res := core.PluginResult{}
+	getStoppedEc2Recommendation(params, &res)
+	return res
+}
@@ -0,0 +1,119 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_stopped_ec2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/trackit/trackit/aws/pricings"
+	core "github.com/trackit/trackit/plugins/account/core"
+)
+
+func TestPrepareResult(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		checked int
+		passed  int
+		status  string
+		result  string
+	}{
+		{"No instance", 0, 0, "green", "You don't have any stopped EC2 instance with attached volumes"},
+		{"All running", 3, 3, "green", "You don't have any stopped EC2 instance with attached volumes"},
+		{"Green threshold", 10, 8, "green", "You have 2 stopped EC2 instance(s) with attached volumes"},
+		{"Orange threshold", 10, 5, "orange", "You have 5 stopped EC2 instance(s) with attached volumes"},
+		{"Below orange threshold", 10, 4, "red", "You have 6 stopped EC2 instance(s) with attached volumes"},
+	} {
+		pr := core.PluginResult{Checked: c.checked, Passed: c.passed}
+		prepareResult(&pr)
+		if pr.Status != c.status {
+			t.Errorf("%s: Status should be %q, is %q instead.", c.name, c.status, pr.Status)
+		}
+		if pr.Result != c.result {
+			t.Errorf("%s: Result should be %q, is %q instead.", c.name, c.result, pr.Result)
+		}
+	}
+}
+
+func getTestInstance(id, state string, volumes ...string) *ec2.Instance {
+	mappings := []*ec2.InstanceBlockDeviceMapping{}
+	for _, volume := range volumes {
+		mappings = append(mappings, &ec2.InstanceBlockDeviceMapping{Ebs: &ec2.EbsInstanceBlockDevice{VolumeId: aws.String(volume)}})
+	}
+	return &ec2.Instance{
+		InstanceId:          aws.String(id),
+		State:               &ec2.InstanceState{Name: aws.String(state)},
+		BlockDeviceMappings: mappings,
+	}
+}
+
+func TestFilterStoppedInstances(t *testing.T) {
+	stoppedWithVolume := getTestInstance("i-stopped", ec2.InstanceStateNameStopped, "vol-1")
+	stoppingWithVolume := getTestInstance("i-stopping", ec2.InstanceStateNameStopping, "vol-2")
+	stoppedInstances, checked := filterStoppedInstances([]*ec2.Reservation{
+		{Instances: []*ec2.Instance{
+			getTestInstance("i-running", ec2.InstanceStateNameRunning, "vol-0"),
+			stoppedWithVolume,
+			getTestInstance("i-stopped-without-volume", ec2.InstanceStateNameStopped),
+		}},
+		{Instances: []*ec2.Instance{
+			getTestInstance("i-terminated", ec2.InstanceStateNameTerminated),
+			getTestInstance("i-shutting-down", ec2.InstanceStateNameShuttingDown, "vol-3"),
+			stoppingWithVolume,
+		}},
+	})
+	if checked != 4 {
+		t.Errorf("Checked should be 4, is %d instead.", checked)
+	}
+	if expected := []*ec2.Instance{stoppedWithVolume}; !reflect.DeepEqual(stoppedInstances, expected) {
+		t.Errorf("Stopped instances should be %v, are %v instead.", expected, stoppedInstances)
+	}
+}
+
+func TestAddVolumesCost(t *testing.T) {
+	catalog := pricings.ServiceCatalog{
+		Product: pricings.EBSProduct,
+		Skus: map[string]*pricings.SkuPricing{
+			"GP2": {
+				Sku:           "GP2",
+				ProductFamily: "Storage",
+				Region:        "us-east-1",
+				Attributes:    map[string]string{"volumeApiName": "gp2", "usagetype": "EBS:VolumeUsage.gp2"},
+				OnDemand:      []pricings.PriceDimension{{Unit: "GB-Mo", BeginRange: 0, EndRange: -1, PricePerUnit: 0.1}},
+			},
+		},
+	}
+	attachedTo := func(instanceIds ...string) []*ec2.VolumeAttachment {
+		attachments := []*ec2.VolumeAttachment{}
+		for _, instanceId := range instanceIds {
+			attachments = append(attachments, &ec2.VolumeAttachment{InstanceId: aws.String(instanceId)})
+		}
+		return attachments
+	}
+	costs := map[string]float64{}
+	addVolumesCost(costs, catalog, "us-east-1", []*ec2.Volume{
+		{VolumeType: aws.String("gp2"), Size: aws.Int64(100), Attachments: attachedTo("i-1")},
+		{VolumeType: aws.String("gp2"), Size: aws.Int64(50), Attachments: attachedTo("i-1", "i-2")},
+		{VolumeType: aws.String("gp2"), Size: aws.Int64(20), Attachments: attachedTo()},
+		{VolumeType: aws.String("io2"), Size: aws.Int64(100), Attachments: attachedTo("i-3")},
+	})
+	expected := map[string]float64{"i-1": 15, "i-2": 5, "i-3": 0}
+	if !reflect.DeepEqual(costs, expected) {
+		t.Errorf("Costs should be %v, are %v instead.", expected, costs)
+	}
+}
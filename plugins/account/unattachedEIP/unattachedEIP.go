@@ -25,14 +25,19 @@ import (
 	utils "github.com/trackit/trackit/plugins/utils"
 )
 
+// unattachedEIPHourlyCost is the hourly cost of an Elastic IP address which is not
+// associated with a running instance
+const unattachedEIPHourlyCost = 0.005
+
 func init() {
 	// Register the plugin
 	core.AccountPlugin{
-		Name:        "Unattached EIP",
-		Description: "Returns the list of unattached EIP",
-		Category:    utils.PluginsCategories["EC2"],
-		Label:       "attached EIP(s)",
-		Func:        processUnattachedEIP,
+		Name:          "Unattached EIP",
+		Description:   "Returns the list of unattached EIP",
+		Category:      utils.PluginsCategories["EC2"],
+		Label:         "attached EIP(s)",
+		Func:          processUnattachedEIP,
+		PrepareResult: prepareResult,
 	}.Register()
 }
 
@@ -59,7 +64,21 @@ func processEIP(pluginRes *core.PluginResult, region *string, eipRes *ec2.Descri
 				if eipDesc == "" {
 					eipDesc = aws.StringValue(eip.AssociationId)
 				}
-				pluginRes.Details = append(pluginRes.Details, fmt.Sprintf("%s (%s)", eipDesc, *region))
+				detail := fmt.Sprintf("%s (%s)", eipDesc, *region)
+				pluginRes.Details = append(pluginRes.Details, detail)
+				resourceId := aws.StringValue(eip.AllocationId)
+				if resourceId == "" {
+					resourceId = eipDesc
+				}
+				pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+					ResourceId:              resourceId,
+					Region:                  aws.StringValue(region),
+					ResourceType:            "AWS::EC2::EIP",
+					Severity:                core.SeverityLow,
+					EstimatedMonthlySavings: unattachedEIPHourlyCost * utils.HoursPerMonth,
+					Remediation:             "Release the Elastic IP address if it is not needed anymore",
+					Detail:                  detail,
+				})
 			}
 		}
 	}
@@ -0,0 +1,65 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_unattached_eip
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+)
+
+func TestProcessEIP(t *testing.T) {
+	pr := core.PluginResult{}
+	processEIP(&pr, aws.String("us-east-1"), &ec2.DescribeAddressesOutput{
+		Addresses: []*ec2.Address{
+			{PublicIp: aws.String("192.0.2.1"), AllocationId: aws.String("eipalloc-1"), AssociationId: aws.String("eipassoc-1")},
+			{PublicIp: aws.String("192.0.2.2"), AllocationId: aws.String("eipalloc-2")},
+			{PublicIp: aws.String("192.0.2.3")},
+		},
+	})
+	if pr.Checked != 3 || pr.Passed != 1 {
+		t.Errorf("Checked and Passed should be 3 and 1, are %d and %d instead.", pr.Checked, pr.Passed)
+	}
+	expected := []core.Finding{
+		{
+			ResourceId:              "eipalloc-2",
+			Region:                  "us-east-1",
+			ResourceType:            "AWS::EC2::EIP",
+			Severity:                core.SeverityLow,
+			EstimatedMonthlySavings: 3.65,
+			Remediation:             "Release the Elastic IP address if it is not needed anymore",
+			Detail:                  "192.0.2.2 (us-east-1)",
+		},
+		{
+			ResourceId:              "192.0.2.3",
+			Region:                  "us-east-1",
+			ResourceType:            "AWS::EC2::EIP",
+			Severity:                core.SeverityLow,
+			EstimatedMonthlySavings: 3.65,
+			Remediation:             "Release the Elastic IP address if it is not needed anymore",
+			Detail:                  "192.0.2.3 (us-east-1)",
+		},
+	}
+	if !reflect.DeepEqual(pr.Findings, expected) {
+		t.Errorf("Findings should be %v, are %v instead.", expected, pr.Findings)
+	}
+	if details := []string{"192.0.2.2 (us-east-1)", "192.0.2.3 (us-east-1)"}; !reflect.DeepEqual(pr.Details, details) {
+		t.Errorf("Details should be %v, are %v instead.", details, pr.Details)
+	}
+}
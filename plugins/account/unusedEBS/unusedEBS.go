@@ -16,9 +16,12 @@ package plugins_account_unused_ebs
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 
+	"github.com/trackit/trackit/aws/pricings"
 	"github.com/trackit/trackit/config"
 	core "github.com/trackit/trackit/plugins/account/core"
 	utils "github.com/trackit/trackit/plugins/utils"
@@ -27,22 +30,32 @@ import (
 func init() {
 	// Register the plugin
 	core.AccountPlugin{
-		Name:        "Unused EBS",
-		Description: "unused ebs plugin",
-		Category:    utils.PluginsCategories["EC2"],
-		Label:       "attached EBS volume(s)",
-		Func:        processUnusedEBS,
+		Name:          "Unused EBS",
+		Description:   "unused ebs plugin",
+		Category:      utils.PluginsCategories["EC2"],
+		Label:         "attached EBS volume(s)",
+		Func:          processUnusedEBS,
+		PrepareResult: prepareResult,
 	}.Register()
 }
 
-// prepareResult takes a map of unused EBS and a *core.PluginResult as parameters
-// and fills the PluginResult
-func prepareResult(unusedByAZ map[string]int, pluginRes *core.PluginResult) {
-	total := 0
-	for az, totalAz := range unusedByAZ {
-		pluginRes.Details = append(pluginRes.Details, fmt.Sprintf("%s: %d unused volume(s)", az, totalAz))
-		total += totalAz
+// prepareResult counts the unused volumes of each availability zone from the
+// findings of pluginRes, and sets its Details, Result and Status
+func prepareResult(pluginRes *core.PluginResult) {
+	unusedByAZ := make(map[string]int)
+	for _, finding := range pluginRes.Findings {
+		unusedByAZ[finding.AvailabilityZone]++
+	}
+	azs := make([]string, 0, len(unusedByAZ))
+	for az := range unusedByAZ {
+		azs = append(azs, az)
 	}
+	sort.Strings(azs)
+	pluginRes.Details = make([]string, 0, len(azs))
+	for _, az := range azs {
+		pluginRes.Details = append(pluginRes.Details, fmt.Sprintf("%s: %d unused volume(s)", az, unusedByAZ[az]))
+	}
+	total := pluginRes.Checked - pluginRes.Passed
 	if total == 0 {
 		pluginRes.Result = "You don't have any unused EBS"
 		pluginRes.Status = "green"
@@ -52,6 +65,19 @@ func prepareResult(unusedByAZ map[string]int, pluginRes *core.PluginResult) {
 	pluginRes.Status = utils.StatusPercentSteps{50, 95}.GetStatus(pluginRes.Checked, pluginRes.Passed)
 }
 
+// getUnusedVolumeFinding returns the finding of an unused volume
+func getUnusedVolumeFinding(catalog pricings.ServiceCatalog, region string, volume *ec2.Volume) core.Finding {
+	return core.Finding{
+		ResourceId:              aws.StringValue(volume.VolumeId),
+		Region:                  region,
+		ResourceType:            "AWS::EC2::Volume",
+		Severity:                core.SeverityMedium,
+		EstimatedMonthlySavings: utils.GetVolumeMonthlyCost(catalog, region, aws.StringValue(volume.VolumeType), aws.Int64Value(volume.Size)),
+		Remediation:             "Snapshot the volume and delete it",
+		AvailabilityZone:        aws.StringValue(volume.AvailabilityZone),
+	}
+}
+
 // getUnusedEBsRecommendation searches for unused ebs in every region available
 // It takes a core.PluginParams struct and a *core.PluginResult as parameters
 func getUnusedEBsRecommendation(pluginParams core.PluginParams, pluginRes *core.PluginResult) {
@@ -63,7 +89,7 @@ func getUnusedEBsRecommendation(pluginParams core.PluginParams, pluginRes *core.
 		return
 	}
 
-	unusedByAZ := make(map[string]int)
+	catalog := utils.GetEbsCatalog(pluginParams.Context)
 	for _, region := range regionsOutput.Regions {
 		svc = utils.GetEc2ClientSession(pluginParams.AccountCredentials, region.RegionName)
 		err = svc.DescribeVolumesPages(&ec2.DescribeVolumesInput{},
@@ -71,7 +97,7 @@ func getUnusedEBsRecommendation(pluginParams core.PluginParams, pluginRes *core.
 				for _, volume := range page.Volumes {
 					pluginRes.Checked++
 					if volume != nil && *volume.State == "available" {
-						unusedByAZ[*volume.AvailabilityZone] = unusedByAZ[*volume.AvailabilityZone] + 1
+						pluginRes.Findings = append(pluginRes.Findings, getUnusedVolumeFinding(catalog, *region.RegionName, volume))
 					} else {
 						pluginRes.Passed++
 					}
@@ -84,7 +110,7 @@ func getUnusedEBsRecommendation(pluginParams core.PluginParams, pluginRes *core.
 			return
 		}
 	}
-	prepareResult(unusedByAZ, pluginRes)
+	prepareResult(pluginRes)
 }
 
 // processUnusedEBS is the handler function for the Unused EBS plugin
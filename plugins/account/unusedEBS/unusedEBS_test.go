@@ -0,0 +1,54 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_unused_ebs
+
+import (
+	"reflect"
+	"testing"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+)
+
+func TestPrepareResultCountsUnsuppressedVolumes(t *testing.T) {
+	plugin := core.AccountPlugin{Name: "Unused EBS", PrepareResult: prepareResult}
+	pr := core.PluginResult{
+		Checked: 4,
+		Passed:  1,
+		Findings: []core.Finding{
+			{ResourceId: "vol-1", AvailabilityZone: "us-east-1b"},
+			{ResourceId: "vol-2", AvailabilityZone: "us-east-1a"},
+			{ResourceId: "vol-3", AvailabilityZone: "us-east-1b"},
+		},
+	}
+	prepareResult(&pr)
+	expected := []string{"us-east-1a: 1 unused volume(s)", "us-east-1b: 2 unused volume(s)"}
+	if !reflect.DeepEqual(pr.Details, expected) {
+		t.Errorf("Details should be %v, are %v instead.", expected, pr.Details)
+	} else if pr.Result != "You have 3 unused EBS" {
+		t.Errorf("Unexpected result %q.", pr.Result)
+	}
+
+	suppression := core.Suppression{ResourcePattern: "vol-3"}
+	if err := suppression.Compile(); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	pr.ApplySuppressions(plugin, []core.Suppression{suppression})
+	expected = []string{"us-east-1a: 1 unused volume(s)", "us-east-1b: 1 unused volume(s)", "1 suppressed resource(s)"}
+	if !reflect.DeepEqual(pr.Details, expected) {
+		t.Errorf("Details should be %v after suppression, are %v instead.", expected, pr.Details)
+	} else if pr.Result != "You have 2 unused EBS" {
+		t.Errorf("Unexpected result %q after suppression.", pr.Result)
+	}
+}
@@ -0,0 +1,133 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_unused_nat_gateway
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+)
+
+// natGatewayHourlyCost is the hourly cost of a NAT gateway, excluding the data processed
+const natGatewayHourlyCost = 0.045
+
+func init() {
+	// Register the plugin
+	core.AccountPlugin{
+		Name:          "Unused NAT gateways",
+		Description:   "Returns the list of NAT gateways which did not send any traffic during the last week",
+		Category:      utils.PluginsCategories["VPC"],
+		Label:         "NAT gateway(s) with traffic",
+		Func:          processUnusedNatGateway,
+		PrepareResult: prepareResult,
+	}.Register()
+}
+
+// prepareResult sets the Result and Status in the pluginRes struct
+func prepareResult(pluginRes *core.PluginResult) {
+	if pluginRes.Checked == pluginRes.Passed {
+		pluginRes.Status = "green"
+		pluginRes.Result = "All your NAT gateways have traffic"
+	} else {
+		pluginRes.Result = fmt.Sprintf("You have %d unused NAT gateway(s)", pluginRes.Checked-pluginRes.Passed)
+		pluginRes.Status = utils.StatusPercentSteps{50, 80}.GetStatus(pluginRes.Checked, pluginRes.Passed)
+	}
+}
+
+// isNatGatewayUnused returns true if a NAT gateway did not send any byte to its
+// destinations during the last utils.IdleDays days
+func isNatGatewayUnused(svc *cloudwatch.CloudWatch, natGateway *ec2.NatGateway, date time.Time) (bool, error) {
+	return utils.IsIdle(svc, utils.MetricQuery{
+		Namespace:  "AWS/NATGateway",
+		MetricName: "BytesOutToDestination",
+		Statistic:  cloudwatch.StatisticSum,
+		Dimensions: map[string]string{"NatGatewayId": aws.StringValue(natGateway.NatGatewayId)},
+	}, date)
+}
+
+// addUnusedNatGateway adds an unused NAT gateway to the details and findings of pluginRes
+func addUnusedNatGateway(pluginRes *core.PluginResult, natGatewayId, region string) {
+	detail := fmt.Sprintf("%s (%s)", natGatewayId, region)
+	pluginRes.Details = append(pluginRes.Details, detail)
+	pluginRes.Findings = append(pluginRes.Findings, core.Finding{
+		ResourceId:              natGatewayId,
+		Region:                  region,
+		ResourceType:            "AWS::EC2::NatGateway",
+		Severity:                core.SeverityMedium,
+		EstimatedMonthlySavings: natGatewayHourlyCost * utils.HoursPerMonth,
+		Remediation:             "Delete the NAT gateway if the private subnets do not need internet access",
+		Detail:                  detail,
+	})
+}
+
+// getUnusedNatGatewayRecommendation searches for unused NAT gateways in every region available
+// It takes a core.PluginParams struct and a *core.PluginResult as parameters
+func getUnusedNatGatewayRecommendation(pluginParams core.PluginParams, pluginRes *core.PluginResult) {
+	regions, err := utils.GetRegions(pluginParams.AccountCredentials)
+	if err != nil {
+		pluginRes.Status = "red"
+		pluginRes.Error = fmt.Sprintf("Unable to retrieve the list of regions: %s", err.Error())
+		return
+	}
+	date := time.Now().UTC()
+	for _, region := range regions {
+		svc := utils.GetEc2ClientSession(pluginParams.AccountCredentials, aws.String(region))
+		cw := utils.GetCloudWatchClientSession(pluginParams.AccountCredentials, aws.String(region))
+		var metricErr error
+		err = svc.DescribeNatGatewaysPages(&ec2.DescribeNatGatewaysInput{
+			Filter: []*ec2.Filter{{
+				Name:   aws.String("state"),
+				Values: []*string{aws.String(ec2.NatGatewayStateAvailable)},
+			}},
+		}, func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+			for _, natGateway := range page.NatGateways {
+				var unused bool
+				if unused, metricErr = isNatGatewayUnused(cw, natGateway, date); metricErr != nil {
+					return false
+				}
+				pluginRes.Checked++
+				if unused {
+					addUnusedNatGateway(pluginRes, aws.StringValue(natGateway.NatGatewayId), region)
+				} else {
+					pluginRes.Passed++
+				}
+			}
+			return true
+		})
+		if err == nil {
+			err = metricErr
+		}
+		if err != nil {
+			pluginRes.Status = "red"
+			pluginRes.Error = fmt.Sprintf("Unable to check NAT gateways: %s", err.Error())
+			return
+		}
+	}
+	prepareResult(pluginRes)
+}
+
+// processUnusedNatGateway is the handler function for the Unused NAT gateways plugin
+// it takes a core.PluginParams struct and returns a core.PluginResult struct
+func processUnusedNatGateway(params core.PluginParams) core.PluginResult {
+	res := core.PluginResult{}
+	getUnusedNatGatewayRecommendation(params, &res)
+	return res
+}
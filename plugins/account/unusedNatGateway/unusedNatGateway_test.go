@@ -0,0 +1,68 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_account_unused_nat_gateway
+
+import (
+	"reflect"
+	"testing"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+	utils "github.com/trackit/trackit/plugins/utils"
+)
+
+func TestPrepareResult(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		checked int
+		passed  int
+		status  string
+		result  string
+	}{
+		{"No NAT gateway", 0, 0, "green", "All your NAT gateways have traffic"},
+		{"All used", 2, 2, "green", "All your NAT gateways have traffic"},
+		{"Green threshold", 5, 4, "green", "You have 1 unused NAT gateway(s)"},
+		{"Orange threshold", 4, 2, "orange", "You have 2 unused NAT gateway(s)"},
+		{"Below orange threshold", 3, 1, "red", "You have 2 unused NAT gateway(s)"},
+	} {
+		pr := core.PluginResult{Checked: c.checked, Passed: c.passed}
+		prepareResult(&pr)
+		if pr.Status != c.status {
+			t.Errorf("%s: Status should be %q, is %q instead.", c.name, c.status, pr.Status)
+		}
+		if pr.Result != c.result {
+			t.Errorf("%s: Result should be %q, is %q instead.", c.name, c.result, pr.Result)
+		}
+	}
+}
+
+func TestAddUnusedNatGateway(t *testing.T) {
+	pr := core.PluginResult{}
+	addUnusedNatGateway(&pr, "nat-0123456789abcdef0", "us-west-2")
+	expected := []core.Finding{{
+		ResourceId:              "nat-0123456789abcdef0",
+		Region:                  "us-west-2",
+		ResourceType:            "AWS::EC2::NatGateway",
+		Severity:                core.SeverityMedium,
+		EstimatedMonthlySavings: natGatewayHourlyCost * utils.HoursPerMonth,
+		Remediation:             "Delete the NAT gateway if the private subnets do not need internet access",
+		Detail:                  "nat-0123456789abcdef0 (us-west-2)",
+	}}
+	if !reflect.DeepEqual(pr.Findings, expected) {
+		t.Errorf("Findings should be %v, are %v instead.", expected, pr.Findings)
+	}
+	if details := []string{"nat-0123456789abcdef0 (us-west-2)"}; !reflect.DeepEqual(pr.Details, details) {
+		t.Errorf("Details should be %v, are %v instead.", details, pr.Details)
+	}
+}
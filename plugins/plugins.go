@@ -16,8 +16,14 @@ package plugins
 
 import (
 	// This is solely for the side effects of importation, i.e. registration of the plugins
+	_ "github.com/trackit/trackit/plugins/account/idleELB"
+	_ "github.com/trackit/trackit/plugins/account/idleRDS"
 	_ "github.com/trackit/trackit/plugins/account/networkEc2"
+	_ "github.com/trackit/trackit/plugins/account/oldEbsSnapshots"
+	_ "github.com/trackit/trackit/plugins/account/oldRdsSnapshots"
 	_ "github.com/trackit/trackit/plugins/account/s3Traffic"
+	_ "github.com/trackit/trackit/plugins/account/stoppedEc2"
 	_ "github.com/trackit/trackit/plugins/account/unattachedEIP"
 	_ "github.com/trackit/trackit/plugins/account/unusedEBS"
+	_ "github.com/trackit/trackit/plugins/account/unusedNatGateway"
 )
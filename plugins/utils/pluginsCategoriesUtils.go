@@ -19,4 +19,7 @@ package plugins_utils
 var PluginsCategories = map[string]string{
 	"EC2": "EC2",
 	"S3":  "S3",
+	"RDS": "RDS",
+	"ELB": "ELB",
+	"VPC": "VPC",
 }
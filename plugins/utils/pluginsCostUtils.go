@@ -0,0 +1,68 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_utils
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws/pricings"
+	"github.com/trackit/trackit/db"
+)
+
+// HoursPerMonth is the average number of hours in a month
+const HoursPerMonth = 730.0
+
+// EstimateMonthlyCost extrapolates a cost incurred since the beginning of the month
+// of date to the whole month
+func EstimateMonthlyCost(monthToDateCost float64, date time.Time) float64 {
+	daysInMonth := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return monthToDateCost / float64(date.Day()) * float64(daysInMonth)
+}
+
+// GetEbsCatalog retrieves the EBS pricings
+// Without pricings the resources can still be reported, so an error is only logged
+// and an empty catalog is returned
+func GetEbsCatalog(ctx context.Context) pricings.ServiceCatalog {
+	catalog, err := pricings.GetServiceCatalog(db.Db, pricings.EBSProduct)
+	if err != nil {
+		jsonlog.LoggerFromContextOrDefault(ctx).Warning("Unable to retrieve EBS pricings", err.Error())
+	}
+	return catalog
+}
+
+// GetVolumeMonthlyCost returns the monthly storage cost of an EBS volume
+// It returns 0 if the volume type is not found in the pricings
+func GetVolumeMonthlyCost(catalog pricings.ServiceCatalog, region, volumeType string, size int64) float64 {
+	sku, err := catalog.FindOne(region, "Storage", map[string]string{"volumeApiName": volumeType})
+	if err != nil {
+		return 0
+	}
+	return sku.Cost(float64(size))
+}
+
+// GetSnapshotMonthlyCost returns the monthly storage cost of an EBS snapshot of a given size
+// It returns 0 if the snapshot storage is not found in the pricings
+func GetSnapshotMonthlyCost(catalog pricings.ServiceCatalog, region string, size int64) float64 {
+	for _, sku := range catalog.Find(region, "Storage Snapshot", nil) {
+		if strings.HasSuffix(sku.Attributes["usagetype"], "EBS:SnapshotUsage") {
+			return sku.Cost(float64(size))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_utils
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/trackit/trackit/aws/pricings"
+)
+
+func TestEstimateMonthlyCost(t *testing.T) {
+	for _, c := range []struct {
+		monthToDateCost float64
+		date            time.Time
+		monthlyCost     float64
+	}{
+		{10, time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC), 310},
+		{15, time.Date(2021, time.April, 15, 0, 0, 0, 0, time.UTC), 30},
+		{28, time.Date(2021, time.February, 28, 0, 0, 0, 0, time.UTC), 28},
+		{29, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), 29},
+		{0, time.Date(2021, time.May, 10, 0, 0, 0, 0, time.UTC), 0},
+	} {
+		if monthlyCost := EstimateMonthlyCost(c.monthToDateCost, c.date); math.Abs(monthlyCost-c.monthlyCost) > 1e-9 {
+			t.Errorf("Monthly cost of %f on %s should be %f, is %f instead.", c.monthToDateCost, c.date.Format("2006-01-02"), c.monthlyCost, monthlyCost)
+		}
+	}
+}
+
+// getTestEbsCatalog returns an EBS catalog with the storage of gp2 and st1
+// volumes in us-east-1
+func getTestEbsCatalog() pricings.ServiceCatalog {
+	sku := func(sku, productFamily string, attributes map[string]string, price float64) *pricings.SkuPricing {
+		return &pricings.SkuPricing{
+			Sku:           sku,
+			ProductFamily: productFamily,
+			Region:        "us-east-1",
+			Attributes:    attributes,
+			OnDemand:      []pricings.PriceDimension{{Unit: "GB-Mo", BeginRange: 0, EndRange: -1, PricePerUnit: price}},
+		}
+	}
+	return pricings.ServiceCatalog{
+		Product: pricings.EBSProduct,
+		Skus: map[string]*pricings.SkuPricing{
+			"GP2":      sku("GP2", "Storage", map[string]string{"volumeApiName": "gp2", "usagetype": "EBS:VolumeUsage.gp2"}, 0.1),
+			"ST1":      sku("ST1", "Storage", map[string]string{"volumeApiName": "st1", "usagetype": "EBS:VolumeUsage.st1"}, 0.045),
+			"SNAPSHOT": sku("SNAPSHOT", "Storage Snapshot", map[string]string{"usagetype": "EBS:SnapshotUsage"}, 0.05),
+			"ARCHIVE":  sku("ARCHIVE", "Storage Snapshot", map[string]string{"usagetype": "EBS:SnapshotArchiveStorage"}, 0.0125),
+		},
+	}
+}
+
+func TestGetVolumeMonthlyCost(t *testing.T) {
+	catalog := getTestEbsCatalog()
+	for _, c := range []struct {
+		region     string
+		volumeType string
+		size       int64
+		cost       float64
+	}{
+		{"us-east-1", "gp2", 100, 10},
+		{"us-east-1", "st1", 500, 22.5},
+		{"us-east-1", "io2", 100, 0},
+		{"eu-west-1", "gp2", 100, 0},
+	} {
+		if cost := GetVolumeMonthlyCost(catalog, c.region, c.volumeType, c.size); math.Abs(cost-c.cost) > 1e-9 {
+			t.Errorf("Cost of a %d GB %s volume in %s should be %f, is %f instead.", c.size, c.volumeType, c.region, c.cost, cost)
+		}
+	}
+	if cost := GetVolumeMonthlyCost(pricings.ServiceCatalog{}, "us-east-1", "gp2", 100); cost != 0 {
+		t.Errorf("Cost without pricings should be 0, is %f instead.", cost)
+	}
+}
+
+func TestGetSnapshotMonthlyCost(t *testing.T) {
+	catalog := getTestEbsCatalog()
+	for _, c := range []struct {
+		region string
+		size   int64
+		cost   float64
+	}{
+		{"us-east-1", 100, 5},
+		{"us-east-1", 0, 0},
+		{"eu-west-1", 100, 0},
+	} {
+		if cost := GetSnapshotMonthlyCost(catalog, c.region, c.size); math.Abs(cost-c.cost) > 1e-9 {
+			t.Errorf("Cost of a %d GB snapshot in %s should be %f, is %f instead.", c.size, c.region, c.cost, cost)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_utils
+
+import (
+	"math"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// IdleDays is the number of days during which a resource must have no activity
+// to be considered idle
+const IdleDays = 7
+
+// MetricQuery describes a CloudWatch metric of a single resource
+// Statistic should be either cloudwatch.StatisticSum or cloudwatch.StatisticMaximum
+type MetricQuery struct {
+	Namespace  string
+	MetricName string
+	Statistic  string
+	Dimensions map[string]string
+}
+
+// GetMetricValue returns the statistic of a metric over the period between start and end
+// It returns 0 if CloudWatch has no datapoint for the metric during the period
+func GetMetricValue(svc *cloudwatch.CloudWatch, query MetricQuery, start, end time.Time) (float64, error) {
+	dimensions := make([]*cloudwatch.Dimension, 0, len(query.Dimensions))
+	for name, value := range query.Dimensions {
+		dimensions = append(dimensions, &cloudwatch.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(value),
+		})
+	}
+	stats, err := svc.GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(query.Namespace),
+		MetricName: aws.String(query.MetricName),
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int64(int64(end.Sub(start).Seconds())),
+		Statistics: []*string{aws.String(query.Statistic)},
+		Dimensions: dimensions,
+	})
+	if err != nil || len(stats.Datapoints) == 0 {
+		return 0, err
+	}
+	var value float64
+	for _, datapoint := range stats.Datapoints {
+		switch query.Statistic {
+		case cloudwatch.StatisticSum:
+			value += aws.Float64Value(datapoint.Sum)
+		case cloudwatch.StatisticMaximum:
+			value = math.Max(value, aws.Float64Value(datapoint.Maximum))
+		}
+	}
+	return value, nil
+}
+
+// IsIdle returns true if the metric of a resource stayed at 0 during the last IdleDays days
+// A resource without any datapoint is considered idle
+func IsIdle(svc *cloudwatch.CloudWatch, query MetricQuery, date time.Time) (bool, error) {
+	return IsBelowThreshold(svc, query, date, 0)
+}
+
+// IsBelowThreshold returns true if the metric of a resource stayed at or below
+// threshold during the last IdleDays days
+// A resource without any datapoint is considered below any positive threshold
+func IsBelowThreshold(svc *cloudwatch.CloudWatch, query MetricQuery, date time.Time, threshold float64) (bool, error) {
+	value, err := GetMetricValue(svc, query, date.AddDate(0, 0, -IdleDays), date)
+	if err != nil {
+		return false, err
+	}
+	return value <= threshold, nil
+}
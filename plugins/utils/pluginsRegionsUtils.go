@@ -0,0 +1,37 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package plugins_utils
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/trackit/trackit/config"
+)
+
+// GetRegions returns the names of the regions available for an account
+func GetRegions(creds *credentials.Credentials) ([]string, error) {
+	svc := GetEc2ClientSession(creds, &config.AwsRegion)
+	regionsOutput, err := svc.DescribeRegions(&ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+	regions := make([]string, 0, len(regionsOutput.Regions))
+	for _, region := range regionsOutput.Regions {
+		regions = append(regions, aws.StringValue(region.RegionName))
+	}
+	return regions, nil
+}
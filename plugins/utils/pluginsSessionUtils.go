@@ -18,7 +18,11 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
@@ -40,3 +44,43 @@ func GetS3ClientSession(creds *credentials.Credentials) *s3.S3 {
 	}))
 	return s3.New(sess)
 }
+
+// GetRdsClientSession is a utility function to create an RDS session
+// it takes credentials and a region and returns an RDS session
+func GetRdsClientSession(creds *credentials.Credentials, region *string) *rds.RDS {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: creds,
+		Region:      region,
+	}))
+	return rds.New(sess)
+}
+
+// GetElbClientSession is a utility function to create a classic load balancing session
+// it takes credentials and a region and returns an ELB session
+func GetElbClientSession(creds *credentials.Credentials, region *string) *elb.ELB {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: creds,
+		Region:      region,
+	}))
+	return elb.New(sess)
+}
+
+// GetElbv2ClientSession is a utility function to create an application and network load balancing session
+// it takes credentials and a region and returns an ELBV2 session
+func GetElbv2ClientSession(creds *credentials.Credentials, region *string) *elbv2.ELBV2 {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: creds,
+		Region:      region,
+	}))
+	return elbv2.New(sess)
+}
+
+// GetCloudWatchClientSession is a utility function to create a CloudWatch session
+// it takes credentials and a region and returns a CloudWatch session
+func GetCloudWatchClientSession(creds *credentials.Credentials, region *string) *cloudwatch.CloudWatch {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: creds,
+		Region:      region,
+	}))
+	return cloudwatch.New(sess)
+}
@@ -0,0 +1,278 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/trackit/trackit/aws"
+	tmail "github.com/trackit/trackit/mail"
+	"github.com/trackit/trackit/models"
+)
+
+const (
+	// DatePolicyPreviousMonth generates the report for the last month with
+	// complete billing data.
+	DatePolicyPreviousMonth = "previousMonth"
+	// DatePolicyCurrentMonth generates the report for the current month.
+	DatePolicyCurrentMonth = "currentMonth"
+)
+
+const maxDefinitionRecipients = 20
+
+// Definition is a report saved by a user, which is generated instead of the
+// default report by the spreadsheet tasks of its AWS account. A master
+// definition is generated by the master spreadsheet task and includes the
+// selected sub accounts, or all of them if none is selected.
+type Definition struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"        req:"nonzero"`
+	Master      bool     `json:"master"`
+	Modules     []string `json:"modules"     req:"nonzero"`
+	AwsAccounts []int    `json:"awsAccounts"`
+	DatePolicy  string   `json:"datePolicy"`
+	Format      Format   `json:"format"`
+	Recipients  []string `json:"recipients"`
+}
+
+// ModuleList is the list of the modules and options available in definitions.
+type ModuleList struct {
+	Modules      []string `json:"modules"`
+	Formats      []Format `json:"formats"`
+	DatePolicies []string `json:"datePolicies"`
+}
+
+// getAvailableModules returns the modules which can be chosen in a definition.
+func getAvailableModules() []module {
+	available := make([]module, 0, len(modules)+1)
+	available = append(available, modules...)
+	return append(available, tagsUsageReportModule)
+}
+
+// GetModuleList returns the modules and options available in definitions.
+func GetModuleList() ModuleList {
+	list := ModuleList{
+		Modules:      []string{},
+		Formats:      []Format{FormatXlsx, FormatHtml, FormatCsv},
+		DatePolicies: []string{DatePolicyPreviousMonth, DatePolicyCurrentMonth},
+	}
+	for _, m := range getAvailableModules() {
+		list.Modules = append(list.Modules, m.Name)
+	}
+	return list
+}
+
+// getModulesByName returns the modules with the given names, in order.
+func getModulesByName(names []string) ([]module, error) {
+	available := getAvailableModules()
+	selected := make([]module, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, m := range available {
+			if m.Name == name {
+				selected = append(selected, m)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("module %s does not exist", name)
+		}
+	}
+	return selected, nil
+}
+
+// validate checks a definition of an AWS account. subAccounts are the ids
+// of the accounts a master definition can select.
+func (d *Definition) validate(subAccounts []int) error {
+	if d.Name == "" || len(d.Name) > 255 {
+		return errors.New("name must be between 1 and 255 characters")
+	} else if len(d.Modules) == 0 {
+		return errors.New("at least one module is required")
+	} else if _, err := getModulesByName(d.Modules); err != nil {
+		return err
+	} else if d.DatePolicy == "" {
+		d.DatePolicy = DatePolicyPreviousMonth
+	} else if d.DatePolicy != DatePolicyPreviousMonth && d.DatePolicy != DatePolicyCurrentMonth {
+		return fmt.Errorf("date policy %s does not exist", d.DatePolicy)
+	}
+	format, err := ParseFormat(string(d.Format))
+	if err != nil {
+		return err
+	}
+	d.Format = format
+	if !d.Master && len(d.AwsAccounts) > 0 {
+		return errors.New("only master definitions can select accounts")
+	}
+	for _, id := range d.AwsAccounts {
+		if !containsInt(subAccounts, id) {
+			return fmt.Errorf("account %d is not a sub account", id)
+		}
+	}
+	if len(d.Recipients) > maxDefinitionRecipients {
+		return fmt.Errorf("a definition can not have more than %d recipients", maxDefinitionRecipients)
+	}
+	for _, recipient := range d.Recipients {
+		if address, err := mail.ParseAddress(recipient); err != nil || address.Address != recipient {
+			return fmt.Errorf("recipient %s is not a valid email address", recipient)
+		}
+	}
+	return nil
+}
+
+// reportDate returns the date given to the modules for a definition, a
+// zero date meaning the last month with complete billing data. A date
+// requested for the generation takes precedence over the policy.
+func (d Definition) reportDate(date time.Time) time.Time {
+	if date.IsZero() && d.DatePolicy == DatePolicyCurrentMonth {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return date
+}
+
+// selectAccounts returns the accounts of aas included in a master definition.
+func (d Definition) selectAccounts(aas []aws.AwsAccount) []aws.AwsAccount {
+	if len(d.AwsAccounts) == 0 {
+		return aas
+	}
+	selected := make([]aws.AwsAccount, 0, len(d.AwsAccounts))
+	for _, aa := range aas {
+		if containsInt(d.AwsAccounts, aa.Id) {
+			selected = append(selected, aa)
+		}
+	}
+	return selected
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// definitionFromDbDefinition builds a Definition from its database row
+func definitionFromDbDefinition(dbDefinition models.ReportDefinition) (Definition, error) {
+	definition := Definition{
+		Id:         dbDefinition.ID,
+		Name:       dbDefinition.Name,
+		Master:     dbDefinition.Master,
+		DatePolicy: dbDefinition.DatePolicy,
+		Format:     Format(dbDefinition.Format),
+	}
+	if err := json.Unmarshal(dbDefinition.Modules, &definition.Modules); err != nil {
+		return definition, err
+	}
+	if err := json.Unmarshal(dbDefinition.AwsAccounts, &definition.AwsAccounts); err != nil {
+		return definition, err
+	}
+	err := json.Unmarshal(dbDefinition.Recipients, &definition.Recipients)
+	return definition, err
+}
+
+// setDbDefinition sets the fields of a database row from a Definition
+func setDbDefinition(dbDefinition *models.ReportDefinition, definition Definition) (err error) {
+	if definition.AwsAccounts == nil {
+		definition.AwsAccounts = []int{}
+	}
+	if definition.Recipients == nil {
+		definition.Recipients = []string{}
+	}
+	dbDefinition.Name = definition.Name
+	dbDefinition.Master = definition.Master
+	dbDefinition.DatePolicy = definition.DatePolicy
+	dbDefinition.Format = string(definition.Format)
+	if dbDefinition.Modules, err = json.Marshal(definition.Modules); err != nil {
+		return
+	}
+	if dbDefinition.AwsAccounts, err = json.Marshal(definition.AwsAccounts); err != nil {
+		return
+	}
+	dbDefinition.Recipients, err = json.Marshal(definition.Recipients)
+	return
+}
+
+// GetDefinitions returns the report definitions of an AWS account. Only the
+// master definitions are returned if master is true, and only the others
+// otherwise.
+func GetDefinitions(db models.DB, aaId int, master bool) ([]Definition, error) {
+	all, err := getAllDefinitions(db, aaId)
+	if err != nil {
+		return nil, err
+	}
+	definitions := make([]Definition, 0, len(all))
+	for _, definition := range all {
+		if definition.Master == master {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions, nil
+}
+
+// getAllDefinitions returns all the report definitions of an AWS account.
+func getAllDefinitions(db models.DB, aaId int) ([]Definition, error) {
+	dbDefinitions, err := models.ReportDefinitionByAwsAccountID(db, aaId)
+	if err != nil {
+		return nil, err
+	}
+	definitions := make([]Definition, 0, len(dbDefinitions))
+	for _, dbDefinition := range dbDefinitions {
+		definition, err := definitionFromDbDefinition(*dbDefinition)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// getDbDefinitionForAccount returns the database row of a definition if it
+// belongs to the AWS account.
+func getDbDefinitionForAccount(db models.DB, aaId int, definitionId int) (*models.ReportDefinition, error) {
+	dbDefinition, err := models.ReportDefinitionByID(db, definitionId)
+	if err != nil {
+		return nil, err
+	} else if dbDefinition.AwsAccountID != aaId {
+		return nil, errors.New("report definition does not belong to the account")
+	}
+	return dbDefinition, nil
+}
+
+// getDefinitionMailBody renders the report of a definition as the HTML body
+// of the mail sent to its recipients.
+func getDefinitionMailBody(ctx context.Context, aa aws.AwsAccount, definition Definition, filename string, doc *document) (string, error) {
+	intro := fmt.Sprintf("The report %s of the AWS account %s has been generated: %s. You can download it on https://re.trackit.io/.", definition.Name, formatAwsAccount(aa), filename)
+	var body strings.Builder
+	if err := renderHtmlWithIntro(ctx, doc, intro, &body); err != nil {
+		return "", err
+	}
+	return body.String(), nil
+}
+
+// notifyDefinitionRecipient sends the rendered report of a definition to one
+// of its recipients.
+func notifyDefinitionRecipient(ctx context.Context, recipient string, definition Definition, body string) error {
+	subject := fmt.Sprintf("TrackIt report %s is available", definition.Name)
+	return tmail.SendHtmlMail(recipient, subject, body, ctx)
+}
@@ -0,0 +1,209 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+// definitionIdQueryArg allows to get the ID of a report definition in the URL parameters.
+var definitionIdQueryArg = routes.QueryArg{
+	Name:        "definition",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of a report definition.",
+}
+
+// definitionExample is the example body of the report definitions routes.
+var definitionExample = Definition{
+	Name:       "Finance",
+	Modules:    []string{"Cost Variations (Last Month)", "S3 Cost Report"},
+	DatePolicy: DatePolicyPreviousMonth,
+	Format:     FormatHtml,
+	Recipients: []string{"finance@example.com"},
+}
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getDefinitions).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.Documentation{
+				Summary:     "get the report definitions of an aws account",
+				Description: "Responds with the report definitions of the AWS account.",
+			},
+			users.RequirePermission{users.PermissionRunReports},
+		),
+		http.MethodPost: routes.H(postDefinition).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{definitionExample},
+			routes.Documentation{
+				Summary:     "create a report definition",
+				Description: "Creates a report definition with its modules, accounts, date policy, format and recipients.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+		http.MethodPatch: routes.H(patchDefinition).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.QueryArgs{definitionIdQueryArg},
+			routes.RequestBody{definitionExample},
+			routes.Documentation{
+				Summary:     "edit a report definition",
+				Description: "Replaces the fields of a report definition.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+		http.MethodDelete: routes.H(deleteDefinition).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.QueryArgs{definitionIdQueryArg},
+			routes.Documentation{
+				Summary:     "delete a report definition",
+				Description: "Deletes a report definition.",
+			},
+			users.RequirePermission{users.PermissionManageAccounts},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.QueryArgs{routes.AwsAccountIdQueryArg},
+		aws.RequireAwsAccountId{},
+		routes.Documentation{
+			Summary:     "interact with the report definitions",
+			Description: "The spreadsheet tasks of an AWS account generate its report definitions instead of the default report. Master definitions are generated by the master spreadsheet task. Since definitions send the reports to their recipients, changing them requires the manageAccounts permission.",
+		},
+	).Register("/reports/definitions")
+
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getModules).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.Documentation{
+				Summary:     "get the report modules",
+				Description: "Responds with the modules, formats and date policies which can be used in report definitions.",
+			},
+			users.RequirePermission{users.PermissionRunReports},
+		),
+	}.H().Register("/reports/modules")
+}
+
+// getModules is a route handler which returns the modules available in definitions.
+func getModules(r *http.Request, a routes.Arguments) (int, interface{}) {
+	return http.StatusOK, GetModuleList()
+}
+
+// getDefinitions is a route handler which returns the report definitions of an AWS account.
+func getDefinitions(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	definitions, err := getAllDefinitions(tx, aa.Id)
+	if err != nil {
+		l.Error("Failed to get report definitions.", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve report definitions.")
+	}
+	return http.StatusOK, definitions
+}
+
+// postDefinition is a route handler which creates a report definition.
+func postDefinition(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Definition
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	body.Id = 0
+	dbDefinition := models.ReportDefinition{AwsAccountID: aa.Id}
+	return saveDefinition(r, tx, aa, &dbDefinition, body)
+}
+
+// patchDefinition is a route handler which replaces a report definition.
+func patchDefinition(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Definition
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	dbDefinition, err := getDbDefinitionForAccount(tx, aa.Id, a[definitionIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Report definition not found.")
+	}
+	body.Id = dbDefinition.ID
+	return saveDefinition(r, tx, aa, dbDefinition, body)
+}
+
+// saveDefinition validates a report definition against the sub accounts of
+// the AWS account and saves it in the database.
+func saveDefinition(r *http.Request, tx *sql.Tx, aa aws.AwsAccount, dbDefinition *models.ReportDefinition, definition Definition) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	dbSubAccounts, err := models.AwsAccountsByParentId(tx, aa.Id)
+	if err != nil {
+		l.Error("Failed to get AWS sub accounts.", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save report definition.")
+	}
+	subAccounts := []int{aa.Id}
+	for _, dbSubAccount := range dbSubAccounts {
+		subAccounts = append(subAccounts, dbSubAccount.ID)
+	}
+	if err := definition.validate(subAccounts); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	err = setDbDefinition(dbDefinition, definition)
+	if err == nil {
+		err = dbDefinition.Save(tx)
+	}
+	if err == nil {
+		definition, err = definitionFromDbDefinition(*dbDefinition)
+	}
+	if err != nil {
+		l.Error("Failed to save report definition.", map[string]interface{}{
+			"definition": definition,
+			"error":      err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save report definition.")
+	}
+	return http.StatusOK, definition
+}
+
+// deleteDefinition is a route handler which deletes a report definition.
+func deleteDefinition(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	dbDefinition, err := getDbDefinitionForAccount(tx, aa.Id, a[definitionIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Report definition not found.")
+	}
+	if err := dbDefinition.Delete(tx); err != nil {
+		l.Error("Failed to delete report definition.", map[string]interface{}{
+			"definitionId": dbDefinition.ID,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to delete report definition.")
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,178 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+)
+
+// document is the format-neutral model filled by the report modules. It
+// holds an ordered list of sheets which are then rendered by one of the
+// renderers of a Format.
+type document struct {
+	sheets []*sheet
+}
+
+// sheet is a named table of cells. Charts and pictures are only rendered by
+// formats which support them.
+type sheet struct {
+	name          string
+	cells         cells
+	columnsWidth  columnsWidth
+	hiddenColumns []string
+	charts        []chart
+	pictures      []picture
+}
+
+type chart struct {
+	location string
+	format   string
+}
+
+type picture struct {
+	location  string
+	format    string
+	name      string
+	extension string
+	content   []byte
+}
+
+func newDocument() *document {
+	return &document{
+		sheets: []*sheet{},
+	}
+}
+
+// newSheet adds a sheet to the document unless a sheet with the same name
+// already exists.
+func (d *document) newSheet(name string) {
+	if d.getSheet(name) == nil {
+		d.sheets = append(d.sheets, &sheet{name: name})
+	}
+}
+
+// getSheet returns the sheet with the given name, or nil if there is none.
+func (d *document) getSheet(name string) *sheet {
+	for _, s := range d.sheets {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (d *document) setColVisible(sheetName, column string, visible bool) {
+	if s := d.getSheet(sheetName); s != nil && !visible {
+		s.hiddenColumns = append(s.hiddenColumns, column)
+	}
+}
+
+// getCellValue returns the value of the last cell set at a location, as a
+// string. Formulas are not evaluated.
+func (d *document) getCellValue(sheetName, location string) string {
+	if s := d.getSheet(sheetName); s != nil {
+		if c, ok := s.getCell(location); ok && len(c.formula) == 0 {
+			return fmt.Sprint(c.value)
+		}
+	}
+	return ""
+}
+
+func (d *document) addChart(sheetName, location, format string) error {
+	s := d.getSheet(sheetName)
+	if s == nil {
+		return fmt.Errorf("Sheet %s does not exist", sheetName)
+	}
+	s.charts = append(s.charts, chart{location, format})
+	return nil
+}
+
+func (d *document) addPictureFromBytes(sheetName, location, format, name, extension string, content []byte) error {
+	s := d.getSheet(sheetName)
+	if s == nil {
+		return fmt.Errorf("Sheet %s does not exist", sheetName)
+	}
+	s.pictures = append(s.pictures, picture{location, format, name, extension, content})
+	return nil
+}
+
+// getCell returns the last cell set at a location.
+func (s *sheet) getCell(location string) (cell, bool) {
+	for index := len(s.cells) - 1; index >= 0; index-- {
+		if s.cells[index].location == location {
+			return s.cells[index], true
+		}
+	}
+	return cell{}, false
+}
+
+// grid returns the cells of the sheet indexed by row then column, both
+// starting at 0, along with the number of rows and columns. A cell set
+// several times at the same location keeps its last value.
+func (s *sheet) grid() (map[int]map[int]cell, int, int) {
+	grid := make(map[int]map[int]cell)
+	rows, columns := 0, 0
+	for _, c := range s.cells {
+		column, row, err := parseLocation(c.location)
+		if err != nil {
+			continue
+		}
+		if grid[row] == nil {
+			grid[row] = make(map[int]cell)
+		}
+		grid[row][column] = c
+		if row >= rows {
+			rows = row + 1
+		}
+		if column >= columns {
+			columns = column + 1
+		}
+	}
+	return grid, rows, columns
+}
+
+// isColumnHidden reports whether a column, starting at 0, has been hidden.
+func (s *sheet) isColumnHidden(column int) bool {
+	for _, hidden := range s.hiddenColumns {
+		if excelize.TitleToNumber(hidden) == column {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLocation splits a location such as "AB12" into its column and row,
+// both starting at 0.
+func parseLocation(location string) (column int, row int, err error) {
+	location = strings.ToUpper(strings.Replace(location, "$", "", -1))
+	split := strings.IndexFunc(location, func(r rune) bool { return r >= '0' && r <= '9' })
+	if split <= 0 {
+		return 0, 0, fmt.Errorf("Invalid cell location %s", location)
+	}
+	for _, r := range location[:split] {
+		if r < 'A' || r > 'Z' {
+			return 0, 0, fmt.Errorf("Invalid cell location %s", location)
+		}
+	}
+	row, err = strconv.Atoi(location[split:])
+	if err != nil || row < 1 {
+		return 0, 0, fmt.Errorf("Invalid cell location %s", location)
+	}
+	return excelize.TitleToNumber(location[:split]), row - 1, nil
+}
@@ -0,0 +1,451 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+)
+
+// maxFormulaDepth is the maximum number of nested cell references followed
+// when evaluating a formula, which protects against circular references.
+const maxFormulaDepth = 32
+
+var (
+	errFormulaSyntax   = errors.New("Invalid formula")
+	errFormulaValue    = errors.New("Invalid value in formula")
+	errFormulaDivision = errors.New("Division by zero in formula")
+	errFormulaCircular = errors.New("Circular reference in formula")
+)
+
+// formulaError is the value of an expression which failed to evaluate. Like
+// spreadsheet errors, it propagates through the expressions using it
+// unless a condition discards it.
+type formulaError struct {
+	err error
+}
+
+// formulaEvaluator evaluates the formulas of a sheet for the renderers which
+// cannot store them, such as HTML and CSV. It only supports what the
+// modules use: arithmetic, comparisons, cell references and ranges, and the
+// SUM and IF functions.
+type formulaEvaluator struct {
+	cells   map[string]cell
+	results map[string]interface{}
+	depth   int
+}
+
+func newFormulaEvaluator(s *sheet) *formulaEvaluator {
+	e := &formulaEvaluator{
+		cells:   make(map[string]cell, len(s.cells)),
+		results: make(map[string]interface{}),
+	}
+	for _, c := range s.cells {
+		e.cells[strings.ToUpper(c.location)] = c
+	}
+	return e
+}
+
+// value returns the value of a cell, evaluating it if it is a formula. An
+// empty cell has a nil value.
+func (e *formulaEvaluator) value(c cell) (interface{}, error) {
+	value := e.evaluate(c)
+	if fe, ok := value.(formulaError); ok {
+		return nil, fe.err
+	}
+	return value, nil
+}
+
+func (e *formulaEvaluator) evaluate(c cell) interface{} {
+	if len(c.formula) == 0 {
+		return c.value
+	}
+	location := strings.ToUpper(c.location)
+	if result, ok := e.results[location]; ok {
+		return result
+	}
+	if e.depth >= maxFormulaDepth {
+		return formulaError{errFormulaCircular}
+	}
+	e.depth++
+	defer func() { e.depth-- }()
+	p := formulaParser{input: strings.TrimPrefix(c.formula, "="), evaluator: e}
+	result, err := p.parse()
+	if err != nil {
+		result = formulaError{err}
+	}
+	e.results[location] = result
+	return result
+}
+
+func (e *formulaEvaluator) reference(location string) interface{} {
+	if c, ok := e.cells[strings.ToUpper(strings.Replace(location, "$", "", -1))]; ok {
+		return e.evaluate(c)
+	}
+	return nil
+}
+
+func (e *formulaEvaluator) referenceRange(from, to string) (interface{}, error) {
+	fromColumn, fromRow, err := parseLocation(from)
+	if err != nil {
+		return nil, errFormulaSyntax
+	}
+	toColumn, toRow, err := parseLocation(to)
+	if err != nil {
+		return nil, errFormulaSyntax
+	}
+	values := []interface{}{}
+	for row := fromRow; row <= toRow; row++ {
+		for column := fromColumn; column <= toColumn; column++ {
+			values = append(values, e.reference(excelize.ToAlphaString(column)+strconv.Itoa(row+1)))
+		}
+	}
+	return values, nil
+}
+
+// formulaParser is a recursive descent parser which evaluates a formula
+// while parsing it. Its errors are syntax errors, evaluation errors being
+// formulaError values.
+type formulaParser struct {
+	input     string
+	position  int
+	evaluator *formulaEvaluator
+}
+
+func (p *formulaParser) parse() (interface{}, error) {
+	value, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.position != len(p.input) {
+		return nil, errFormulaSyntax
+	}
+	if _, ok := value.([]interface{}); ok {
+		return formulaError{errFormulaValue}, nil
+	}
+	return value, nil
+}
+
+func (p *formulaParser) skipSpaces() {
+	for p.position < len(p.input) && p.input[p.position] == ' ' {
+		p.position++
+	}
+}
+
+// consume skips the given token if it is next in the input.
+func (p *formulaParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.input[p.position:], token) {
+		p.position += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *formulaParser) comparison() (interface{}, error) {
+	left, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+	for _, operator := range []string{"<>", "<=", ">=", "=", "<", ">"} {
+		if p.consume(operator) {
+			right, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+			return compareFormulaValues(left, right, operator), nil
+		}
+	}
+	return left, nil
+}
+
+func (p *formulaParser) additive() (interface{}, error) {
+	left, err := p.term()
+	for err == nil {
+		if p.consume("+") {
+			left, err = p.arithmetic(left, p.term, func(a, b float64) interface{} { return a + b })
+		} else if p.consume("-") {
+			left, err = p.arithmetic(left, p.term, func(a, b float64) interface{} { return a - b })
+		} else {
+			break
+		}
+	}
+	return left, err
+}
+
+func (p *formulaParser) term() (interface{}, error) {
+	left, err := p.unary()
+	for err == nil {
+		if p.consume("*") {
+			left, err = p.arithmetic(left, p.unary, func(a, b float64) interface{} { return a * b })
+		} else if p.consume("/") {
+			left, err = p.arithmetic(left, p.unary, func(a, b float64) interface{} {
+				if b == 0 {
+					return formulaError{errFormulaDivision}
+				}
+				return a / b
+			})
+		} else {
+			break
+		}
+	}
+	return left, err
+}
+
+func (p *formulaParser) arithmetic(left interface{}, next func() (interface{}, error), operation func(float64, float64) interface{}) (interface{}, error) {
+	right, err := next()
+	if err != nil {
+		return nil, err
+	}
+	a, ok := formulaNumber(left)
+	if !ok {
+		return formulaErrorOf(left), nil
+	}
+	b, ok := formulaNumber(right)
+	if !ok {
+		return formulaErrorOf(right), nil
+	}
+	return operation(a, b), nil
+}
+
+func (p *formulaParser) unary() (interface{}, error) {
+	if p.consume("-") {
+		value, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if number, ok := formulaNumber(value); ok {
+			return -number, nil
+		}
+		return formulaErrorOf(value), nil
+	}
+	return p.primary()
+}
+
+func (p *formulaParser) primary() (interface{}, error) {
+	p.skipSpaces()
+	if p.position >= len(p.input) {
+		return nil, errFormulaSyntax
+	}
+	switch r := rune(p.input[p.position]); {
+	case r == '(':
+		p.position++
+		value, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, errFormulaSyntax
+		}
+		return value, nil
+	case r == '"':
+		end := strings.IndexByte(p.input[p.position+1:], '"')
+		if end < 0 {
+			return nil, errFormulaSyntax
+		}
+		value := p.input[p.position+1 : p.position+1+end]
+		p.position += end + 2
+		return value, nil
+	case unicode.IsDigit(r) || r == '.':
+		start := p.position
+		for p.position < len(p.input) && (unicode.IsDigit(rune(p.input[p.position])) || p.input[p.position] == '.') {
+			p.position++
+		}
+		value, err := strconv.ParseFloat(p.input[start:p.position], 64)
+		if err != nil {
+			return nil, errFormulaSyntax
+		}
+		return value, nil
+	case unicode.IsLetter(r) || r == '$':
+		return p.identifier()
+	}
+	return nil, errFormulaSyntax
+}
+
+func (p *formulaParser) name() string {
+	start := p.position
+	for p.position < len(p.input) {
+		r := rune(p.input[p.position])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '$' {
+			break
+		}
+		p.position++
+	}
+	return p.input[start:p.position]
+}
+
+// identifier parses a function call, a cell reference or a range of cells.
+func (p *formulaParser) identifier() (interface{}, error) {
+	name := p.name()
+	if p.consume("(") {
+		return p.function(strings.ToUpper(name))
+	}
+	if p.consume(":") {
+		return p.evaluator.referenceRange(name, p.name())
+	}
+	if _, _, err := parseLocation(name); err != nil {
+		return nil, errFormulaSyntax
+	}
+	return p.evaluator.reference(name), nil
+}
+
+func (p *formulaParser) function(name string) (interface{}, error) {
+	var args []interface{}
+	if !p.consume(")") {
+		for {
+			arg, err := p.comparison()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.consume(")") {
+				break
+			} else if !p.consume(",") {
+				return nil, errFormulaSyntax
+			}
+		}
+	}
+	switch name {
+	case "SUM":
+		return formulaSum(args), nil
+	case "IF":
+		if len(args) < 2 || len(args) > 3 {
+			return nil, errFormulaSyntax
+		}
+		if _, ok := args[0].(formulaError); ok {
+			return args[0], nil
+		} else if condition, ok := args[0].(bool); ok && condition {
+			return args[1], nil
+		} else if len(args) == 3 {
+			return args[2], nil
+		}
+		return false, nil
+	}
+	return nil, fmt.Errorf("Unsupported function %s in formula", name)
+}
+
+// formulaSum sums the numbers of its arguments and of the ranges among them,
+// ignoring text like spreadsheets do.
+func formulaSum(args []interface{}) interface{} {
+	var total float64
+	for _, arg := range args {
+		switch value := arg.(type) {
+		case formulaError:
+			return value
+		case []interface{}:
+			sum := formulaSum(value)
+			if _, ok := sum.(formulaError); ok {
+				return sum
+			}
+			total += sum.(float64)
+		case nil, string:
+		default:
+			if number, ok := formulaNumber(value); ok {
+				total += number
+			}
+		}
+	}
+	return total
+}
+
+// formulaNumber converts a value to a number, empty values being zero.
+func formulaNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case nil:
+		return 0, true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		if v == "" {
+			return 0, true
+		}
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// formulaErrorOf returns the error of a value which is not a number.
+func formulaErrorOf(value interface{}) formulaError {
+	if fe, ok := value.(formulaError); ok {
+		return fe
+	}
+	return formulaError{errFormulaValue}
+}
+
+// compareFormulaValues compares two values. Text is compared without case
+// and is never equal to a number.
+func compareFormulaValues(left, right interface{}, operator string) interface{} {
+	if fe, ok := left.(formulaError); ok {
+		return fe
+	} else if fe, ok := right.(formulaError); ok {
+		return fe
+	}
+	var comparison int
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+	if leftIsString || rightIsString {
+		if (!leftIsString && left != nil) || (!rightIsString && right != nil) {
+			return operator == "<>"
+		}
+		comparison = strings.Compare(strings.ToLower(leftString), strings.ToLower(rightString))
+	} else {
+		a, ok := formulaNumber(left)
+		if !ok {
+			return formulaError{errFormulaValue}
+		}
+		b, ok := formulaNumber(right)
+		if !ok {
+			return formulaError{errFormulaValue}
+		}
+		if a < b {
+			comparison = -1
+		} else if a > b {
+			comparison = 1
+		}
+	}
+	switch operator {
+	case "=":
+		return comparison == 0
+	case "<>":
+		return comparison != 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	default:
+		return comparison >= 0
+	}
+}
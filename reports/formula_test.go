@@ -0,0 +1,107 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import "testing"
+
+func TestFormulaEvaluator(t *testing.T) {
+	s := &sheet{
+		name: "Formulas",
+		cells: cells{
+			newCell(2.0, "A1"),
+			newCell(3, "A2"),
+			newFormula("=A1+A2", "A3"),
+			newCell("text", "B1"),
+			newFormula("=C1", "C1"),
+		},
+	}
+	for _, tc := range []struct {
+		name    string
+		formula string
+		value   interface{}
+		err     string
+	}{
+		{"Number", "=42", 42.0, ""},
+		{"Decimal", "=.5", 0.5, ""},
+		{"Text", `="hello"`, "hello", ""},
+		{"Spaces", "= 1 +  2 ", 3.0, ""},
+		{"Product before sum", "=1+2*3", 7.0, ""},
+		{"Quotient before difference", "=10-6/2", 7.0, ""},
+		{"Parentheses", "=(1+2)*3", 9.0, ""},
+		{"Left associative difference", "=2-3-4", -5.0, ""},
+		{"Left associative quotient", "=8/4/2", 1.0, ""},
+		{"Unary minus", "=-2*3", -6.0, ""},
+		{"Double unary minus", "=--2", 2.0, ""},
+		{"Comparison after sum", "=1+2>2", true, ""},
+		{"Not equal", "=1<>2", true, ""},
+		{"Less or equal", "=2<=2", true, ""},
+		{"Greater or equal", "=SUM(A1:A2)>=6", false, ""},
+		{"Less than", "=3<2", false, ""},
+		{"Text result starting with a sign", `="-"`, "-", ""},
+		{"Text comparison ignores case", `="ABC"="abc"`, true, ""},
+		{"Text is not a number", `="1"=1`, false, ""},
+		{"Reference", "=A1*A2", 6.0, ""},
+		{"Absolute reference", "=$A$1", 2.0, ""},
+		{"Formula reference", "=A3*2", 10.0, ""},
+		{"Empty reference", "=D1+1", 1.0, ""},
+		{"Range sum", "=SUM(A1:A3)", 10.0, ""},
+		{"Sum ignores text", "=SUM(B1,A1,4)", 6.0, ""},
+		{"If true", `=IF(A1>1,"yes","no")`, "yes", ""},
+		{"If false", `=IF(A1>2,"yes","no")`, "no", ""},
+		{"If false without else", "=IF(A1>2,1)", false, ""},
+		{"Lowercase function", "=sum(1,2)", 3.0, ""},
+		{"Division by zero", "=1/0", nil, errFormulaDivision.Error()},
+		{"Division by empty cell", "=1/D1", nil, errFormulaDivision.Error()},
+		{"Division by zero propagates", "=1+1/0", nil, errFormulaDivision.Error()},
+		{"Division by zero in condition", "=IF(1/0>1,1,2)", nil, errFormulaDivision.Error()},
+		{"Division by zero in sum", "=SUM(1,1/0)", nil, errFormulaDivision.Error()},
+		{"Arithmetic on text", "=B1+1", nil, errFormulaValue.Error()},
+		{"Bare range", "=A1:A2", nil, errFormulaValue.Error()},
+		{"Circular reference", "=C1", nil, errFormulaCircular.Error()},
+		{"Unknown function", "=AVERAGE(1,2)", nil, "Unsupported function AVERAGE in formula"},
+		{"Unknown identifier", "=TOTAL", nil, errFormulaSyntax.Error()},
+		{"Unknown identifier in expression", "=1+FOO*2", nil, errFormulaSyntax.Error()},
+		{"Missing operand", "=1+", nil, errFormulaSyntax.Error()},
+		{"Missing parenthesis", "=(1+2", nil, errFormulaSyntax.Error()},
+		{"Trailing token", "=1 2", nil, errFormulaSyntax.Error()},
+		{"Unterminated text", `="abc`, nil, errFormulaSyntax.Error()},
+		{"Invalid number", "=1.2.3", nil, errFormulaSyntax.Error()},
+		{"If arity", "=IF(1)", nil, errFormulaSyntax.Error()},
+		{"Empty formula", "=", nil, errFormulaSyntax.Error()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator := newFormulaEvaluator(s)
+			value, err := evaluator.value(newFormula(tc.formula, "Z99"))
+			if tc.err != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q, got value %v", tc.err, value)
+				} else if err.Error() != tc.err {
+					t.Fatalf("Expected error %q, got %q", tc.err, err.Error())
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			} else if value != tc.value {
+				t.Errorf("Expected %#v, got %#v", tc.value, value)
+			}
+		})
+	}
+}
+
+func TestFormulaEvaluatorPlainValue(t *testing.T) {
+	evaluator := newFormulaEvaluator(&sheet{})
+	if value, err := evaluator.value(newCell("text", "A1")); err != nil || value != "text" {
+		t.Errorf("Expected the value of the cell, got %#v (%v)", value, err)
+	}
+}
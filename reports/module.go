@@ -19,8 +19,6 @@ import (
 	"database/sql"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
-
 	"github.com/trackit/trackit/aws"
 )
 
@@ -28,7 +26,7 @@ type module struct {
 	Name          string
 	SheetName     string
 	ErrorName     string
-	GenerateSheet func(context.Context, []aws.AwsAccount, time.Time, *sql.Tx, *excelize.File) error
+	GenerateSheet func(context.Context, []aws.AwsAccount, time.Time, *sql.Tx, *document) error
 }
 
 var modules = []module{
@@ -43,4 +41,7 @@ var modules = []module{
 	ebsUsageReportModule,
 	instanceCountUsageReportModule,
 	riEc2ReportModule,
+	savingsPlansReportModule,
+	odToRiReportModule,
+	pluginsTrendReportModule,
 }
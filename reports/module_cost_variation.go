@@ -63,7 +63,7 @@ var costVariationLast6Months = module{
 
 // costVariationGenerateLastMonth will generate a sheet with daily cost variation data for last month
 // It will get cost data for given AWS account and for a given date
-func costVariationGenerateLastMonth(ctx context.Context, aas []aws.AwsAccount, date time.Time, _ *sql.Tx, file *excelize.File) (err error) {
+func costVariationGenerateLastMonth(ctx context.Context, aas []aws.AwsAccount, date time.Time, _ *sql.Tx, file *document) (err error) {
 	var dateRange diff.DateRange
 	if date.IsZero() {
 		dateRange.Begin, dateRange.End = history.GetHistoryDate()
@@ -83,7 +83,7 @@ func costVariationGenerateLastMonth(ctx context.Context, aas []aws.AwsAccount, d
 
 // costVariationGenerateLast6Months will generate a sheet with monthly cost variation data for last 6 months
 // It will get cost data for given AWS account and for a given date
-func costVariationGenerateLast6Months(ctx context.Context, aas []aws.AwsAccount, date time.Time, _ *sql.Tx, file *excelize.File) (err error) {
+func costVariationGenerateLast6Months(ctx context.Context, aas []aws.AwsAccount, date time.Time, _ *sql.Tx, file *document) (err error) {
 	var dateRange diff.DateRange
 	if date.IsZero() {
 		_, dateRange.End = history.GetHistoryDate()
@@ -99,7 +99,7 @@ func costVariationGenerateLast6Months(ctx context.Context, aas []aws.AwsAccount,
 	return costVariationGenerateSheet(ctx, aas, dateRange, frequency, file)
 }
 
-func costVariationGenerateSheet(ctx context.Context, aas []aws.AwsAccount, dateRange diff.DateRange, frequency costVariationFrequency, file *excelize.File) (err error) {
+func costVariationGenerateSheet(ctx context.Context, aas []aws.AwsAccount, dateRange diff.DateRange, frequency costVariationFrequency, file *document) (err error) {
 	data, err := costVariationGetData(ctx, aas, dateRange, frequency)
 	if err == nil {
 		return costVariationInsertDataInSheet(frequency, file, data)
@@ -119,10 +119,11 @@ func costVariationGetData(ctx context.Context,
 		"dateStart":   dateRange.Begin,
 		"dateEnd":     dateRange.End,
 		"aggregation": frequency.Aggregation,
+		"metric":      costMetricFromContext(ctx),
 	})
 	data = make(map[aws.AwsAccount]costVariationReport, len(aas))
 	for _, account := range aas {
-		report, err := diff.TaskDiffData(ctx, account, dateRange, frequency.Aggregation)
+		report, err := diff.TaskDiffData(ctx, account, dateRange, frequency.Aggregation, costMetricFromContext(ctx))
 		if err != nil {
 			logger.Error("An error occurred while generating a Cost Variation Report", map[string]interface{}{
 				"error":     err,
@@ -152,9 +153,9 @@ func costVariationGetData(ctx context.Context,
 
 func costVariationInsertDataInSheet(
 	frequency costVariationFrequency,
-	file *excelize.File,
+	file *document,
 	data map[aws.AwsAccount]costVariationReport) (err error) {
-	file.NewSheet(frequency.SheetName)
+	file.newSheet(frequency.SheetName)
 	costVariationGenerateHeader(file, frequency.SheetName, frequency.Dates, frequency)
 	line := 4
 	for account, report := range data {
@@ -190,7 +191,7 @@ func costVariationInsertDataInSheet(
 	return
 }
 
-func costVariationGenerateHeader(file *excelize.File, sheetName string, dates []time.Time, frequency costVariationFrequency) {
+func costVariationGenerateHeader(file *document, sheetName string, dates []time.Time, frequency costVariationFrequency) {
 	header := make(cells, 0, len(dates)*3+2)
 	totalCol := excelize.ToAlphaString(len(dates)*2 + 1)
 	header = append(header, newCell("Account", "A1").mergeTo("A3"),
@@ -21,7 +21,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -39,14 +38,14 @@ var ebsUsageReportModule = module{
 	GenerateSheet: generateEbsUsageReportSheet,
 }
 
-func generateEbsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateEbsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return ebsUsageReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func ebsUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func ebsUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := ebsUsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return ebsUsageReportInsertDataInSheet(ctx, aas, file, data)
@@ -81,8 +80,8 @@ func ebsUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.
 	return
 }
 
-func ebsUsageReportInsertDataInSheet(_ context.Context, aas []aws.AwsAccount, file *excelize.File, data []ebs.SnapshotReport) (err error) {
-	file.NewSheet(ebsUsageReportSheetName)
+func ebsUsageReportInsertDataInSheet(_ context.Context, aas []aws.AwsAccount, file *document, data []ebs.SnapshotReport) (err error) {
+	file.newSheet(ebsUsageReportSheetName)
 	ebsUsageReportGenerateHeader(file)
 	line := 3
 	for _, report := range data {
@@ -110,7 +109,7 @@ func ebsUsageReportInsertDataInSheet(_ context.Context, aas []aws.AwsAccount, fi
 	return
 }
 
-func ebsUsageReportGenerateHeader(file *excelize.File) {
+func ebsUsageReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A2"),
 		newCell("ID", "B1").mergeTo("B2"),
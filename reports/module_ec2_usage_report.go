@@ -21,7 +21,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -42,14 +41,14 @@ var ec2UsageReportModule = module{
 
 // generateEc2UsageReportSheet will generate a sheet with EC2 usage report
 // It will get data for given AWS account and for a given date
-func generateEc2UsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateEc2UsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return ec2UsageReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func ec2UsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func ec2UsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := ec2UsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return ec2UsageReportInsertDataInSheet(aas, file, data)
@@ -83,8 +82,8 @@ func ec2UsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.
 	return
 }
 
-func ec2UsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, data []ec2.InstanceReport) (err error) {
-	file.NewSheet(ec2UsageReportSheetName)
+func ec2UsageReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data []ec2.InstanceReport) (err error) {
+	file.newSheet(ec2UsageReportSheetName)
 	ec2SizingRecommendationsHeader(file)
 	ec2UsageReportGenerateHeader(file)
 	line := 3
@@ -132,7 +131,7 @@ func ec2UsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File,
 	return
 }
 
-func ec2UsageReportGenerateHeader(file *excelize.File) {
+func ec2UsageReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A2"),
 		newCell("ID", "B1").mergeTo("B2"),
@@ -166,7 +165,7 @@ func ec2UsageReportGenerateHeader(file *excelize.File) {
 	columns.setValues(file, ec2UsageReportSheetName)
 }
 
-func ec2SizingRecommendationInsertData(file *excelize.File, instance ec2.Instance, name, formattedAccount string, line int) {
+func ec2SizingRecommendationInsertData(file *document, instance ec2.Instance, name, formattedAccount string, line int) {
 	cellsRecommendation := cells{
 		newCell(formattedAccount, "A"+strconv.Itoa(line)),
 		newCell(instance.Id, "B"+strconv.Itoa(line)),
@@ -180,8 +179,8 @@ func ec2SizingRecommendationInsertData(file *excelize.File, instance ec2.Instanc
 	cellsRecommendation.addStyles("borders", "centerText").setValues(file, ec2SizingRecommendationsSheetName)
 }
 
-func ec2SizingRecommendationsHeader(file *excelize.File) {
-	file.NewSheet(ec2SizingRecommendationsSheetName)
+func ec2SizingRecommendationsHeader(file *document) {
+	file.newSheet(ec2SizingRecommendationsSheetName)
 	header := cells{
 		newCell("Account", "A1").mergeTo("A2"),
 		newCell("ID", "B1").mergeTo("B2"),
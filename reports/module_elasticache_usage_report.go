@@ -21,7 +21,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -41,14 +40,14 @@ var elastiCacheUsageReportModule = module{
 
 // generateElastiCacheUsageReportSheet will generate a sheet with ElastiCache usage report
 // It will get data for given AWS account and for a given date
-func generateElastiCacheUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateElastiCacheUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return elastiCacheUsageReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func elastiCacheUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func elastiCacheUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := elastiCacheUsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return elastiCacheUsageReportInsertDataInSheet(aas, file, data)
@@ -82,8 +81,8 @@ func elastiCacheUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, da
 	return
 }
 
-func elastiCacheUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, data []elasticache.InstanceReport) (err error) {
-	file.NewSheet(elastiCacheUsageReportSheetName)
+func elastiCacheUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data []elasticache.InstanceReport) (err error) {
+	file.newSheet(elastiCacheUsageReportSheetName)
 	elastiCacheUsageReportGenerateHeader(file)
 	line := 3
 	for _, report := range data {
@@ -122,7 +121,7 @@ func elastiCacheUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *exceliz
 	return
 }
 
-func elastiCacheUsageReportGenerateHeader(file *excelize.File) {
+func elastiCacheUsageReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A2"),
 		newCell("ID", "B1").mergeTo("B2"),
@@ -21,7 +21,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -41,14 +40,14 @@ var esUsageReportModule = module{
 
 // generateEsUsageReportSheet will generate a sheet with ES usage report
 // It will get data for given AWS account and for a given date
-func generateEsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateEsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return esUsageReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func esUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func esUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := esUsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return esUsageReportInsertDataInSheet(aas, file, data)
@@ -82,8 +81,8 @@ func esUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.T
 	return
 }
 
-func esUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, data []es.DomainReport) (err error) {
-	file.NewSheet(esUsageReportSheetName)
+func esUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data []es.DomainReport) (err error) {
+	file.newSheet(esUsageReportSheetName)
 	esUsageReportGenerateHeader(file)
 	line := 3
 	for _, report := range data {
@@ -132,7 +131,7 @@ func esUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, d
 	return
 }
 
-func esUsageReportGenerateHeader(file *excelize.File) {
+func esUsageReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A2"),
 		newCell("ID", "B1").mergeTo("B2"),
@@ -41,7 +41,7 @@ var instanceCountUsageReportModule = module{
 	GenerateSheet: generateInstanceCountUsageReportSheet,
 }
 
-func generateInstanceCountUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateInstanceCountUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	var dateRange diff.DateRange
 	if date.IsZero() {
 		dateRange.Begin, dateRange.End = history.GetHistoryDate()
@@ -54,7 +54,7 @@ func generateInstanceCountUsageReportSheet(ctx context.Context, aas []aws.AwsAcc
 	return instanceCountUsageReportGenerateSheet(ctx, aas, dateRange, tx, file)
 }
 
-func instanceCountUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date diff.DateRange, tx *sql.Tx, file *excelize.File) (err error) {
+func instanceCountUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date diff.DateRange, tx *sql.Tx, file *document) (err error) {
 	data, dates, err := instanceCountUsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return instanceCountUsageReportInsertDataInSheet(ctx, aas, file, data, dates)
@@ -105,8 +105,8 @@ func getAllDateInstanceCount(date diff.DateRange) map[time.Time]int {
 	return dates
 }
 
-func instanceCountUsageReportInsertDataInSheet(_ context.Context, aas []aws.AwsAccount, file *excelize.File, data []instanceCount.InstanceCountReport, dates map[time.Time]int) (err error) {
-	file.NewSheet(instanceCountReportDetailledSheetName)
+func instanceCountUsageReportInsertDataInSheet(_ context.Context, aas []aws.AwsAccount, file *document, data []instanceCount.InstanceCountReport, dates map[time.Time]int) (err error) {
+	file.newSheet(instanceCountReportDetailledSheetName)
 	lastColumn := instanceCountUsageReportGenerateHeader(file, dates)
 	line := 4
 	reportsCells := make(cells, 0)
@@ -131,7 +131,7 @@ func instanceCountUsageReportInsertDataInSheet(_ context.Context, aas []aws.AwsA
 }
 
 //instanceCountReportDatesAmountInSheet put the Amount in terms of dates in the sheet
-func instanceCountReportDatesAmountInSheet(file *excelize.File, dates map[time.Time]int, report instanceCount.InstanceCountReport,
+func instanceCountReportDatesAmountInSheet(file *document, dates map[time.Time]int, report instanceCount.InstanceCountReport,
 	lastColumn int, line int, reportCells cells) cells {
 	totalColumnPosition := make([]string, 0)
 	for date, column := range dates {
@@ -148,7 +148,7 @@ func instanceCountReportDatesAmountInSheet(file *excelize.File, dates map[time.T
 		if ok {
 			reportCells = append(reportCells, newCell(int(instanceDate.Count), excelize.ToAlphaString(column)+strconv.Itoa(line)))
 		}
-		file.SetColVisible(instanceCountReportDetailledSheetName, excelize.ToAlphaString(column), false)
+		file.setColVisible(instanceCountReportDetailledSheetName, excelize.ToAlphaString(column), false)
 	}
 	formula := fmt.Sprintf("SUM(%s)", strings.Join(totalColumnPosition, ","))
 	formulaLocation := excelize.ToAlphaString(lastColumn+1) + strconv.Itoa(line)
@@ -156,7 +156,7 @@ func instanceCountReportDatesAmountInSheet(file *excelize.File, dates map[time.T
 	return reportCells
 }
 
-func instanceCountUsageReportGenerateHeader(file *excelize.File, dates map[time.Time]int) int {
+func instanceCountUsageReportGenerateHeader(file *document, dates map[time.Time]int) int {
 	lastColumn, totalColumnsWidth := instanceCountDatesHeader(file, dates)
 	header := cells{
 		newCell("Account", "A1").mergeTo("A3"),
@@ -179,7 +179,7 @@ func instanceCountUsageReportGenerateHeader(file *excelize.File, dates map[time.
 }
 
 //instanceCountDatesHeader generate header for all the dates and total
-func instanceCountDatesHeader(file *excelize.File, dates map[time.Time]int) (int, columnsWidth) {
+func instanceCountDatesHeader(file *document, dates map[time.Time]int) (int, columnsWidth) {
 	widthColumn := 0
 	lastColumn := 3
 	totalColumnsWidth := make(columnsWidth, 0)
@@ -22,7 +22,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -42,14 +41,14 @@ var lambdaUsageReportModule = module{
 
 // generateLambdaUsageReportSheet will generate a sheet with Lambda usage report
 // It will get data for given AWS account and for a given date
-func generateLambdaUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateLambdaUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return lambdaUsageReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func lambdaUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func lambdaUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := lambdaUsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return lambdaUsageReportInsertDataInSheet(aas, file, data)
@@ -83,8 +82,8 @@ func lambdaUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date ti
 	return
 }
 
-func lambdaUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, data []lambda.FunctionReport) (err error) {
-	file.NewSheet(lambdaUsageReportSheetName)
+func lambdaUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data []lambda.FunctionReport) (err error) {
+	file.newSheet(lambdaUsageReportSheetName)
 	lambdaUsageReportGenerateHeader(file)
 	line := 3
 	for _, report := range data {
@@ -122,7 +121,7 @@ func lambdaUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.Fil
 	return
 }
 
-func lambdaUsageReportGenerateHeader(file *excelize.File) {
+func lambdaUsageReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A2"),
 		newCell("Name", "B1").mergeTo("B2"),
@@ -0,0 +1,160 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/usageReports/history"
+	"github.com/trackit/trackit/onDemandToRI"
+	"github.com/trackit/trackit/users"
+)
+
+const odToRiReportSheetName = "Reservation Recommendations"
+
+var odToRiReportModule = module{
+	Name:          "Reservation Recommendations",
+	SheetName:     odToRiReportSheetName,
+	ErrorName:     "odToRiReportError",
+	GenerateSheet: generateOdToRiReportSheet,
+}
+
+// odToRiReportServices are the services listed in the sheet and their display names
+var odToRiReportServices = []struct {
+	Service string
+	Name    string
+}{
+	{onDemandToRI.ServiceRds, "RDS"},
+	{onDemandToRI.ServiceElastiCache, "ElastiCache"},
+	{onDemandToRI.ServiceEs, "ES"},
+}
+
+// generateOdToRiReportSheet will generate a sheet with the reservation recommendations of the managed services
+// It will get data for given AWS account and for a given date
+func generateOdToRiReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
+	if date.IsZero() {
+		date, _ = history.GetHistoryDate()
+	}
+	return odToRiReportGenerateSheet(ctx, aas, date, tx, file)
+}
+
+func odToRiReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
+	data, err := odToRiReportGetData(ctx, aas, date, tx)
+	if err == nil {
+		return odToRiReportInsertDataInSheet(aas, file, data)
+	}
+	return
+}
+
+func odToRiReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx) (reports map[string][]onDemandToRI.OdToRiReport, err error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	identities := getAwsIdentities(aas)
+	user, err := users.GetUserWithId(tx, aas[0].UserId)
+	if err != nil {
+		return
+	}
+	logger.Debug("Getting Reservation Recommendations for accounts", map[string]interface{}{
+		"accounts": aas,
+		"date":     date,
+	})
+	reports = make(map[string][]onDemandToRI.OdToRiReport, len(odToRiReportServices))
+	for _, service := range odToRiReportServices {
+		parameters := onDemandToRI.OdToRiQueryParams{
+			AccountList: identities,
+			Service:     service.Service,
+			Date:        date,
+		}
+		_, reports[service.Service], err = onDemandToRI.GetOdToRiReports(ctx, parameters, user, tx)
+		if err != nil {
+			logger.Error("An error occurred while generating Reservation Recommendations", map[string]interface{}{
+				"error":    err,
+				"service":  service.Service,
+				"accounts": aas,
+				"date":     date,
+			})
+			return
+		}
+	}
+	return
+}
+
+func odToRiReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data map[string][]onDemandToRI.OdToRiReport) (err error) {
+	file.newSheet(odToRiReportSheetName)
+	odToRiReportGenerateHeader(file)
+	line := 4
+	for _, service := range odToRiReportServices {
+		for _, report := range data[service.Service] {
+			account := getAwsAccount(report.Account, aas)
+			formattedAccount := report.Account
+			if account != nil {
+				formattedAccount = formatAwsAccount(*account)
+			}
+			for _, instance := range report.Instances {
+				cells := cells{
+					newCell(formattedAccount, "A"+strconv.Itoa(line)),
+					newCell(service.Name, "B"+strconv.Itoa(line)),
+					newCell(instance.Region, "C"+strconv.Itoa(line)),
+					newCell(instance.Engine, "D"+strconv.Itoa(line)),
+					newCell(instance.Type, "E"+strconv.Itoa(line)),
+					newCell(instance.InstanceCount, "F"+strconv.Itoa(line)),
+					newCell(instance.OnDemand.Monthly.Total, "G"+strconv.Itoa(line)).addStyles("price"),
+					newCell(instance.Reservation.OneYear.Monthly.Total, "H"+strconv.Itoa(line)).addStyles("price"),
+					newCell(instance.Reservation.OneYear.Saving.Total, "I"+strconv.Itoa(line)).addStyles("price"),
+					newCell(instance.Reservation.ThreeYear.Monthly.Total, "J"+strconv.Itoa(line)).addStyles("price"),
+					newCell(instance.Reservation.ThreeYear.Saving.Total, "K"+strconv.Itoa(line)).addStyles("price"),
+				}
+				cells.addStyles("borders", "centerText").setValues(file, odToRiReportSheetName)
+				line++
+			}
+		}
+	}
+	return
+}
+
+func odToRiReportGenerateHeader(file *document) {
+	header := cells{
+		newCell("Account", "A1").mergeTo("A3"),
+		newCell("Instances", "B1").mergeTo("F2"),
+		newCell("Service", "B3"),
+		newCell("Region", "C3"),
+		newCell("Engine", "D3"),
+		newCell("Type", "E3"),
+		newCell("Count", "F3"),
+		newCell("On Demand", "G1").mergeTo("G2"),
+		newCell("Monthly", "G3"),
+		newCell("Reservation", "H1").mergeTo("K1"),
+		newCell("1 Year", "H2").mergeTo("I2"),
+		newCell("Monthly", "H3"),
+		newCell("Saving", "I3"),
+		newCell("3 Years", "J2").mergeTo("K2"),
+		newCell("Monthly", "J3"),
+		newCell("Saving", "K3"),
+	}
+	header.addStyles("borders", "bold", "centerText").setValues(file, odToRiReportSheetName)
+	columns := columnsWidth{
+		newColumnWidth("A", 30),
+		newColumnWidth("B", 12.5).toColumn("D"),
+		newColumnWidth("E", 20),
+		newColumnWidth("F", 7.5),
+		newColumnWidth("G", 15).toColumn("K"),
+	}
+	columns.setValues(file, odToRiReportSheetName)
+}
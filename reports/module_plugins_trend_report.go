@@ -0,0 +1,148 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/usageReports/history"
+	core "github.com/trackit/trackit/plugins/account/core"
+	"github.com/trackit/trackit/users"
+)
+
+const pluginsTrendReportSheetName = "Plugins Trend"
+
+// pluginsTrendReportWeeks is the number of weeks displayed in the sheet
+const pluginsTrendReportWeeks = 8
+
+var pluginsTrendReportModule = module{
+	Name:          "Plugins Trend",
+	SheetName:     pluginsTrendReportSheetName,
+	ErrorName:     "pluginsTrendReportError",
+	GenerateSheet: generatePluginsTrendReportSheet,
+}
+
+// generatePluginsTrendReportSheet will generate a sheet with the weekly results of the plugins
+// It will get data for given AWS account for the weeks before the end of the month of the given date
+func generatePluginsTrendReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
+	if date.IsZero() {
+		_, date = history.GetHistoryDate()
+	} else {
+		date = time.Date(date.Year(), date.Month()+1, 0, 23, 59, 59, 999999999, time.UTC)
+	}
+	return pluginsTrendReportGenerateSheet(ctx, aas, date, tx, file)
+}
+
+func pluginsTrendReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
+	data, err := pluginsTrendReportGetData(ctx, aas, date, tx)
+	if err == nil {
+		return pluginsTrendReportInsertDataInSheet(file, data)
+	}
+	return
+}
+
+func pluginsTrendReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx) (pluginsHistory []core.PluginHistory, err error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	identities := getAwsIdentities(aas)
+	user, err := users.GetUserWithId(tx, aas[0].UserId)
+	if err != nil {
+		return
+	}
+	parameters := core.PluginsHistoryQueryParams{
+		AccountList: identities,
+		Begin:       date.AddDate(0, 0, -7*pluginsTrendReportWeeks),
+		End:         date,
+		Interval:    "week",
+	}
+	logger.Debug("Getting Plugins Trend for accounts", map[string]interface{}{
+		"accounts": aas,
+		"date":     date,
+	})
+	_, pluginsHistory, err = core.GetPluginsHistory(ctx, parameters, user, tx)
+	if err != nil {
+		logger.Error("An error occurred while generating a Plugins Trend", map[string]interface{}{
+			"error":    err,
+			"accounts": aas,
+			"date":     date,
+		})
+	}
+	return
+}
+
+func pluginsTrendReportInsertDataInSheet(file *document, data []core.PluginHistory) (err error) {
+	file.newSheet(pluginsTrendReportSheetName)
+	pluginsTrendReportGenerateHeader(file)
+	line := 3
+	for _, plugin := range data {
+		for index, point := range plugin.Points {
+			row := strconv.Itoa(line)
+			cells := cells{
+				newCell(plugin.PluginName, "A"+row),
+				newCell(plugin.Category, "B"+row),
+				newCell(point.Date.Format("2006-01-02"), "C"+row),
+				newCell(point.Checked, "D"+row),
+				newCell(point.Passed, "E"+row),
+				newFormula(fmt.Sprintf("D%s-E%s", row, row), "F"+row),
+				newFormula(fmt.Sprintf(`IF(D%s=0,"",E%s/D%s)`, row, row, row), "G"+row).addStyles("percentage"),
+				newCell(point.EstimatedMonthlySavings, "H"+row).addStyles("price"),
+			}
+			if index > 0 {
+				previousRow := strconv.Itoa(line - 1)
+				formula := fmt.Sprintf(`IF(H%s=0,"",H%s/H%s-1)`, previousRow, row, previousRow)
+				variation := newFormula(formula, "I"+row).addStyles("percentage")
+				variation = variation.addConditionalFormat("negative", "green", "borders")
+				variation = variation.addConditionalFormat("positive", "red", "borders")
+				cells = append(cells, variation)
+			} else {
+				cells = append(cells, newCell("", "I"+row))
+			}
+			cells.addStyles("borders", "centerText").setValues(file, pluginsTrendReportSheetName)
+			line++
+		}
+	}
+	return
+}
+
+func pluginsTrendReportGenerateHeader(file *document) {
+	header := cells{
+		newCell("Plugin", "A1").mergeTo("A2"),
+		newCell("Category", "B1").mergeTo("B2"),
+		newCell("Week", "C1").mergeTo("C2"),
+		newCell("Checks", "D1").mergeTo("G1"),
+		newCell("Checked", "D2"),
+		newCell("Passed", "E2"),
+		newCell("Failed", "F2"),
+		newCell("Passed %", "G2"),
+		newCell("Estimated Monthly Savings", "H1").mergeTo("I1"),
+		newCell("Amount", "H2"),
+		newCell("Variation", "I2"),
+	}
+	header.addStyles("borders", "bold", "centerText").setValues(file, pluginsTrendReportSheetName)
+	columns := columnsWidth{
+		newColumnWidth("A", 30),
+		newColumnWidth("B", 12.5),
+		newColumnWidth("C", 15),
+		newColumnWidth("D", 10).toColumn("G"),
+		newColumnWidth("H", 15).toColumn("I"),
+	}
+	columns.setValues(file, pluginsTrendReportSheetName)
+}
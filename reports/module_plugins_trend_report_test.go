@@ -0,0 +1,95 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	core "github.com/trackit/trackit/plugins/account/core"
+)
+
+func TestPluginsTrendReportInsertDataInSheet(t *testing.T) {
+	week := func(day int) time.Time { return time.Date(2021, time.January, day, 0, 0, 0, 0, time.UTC) }
+	file := newDocument()
+	err := pluginsTrendReportInsertDataInSheet(file, []core.PluginHistory{
+		{
+			PluginName: "Idle ELB",
+			Category:   "ELB",
+			Points: []core.PluginHistoryPoint{
+				{Date: week(4), Checked: 4, Passed: 2, EstimatedMonthlySavings: 40},
+				{Date: week(11), Checked: 4, Passed: 3, EstimatedMonthlySavings: 30},
+				{Date: week(18), Checked: 0, Passed: 0, EstimatedMonthlySavings: 30},
+			},
+		},
+		{
+			PluginName: "Unused EBS",
+			Category:   "EC2",
+			Points: []core.PluginHistoryPoint{
+				{Date: week(4), Checked: 5, Passed: 5, EstimatedMonthlySavings: 0},
+				{Date: week(11), Checked: 5, Passed: 4, EstimatedMonthlySavings: 10},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	s := file.getSheet(pluginsTrendReportSheetName)
+	if s == nil {
+		t.Fatalf("Sheet %q should exist.", pluginsTrendReportSheetName)
+	}
+	evaluator := newFormulaEvaluator(s)
+	for _, tc := range []struct {
+		row       int
+		plugin    string
+		week      string
+		failed    interface{}
+		passed    interface{}
+		variation interface{}
+	}{
+		{3, "Idle ELB", "2021-01-04", 2.0, 0.5, ""},
+		{4, "Idle ELB", "2021-01-11", 1.0, 0.75, -0.25},
+		{5, "Idle ELB", "2021-01-18", 0.0, "", 0.0},
+		{6, "Unused EBS", "2021-01-04", 0.0, 1.0, ""},
+		{7, "Unused EBS", "2021-01-11", 1.0, 0.8, ""},
+	} {
+		t.Run(fmt.Sprintf("Row %d", tc.row), func(t *testing.T) {
+			if plugin := file.getCellValue(pluginsTrendReportSheetName, fmt.Sprintf("A%d", tc.row)); plugin != tc.plugin {
+				t.Errorf("Expected plugin %q, got %q", tc.plugin, plugin)
+			}
+			if week := file.getCellValue(pluginsTrendReportSheetName, fmt.Sprintf("C%d", tc.row)); week != tc.week {
+				t.Errorf("Expected week %q, got %q", tc.week, week)
+			}
+			for column, expected := range map[string]interface{}{"F": tc.failed, "G": tc.passed, "I": tc.variation} {
+				c, ok := s.getCell(fmt.Sprintf("%s%d", column, tc.row))
+				if !ok {
+					t.Errorf("Expected a cell in column %s", column)
+					continue
+				}
+				value, err := evaluator.value(c)
+				if err != nil {
+					t.Errorf("Unexpected error in column %s: %s", column, err.Error())
+				} else if !reflect.DeepEqual(value, expected) {
+					t.Errorf("Expected %v in column %s, got %v", expected, column, value)
+				}
+			}
+		})
+	}
+	if _, ok := s.getCell("A8"); ok {
+		t.Errorf("Expected no row after the last point")
+	}
+}
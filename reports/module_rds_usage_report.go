@@ -20,7 +20,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -40,14 +39,14 @@ var rdsUsageReportModule = module{
 
 // generateRdsUsageReportSheet will generate a sheet with RDS usage report
 // It will get data for given AWS account and for a given date
-func generateRdsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateRdsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return rdsUsageReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func rdsUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func rdsUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := rdsUsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return rdsUsageReportInsertDataInSheet(aas, file, data)
@@ -81,8 +80,8 @@ func rdsUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.
 	return
 }
 
-func rdsUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, data []rds.InstanceReport) (err error) {
-	file.NewSheet(rdsUsageReportSheetName)
+func rdsUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data []rds.InstanceReport) (err error) {
+	file.newSheet(rdsUsageReportSheetName)
 	rdsUsageReportGenerateHeader(file)
 	line := 4
 	for _, report := range data {
@@ -121,7 +120,7 @@ func rdsUsageReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File,
 	return
 }
 
-func rdsUsageReportGenerateHeader(file *excelize.File) {
+func rdsUsageReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A3"),
 		newCell("Name", "B1").mergeTo("B3"),
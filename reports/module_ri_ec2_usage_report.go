@@ -20,7 +20,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -40,14 +39,14 @@ var riEc2ReportModule = module{
 
 // generateEc2ReportSheet will generate a sheet with RiEC2 usage report
 // It will get data for given AWS account and for a given date
-func generateRiEc2ReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateRiEc2ReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return riEc2ReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func riEc2ReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func riEc2ReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := riEc2ReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		return riEc2ReportInsertDataInSheet(aas, file, data)
@@ -81,8 +80,8 @@ func riEc2ReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.Tim
 	return
 }
 
-func riEc2ReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, data []riEc2.ReservationReport) (err error) {
-	file.NewSheet(riEc2ReportSheetName)
+func riEc2ReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data []riEc2.ReservationReport) (err error) {
+	file.newSheet(riEc2ReportSheetName)
 	riEc2ReportGenerateHeader(file)
 	line := 4
 	toLine := 0
@@ -120,7 +119,7 @@ func riEc2ReportInsertDataInSheet(aas []aws.AwsAccount, file *excelize.File, dat
 	return
 }
 
-func riEc2ReportGenerateHeader(file *excelize.File) {
+func riEc2ReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A3"),
 		newCell("Reservation", "B1").mergeTo("M1"),
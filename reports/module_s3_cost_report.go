@@ -22,7 +22,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -39,14 +38,14 @@ var s3CostReportModule = module{
 	GenerateSheet: generateS3CostReportSheet,
 }
 
-func generateS3CostReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, _ *sql.Tx, file *excelize.File) (err error) {
+func generateS3CostReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, _ *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return s3CostReportGenerateSheet(ctx, aas, date, file)
 }
 
-func s3CostReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, file *excelize.File) (err error) {
+func s3CostReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, file *document) (err error) {
 	data, err := s3CostReportGetData(ctx, aas, date)
 	if err == nil {
 		return s3CostReportInsertDataInSheet(file, data)
@@ -63,6 +62,7 @@ func s3CostReportGetData(ctx context.Context, aas []aws.AwsAccount, dateBegin ti
 			AccountList: []string{aa.AwsIdentity},
 			DateBegin:   dateBegin,
 			DateEnd:     dateEnd,
+			Metric:      costMetricFromContext(ctx),
 		}
 		logger.Debug("Getting S3 Cost Report for accounts", map[string]interface{}{
 			"accounts": aa,
@@ -81,8 +81,8 @@ func s3CostReportGetData(ctx context.Context, aas []aws.AwsAccount, dateBegin ti
 	return
 }
 
-func s3CostReportInsertDataInSheet(file *excelize.File, data map[aws.AwsAccount]costs.BucketsInfo) (err error) {
-	file.NewSheet(s3CostReportSheetName)
+func s3CostReportInsertDataInSheet(file *document, data map[aws.AwsAccount]costs.BucketsInfo) (err error) {
+	file.newSheet(s3CostReportSheetName)
 	s3CostReportGenerateHeader(file)
 	line := 3
 	for acc, report := range data {
@@ -111,7 +111,7 @@ func s3CostReportInsertDataInSheet(file *excelize.File, data map[aws.AwsAccount]
 	return
 }
 
-func s3CostReportGenerateHeader(file *excelize.File) {
+func s3CostReportGenerateHeader(file *document) {
 	header := cells{
 		newCell("Account", "A1").mergeTo("A2"),
 		newCell("Name", "B1").mergeTo("B2"),
@@ -0,0 +1,157 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/usageReports/history"
+	awsSavingsPlans "github.com/trackit/trackit/aws/usageReports/savingsPlans"
+	"github.com/trackit/trackit/usageReports/savingsPlans"
+	"github.com/trackit/trackit/users"
+)
+
+const savingsPlansReportSheetName = "Savings Plans Report"
+
+var savingsPlansReportModule = module{
+	Name:          "Savings Plans Report",
+	SheetName:     savingsPlansReportSheetName,
+	ErrorName:     "savingsPlansReportError",
+	GenerateSheet: generateSavingsPlansReportSheet,
+}
+
+// generateSavingsPlansReportSheet will generate a sheet with the Savings Plans coverage and utilization
+// It will get data for given AWS account and for a given date
+func generateSavingsPlansReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
+	if date.IsZero() {
+		date, _ = history.GetHistoryDate()
+	}
+	return savingsPlansReportGenerateSheet(ctx, aas, date, tx, file)
+}
+
+func savingsPlansReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
+	data, err := savingsPlansReportGetData(ctx, aas, date, tx)
+	if err == nil {
+		return savingsPlansReportInsertDataInSheet(aas, file, data)
+	}
+	return
+}
+
+func savingsPlansReportGetData(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx) (reports []awsSavingsPlans.SavingsPlansReport, err error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	identities := getAwsIdentities(aas)
+	user, err := users.GetUserWithId(tx, aas[0].UserId)
+	if err != nil {
+		return
+	}
+	parameters := savingsPlans.SavingsPlansQueryParams{
+		AccountList: identities,
+		Date:        date,
+	}
+	logger.Debug("Getting Savings Plans Report for accounts", map[string]interface{}{
+		"accounts": aas,
+		"date":     date,
+	})
+	_, reports, err = savingsPlans.GetSavingsPlansData(ctx, parameters, user, tx)
+	if err != nil {
+		logger.Error("An error occurred while generating a Savings Plans Report", map[string]interface{}{
+			"error":    err,
+			"accounts": aas,
+			"date":     date,
+		})
+	}
+	return
+}
+
+func savingsPlansReportInsertDataInSheet(aas []aws.AwsAccount, file *document, data []awsSavingsPlans.SavingsPlansReport) (err error) {
+	file.newSheet(savingsPlansReportSheetName)
+	savingsPlansReportGenerateHeader(file)
+	line := 4
+	for _, report := range data {
+		account := getAwsAccount(report.Account, aas)
+		formattedAccount := report.Account
+		if account != nil {
+			formattedAccount = formatAwsAccount(*account)
+		}
+		usage := report.SavingsPlans
+		toLine := line
+		for currentLine, plan := range usage.Plans {
+			planCells := cells{
+				newCell(plan.Arn, "L"+strconv.Itoa(currentLine+line)),
+				newCell(plan.TotalCommitment, "M"+strconv.Itoa(currentLine+line)).addStyles("price"),
+				newCell(plan.UnusedCommitment, "N"+strconv.Itoa(currentLine+line)).addStyles("price"),
+				newCell(formatMetricPercentage(plan.Utilization), "O"+strconv.Itoa(currentLine+line)).addStyles("percentage"),
+			}
+			planCells.addStyles("borders", "centerText").setValues(file, savingsPlansReportSheetName)
+			toLine = currentLine + line
+		}
+		cells := cells{
+			newCell(formattedAccount, "A"+strconv.Itoa(line)).mergeTo("A" + strconv.Itoa(toLine)),
+			newCell(usage.Begin.Format("2006-01-02"), "B"+strconv.Itoa(line)).mergeTo("B" + strconv.Itoa(toLine)),
+			newCell(usage.End.Format("2006-01-02"), "C"+strconv.Itoa(line)).mergeTo("C" + strconv.Itoa(toLine)),
+			newCell(usage.TotalCommitment, "D"+strconv.Itoa(line)).mergeTo("D" + strconv.Itoa(toLine)).addStyles("price"),
+			newCell(usage.UnusedCommitment, "E"+strconv.Itoa(line)).mergeTo("E" + strconv.Itoa(toLine)).addStyles("price"),
+			newCell(formatMetricPercentage(usage.Utilization), "F"+strconv.Itoa(line)).mergeTo("F" + strconv.Itoa(toLine)).addStyles("percentage"),
+			newCell(usage.CoveredOnDemandCost, "G"+strconv.Itoa(line)).mergeTo("G" + strconv.Itoa(toLine)).addStyles("price"),
+			newCell(usage.UncoveredOnDemandCost, "H"+strconv.Itoa(line)).mergeTo("H" + strconv.Itoa(toLine)).addStyles("price"),
+			newCell(formatMetricPercentage(usage.Coverage), "I"+strconv.Itoa(line)).mergeTo("I" + strconv.Itoa(toLine)).addStyles("percentage"),
+			newCell(usage.EffectiveCost, "J"+strconv.Itoa(line)).mergeTo("J" + strconv.Itoa(toLine)).addStyles("price"),
+			newCell(usage.Savings, "K"+strconv.Itoa(line)).mergeTo("K" + strconv.Itoa(toLine)).addStyles("price"),
+		}
+		cells.addStyles("borders", "centerText").setValues(file, savingsPlansReportSheetName)
+		line = toLine + 1
+	}
+	return
+}
+
+func savingsPlansReportGenerateHeader(file *document) {
+	header := cells{
+		newCell("Account", "A1").mergeTo("A3"),
+		newCell("Period", "B1").mergeTo("C2"),
+		newCell("Start", "B3"),
+		newCell("End", "C3"),
+		newCell("Utilization", "D1").mergeTo("F2"),
+		newCell("Commitment", "D3"),
+		newCell("Unused", "E3"),
+		newCell("Utilization", "F3"),
+		newCell("Coverage", "G1").mergeTo("I2"),
+		newCell("Covered (On Demand)", "G3"),
+		newCell("Not Covered (On Demand)", "H3"),
+		newCell("Coverage", "I3"),
+		newCell("Cost", "J1").mergeTo("K2"),
+		newCell("Effective", "J3"),
+		newCell("Savings", "K3"),
+		newCell("Savings Plans", "L1").mergeTo("O2"),
+		newCell("ARN", "L3"),
+		newCell("Commitment", "M3"),
+		newCell("Unused", "N3"),
+		newCell("Utilization", "O3"),
+	}
+	header.addStyles("borders", "bold", "centerText").setValues(file, savingsPlansReportSheetName)
+	columns := columnsWidth{
+		newColumnWidth("A", 30),
+		newColumnWidth("B", 12.5).toColumn("C"),
+		newColumnWidth("D", 15).toColumn("K"),
+		newColumnWidth("L", 70),
+		newColumnWidth("M", 15).toColumn("O"),
+	}
+	columns.setValues(file, savingsPlansReportSheetName)
+}
@@ -23,7 +23,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -31,6 +30,7 @@ import (
 	"github.com/trackit/trackit/aws/usageReports/history"
 	"github.com/trackit/trackit/costs/tags"
 	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/tagging/aliases"
 	"github.com/trackit/trackit/users"
 )
 
@@ -45,14 +45,14 @@ var tagsUsageReportModule = module{
 
 // generateTagsUsageReportSheet will generate a sheet with Tags usage report
 // It will get data for given AWS account and for a given date
-func generateTagsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func generateTagsUsageReportSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	if date.IsZero() {
 		date, _ = history.GetHistoryDate()
 	}
 	return tagsUsageReportGenerateSheet(ctx, aas, date, tx, file)
 }
 
-func tagsUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *excelize.File) (err error) {
+func tagsUsageReportGenerateSheet(ctx context.Context, aas []aws.AwsAccount, date time.Time, tx *sql.Tx, file *document) (err error) {
 	data, err := tagsUsageReportGetData(ctx, aas, date, tx)
 	if err == nil {
 		for key, report := range data {
@@ -71,6 +71,7 @@ func getTagsKey(ctx context.Context, parsedParams tags.TagsValuesQueryParams) ([
 		IndexList:   []string{},
 		DateBegin:   parsedParams.DateBegin,
 		DateEnd:     parsedParams.DateEnd,
+		Aliases:     parsedParams.Aliases,
 	}
 	_, keys, err := tags.GetTagsKeysWithParsedParams(ctx, parsedParamsKeys)
 	if err != nil {
@@ -95,6 +96,7 @@ func tagsUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date time
 		TagsKeys:    []string{},
 		By:          "product",
 		Detailed:    true,
+		Metric:      costMetricFromContext(ctx),
 	}
 	parsedParams.AccountList = identities
 	accountsAndIndexes, _, err := es.GetAccountsAndIndexes(parsedParams.AccountList, user, tx, s3.IndexPrefixLineItem)
@@ -103,6 +105,9 @@ func tagsUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date time
 	}
 	parsedParams.AccountList = accountsAndIndexes.Accounts
 	parsedParams.IndexList = accountsAndIndexes.Indexes
+	if parsedParams.Aliases, err = aliases.GetResolverForUser(tx, user.Id); err != nil {
+		return nil, err
+	}
 	keys, err := getTagsKey(ctx, parsedParams)
 	if err != nil {
 		return nil, err
@@ -116,8 +121,8 @@ func tagsUsageReportGetData(ctx context.Context, aas []aws.AwsAccount, date time
 	return
 }
 
-func tagsUsageReportInsertDataInSheet(ctx context.Context, file *excelize.File, key string, data []tags.TagsValues) (err error) {
-	file.NewSheet(key)
+func tagsUsageReportInsertDataInSheet(ctx context.Context, file *document, key string, data []tags.TagsValues) (err error) {
+	file.newSheet(key)
 	tagsUsageReportGenerateHeader(file, key)
 	column := 2
 	maxColumn := 2
@@ -136,7 +141,7 @@ func tagsUsageReportInsertDataInSheet(ctx context.Context, file *excelize.File,
 			}
 			cellsTagsCosts.addStyles("borders", "centerText").setValues(file, key)
 			cellsChart := cells{
-				newCell(file.GetCellValue(key, fmt.Sprintf("A%s", strconv.Itoa(column))), "H"+strconv.Itoa(charColumn)),
+				newCell(file.getCellValue(key, fmt.Sprintf("A%s", strconv.Itoa(column))), "H"+strconv.Itoa(charColumn)),
 				newFormula(fmt.Sprintf("=%s", "F"+strconv.Itoa(column)), "I"+strconv.Itoa(charColumn)).addStyles("price"),
 			}
 			cellsChart.addStyles("borders", "centerText").setValues(file, key)
@@ -151,7 +156,7 @@ func tagsUsageReportInsertDataInSheet(ctx context.Context, file *excelize.File,
 	return
 }
 
-func putProductDataInSheet(file *excelize.File, sheetName string, tag tags.TagsValues, maxColumn int) (int, []string, bool) {
+func putProductDataInSheet(file *document, sheetName string, tag tags.TagsValues, maxColumn int) (int, []string, bool) {
 	productColumn := maxColumn
 	totalCostCells := make([]string, 0)
 	valueProductExist := false
@@ -185,7 +190,7 @@ func putProductDataInSheet(file *excelize.File, sheetName string, tag tags.TagsV
 	return maxColumn, totalCostCells, valueProductExist
 }
 
-func tagsUsageReportGenerateHeader(file *excelize.File, key string) {
+func tagsUsageReportGenerateHeader(file *document, key string) {
 	header := cells{
 		newCell("Tags", "A1"),
 		newCell("Products", "B1"),
@@ -208,9 +213,9 @@ func tagsUsageReportGenerateHeader(file *excelize.File, key string) {
 	columns.setValues(file, key)
 }
 
-func generateLinearChart(ctx context.Context, file *excelize.File, sheetName, data string) error {
+func generateLinearChart(ctx context.Context, file *document, sheetName, data string) error {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
-	err := file.AddChart(sheetName, "J1", fmt.Sprintf(
+	err := file.addChart(sheetName, "J1", fmt.Sprintf(
 		`{"type":"bar",
 				"series":[%s],
 				"format":{
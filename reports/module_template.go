@@ -20,7 +20,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
@@ -36,7 +35,7 @@ var templateModule = module{
 	GenerateSheet: generateTemplateSheet,
 }
 
-func generateTemplateSheet(ctx context.Context, _ []aws.AwsAccount, _ time.Time, _ *sql.Tx, file *excelize.File) (err error) {
+func generateTemplateSheet(ctx context.Context, _ []aws.AwsAccount, _ time.Time, _ *sql.Tx, file *document) (err error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	if len(config.ReportsCover) == 0 {
 		return
@@ -50,8 +49,8 @@ func generateTemplateSheet(ctx context.Context, _ []aws.AwsAccount, _ time.Time,
 			"cover": config.ReportsCover,
 		})
 	}
-	file.NewSheet(templateSheetName)
-	err = file.AddPictureFromBytes(templateSheetName, "A1", `{"x_scale": 0.95, "y_scale": 1}`, imageFile[0], "."+imageFile[len(imageFile)-1], image)
+	file.newSheet(templateSheetName)
+	err = file.addPictureFromBytes(templateSheetName, "A1", `{"x_scale": 0.95, "y_scale": 1}`, imageFile[0], "."+imageFile[len(imageFile)-1], image)
 	if err != nil {
 		logger.Error("An error occurred while generating template for report", map[string]interface{}{
 			"error": err,
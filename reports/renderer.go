@@ -0,0 +1,101 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format is the output format of a generated report.
+type Format string
+
+const (
+	FormatXlsx = Format("xlsx")
+	FormatHtml = Format("html")
+	FormatCsv  = Format("csv")
+
+	DefaultFormat = FormatXlsx
+)
+
+// renderer writes a document in a given format.
+type renderer struct {
+	extension   string
+	contentType string
+	render      func(context.Context, *document, io.Writer) error
+}
+
+var renderers = map[Format]renderer{
+	FormatXlsx: {"xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", renderXlsx},
+	FormatHtml: {"html", "text/html; charset=utf-8", renderHtml},
+	FormatCsv:  {"zip", "application/zip", renderCsvZip},
+}
+
+// ParseFormat returns the format with the given name, the default format
+// being used if the name is empty.
+func ParseFormat(name string) (Format, error) {
+	if name == "" {
+		return DefaultFormat, nil
+	}
+	format := Format(strings.ToLower(name))
+	if _, ok := renderers[format]; !ok {
+		return "", fmt.Errorf("Report format %s does not exist", name)
+	}
+	return format, nil
+}
+
+func getRenderer(format Format) renderer {
+	if r, ok := renderers[format]; ok {
+		return r
+	}
+	return renderers[DefaultFormat]
+}
+
+// displayValue formats a value the way the styles of its cell display it in
+// a spreadsheet.
+func displayValue(value interface{}, styles []string) string {
+	if value == nil {
+		return ""
+	}
+	number, ok := formulaNumber(value)
+	if _, isString := value.(string); isString || !ok {
+		return fmt.Sprint(value)
+	}
+	for _, style := range styles {
+		switch style {
+		case "price":
+			return "$" + strconv.FormatFloat(number, 'f', 3, 64)
+		case "percentage":
+			return strconv.FormatFloat(number*100, 'f', 2, 64) + "%"
+		}
+	}
+	return rawValue(value)
+}
+
+// rawValue formats a value without taking styles into account.
+func rawValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,100 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// renderCsvZip writes a document as a zip archive with one CSV file per
+// sheet. Formulas are evaluated and values are written without their
+// styles so that they can be processed. Merged cells are only written in
+// their first position, charts and pictures are left out. Text which a
+// spreadsheet would run as a formula is prefixed with a quote.
+func renderCsvZip(_ context.Context, doc *document, writer io.Writer) error {
+	archive := zip.NewWriter(writer)
+	names := make(map[string]int)
+	for _, s := range doc.sheets {
+		name := csvFileName(s.name)
+		names[name]++
+		if names[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, names[name])
+		}
+		file, err := archive.Create(name + ".csv")
+		if err != nil {
+			return err
+		}
+		if err = renderCsvSheet(s, file); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func renderCsvSheet(s *sheet, writer io.Writer) error {
+	evaluator := newFormulaEvaluator(s)
+	grid, rows, columns := s.grid()
+	csvWriter := csv.NewWriter(writer)
+	for row := 0; row < rows; row++ {
+		record := make([]string, columns)
+		for column, c := range grid[row] {
+			if value, err := evaluator.value(c); err != nil {
+				record[column] = "#ERROR"
+			} else {
+				record[column] = csvValue(value)
+			}
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// csvFormulaPrefixes are the first characters which make a spreadsheet
+// interpret a CSV cell as a formula.
+const csvFormulaPrefixes = "=+-@"
+
+// csvValue returns the text of a value in a CSV file. Text starting with
+// one of csvFormulaPrefixes is prefixed with a quote so that it is opened
+// as text, numbers are written as they are.
+func csvValue(value interface{}) string {
+	text := rawValue(value)
+	if _, ok := value.(string); ok && text != "" && strings.IndexByte(csvFormulaPrefixes, text[0]) >= 0 {
+		return "'" + text
+	}
+	return text
+}
+
+// csvFileName replaces the characters of a sheet name which are not safe in
+// a file name.
+func csvFileName(sheetName string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, sheetName)
+	if name == "" {
+		return "sheet"
+	}
+	return name
+}
@@ -0,0 +1,124 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestCsvValue(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value interface{}
+		text  string
+	}{
+		{"Text", "text", "text"},
+		{"Empty text", "", ""},
+		{"Formula", "=HYPERLINK(\"http://example.com\")", "'=HYPERLINK(\"http://example.com\")"},
+		{"Plus", "+1+2", "'+1+2"},
+		{"Minus", "-2+3", "'-2+3"},
+		{"At", "@SUM(1,2)", "'@SUM(1,2)"},
+		{"Sign inside text", "a=b", "a=b"},
+		{"Negative number", -2.5, "-2.5"},
+		{"Integer", 42, "42"},
+		{"Boolean", true, "true"},
+		{"Empty cell", nil, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if text := csvValue(tc.value); text != tc.text {
+				t.Errorf("Expected %q, got %q", tc.text, text)
+			}
+		})
+	}
+}
+
+func TestRenderCsvSheet(t *testing.T) {
+	s := &sheet{
+		name: "Costs",
+		cells: cells{
+			newCell("Name", "A1"),
+			newCell("Cost", "B1"),
+			newCell("=cmd|' /C calc'!A0", "A2"),
+			newCell(-1.5, "B2"),
+			newCell("@account", "A3"),
+			newCell(3, "B3"),
+			newFormula(`="-"`, "A4"),
+			newFormula("=B2+B3", "B4"),
+			newFormula(`="+total"`, "A5"),
+			newFormula("=1/0", "B5"),
+		},
+	}
+	var buffer bytes.Buffer
+	if err := renderCsvSheet(s, &buffer); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read the CSV: %s", err.Error())
+	}
+	expected := [][]string{
+		{"Name", "Cost"},
+		{"'=cmd|' /C calc'!A0", "-1.5"},
+		{"'@account", "3"},
+		{"'-", "1.5"},
+		{"'+total", "#ERROR"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %q, got %q", expected, records)
+	}
+}
+
+func TestRenderCsvZip(t *testing.T) {
+	doc := &document{sheets: []*sheet{
+		{name: "Costs/Month", cells: cells{newCell("a", "A1")}},
+		{name: "Costs:Month", cells: cells{newCell("b", "A1")}},
+		{name: "", cells: cells{newCell("c", "A1")}},
+	}}
+	var buffer bytes.Buffer
+	if err := renderCsvZip(context.Background(), doc, &buffer); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read the archive: %s", err.Error())
+	}
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %s", file.Name, err.Error())
+		}
+		content, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %s", file.Name, err.Error())
+		}
+		files[file.Name] = string(content)
+	}
+	expected := map[string]string{
+		"Costs_Month.csv":   "a\n",
+		"Costs_Month_2.csv": "b\n",
+		"sheet.csv":         "c\n",
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %q, got %q", expected, files)
+	}
+}
@@ -0,0 +1,199 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// htmlStyles are the CSS rules of the cell styles used by the modules.
+var htmlStyles = map[string]string{
+	"borders":    "border: 1px solid #000000;",
+	"bold":       "font-weight: bold;",
+	"centerText": "text-align: center; vertical-align: middle;",
+	"green":      "color: #006600; background-color: #CCFFCC;",
+	"orange":     "color: #C65911; background-color: #F8CBAD;",
+	"red":        "color: #CC0000; background-color: #FFCCCC;",
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>TrackIt report</title>
+<style>
+body { font-family: Arial, Helvetica, sans-serif; font-size: 12px; }
+table { border-collapse: collapse; margin-bottom: 32px; }
+td { padding: 2px 6px; white-space: nowrap; }
+</style>
+</head>
+<body>
+{{- if .Intro}}
+<p>{{.Intro}}</p>
+{{- end}}
+{{- range .Sheets}}
+<h2>{{.Name}}</h2>
+{{- range .Pictures}}
+<img src="{{.}}" alt="">
+{{- end}}
+{{- if .Rows}}
+<table>
+{{- range .Rows}}
+<tr>
+{{- range .}}
+<td{{if gt .Colspan 1}} colspan="{{.Colspan}}"{{end}}{{if gt .Rowspan 1}} rowspan="{{.Rowspan}}"{{end}}{{if .Style}} style="{{.Style}}"{{end}}>{{.Value}}</td>
+{{- end}}
+</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlReport struct {
+	Intro  string
+	Sheets []htmlSheet
+}
+
+type htmlSheet struct {
+	Name     string
+	Pictures []template.URL
+	Rows     [][]htmlCell
+}
+
+type htmlCell struct {
+	Value   string
+	Colspan int
+	Rowspan int
+	Style   template.CSS
+}
+
+// renderHtml writes a document as a single HTML page with one table per
+// sheet, which can be sent as an email. Formulas are evaluated, hidden
+// columns and charts are left out.
+func renderHtml(ctx context.Context, doc *document, writer io.Writer) error {
+	return renderHtmlWithIntro(ctx, doc, "", writer)
+}
+
+// renderHtmlWithIntro renders a document like renderHtml, with a paragraph
+// of text before the sheets.
+func renderHtmlWithIntro(_ context.Context, doc *document, intro string, writer io.Writer) error {
+	report := htmlReport{
+		Intro:  intro,
+		Sheets: make([]htmlSheet, 0, len(doc.sheets)),
+	}
+	for _, s := range doc.sheets {
+		report.Sheets = append(report.Sheets, htmlSheetFromSheet(s))
+	}
+	return htmlTemplate.Execute(writer, report)
+}
+
+func htmlSheetFromSheet(s *sheet) htmlSheet {
+	evaluator := newFormulaEvaluator(s)
+	grid, rows, columns := s.grid()
+	covered := make(map[[2]int]bool)
+	output := htmlSheet{
+		Name:     s.name,
+		Pictures: make([]template.URL, 0, len(s.pictures)),
+		Rows:     make([][]htmlCell, 0, rows),
+	}
+	for _, p := range s.pictures {
+		output.Pictures = append(output.Pictures, template.URL("data:"+mime.TypeByExtension(p.extension)+";base64,"+base64.StdEncoding.EncodeToString(p.content)))
+	}
+	for row := 0; row < rows; row++ {
+		htmlRow := make([]htmlCell, 0, columns)
+		for column := 0; column < columns; column++ {
+			if covered[[2]int{row, column}] || s.isColumnHidden(column) {
+				continue
+			}
+			c, ok := grid[row][column]
+			if !ok {
+				htmlRow = append(htmlRow, htmlCell{Colspan: 1, Rowspan: 1})
+				continue
+			}
+			htmlRow = append(htmlRow, htmlCellFromCell(s, c, evaluator, row, column, covered))
+		}
+		output.Rows = append(output.Rows, htmlRow)
+	}
+	return output
+}
+
+// htmlCellFromCell converts a cell, marking the positions covered by its
+// merge so that they are not rendered.
+func htmlCellFromCell(s *sheet, c cell, evaluator *formulaEvaluator, row, column int, covered map[[2]int]bool) htmlCell {
+	output := htmlCell{Colspan: 1, Rowspan: 1}
+	if mergeColumn, mergeRow, err := parseLocation(c.merge); err == nil {
+		output.Colspan = 0
+		output.Rowspan = mergeRow - row + 1
+		for mergedColumn := column; mergedColumn <= mergeColumn; mergedColumn++ {
+			if !s.isColumnHidden(mergedColumn) {
+				output.Colspan++
+			}
+			for mergedRow := row; mergedRow <= mergeRow; mergedRow++ {
+				covered[[2]int{mergedRow, mergedColumn}] = true
+			}
+		}
+	}
+	styles := append([]string{}, c.styles...)
+	value, err := evaluator.value(c)
+	if err != nil {
+		output.Value = "#ERROR"
+	} else {
+		output.Value = displayValue(value, c.styles)
+		styles = append(styles, matchingConditionalStyles(value, c.conditionalFormats)...)
+	}
+	var style strings.Builder
+	for _, name := range styles {
+		style.WriteString(htmlStyles[name])
+	}
+	output.Style = template.CSS(style.String())
+	return output
+}
+
+// matchingConditionalStyles returns the styles of the first predefined
+// conditional format matched by a value.
+func matchingConditionalStyles(value interface{}, formats conditionalFormats) []string {
+	for _, format := range formats {
+		var condition struct {
+			Criteria string `json:"criteria"`
+			Value    string `json:"value"`
+		}
+		raw, ok := conditionsRaw[format.value]
+		if format.custom || !ok || json.Unmarshal([]byte(raw), &condition) != nil {
+			continue
+		}
+		var expected interface{} = condition.Value
+		if number, err := strconv.ParseFloat(condition.Value, 64); err == nil {
+			expected = number
+		}
+		operator := condition.Criteria
+		if operator == "!=" {
+			operator = "<>"
+		}
+		if matches, ok := compareFormulaValues(value, expected, operator).(bool); ok && matches {
+			return format.styles
+		}
+	}
+	return nil
+}
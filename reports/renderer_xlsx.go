@@ -0,0 +1,118 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package reports
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/trackit/jsonlog"
+)
+
+// renderXlsx writes a document as an XLSX spreadsheet, with one worksheet
+// per sheet of the document.
+// Note: The first worksheet created by excelize is removed since it is unused
+func renderXlsx(ctx context.Context, doc *document, writer io.Writer) error {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	file := excelize.NewFile()
+	defaultSheet := file.GetSheetName(1)
+	for _, s := range doc.sheets {
+		file.NewSheet(s.name)
+		s.columnsWidth.renderXlsx(file, s.name)
+		s.cells.renderXlsx(file, s.name)
+		for _, column := range s.hiddenColumns {
+			file.SetColVisible(s.name, column, false)
+		}
+		for _, c := range s.charts {
+			if err := file.AddChart(s.name, c.location, c.format); err != nil {
+				logger.Error("Error when generating the chart", map[string]interface{}{
+					"error": err.Error(),
+					"sheet": s.name,
+				})
+			}
+		}
+		for _, p := range s.pictures {
+			if err := file.AddPictureFromBytes(s.name, p.location, p.format, p.name, p.extension, p.content); err != nil {
+				logger.Error("Error when adding a picture", map[string]interface{}{
+					"error": err.Error(),
+					"sheet": s.name,
+				})
+			}
+		}
+	}
+	if doc.getSheet(defaultSheet) == nil {
+		file.DeleteSheet(defaultSheet)
+	}
+	return file.Write(writer)
+}
+
+func (cs cells) renderXlsx(file *excelize.File, sheet string) {
+	for _, cell := range cs {
+		cell.renderXlsx(file, sheet)
+	}
+}
+
+func (c cell) renderXlsx(file *excelize.File, sheet string) {
+	if len(c.formula) > 0 {
+		file.SetCellFormula(sheet, c.location, c.formula)
+	} else {
+		file.SetCellValue(sheet, c.location, c.value)
+	}
+	endCellLocation := c.location
+	if len(c.merge) > 0 {
+		file.MergeCell(sheet, c.location, c.merge)
+		endCellLocation = c.merge
+	}
+	if len(c.styles) > 0 {
+		styleId, err := getStyleId(file, c.styles)
+		if err == nil {
+			file.SetCellStyle(sheet, c.location, endCellLocation, styleId)
+		} else {
+			jsonlog.DefaultLogger.Warning("Error while applying style to a cell", map[string]interface{}{
+				"error": err,
+				"cell":  c,
+			})
+		}
+	}
+	if len(c.conditionalFormats) > 0 {
+		formattedConditions, err := c.conditionalFormats.getConditionalFormatting(file)
+		if err == nil {
+			err = file.SetConditionalFormat(sheet, strings.Join([]string{c.location, endCellLocation}, ":"), formattedConditions)
+			if err != nil {
+				jsonlog.DefaultLogger.Warning("Error while applying conditional formatting to a cell", map[string]interface{}{
+					"error": err,
+					"cell":  c,
+				})
+			}
+		} else {
+			jsonlog.DefaultLogger.Warning("Error while getting conditional formatting", map[string]interface{}{
+				"error": err,
+				"cell":  c,
+			})
+		}
+	}
+}
+
+func (c columnWidth) renderXlsx(file *excelize.File, sheet string) {
+	file.SetColWidth(sheet, c.from, c.to, c.width)
+}
+
+func (cs columnsWidth) renderXlsx(file *excelize.File, sheet string) {
+	for _, col := range cs {
+		col.renderXlsx(file, sheet)
+	}
+}
@@ -16,6 +16,7 @@ package reports
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -23,17 +24,94 @@ import (
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/s3"
 	"github.com/trackit/trackit/db"
 )
 
+type contextKey uint
+
+const (
+	contextKeyCostMetric = contextKey(iota)
+)
+
+// contextWithCostMetric returns a context in which the modules of a report
+// use the given cost metric.
+func contextWithCostMetric(ctx context.Context, metric string) context.Context {
+	return context.WithValue(ctx, contextKeyCostMetric, metric)
+}
+
+// costMetricFromContext returns the cost metric used by the modules of a
+// report, or the default one.
+func costMetricFromContext(ctx context.Context) string {
+	if metric, ok := ctx.Value(contextKeyCostMetric).(string); ok && metric != "" {
+		return metric
+	}
+	return s3.DefaultCostMetric
+}
+
 // GenerateReport will generate a spreadsheet report for a given AWS account and for a given month
 // It will iterate over available modules and generate a sheet for each module.
-// Note: First sheet is removed since it is unused (Created by excelize)
-// Report is then uploaded to an S3 bucket
+// The costs are computed with the given cost metric, the unblended cost being used if it is empty.
+// Report is then rendered in the given format and uploaded to an S3 bucket
 // Note: File can be saved locally by using `saveSpreadsheetLocally` instead of `saveSpreadsheet`
-func GenerateReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount, date time.Time) (errs map[string]error) {
+func GenerateReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount, date time.Time, metric string, format Format) (errs map[string]error) {
+	_, _, errs = generateReport(ctx, aa, aas, date, metric, format, modules, "")
+	return
+}
+
+// GenerateDefinitionReport will generate the report of a definition for a given AWS account and for a given month
+// It will only generate the sheets of the modules of the definition, in their order.
+// The accounts of a master definition are selected among aas, which is nil for other definitions.
+// The date and format given for the generation take precedence over the ones of the definition.
+// Report is then uploaded to an S3 bucket and the recipients of the definition are notified by mail
+func GenerateDefinitionReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount, date time.Time, metric string, format Format, definition Definition) (errs map[string]error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	definitionModules, err := getModulesByName(definition.Modules)
+	if err != nil {
+		return map[string]error{"speadsheetError": err}
+	}
+	if format == "" {
+		format = definition.Format
+	}
+	if definition.Master {
+		if aas = definition.selectAccounts(aas); len(aas) == 0 {
+			return map[string]error{"speadsheetError": errors.New("No account of the report definition is available")}
+		}
+	} else {
+		aas = nil
+	}
+	filename, doc, errs := generateReport(ctx, aa, aas, definition.reportDate(date), metric, format, definitionModules, definition.Name)
+	if errs["speadsheetError"] == nil && len(definition.Recipients) > 0 {
+		body, err := getDefinitionMailBody(ctx, aa, definition, filename, doc)
+		if err != nil {
+			logger.Error("Failed to render report notification.", map[string]interface{}{
+				"definitionId": definition.Id,
+				"error":        err.Error(),
+			})
+			return
+		}
+		for _, recipient := range definition.Recipients {
+			if err := notifyDefinitionRecipient(ctx, recipient, definition, body); err != nil {
+				logger.Error("Failed to notify report recipient.", map[string]interface{}{
+					"definitionId": definition.Id,
+					"recipient":    recipient,
+					"error":        err.Error(),
+				})
+			}
+		}
+	}
+	return
+}
+
+// generateReport generates a report with the given modules. The name of the
+// report file and its document are returned along with the errors of the
+// modules.
+func generateReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount, date time.Time, metric string, format Format, reportModules []module, name string) (filename string, doc *document, errs map[string]error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	now := time.Now()
+	if format == "" {
+		format = DefaultFormat
+	}
 	var reportType spreadsheetType
 	var reportDate string
 	if date.IsZero() {
@@ -45,12 +123,17 @@ func GenerateReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount
 	} else {
 		reportDate = fmt.Sprintf("%s%s", (date.Month()).String(), strconv.Itoa(date.Year()))
 	}
+	if metric != "" && metric != s3.DefaultCostMetric {
+		reportDate = fmt.Sprintf("%s_%s", reportDate, metric)
+	}
+	ctx = contextWithCostMetric(ctx, metric)
 	if aas == nil {
 		aas = []aws.AwsAccount{aa}
 		reportType = RegularReport
 		logger.Info("Generating spreadsheet for account", map[string]interface{}{
 			"account": aa,
 			"date":    reportDate,
+			"format":  format,
 		})
 	} else {
 		reportType = MasterReport
@@ -58,20 +141,23 @@ func GenerateReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount
 			"masterAccount": aa,
 			"accounts":      aas,
 			"date":          reportDate,
+			"format":        format,
 		})
 	}
 	errs = make(map[string]error)
-	file := createSpreadsheet(aa, reportDate)
+	file := createSpreadsheet(aa, reportDate, format)
+	file.name = name
+	filename = getFilename(file, reportType)
+	doc = file.document
 	if tx, err := db.Db.BeginTx(ctx, nil); err != nil {
 		errs["speadsheetError"] = err
 	} else {
-		for _, module := range modules {
-			err = module.GenerateSheet(ctx, aas, date, tx, file.File)
+		for _, module := range reportModules {
+			err = module.GenerateSheet(ctx, aas, date, tx, file.document)
 			if err != nil {
 				errs[module.ErrorName] = err
 			}
 		}
-		file.File.DeleteSheet(file.File.GetSheetName(1))
 		errs["speadsheetError"] = saveSpreadsheet(ctx, file, reportType)
 	}
 	return
@@ -79,12 +165,14 @@ func GenerateReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount
 
 // GenerateTagsReport will generate a spreadsheet tags report for a given AWS account and for a given month
 // It will iterate over available modules and generate a sheet for each module.
-// Note: First sheet is removed since it is unused (Created by excelize)
-// Report is then uploaded to an S3 bucket
+// Report is then rendered in the given format and uploaded to an S3 bucket
 // Note: File can be saved locally by using `saveSpreadsheetLocally` instead of `saveSpreadsheet`
-func GenerateTagsReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount, date time.Time) (errs map[string]error) {
+func GenerateTagsReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount, date time.Time, format Format) (errs map[string]error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	now := time.Now()
+	if format == "" {
+		format = DefaultFormat
+	}
 	var reportDate string
 	if date.IsZero() {
 		if now.Month() != time.January {
@@ -100,24 +188,25 @@ func GenerateTagsReport(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAcc
 		logger.Info("Generating spreadsheet tags for account", map[string]interface{}{
 			"account": aa,
 			"date":    reportDate,
+			"format":  format,
 		})
 	} else {
 		logger.Info("Generating spreadsheet tags for accounts", map[string]interface{}{
 			"masterAccount": aa,
 			"accounts":      aas,
 			"date":          reportDate,
+			"format":        format,
 		})
 	}
 	errs = make(map[string]error)
-	file := createSpreadsheet(aa, reportDate)
+	file := createSpreadsheet(aa, reportDate, format)
 	if tx, err := db.Db.BeginTx(ctx, nil); err != nil {
 		errs["speadsheetError"] = err
 	} else {
-		err = generateTagsUsageReportSheet(ctx, aas, date, tx, file.File)
+		err = generateTagsUsageReportSheet(ctx, aas, date, tx, file.document)
 		if err != nil {
 			errs["tagsError"] = err
 		}
-		file.File.DeleteSheet(file.File.GetSheetName(1))
 		errs["speadsheetError"] = saveSpreadsheet(ctx, file, TagsReport)
 	}
 	return
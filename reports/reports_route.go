@@ -44,6 +44,7 @@ func init() {
 				Description: "Responds with the list of reports based on the queryparams passed to it",
 			},
 			routes.QueryArgs{routes.AwsAccountIdQueryArg},
+			users.RequirePermission{users.PermissionRunReports},
 		),
 	}.H().Register("/reports")
 
@@ -58,11 +59,15 @@ func init() {
 			routes.QueryArgs{routes.AwsAccountIdQueryArg},
 			routes.QueryArgs{routes.ReportTypeQueryArg},
 			routes.QueryArgs{routes.FileNameQueryArg},
+			users.RequirePermission{users.PermissionRunReports},
 		),
 	}.H().Register("/report")
 }
 
 func isUserAccount(tx *sql.Tx, user users.User, aa int) (bool, error) {
+	if !user.CanAccessAwsAccount(aa) {
+		return false, nil
+	}
 	aaDB, err := models.AwsAccountByID(tx, aa)
 	if err != nil {
 		return false, err
@@ -20,8 +20,9 @@ import (
 	"io"
 	"path"
 	"strconv"
+	"strings"
+	"unicode"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/trackit/jsonlog"
@@ -32,9 +33,11 @@ import (
 )
 
 type spreadsheet struct {
-	account taws.AwsAccount
-	date    string
-	File    *excelize.File
+	account  taws.AwsAccount
+	date     string
+	format   Format
+	name     string
+	document *document
 }
 
 type spreadsheetType uint8
@@ -45,37 +48,59 @@ const (
 	TagsReport
 )
 
-func createSpreadsheet(aa taws.AwsAccount, date string) spreadsheet {
+func createSpreadsheet(aa taws.AwsAccount, date string, format Format) spreadsheet {
 	return spreadsheet{
-		account: aa,
-		date:    date,
-		File:    excelize.NewFile(),
+		account:  aa,
+		date:     date,
+		format:   format,
+		document: newDocument(),
 	}
 }
 
 /*
-func getFilenameLocally(account taws.AwsAccount, date string, reportType spreadsheetType) string {
-	return fmt.Sprintf("/reports/%s", getFilename(account, date, reportType))
+func getFilenameLocally(file spreadsheet, reportType spreadsheetType) string {
+	return fmt.Sprintf("/reports/%s", getFilename(file, reportType))
 }
 */
 
-func getFilename(account taws.AwsAccount, date string, reportType spreadsheetType) string {
+// getFilename returns the name of a report file. The reports of a definition
+// are prefixed by the name of the definition.
+func getFilename(file spreadsheet, reportType spreadsheetType) string {
 	reportName := ""
-	if reportType == MasterReport {
+	if file.name != "" {
+		reportName = sanitizeFilename(file.name) + "_"
+	} else if reportType == MasterReport {
 		reportName = "MasterReport_"
 	} else if reportType == TagsReport {
 		reportName = "TagsReport_"
 	}
-	return fmt.Sprintf("TRACKIT_%s%s_%s.xlsx", reportName, account.Pretty, date)
+	return fmt.Sprintf("TRACKIT_%s%s_%s.%s", reportName, file.account.Pretty, file.date, getRenderer(file.format).extension)
+}
+
+// sanitizeFilename replaces the characters of a name which are not letters,
+// digits or dashes.
+func sanitizeFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
 }
 
 /*
 func saveSpreadsheetLocally(ctx context.Context, file spreadsheet, reportType spreadsheetType) (err error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 
-	filename := getFilenameLocally(file.account, file.date, reportType)
+	filename := getFilenameLocally(file, reportType)
 
-	err = file.File.SaveAs(filename)
+	output, err := os.Create(filename)
+	if err == nil {
+		err = getRenderer(file.format).render(ctx, file.document, output)
+		if closeErr := output.Close(); err == nil {
+			err = closeErr
+		}
+	}
 	if err != nil {
 		logger.Error("Error while saving file", err)
 	}
@@ -86,7 +111,8 @@ func saveSpreadsheetLocally(ctx context.Context, file spreadsheet, reportType sp
 func saveSpreadsheet(ctx context.Context, file spreadsheet, reportType spreadsheetType) (err error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 
-	filename := getFilename(file.account, file.date, reportType)
+	renderer := getRenderer(file.format)
+	filename := getFilename(file, reportType)
 	reportPath := path.Join(strconv.Itoa(file.account.Id), "generated-report", filename)
 
 	logger.Info("Uploading spreadsheet", reportPath)
@@ -101,20 +127,22 @@ func saveSpreadsheet(ctx context.Context, file spreadsheet, reportType spreadshe
 				})
 			}
 		}()
-		err := file.File.Write(writer)
+		err := renderer.render(ctx, file.document, writer)
 		if err != nil {
 			logger.Error("Error while saving report", map[string]interface{}{
 				"report": reportPath,
 				"error":  err.Error(),
 			})
+			writer.CloseWithError(err)
 		}
 	}()
 
 	uploader := s3manager.NewUploader(awsSession.Session)
 	result, err := uploader.Upload(&s3manager.UploadInput{
-		Body:   reader,
-		Bucket: aws.String(config.ReportsBucket),
-		Key:    aws.String(reportPath),
+		Body:        reader,
+		Bucket:      aws.String(config.ReportsBucket),
+		Key:         aws.String(reportPath),
+		ContentType: aws.String(renderer.contentType),
 	})
 	if err != nil {
 		logger.Error("Failed to upload report", map[string]interface{}{
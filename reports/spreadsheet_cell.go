@@ -14,14 +14,6 @@
 
 package reports
 
-import (
-	"strings"
-
-	"github.com/trackit/jsonlog"
-
-	"github.com/360EntSecGroup-Skylar/excelize"
-)
-
 type cell struct {
 	value              interface{}
 	formula            string
@@ -92,50 +84,11 @@ func (cs cells) addCustomConditionalFormat(condition string, styles ...string) c
 }
 */
 
-func (cs cells) setValues(file *excelize.File, sheet string) {
-	for _, cell := range cs {
-		cell.setValue(file, sheet)
-	}
-}
-
-func (c cell) setValue(file *excelize.File, sheet string) {
-	if len(c.formula) > 0 {
-		file.SetCellFormula(sheet, c.location, c.formula)
-	} else {
-		file.SetCellValue(sheet, c.location, c.value)
-	}
-	endCellLocation := c.location
-	if len(c.merge) > 0 {
-		file.MergeCell(sheet, c.location, c.merge)
-		endCellLocation = c.merge
-	}
-	if len(c.styles) > 0 {
-		styleId, err := getStyleId(file, c.styles)
-		if err == nil {
-			file.SetCellStyle(sheet, c.location, endCellLocation, styleId)
-		} else {
-			jsonlog.DefaultLogger.Warning("Error while applying style to a cell", map[string]interface{}{
-				"error": err,
-				"cell":  c,
-			})
-		}
-	}
-	if len(c.conditionalFormats) > 0 {
-		formattedConditions, err := c.conditionalFormats.getConditionalFormatting(file)
-		if err == nil {
-			err = file.SetConditionalFormat(sheet, strings.Join([]string{c.location, endCellLocation}, ":"), formattedConditions)
-			if err != nil {
-				jsonlog.DefaultLogger.Warning("Error while applying conditional formatting to a cell", map[string]interface{}{
-					"error": err,
-					"cell":  c,
-				})
-			}
-		} else {
-			jsonlog.DefaultLogger.Warning("Error while getting conditional formatting", map[string]interface{}{
-				"error": err,
-				"cell":  c,
-			})
-		}
+// setValues adds the cells to a sheet of the document. Cells are ignored if
+// the sheet does not exist.
+func (cs cells) setValues(file *document, sheet string) {
+	if s := file.getSheet(sheet); s != nil {
+		s.cells = append(s.cells, cs...)
 	}
 }
 
@@ -148,12 +101,8 @@ func (c columnWidth) toColumn(column string) columnWidth {
 	return c
 }
 
-func (c columnWidth) setValue(file *excelize.File, sheet string) {
-	file.SetColWidth(sheet, c.from, c.to, c.width)
-}
-
-func (cs columnsWidth) setValues(file *excelize.File, sheet string) {
-	for _, col := range cs {
-		col.setValue(file, sheet)
+func (cs columnsWidth) setValues(file *document, sheet string) {
+	if s := file.getSheet(sheet); s != nil {
+		s.columnsWidth = append(s.columnsWidth, cs...)
 	}
 }
@@ -126,6 +126,17 @@ var (
 		Optional:    false,
 	}
 
+	// CostMetricQueryArg allows to get the cost metric in the URL
+	// Parameters with routes.QueryArgs. This metric will be a
+	// string stored in the routes.Arguments map with itself for key.
+	// CostMetricQueryArg is optional: the unblended cost is used by default.
+	CostMetricQueryArg = QueryArg{
+		Name:        "metric",
+		Type:        QueryArgString{},
+		Description: "The cost metric. Possible values are unblended, blended, netunblended and amortized. Default is unblended. Periods whose bills were ingested before the other metrics were available only support unblended",
+		Optional:    true,
+	}
+
 	// ShareIdQueryArg allows to get the DB id for an Shared access in the URL Parameters
 	// with routes.QueryArgs. This Shared ID will be an Uint stored
 	// in the routes.Arguments map with itself for key.
@@ -39,6 +39,7 @@ type S3QueryParams struct {
 	DateBegin   time.Time
 	DateEnd     time.Time
 	AccountList []string
+	Metric      string
 	indexList   []string
 }
 
@@ -77,6 +78,7 @@ func init() {
 			routes.QueryArgs{routes.AwsAccountsOptionalQueryArg},
 			routes.QueryArgs{routes.DateBeginQueryArg},
 			routes.QueryArgs{routes.DateEndQueryArg},
+			users.RequirePermission{users.PermissionViewCosts},
 			cache.UsersCache{},
 			routes.Documentation{
 				Summary:     "get the s3 costs data",
@@ -105,11 +107,16 @@ func makeElasticSearchRequest(ctx context.Context, parsedParams S3QueryParams,
 		return nil, http.StatusInternalServerError, err
 	}
 
+	metricField, err := s3.CostMetricField(parsedParams.Metric)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
 	searchService := GetS3UsageAndCostElasticSearchParams(
 		parsedParams.AccountList,
 		parsedParams.DateBegin,
 		parsedParams.DateEnd,
 		esFilters,
+		metricField,
 		es.Client,
 		index,
 	)
@@ -162,9 +169,18 @@ func getS3CostDataHandler(request *http.Request, a routes.Arguments) (int, inter
 }
 
 // GetS3CostData returns the s3 cost data based on the query params.
+// The costs are computed with the metric of the params, the unblended cost
+// being used if it is empty.
 func GetS3CostData(ctx context.Context, parsedParams S3QueryParams) (int, BucketsInfo, error) {
 	var returnCode int
-	var err error
+	index := strings.Join(parsedParams.indexList, ",")
+	err := s3.CheckCostMetricIngested(ctx, es.Client, index, parsedParams.AccountList, parsedParams.DateBegin, parsedParams.DateEnd, parsedParams.Metric)
+	if err == s3.ErrCostMetricNotIngested {
+		return http.StatusBadRequest, nil, err
+	} else if err != nil {
+		jsonlog.LoggerFromContextOrDefault(ctx).Error("Failed to check the cost metric of line items", map[string]interface{}{"error": err.Error()})
+		return http.StatusInternalServerError, nil, errors.GetErrorMessage(ctx, err)
+	}
 	var components = [...]struct {
 		k  string
 		sr *elastic.SearchResult
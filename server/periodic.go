@@ -0,0 +1,119 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/periodic"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+// maxTaskErrorLength is the length of the last_error column of the
+// scheduled_task table, in characters.
+const maxTaskErrorLength = 1024
+
+// truncateTaskError truncates an error message to maxTaskErrorLength
+// characters, so that a multi-byte character is never split.
+func truncateTaskError(message string) string {
+	length := 0
+	for index := range message {
+		if length == maxTaskErrorLength {
+			return message[:index]
+		}
+		length++
+	}
+	return message
+}
+
+// sqlTaskStore is a periodic.Store backed by the scheduled_task table, so
+// that periodic tasks run on a single replica of the server.
+type sqlTaskStore struct{}
+
+func (sqlTaskStore) Claim(name, holder string, planned, since, leaseUntil time.Time) (bool, error) {
+	return models.ClaimScheduledTask(db.Db, name, holder, planned, since, leaseUntil)
+}
+
+func (sqlTaskStore) Release(name, holder string, finished time.Time, runErr error) error {
+	var lastError string
+	if runErr != nil {
+		lastError = truncateTaskError(runErr.Error())
+	}
+	return models.ReleaseScheduledTask(db.Db, name, holder, finished, lastError)
+}
+
+func (sqlTaskStore) State(name string) (periodic.TaskState, error) {
+	dbTask, err := models.ScheduledTaskByName(db.Db, name)
+	if err == sql.ErrNoRows {
+		return periodic.TaskState{}, nil
+	} else if err != nil {
+		return periodic.TaskState{}, err
+	}
+	return periodic.TaskState{
+		LastRun:      dbTask.LastRun.Time,
+		LastFinished: dbTask.LastFinished.Time,
+		LastError:    dbTask.LastError,
+		Holder:       dbTask.LeaseHolder,
+		LeaseUntil:   dbTask.LeaseUntil.Time,
+	}, nil
+}
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getPeriodicTasks).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.Documentation{
+				Summary:     "get the periodic tasks",
+				Description: "Responds with the periodic tasks registered by the server, their schedule, last and next run and last error. The last runs are the ones of all replicas. Only the users set with the periodics-admins option can see them.",
+			},
+		),
+	}.H().With(
+		db.RequestTransaction{db.Db},
+	).Register("/periodic/tasks")
+}
+
+// isPeriodicsAdmin tells whether a user is allowed to see the periodic tasks.
+func isPeriodicsAdmin(user users.User) bool {
+	for _, id := range strings.Split(config.PeriodicsAdmins, ",") {
+		if adminId, err := strconv.Atoi(strings.TrimSpace(id)); err == nil && adminId == user.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// getPeriodicTasks responds with the status of the periodic tasks.
+func getPeriodicTasks(r *http.Request, a routes.Arguments) (int, interface{}) {
+	if !isPeriodicsAdmin(a[users.AuthenticatedUser].(users.User)) {
+		return http.StatusForbidden, errors.New("This action is unavailable to this user.")
+	}
+	tasks, err := sched.Tasks()
+	if err != nil {
+		jsonlog.LoggerFromContextOrDefault(r.Context()).Error("Failed to get periodic tasks.", err.Error())
+		return http.StatusInternalServerError, errors.New("Failed to retrieve periodic tasks.")
+	}
+	return http.StatusOK, tasks
+}
@@ -0,0 +1,45 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateTaskError(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		message string
+		result  string
+	}{
+		{"Empty", "", ""},
+		{"Short", "error", "error"},
+		{"Exact length", strings.Repeat("a", maxTaskErrorLength), strings.Repeat("a", maxTaskErrorLength)},
+		{"Too long", strings.Repeat("a", maxTaskErrorLength+1), strings.Repeat("a", maxTaskErrorLength)},
+		{"Multi-byte characters", strings.Repeat("é", maxTaskErrorLength), strings.Repeat("é", maxTaskErrorLength)},
+		{"Multi-byte character at the limit", strings.Repeat("a", maxTaskErrorLength-1) + "日本", strings.Repeat("a", maxTaskErrorLength-1) + "日"},
+		{"Too many multi-byte characters", strings.Repeat("€", maxTaskErrorLength+10), strings.Repeat("€", maxTaskErrorLength)},
+	} {
+		result := truncateTaskError(c.message)
+		if result != c.result {
+			t.Errorf("%s: Message should be truncated to %d characters, is %d characters long instead.", c.name, utf8.RuneCountInString(c.result), utf8.RuneCountInString(result))
+		}
+		if !utf8.ValidString(result) {
+			t.Errorf("%s: Truncated message should be valid UTF-8.", c.name)
+		}
+	}
+}
@@ -25,12 +25,14 @@ import (
 	uuid "github.com/satori/go.uuid"
 	"github.com/trackit/jsonlog"
 
+	_ "github.com/trackit/trackit/audit/routes"
 	_ "github.com/trackit/trackit/aws"
 	_ "github.com/trackit/trackit/aws/routes"
 	_ "github.com/trackit/trackit/aws/s3"
 	"github.com/trackit/trackit/config"
 	_ "github.com/trackit/trackit/costs"
 	_ "github.com/trackit/trackit/costs/anomalies"
+	_ "github.com/trackit/trackit/costs/budgets"
 	_ "github.com/trackit/trackit/costs/diff"
 	_ "github.com/trackit/trackit/costs/tags"
 	"github.com/trackit/trackit/db"
@@ -40,6 +42,8 @@ import (
 	_ "github.com/trackit/trackit/reports"
 	"github.com/trackit/trackit/routes"
 	_ "github.com/trackit/trackit/s3/costs"
+	_ "github.com/trackit/trackit/tagging/policies"
+	_ "github.com/trackit/trackit/tagging/remediation"
 	_ "github.com/trackit/trackit/tagging/routes"
 	_ "github.com/trackit/trackit/usageReports/ec2"
 	_ "github.com/trackit/trackit/usageReports/ec2Coverage"
@@ -49,8 +53,10 @@ import (
 	_ "github.com/trackit/trackit/usageReports/rds"
 	_ "github.com/trackit/trackit/usageReports/riEc2"
 	_ "github.com/trackit/trackit/usageReports/riRds"
+	_ "github.com/trackit/trackit/usageReports/savingsPlans"
 	_ "github.com/trackit/trackit/users"
 	_ "github.com/trackit/trackit/users/shared_account"
+	_ "github.com/trackit/trackit/users/sso"
 )
 
 var buildNumber string = "unknown-build"
@@ -73,6 +79,7 @@ var tasks = map[string]func(context.Context) error{
 	"generate-master-spreadsheet": taskMasterSpreadsheet,
 	"update-aws-identity":         taskUpdateAwsIdentity,
 	"check-cost":                  taskCheckCost,
+	"check-budgets":               taskCheckBudgets,
 	"fetch-pricings":              taskFetchPricings,
 	"ingest-limit":                taskIngestLimit,
 	"update-tags":                 taskUpdateTags,
@@ -118,18 +125,27 @@ func main() {
 	}
 }
 
-var sched periodic.Scheduler
+var sched = periodic.Scheduler{
+	Store:  sqlTaskStore{},
+	Holder: backendId,
+}
 
-func schedulePeriodicTasks() {
-	sched.Register(taskIngestDue, 10*time.Minute, "ingest-due-updates")
+func schedulePeriodicTasks() error {
+	if err := sched.RegisterCron(taskIngestDue, "*/10 * * * *", time.UTC, "ingest-due-updates"); err != nil {
+		return err
+	}
 	sched.Start()
+	return nil
 }
 
 func taskServer(ctx context.Context) error {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	initializeHandlers()
 	if config.Periodics {
-		schedulePeriodicTasks()
+		if err := schedulePeriodicTasks(); err != nil {
+			logger.Error("Failed to schedule periodic tasks.", err.Error())
+			return err
+		}
 		logger.Info("Scheduled periodic tasks.", nil)
 	}
 	logger.Info(fmt.Sprintf("Listening on %s.", config.HttpAddress), nil)
@@ -67,7 +67,7 @@ func processAnomaliesForAccount(ctx context.Context, aaId int) (err error) {
 				"requiredAccount": "trackit",
 			})
 		}
-	} else if lastUpdate, err = anomalies.RunAnomaliesDetection(aa, dbaa.LastAnomaliesUpdate, ctx); err == nil {
+	} else if lastUpdate, err = anomalies.RunAnomaliesDetection(aa, dbaa.LastAnomaliesUpdate, tx, ctx); err == nil {
 		err = registerAnomaliesUpdate(tx, lastUpdate, aa.Id)
 	}
 	if err != nil && !elastic.IsNotFound(err) {
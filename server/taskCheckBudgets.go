@@ -0,0 +1,78 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/costs/budgets"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+)
+
+// taskCheckBudgets checks the spend of every budget, or of the budget whose
+// ID is passed as argument, and alerts their users
+func taskCheckBudgets(ctx context.Context) (err error) {
+	args := paramsFromContextOrArgs(ctx)
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	logger.Debug("Running task 'check-budgets'.", map[string]interface{}{
+		"args": args,
+	})
+	var dbBudgets []*models.Budget
+	if len(args) == 0 {
+		dbBudgets, err = models.AllBudgets(db.Db)
+	} else if len(args) != 1 {
+		err = errors.New("taskCheckBudgets takes at most one integer argument")
+	} else if budgetId, convErr := strconv.Atoi(args[0]); convErr != nil {
+		err = convErr
+	} else if dbBudget, dbErr := models.BudgetByID(db.Db, budgetId); dbErr != nil {
+		err = dbErr
+	} else {
+		dbBudgets = []*models.Budget{dbBudget}
+	}
+	if err != nil {
+		return
+	}
+	date := time.Now().UTC()
+	// Each budget is checked in its own transaction so that a failure does
+	// not cancel the alerts already sent for the other budgets
+	for _, dbBudget := range dbBudgets {
+		if checkErr := checkBudget(ctx, *dbBudget, date); checkErr != nil {
+			logger.Error("Failed to check budget.", map[string]interface{}{
+				"budgetId": dbBudget.ID,
+				"error":    checkErr.Error(),
+			})
+			err = checkErr
+		}
+	}
+	return
+}
+
+// checkBudget checks the spend of a budget and alerts its user
+func checkBudget(ctx context.Context, dbBudget models.Budget, date time.Time) (err error) {
+	var tx *sql.Tx
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	defer utilsUsualTxFinalize(&tx, &err, &logger, "check-budgets")
+	if tx, err = db.Db.BeginTx(ctx, nil); err != nil {
+		return
+	}
+	return budgets.CheckBudget(ctx, tx, dbBudget, date)
+}
@@ -26,18 +26,38 @@ import (
 	"github.com/trackit/trackit/models"
 )
 
-// taskFetchPricings fetches the EC2 pricings and saves them in the database
+// taskFetchPricings fetches the pricings of each service and saves them in the database
+// A failure for a service does not prevent the pricings of the other services from being saved
 func taskFetchPricings(ctx context.Context) (err error) {
+	for _, serviceCode := range pricings.PricingServiceCodes {
+		if fetchErr := fetchPricing(ctx, serviceCode); fetchErr != nil && err == nil {
+			err = fetchErr
+		}
+	}
+	return
+}
+
+// fetchPricing fetches the pricings of a service and saves every product derived
+// from them in the database, in a single transaction
+func fetchPricing(ctx context.Context, serviceCode string) (err error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
-	res, err := pricings.FetchEc2Pricings(ctx)
+	res, err := pricings.FetchServicePricings(ctx, serviceCode)
 	if err != nil {
-		logger.Error("Failed to retrieve ec2 pricings", err.Error())
+		logger.Error("Failed to retrieve pricings", map[string]interface{}{
+			"service": serviceCode,
+			"error":   err.Error(),
+		})
 		return
 	}
-	serializedPricing, err := json.Marshal(res)
-	if err != nil {
-		logger.Error("Failed to serialize ec2 pricings", err.Error())
-		return
+	serializedPricings := make([][]byte, len(res))
+	for i, pricing := range res {
+		if serializedPricings[i], err = json.Marshal(pricing.Pricing); err != nil {
+			logger.Error("Failed to serialize pricings", map[string]interface{}{
+				"product": pricing.Product,
+				"error":   err.Error(),
+			})
+			return
+		}
 	}
 	var tx *sql.Tx
 	defer utilsUsualTxFinalize(&tx, &err, &logger, "fetch-pricings")
@@ -45,17 +65,20 @@ func taskFetchPricings(ctx context.Context) (err error) {
 	if tx, err = db.Db.BeginTx(ctx, nil); err != nil {
 		logger.Error("Failed to initiate sql transaction", err.Error())
 		return
-	} else {
-		ec2PricingDb, _ := models.AwsPricingByProduct(tx, pricings.EC2ServiceCode)
-		if ec2PricingDb == nil {
-			ec2PricingDb = &models.AwsPricing{
-				Product: pricings.EC2ServiceCode,
+	}
+	for i, pricing := range res {
+		pricingDb, _ := models.AwsPricingByProduct(tx, pricing.Product)
+		if pricingDb == nil {
+			pricingDb = &models.AwsPricing{
+				Product: pricing.Product,
 			}
 		}
-		ec2PricingDb.Pricing = serializedPricing
-		err = ec2PricingDb.Save(tx)
-		if err != nil {
-			logger.Error("Failed to save ec2 pricings", err.Error())
+		pricingDb.Pricing = serializedPricings[i]
+		if err = pricingDb.Save(tx); err != nil {
+			logger.Error("Failed to save pricings", map[string]interface{}{
+				"product": pricing.Product,
+				"error":   err.Error(),
+			})
 			return
 		}
 	}
@@ -36,20 +36,20 @@ func taskMasterSpreadsheet(ctx context.Context) error {
 		"args": args,
 	})
 
-	aaId, date, err := checkArguments(args)
+	aaId, date, metric, format, err := checkArgumentsWithOptions(args)
 	if err != nil {
 		return err
 	} else {
-		return generateMasterReport(ctx, aaId, date)
+		return generateMasterReport(ctx, aaId, date, metric, format)
 	}
 }
 
-func generateMasterReport(ctx context.Context, aaId int, date time.Time) (err error) {
+func generateMasterReport(ctx context.Context, aaId int, date time.Time, metric string, format reports.Format) (err error) {
 	var tx *sql.Tx
 	var aa aws.AwsAccount
 	var updateId int64
 	var generation bool
-	forceGeneration := !date.IsZero()
+	forceGeneration := !date.IsZero() || metric != ""
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	defer utilsUsualTxFinalize(&tx, &err, &logger, "generate-master-spreadsheet")
 
@@ -73,7 +73,7 @@ func generateMasterReport(ctx context.Context, aaId int, date time.Time) (err er
 			account := aws.AwsAccountFromDbAwsAccount(*dbAccount)
 			accounts = append(accounts, account)
 		}
-		errs := reports.GenerateReport(ctx, aa, accounts, date)
+		errs := generateReports(ctx, aa, accounts, date, metric, format)
 		updateMasterAccountReportGenerationCompletion(ctx, aaId, db.Db, updateId, nil, errs, forceGeneration)
 	}
 	if err != nil {
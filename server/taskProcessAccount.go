@@ -36,11 +36,15 @@ import (
 	"github.com/trackit/trackit/aws/usageReports/riRdS"
 	"github.com/trackit/trackit/aws/usageReports/route53"
 	"github.com/trackit/trackit/aws/usageReports/s3"
+	"github.com/trackit/trackit/aws/usageReports/savingsPlans"
 	"github.com/trackit/trackit/aws/usageReports/sqs"
 	"github.com/trackit/trackit/aws/usageReports/stepfunction"
 	"github.com/trackit/trackit/cache"
 	"github.com/trackit/trackit/db"
 	onDemandToRiEc2 "github.com/trackit/trackit/onDemandToRI/ec2"
+	onDemandToRiElastiCache "github.com/trackit/trackit/onDemandToRI/elasticache"
+	onDemandToRiEs "github.com/trackit/trackit/onDemandToRI/es"
+	onDemandToRiRds "github.com/trackit/trackit/onDemandToRI/rds"
 )
 
 const invalidAccId = -1
@@ -94,9 +98,21 @@ var accountProcessors = []accountProcessor{
 	}, {
 		ErrName: "rds-ri",
 		Run:     riRdS.FetchDailyInstancesStats,
+	}, {
+		ErrName: "savings-plans",
+		Run:     savingsPlans.FetchDailySavingsPlansStats,
 	}, {
 		ErrName: "od-ec2-ri",
 		Run:     onDemandToRiEc2.RunOnDemandToRiEc2,
+	}, {
+		ErrName: "od-rds-ri",
+		Run:     onDemandToRiRds.RunOnDemandToRiRds,
+	}, {
+		ErrName: "od-elasticache-ri",
+		Run:     onDemandToRiElastiCache.RunOnDemandToRiElastiCache,
+	}, {
+		ErrName: "od-es-ri",
+		Run:     onDemandToRiEs.RunOnDemandToRiEs,
 	}, {
 		ErrName: "ebs",
 		Run:     processAccountEbsSnapshot,
@@ -182,6 +198,10 @@ func ingestDataForAccount(ctx context.Context, aaId int, date time.Time) (err er
 		"/rds/unused",
 		"/ri/ec2",
 		"/ri/rds",
+		"/ri/rds/recommendations",
+		"/ri/elasticache/recommendations",
+		"/ri/es/recommendations",
+		"/savingsplans",
 	}
 	err = cache.RemoveMatchingCache(affectedRoutes, []string{aa.AwsIdentity}, logger)
 	return
@@ -249,6 +269,10 @@ func registerAccountProcessingCompletion(db *sql.DB, updateId int64, jobErr erro
 		sqsError=?,
 		cloudFormationError=?,
 		route53Error=?,
+		savingsPlansError=?,
+		odToRiRdsError=?,
+		odToRiElastiCacheError=?,
+		odToRiEsError=?,
 		historyError=?,
 		monthly_reports_generated=?
 	WHERE id=?`
@@ -270,6 +294,10 @@ func registerAccountProcessingCompletion(db *sql.DB, updateId int64, jobErr erro
 		errToStr(errors["sqs"]),
 		errToStr(errors["cloudformation"]),
 		errToStr(errors["route53"]),
+		errToStr(errors["savings-plans"]),
+		errToStr(errors["od-rds-ri"]),
+		errToStr(errors["od-elasticache-ri"]),
+		errToStr(errors["od-es-ri"]),
 		errToStr(historyErr),
 		historyCreated,
 		updateId)
@@ -57,6 +57,7 @@ func preparePluginsProcessingForAccount(ctx context.Context, aaId int) (err erro
 	var aa aws.AwsAccount
 	var user users.User
 	var updateId int64
+	var suppressions []core.Suppression
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	defer utilsUsualTxFinalize(&tx, &err, &logger, "process-account-plugins")
 
@@ -71,9 +72,10 @@ func preparePluginsProcessingForAccount(ctx context.Context, aaId int) (err erro
 			})
 		}
 	} else if user, err = users.GetUserWithId(tx, aa.UserId); err != nil {
+	} else if suppressions, err = core.GetActiveSuppressions(tx, aa.Id, time.Now().UTC()); err != nil {
 	} else if updateId, err = registerAccountPluginsProcessing(db.Db, aa); err != nil {
 	} else {
-		runPluginsForAccount(ctx, user, aa)
+		runPluginsForAccount(ctx, user, aa, suppressions)
 		updateAccountPluginsCompletion(ctx, aaId, db.Db, updateId, nil)
 	}
 	if err != nil {
@@ -85,13 +87,15 @@ func preparePluginsProcessingForAccount(ctx context.Context, aaId int) (err erro
 	}
 	var affectedRoutes = []string{
 		"/plugins/results",
+		"/plugins/findings",
 	}
 	err = cache.RemoveMatchingCache(affectedRoutes, []string{aa.AwsIdentity}, logger)
 	return
 }
 
 // runPluginsForAccount runs all the registered plugins for an account
-func runPluginsForAccount(ctx context.Context, user users.User, aa aws.AwsAccount) {
+// The findings matching one of the suppressions count as passed checks
+func runPluginsForAccount(ctx context.Context, user users.User, aa aws.AwsAccount, suppressions []core.Suppression) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	for _, plugin := range core.RegisteredAccountPlugins {
 		if !plugin.BillingDataOnly && strings.TrimSpace(aa.RoleArn) == "" {
@@ -123,12 +127,16 @@ func runPluginsForAccount(ctx context.Context, user users.User, aa aws.AwsAccoun
 		}
 		if pluginResultES.Error == "" {
 			res := plugin.Func(params)
+			res.ApplySuppressions(plugin, suppressions)
 			pluginResultES.Result = res.Result
 			pluginResultES.Status = res.Status
 			pluginResultES.Details = res.Details
 			pluginResultES.Error = res.Error
 			pluginResultES.Checked = res.Checked
 			pluginResultES.Passed = res.Passed
+			pluginResultES.Findings = res.Findings
+			pluginResultES.EstimatedMonthlySavings = res.EstimatedMonthlySavings()
+			pluginResultES.SuppressedFindings = res.SuppressedFindings
 		}
 		core.IngestPluginResult(ctx, aa, pluginResultES)
 	}
@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/trackit/jsonlog"
 
 	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/aws/s3"
 	"github.com/trackit/trackit/aws/usageReports/history"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/models"
@@ -38,15 +40,39 @@ func taskSpreadsheet(ctx context.Context) error {
 		"args": args,
 	})
 
-	aaId, date, err := checkArguments(args)
+	aaId, date, metric, format, err := checkArgumentsWithOptions(args)
 	if err != nil {
 		logger.Error("Failed to parse arguments", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return err
 	} else {
-		return generateReport(ctx, aaId, date)
+		return generateReport(ctx, aaId, date, metric, format)
+	}
+}
+
+// checkArgumentsWithOptions parses the arguments of checkArguments optionally
+// followed by a cost metric and a report format, in any order. The format is
+// empty if it is not given.
+func checkArgumentsWithOptions(args []string) (int, time.Time, string, reports.Format, error) {
+	var metric string
+	var format reports.Format
+	for i := 0; i < 2 && len(args) > 1; i++ {
+		option := args[len(args)-1]
+		if _, err := strconv.Atoi(option); err == nil {
+			break
+		}
+		args = args[:len(args)-1]
+		if parsedFormat, err := reports.ParseFormat(option); err == nil {
+			format = parsedFormat
+		} else if _, err := s3.CostMetricField(option); err == nil {
+			metric = option
+		} else {
+			return -1, time.Time{}, "", "", fmt.Errorf("%s is neither a cost metric nor a report format", option)
+		}
 	}
+	aaId, date, err := checkArguments(args)
+	return aaId, date, metric, format, err
 }
 
 func checkArguments(args []string) (int, time.Time, error) {
@@ -73,12 +99,12 @@ func checkArguments(args []string) (int, time.Time, error) {
 	return aaId, date, nil
 }
 
-func generateReport(ctx context.Context, aaId int, date time.Time) (err error) {
+func generateReport(ctx context.Context, aaId int, date time.Time, metric string, format reports.Format) (err error) {
 	var tx *sql.Tx
 	var aa aws.AwsAccount
 	var updateId int64
 	var generation bool
-	forceGeneration := !date.IsZero()
+	forceGeneration := !date.IsZero() || metric != ""
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	defer utilsUsualTxFinalize(&tx, &err, &logger, "generate-spreadsheet")
 
@@ -95,7 +121,7 @@ func generateReport(ctx context.Context, aaId int, date time.Time) (err error) {
 	} else if generation, err = checkReportGeneration(ctx, db.Db, aa, forceGeneration); err != nil || !generation {
 	} else if updateId, err = registerAccountReportGeneration(db.Db, aa); err != nil {
 	} else {
-		errs := reports.GenerateReport(ctx, aa, nil, date)
+		errs := generateReports(ctx, aa, nil, date, metric, format)
 		updateAccountReportGenerationCompletion(ctx, aaId, db.Db, updateId, nil, errs, forceGeneration)
 	}
 	if err != nil {
@@ -108,6 +134,27 @@ func generateReport(ctx context.Context, aaId int, date time.Time) (err error) {
 	return
 }
 
+// generateReports generates the report definitions of an AWS account, or the
+// default report if it has none. The master definitions are generated if
+// the sub accounts aas are given. The errors of the reports are merged.
+func generateReports(ctx context.Context, aa aws.AwsAccount, aas []aws.AwsAccount, date time.Time, metric string, format reports.Format) map[string]error {
+	definitions, err := reports.GetDefinitions(db.Db, aa.Id, aas != nil)
+	if err != nil {
+		return map[string]error{"speadsheetError": err}
+	} else if len(definitions) == 0 {
+		return reports.GenerateReport(ctx, aa, aas, date, metric, format)
+	}
+	errs := make(map[string]error)
+	for _, definition := range definitions {
+		for key, err := range reports.GenerateDefinitionReport(ctx, aa, aas, date, metric, format, definition) {
+			if errs[key] == nil {
+				errs[key] = err
+			}
+		}
+	}
+	return errs
+}
+
 func checkReportGeneration(ctx context.Context, db *sql.DB, aa aws.AwsAccount, forceGeneration bool) (bool, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	if forceGeneration {
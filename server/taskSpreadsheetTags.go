@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/trackit/jsonlog"
@@ -35,15 +36,17 @@ func taskTagsSpreadsheet(ctx context.Context) error {
 	logger.Debug("Running task 'Spreadsheet Tags'.", map[string]interface{}{
 		"args": args,
 	})
-	aaId, date, err := checkArguments(args)
+	aaId, date, metric, format, err := checkArgumentsWithOptions(args)
 	if err != nil {
 		return err
+	} else if metric != "" {
+		return errors.New("taskTagsSpreadsheet does not take a cost metric")
 	} else {
-		return generateTagsReport(ctx, aaId, date)
+		return generateTagsReport(ctx, aaId, date, format)
 	}
 }
 
-func generateTagsReport(ctx context.Context, aaId int, date time.Time) (err error) {
+func generateTagsReport(ctx context.Context, aaId int, date time.Time, format reports.Format) (err error) {
 	var tx *sql.Tx
 	var aa aws.AwsAccount
 	var updateId int64
@@ -65,7 +68,7 @@ func generateTagsReport(ctx context.Context, aaId int, date time.Time) (err erro
 	} else if generation, err = checkTagsReportGeneration(ctx, db.Db, aa, forceGeneration); err != nil || !generation {
 	} else if updateId, err = registerAccountTagsReportGeneration(db.Db, aa); err != nil {
 	} else {
-		errs := reports.GenerateTagsReport(ctx, aa, nil, date)
+		errs := reports.GenerateTagsReport(ctx, aa, nil, date, format)
 		updateAccountTagsReportGenerationCompletion(ctx, aaId, db.Db, updateId, nil, errs, forceGeneration)
 	}
 	if err != nil {
@@ -0,0 +1,125 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package aliases maps the tag keys used for the same concept across accounts,
+// and optionally their values, to a canonical key and value.
+package aliases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trackit/trackit/models"
+)
+
+// Alias groups the keys which are aliases of a canonical key
+// ValueMapping maps the values used with these keys to a canonical value
+type Alias struct {
+	Id           int               `json:"id"`
+	CanonicalKey string            `json:"canonicalKey" req:"nonzero"`
+	Aliases      []string          `json:"aliases"      req:"nonzero"`
+	ValueMapping map[string]string `json:"valueMapping"`
+}
+
+// validate checks that an alias does not conflict with the other aliases of the user
+func (a Alias) validate(others []Alias) error {
+	if a.CanonicalKey == "" {
+		return errors.New("canonicalKey is required")
+	} else if len(a.Aliases) == 0 {
+		return errors.New("at least one alias is required")
+	}
+	keys := map[string]bool{a.CanonicalKey: true}
+	for _, alias := range a.Aliases {
+		if alias == "" {
+			return errors.New("aliases can not be empty")
+		} else if keys[alias] {
+			return fmt.Errorf("key %s is set several times", alias)
+		}
+		keys[alias] = true
+	}
+	for _, other := range others {
+		if other.Id == a.Id {
+			continue
+		}
+		for _, key := range append([]string{other.CanonicalKey}, other.Aliases...) {
+			if keys[key] {
+				return fmt.Errorf("key %s is already used by the aliases of %s", key, other.CanonicalKey)
+			}
+		}
+	}
+	return nil
+}
+
+// aliasFromDbAlias builds an Alias from its database row
+func aliasFromDbAlias(dbAlias models.TagKeyAlias) (Alias, error) {
+	alias := Alias{
+		Id:           dbAlias.ID,
+		CanonicalKey: dbAlias.CanonicalKey,
+	}
+	if err := json.Unmarshal(dbAlias.Aliases, &alias.Aliases); err != nil {
+		return alias, err
+	}
+	err := json.Unmarshal(dbAlias.ValueMapping, &alias.ValueMapping)
+	return alias, err
+}
+
+// setDbAlias sets the fields of a database row from an Alias
+func setDbAlias(dbAlias *models.TagKeyAlias, alias Alias) (err error) {
+	if alias.ValueMapping == nil {
+		alias.ValueMapping = map[string]string{}
+	}
+	dbAlias.CanonicalKey = alias.CanonicalKey
+	if dbAlias.Aliases, err = json.Marshal(alias.Aliases); err == nil {
+		dbAlias.ValueMapping, err = json.Marshal(alias.ValueMapping)
+	}
+	return
+}
+
+// GetAliasesForUser returns the tag key aliases of a user
+func GetAliasesForUser(db models.DB, userId int) ([]Alias, error) {
+	dbAliases, err := models.TagKeyAliasByUserID(db, userId)
+	if err != nil {
+		return nil, err
+	}
+	aliases := make([]Alias, 0, len(dbAliases))
+	for _, dbAlias := range dbAliases {
+		alias, err := aliasFromDbAlias(*dbAlias)
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, nil
+}
+
+// GetResolverForUser returns the Resolver of the tag key aliases of a user
+func GetResolverForUser(db models.DB, userId int) (Resolver, error) {
+	aliases, err := GetAliasesForUser(db, userId)
+	if err != nil {
+		return Resolver{}, err
+	}
+	return NewResolver(aliases), nil
+}
+
+// getDbAliasForUser returns the database row of an alias if it belongs to the user
+func getDbAliasForUser(db models.DB, userId, aliasId int) (*models.TagKeyAlias, error) {
+	dbAlias, err := models.TagKeyAliasByID(db, aliasId)
+	if err != nil {
+		return nil, err
+	} else if dbAlias.UserID != userId {
+		return nil, errors.New("alias does not belong to the user")
+	}
+	return dbAlias, nil
+}
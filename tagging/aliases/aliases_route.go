@@ -0,0 +1,215 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package aliases
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/cache"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+// aliasIdQueryArg allows to get the ID of a tag key alias in the URL parameters.
+var aliasIdQueryArg = routes.QueryArg{
+	Name:        "alias",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of a tag key alias.",
+}
+
+// aliasedRoutes are the cached routes whose responses depend on the aliases.
+var aliasedRoutes = []string{"/costs", "/costs/tags/keys", "/costs/tags/values"}
+
+// aliasExample is the example body of the tag key aliases routes.
+var aliasExample = Alias{
+	CanonicalKey: "team",
+	Aliases:      []string{"Team", "owner-team"},
+	ValueMapping: map[string]string{"Eng": "engineering"},
+}
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getAliases).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.Documentation{
+				Summary:     "get the tag key aliases",
+				Description: "Responds with the tag key aliases of the user.",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+		http.MethodPost: routes.H(postAlias).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{aliasExample},
+			routes.Documentation{
+				Summary:     "create a tag key alias",
+				Description: "Creates a canonical tag key with its aliases and an optional mapping of their values.",
+			},
+			users.RequirePermission{users.PermissionManageTagging},
+		),
+		http.MethodPatch: routes.H(patchAlias).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.QueryArgs{aliasIdQueryArg},
+			routes.RequestBody{aliasExample},
+			routes.Documentation{
+				Summary:     "edit a tag key alias",
+				Description: "Replaces the canonical key, the aliases and the value mapping of a tag key alias.",
+			},
+			users.RequirePermission{users.PermissionManageTagging},
+		),
+		http.MethodDelete: routes.H(deleteAlias).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.QueryArgs{aliasIdQueryArg},
+			routes.Documentation{
+				Summary:     "delete a tag key alias",
+				Description: "Deletes a tag key alias.",
+			},
+			users.RequirePermission{users.PermissionManageTagging},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.Documentation{
+			Summary:     "interact with the tag key aliases",
+			Description: "Tag key aliases are resolved to their canonical key when listing tags, aggregating costs by tag and computing the tagging compliance. Saving or deleting an alias removes the cached responses of /costs, /costs/tags/keys and /costs/tags/values for the AWS accounts of the user, so that they are not stale until the cache expires.",
+		},
+	).Register("/tagging/aliases")
+}
+
+// getAliases is a route handler which returns the tag key aliases of the user.
+func getAliases(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	aliases, err := GetAliasesForUser(tx, user.Id)
+	if err != nil {
+		l.Error("Failed to get tag key aliases.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve tag key aliases.")
+	}
+	return http.StatusOK, aliases
+}
+
+// postAlias is a route handler which creates a tag key alias.
+func postAlias(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Alias
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	body.Id = 0
+	dbAlias := models.TagKeyAlias{UserID: user.Id}
+	return saveAlias(r, tx, user, &dbAlias, body)
+}
+
+// patchAlias is a route handler which replaces a tag key alias.
+func patchAlias(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Alias
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbAlias, err := getDbAliasForUser(tx, user.Id, a[aliasIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Tag key alias not found.")
+	}
+	body.Id = dbAlias.ID
+	return saveAlias(r, tx, user, dbAlias, body)
+}
+
+// saveAlias validates a tag key alias against the other aliases of the user
+// and saves it in the database.
+func saveAlias(r *http.Request, tx *sql.Tx, user users.User, dbAlias *models.TagKeyAlias, alias Alias) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	others, err := GetAliasesForUser(tx, user.Id)
+	if err != nil {
+		l.Error("Failed to get tag key aliases.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save tag key alias.")
+	}
+	if err := alias.validate(others); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	err = setDbAlias(dbAlias, alias)
+	if err == nil {
+		err = dbAlias.Save(tx)
+	}
+	if err == nil {
+		alias, err = aliasFromDbAlias(*dbAlias)
+	}
+	if err != nil {
+		l.Error("Failed to save tag key alias.", map[string]interface{}{
+			"alias": alias,
+			"error": err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save tag key alias.")
+	}
+	removeAliasedCache(r, tx, user)
+	return http.StatusOK, alias
+}
+
+// deleteAlias is a route handler which deletes a tag key alias.
+func deleteAlias(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbAlias, err := getDbAliasForUser(tx, user.Id, a[aliasIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Tag key alias not found.")
+	}
+	if err := dbAlias.Delete(tx); err != nil {
+		l.Error("Failed to delete tag key alias.", map[string]interface{}{
+			"aliasId": dbAlias.ID,
+			"error":   err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to delete tag key alias.")
+	}
+	removeAliasedCache(r, tx, user)
+	return http.StatusOK, nil
+}
+
+// removeAliasedCache removes the cached responses of the routes which resolve
+// the aliases, for every AWS account of the user.
+func removeAliasedCache(r *http.Request, tx *sql.Tx, user users.User) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	awsAccounts, err := aws.GetAwsAccountsFromUser(user, tx)
+	if err != nil {
+		l.Error("Failed to get Aws Accounts", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return
+	}
+	identities := make([]string, 0, len(awsAccounts))
+	for _, aa := range awsAccounts {
+		identities = append(identities, aa.AwsIdentity)
+	}
+	if err := cache.RemoveMatchingCache(aliasedRoutes, identities, l); err != nil {
+		l.Error("Failed to remove cache", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+	}
+}
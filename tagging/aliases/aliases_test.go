@@ -0,0 +1,48 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package aliases
+
+import "testing"
+
+func TestAliasValidate(t *testing.T) {
+	others := []Alias{
+		{Id: 1, CanonicalKey: "team", Aliases: []string{"Team", "owner-team"}},
+		{Id: 2, CanonicalKey: "env", Aliases: []string{"Environment"}},
+	}
+	cases := []struct {
+		name  string
+		alias Alias
+		fails bool
+	}{
+		{"New", Alias{CanonicalKey: "project", Aliases: []string{"Project"}}, false},
+		{"Edited", Alias{Id: 1, CanonicalKey: "team", Aliases: []string{"Team", "squad"}}, false},
+		{"NoCanonicalKey", Alias{Aliases: []string{"Project"}}, true},
+		{"NoAliases", Alias{CanonicalKey: "project"}, true},
+		{"EmptyAlias", Alias{CanonicalKey: "project", Aliases: []string{""}}, true},
+		{"AliasOfItself", Alias{CanonicalKey: "project", Aliases: []string{"project"}}, true},
+		{"DuplicateAlias", Alias{CanonicalKey: "project", Aliases: []string{"Project", "Project"}}, true},
+		{"OtherCanonicalKey", Alias{CanonicalKey: "project", Aliases: []string{"team"}}, true},
+		{"OtherAlias", Alias{CanonicalKey: "project", Aliases: []string{"owner-team"}}, true},
+		{"AliasOfOtherCanonicalKey", Alias{CanonicalKey: "Environment", Aliases: []string{"stage"}}, true},
+		{"EditedIntoConflict", Alias{Id: 1, CanonicalKey: "team", Aliases: []string{"Environment"}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.alias.validate(others); (err != nil) != tc.fails {
+				t.Errorf("Validation returned %v, expected failure: %t", err, tc.fails)
+			}
+		})
+	}
+}
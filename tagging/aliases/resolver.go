@@ -0,0 +1,162 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package aliases
+
+import (
+	"github.com/olivere/elastic"
+
+	usageReports "github.com/trackit/trackit/aws/usageReports"
+)
+
+// Resolver resolves the tag keys and values to their canonical form
+// The zero value resolves every key and value to itself
+type Resolver struct {
+	canonicalKeys map[string]string
+	aliases       map[string][]string
+	values        map[string]map[string]string
+}
+
+// keyScript returns the canonical key of the key field of a document
+const keyScript = `def k = doc[params.keyField].value; return params.keys.containsKey(k) ? params.keys.get(k) : k;`
+
+// valueScript returns the canonical value of the value field of a document
+const valueScript = `def k = doc[params.keyField].value; if (params.keys.containsKey(k)) { k = params.keys.get(k); }
+def v = doc[params.valueField].value; def m = params.values.get(k);
+return m != null && m.containsKey(v) ? m.get(v) : v;`
+
+// NewResolver creates the Resolver of a list of aliases
+func NewResolver(aliases []Alias) Resolver {
+	r := Resolver{
+		canonicalKeys: make(map[string]string),
+		aliases:       make(map[string][]string),
+		values:        make(map[string]map[string]string),
+	}
+	for _, alias := range aliases {
+		for _, key := range alias.Aliases {
+			r.canonicalKeys[key] = alias.CanonicalKey
+		}
+		r.aliases[alias.CanonicalKey] = alias.Aliases
+		if len(alias.ValueMapping) > 0 {
+			r.values[alias.CanonicalKey] = alias.ValueMapping
+		}
+	}
+	return r
+}
+
+// IsEmpty returns true if the Resolver has no alias
+func (r Resolver) IsEmpty() bool {
+	return len(r.canonicalKeys) == 0
+}
+
+// Key returns the canonical key of a key
+func (r Resolver) Key(key string) string {
+	if canonicalKey, ok := r.canonicalKeys[key]; ok {
+		return canonicalKey
+	}
+	return key
+}
+
+// Keys returns the canonical key of a key followed by its aliases
+func (r Resolver) Keys(key string) []string {
+	canonicalKey := r.Key(key)
+	return append([]string{canonicalKey}, r.aliases[canonicalKey]...)
+}
+
+// Value returns the canonical value of the value of a key
+func (r Resolver) Value(key, value string) string {
+	if canonicalValue, ok := r.values[r.Key(key)][value]; ok {
+		return canonicalValue
+	}
+	return value
+}
+
+// Values returns the values of a key followed by the values which are mapped to them
+func (r Resolver) Values(key string, values []string) []string {
+	res := append([]string{}, values...)
+	wanted := make(map[string]bool, len(values))
+	for _, value := range values {
+		wanted[value] = true
+	}
+	for value, canonicalValue := range r.values[r.Key(key)] {
+		if wanted[canonicalValue] && !wanted[value] {
+			res = append(res, value)
+		}
+	}
+	return res
+}
+
+// NormalizeTags returns the tags with their canonical key and value
+// When several tags have the same canonical key, the first one is kept
+func (r Resolver) NormalizeTags(tags []usageReports.Tag) []usageReports.Tag {
+	if r.IsEmpty() {
+		return tags
+	}
+	res := make([]usageReports.Tag, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		key := r.Key(tag.Key)
+		if !seen[key] {
+			seen[key] = true
+			res = append(res, usageReports.Tag{Key: key, Value: r.Value(key, tag.Value)})
+		}
+	}
+	return res
+}
+
+// KeyScript returns the script resolving the canonical key of the key field of the
+// documents, to be used in place of the field in ElasticSearch aggregations
+// It returns nil if the Resolver has no alias
+func (r Resolver) KeyScript(keyField string) *elastic.Script {
+	if r.IsEmpty() {
+		return nil
+	}
+	return elastic.NewScriptInline(keyScript).Lang("painless").Params(map[string]interface{}{
+		"keyField": keyField,
+		"keys":     r.canonicalKeys,
+	})
+}
+
+// ValueScript returns the script resolving the canonical value of the value field of the
+// documents, to be used in place of the field in ElasticSearch aggregations
+// It returns nil if the Resolver has no value mapping
+func (r Resolver) ValueScript(keyField, valueField string) *elastic.Script {
+	if len(r.values) == 0 {
+		return nil
+	}
+	return elastic.NewScriptInline(valueScript).Lang("painless").Params(map[string]interface{}{
+		"keyField":   keyField,
+		"valueField": valueField,
+		"keys":       r.canonicalKeys,
+		"values":     r.values,
+	})
+}
+
+// KeysQuery returns the query matching the documents whose key field is the key or one of its aliases
+func (r Resolver) KeysQuery(keyField, key string) elastic.Query {
+	keys := r.Keys(key)
+	if len(keys) == 1 {
+		return elastic.NewTermQuery(keyField, keys[0])
+	}
+	return elastic.NewTermsQuery(keyField, stringsToInterfaces(keys)...)
+}
+
+// stringsToInterfaces converts a slice of strings to a slice of interfaces
+func stringsToInterfaces(values []string) []interface{} {
+	res := make([]interface{}, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+	return res
+}
@@ -0,0 +1,253 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package aliases
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	usageReports "github.com/trackit/trackit/aws/usageReports"
+)
+
+var testAliases = []Alias{
+	{CanonicalKey: "team", Aliases: []string{"Team", "owner-team"}, ValueMapping: map[string]string{"Eng": "engineering", "eng": "engineering", "Fin": "finance"}},
+	{CanonicalKey: "env", Aliases: []string{"Environment"}},
+}
+
+func TestNewResolver(t *testing.T) {
+	r := NewResolver(testAliases)
+	expected := Resolver{
+		canonicalKeys: map[string]string{"Team": "team", "owner-team": "team", "Environment": "env"},
+		aliases:       map[string][]string{"team": {"Team", "owner-team"}, "env": {"Environment"}},
+		values:        map[string]map[string]string{"team": {"Eng": "engineering", "eng": "engineering", "Fin": "finance"}},
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("Resolver is %+v, expected %+v", r, expected)
+	}
+	if r.IsEmpty() {
+		t.Errorf("Resolver with aliases is empty")
+	}
+	if !NewResolver(nil).IsEmpty() || !(Resolver{}).IsEmpty() {
+		t.Errorf("Resolver without aliases is not empty")
+	}
+}
+
+func TestResolverKey(t *testing.T) {
+	r := NewResolver(testAliases)
+	cases := []struct {
+		key      string
+		expected string
+		keys     []string
+	}{
+		{"team", "team", []string{"team", "Team", "owner-team"}},
+		{"Team", "team", []string{"team", "Team", "owner-team"}},
+		{"owner-team", "team", []string{"team", "Team", "owner-team"}},
+		{"Environment", "env", []string{"env", "Environment"}},
+		{"TEAM", "TEAM", []string{"TEAM"}},
+		{"project", "project", []string{"project"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			if key := r.Key(tc.key); key != tc.expected {
+				t.Errorf("Key is %s, expected %s", key, tc.expected)
+			}
+			if keys := r.Keys(tc.key); !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf("Keys are %v, expected %v", keys, tc.keys)
+			}
+		})
+	}
+	var zero Resolver
+	if key, keys := zero.Key("Team"), zero.Keys("Team"); key != "Team" || !reflect.DeepEqual(keys, []string{"Team"}) {
+		t.Errorf("Zero Resolver resolves Team to (%s, %v)", key, keys)
+	}
+}
+
+func TestResolverValues(t *testing.T) {
+	r := NewResolver(testAliases)
+	cases := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+		values   []string
+	}{
+		{"Mapped", "team", "Eng", "engineering", []string{"Eng"}},
+		{"MappedThroughAlias", "owner-team", "eng", "engineering", []string{"eng"}},
+		{"Canonical", "Team", "engineering", "engineering", []string{"Eng", "eng", "engineering"}},
+		{"CanonicalNotUsed", "team", "finance", "finance", []string{"Fin", "finance"}},
+		{"Unmapped", "team", "sales", "sales", []string{"sales"}},
+		{"NoMapping", "env", "Eng", "Eng", []string{"Eng"}},
+		{"UnknownKey", "project", "Eng", "Eng", []string{"Eng"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if value := r.Value(tc.key, tc.value); value != tc.expected {
+				t.Errorf("Value is %s, expected %s", value, tc.expected)
+			}
+			values := r.Values(tc.key, []string{tc.value})
+			if values[0] != tc.value {
+				t.Errorf("Values start with %s, expected %s", values[0], tc.value)
+			}
+			sort.Strings(values)
+			if !reflect.DeepEqual(values, tc.values) {
+				t.Errorf("Values are %v, expected %v", values, tc.values)
+			}
+		})
+	}
+	if values := r.Values("team", []string{"engineering", "Eng"}); len(values) != 3 {
+		t.Errorf("Values %v hold a value several times", values)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags := []usageReports.Tag{
+		{Key: "Team", Value: "Eng"},
+		{Key: "owner-team", Value: "Fin"},
+		{Key: "Environment", Value: "prod"},
+		{Key: "project", Value: "Eng"},
+	}
+	cases := []struct {
+		name     string
+		resolver Resolver
+		tags     []usageReports.Tag
+		expected []usageReports.Tag
+	}{
+		{"Empty", Resolver{}, tags, tags},
+		{"Aliases", NewResolver(testAliases), tags, []usageReports.Tag{
+			{Key: "team", Value: "engineering"},
+			{Key: "env", Value: "prod"},
+			{Key: "project", Value: "Eng"},
+		}},
+		{"CanonicalFirst", NewResolver(testAliases), []usageReports.Tag{
+			{Key: "team", Value: "finance"},
+			{Key: "Team", Value: "Eng"},
+		}, []usageReports.Tag{{Key: "team", Value: "finance"}}},
+		{"NoTags", NewResolver(testAliases), nil, []usageReports.Tag{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if normalized := tc.resolver.NormalizeTags(tc.tags); !reflect.DeepEqual(normalized, tc.expected) {
+				t.Errorf("Tags are %v, expected %v", normalized, tc.expected)
+			}
+		})
+	}
+}
+
+// scriptParams returns the source and the parameters of a script, as sent to ElasticSearch
+func scriptParams(t *testing.T, source interface{}) (string, map[string]interface{}) {
+	var script struct {
+		Source string                 `json:"source"`
+		Lang   string                 `json:"lang"`
+		Params map[string]interface{} `json:"params"`
+	}
+	marshaled, err := json.Marshal(source)
+	if err == nil {
+		err = json.Unmarshal(marshaled, &script)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read script: %s", err.Error())
+	} else if script.Lang != "painless" {
+		t.Errorf("Script language is %s, expected painless", script.Lang)
+	}
+	return script.Source, script.Params
+}
+
+// runKeyScript and runValueScript do what keyScript and valueScript do with
+// their parameters, so that the parameters can be checked against the Resolver
+func runKeyScript(params map[string]interface{}, key string) string {
+	if canonicalKey, ok := params["keys"].(map[string]interface{})[key]; ok {
+		return canonicalKey.(string)
+	}
+	return key
+}
+
+func runValueScript(params map[string]interface{}, key, value string) string {
+	key = runKeyScript(params, key)
+	if mapping, ok := params["values"].(map[string]interface{})[key].(map[string]interface{}); ok {
+		if canonicalValue, ok := mapping[value]; ok {
+			return canonicalValue.(string)
+		}
+	}
+	return value
+}
+
+func TestScripts(t *testing.T) {
+	r := NewResolver(testAliases)
+	if NewResolver(nil).KeyScript("tags.key") != nil || NewResolver(nil).ValueScript("tags.key", "tags.value") != nil {
+		t.Errorf("Resolver without aliases returns scripts")
+	}
+	if NewResolver(testAliases[1:]).ValueScript("tags.key", "tags.value") != nil {
+		t.Errorf("Resolver without value mapping returns a value script")
+	}
+	keySource, err := r.KeyScript("tags.key").Source()
+	if err != nil {
+		t.Fatalf("Failed to build key script: %s", err.Error())
+	}
+	valueSource, err := r.ValueScript("tags.key", "tags.value").Source()
+	if err != nil {
+		t.Fatalf("Failed to build value script: %s", err.Error())
+	}
+	keyScriptSource, keyParams := scriptParams(t, keySource)
+	valueScriptSource, valueParams := scriptParams(t, valueSource)
+	if keyScriptSource != keyScript || valueScriptSource != valueScript {
+		t.Errorf("Scripts are not the key and value scripts")
+	}
+	if keyParams["keyField"] != "tags.key" || valueParams["keyField"] != "tags.key" || valueParams["valueField"] != "tags.value" {
+		t.Errorf("Script fields are %v and %v", keyParams, valueParams)
+	}
+	tags := []usageReports.Tag{
+		{Key: "team", Value: "engineering"},
+		{Key: "Team", Value: "Eng"},
+		{Key: "owner-team", Value: "Fin"},
+		{Key: "owner-team", Value: "sales"},
+		{Key: "Environment", Value: "Eng"},
+		{Key: "project", Value: "Eng"},
+	}
+	for _, tag := range tags {
+		if key := runKeyScript(keyParams, tag.Key); key != r.Key(tag.Key) {
+			t.Errorf("Key script resolves %s to %s, expected %s", tag.Key, key, r.Key(tag.Key))
+		}
+		if value := runValueScript(valueParams, tag.Key, tag.Value); value != r.Value(tag.Key, tag.Value) {
+			t.Errorf("Value script resolves %s=%s to %s, expected %s", tag.Key, tag.Value, value, r.Value(tag.Key, tag.Value))
+		}
+	}
+}
+
+func TestKeysQuery(t *testing.T) {
+	r := NewResolver(testAliases)
+	cases := []struct {
+		key      string
+		expected map[string]interface{}
+	}{
+		{"project", map[string]interface{}{"term": map[string]interface{}{"tags.key": "project"}}},
+		{"Environment", map[string]interface{}{"terms": map[string]interface{}{"tags.key": []interface{}{"env", "Environment"}}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			source, err := r.KeysQuery("tags.key", tc.key).Source()
+			if err != nil {
+				t.Fatalf("Failed to build query: %s", err.Error())
+			}
+			var query map[string]interface{}
+			marshaled, _ := json.Marshal(source)
+			json.Unmarshal(marshaled, &query)
+			if !reflect.DeepEqual(query, tc.expected) {
+				t.Errorf("Query is %v, expected %v", query, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package tagging
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+
+	usageReports "github.com/trackit/trackit/aws/usageReports"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/tagging/aliases"
+	"github.com/trackit/trackit/tagging/policies"
+	"github.com/trackit/trackit/tagging/utils"
+)
+
+// costPeriodDays is the number of days of spend weighting the tagging compliance
+const costPeriodDays = 30
+
+// costNumPartitions is the number of partitions of the resources costs aggregation
+const costNumPartitions = 10
+
+// CostCompliance is the spend of the resources of the latest tagging report
+// over the last days, split by tagging status
+// Untagged spend is the spend of the resources missing at least one of the most used tags
+type CostCompliance struct {
+	TotalCost              float64 `json:"totalCost"`
+	TotallyTaggedCost      float64 `json:"totallyTaggedCost"`
+	PartiallyTaggedCost    float64 `json:"partiallyTaggedCost"`
+	NotTaggedCost          float64 `json:"notTaggedCost"`
+	NonCompliantCost       float64 `json:"nonCompliantCost"`
+	UntaggedPercentage     float64 `json:"untaggedPercentage"`
+	NonCompliantPercentage float64 `json:"nonCompliantPercentage"`
+}
+
+// CostComplianceBreakdown is the CostCompliance of an account or a resource type
+type CostComplianceBreakdown struct {
+	Key string `json:"key"`
+	CostCompliance
+}
+
+// costComplianceReport is the cost part of the tagging compliance
+type costComplianceReport struct {
+	Cost               CostCompliance
+	CostByAccount      []CostComplianceBreakdown
+	CostByResourceType []CostComplianceBreakdown
+}
+
+// add adds the cost of a resource to the CostCompliance
+func (c *CostCompliance) add(cost float64, totallyTagged, notTagged, nonCompliant bool) {
+	c.TotalCost += cost
+	if totallyTagged {
+		c.TotallyTaggedCost += cost
+	} else if notTagged {
+		c.NotTaggedCost += cost
+	} else {
+		c.PartiallyTaggedCost += cost
+	}
+	if nonCompliant {
+		c.NonCompliantCost += cost
+	}
+}
+
+// computePercentages sets the percentages of the CostCompliance from its amounts
+func (c *CostCompliance) computePercentages() {
+	if c.TotalCost <= 0 {
+		return
+	}
+	c.UntaggedPercentage = (c.PartiallyTaggedCost + c.NotTaggedCost) / c.TotalCost * 100
+	c.NonCompliantPercentage = c.NonCompliantCost / c.TotalCost * 100
+}
+
+// resourceTypeProductCodes maps the resource types of the tagging reports to
+// the product codes of their line items
+var resourceTypeProductCodes = map[string]string{
+	"cloudformation": "AWSCloudFormation",
+	"ebs":            "AmazonEC2",
+	"ec2":            "AmazonEC2",
+	"ec2-ri":         "AmazonEC2",
+	"elasticache":    "AmazonElastiCache",
+	"es":             "AmazonES",
+	"lambda":         "AWSLambda",
+	"rds":            "AmazonRDS",
+	"rds-ri":         "AmazonRDS",
+	"route53":        "AmazonRoute53",
+	"s3":             "AmazonS3",
+	"sqs":            "AWSQueueService",
+	"stepfunction":   "AmazonStates",
+}
+
+// productCode returns the product code of the line items of a resource type
+// The resource type itself is returned if it is unknown, so that its resources
+// never match the resources of another service
+func productCode(resourceType string) string {
+	if code, ok := resourceTypeProductCodes[resourceType]; ok {
+		return code
+	}
+	return resourceType
+}
+
+// shortResourceId returns the last part of a resource ID so that the ARNs
+// used in the line items match the IDs used in the tagging reports
+func shortResourceId(resourceId string) string {
+	return resourceId[strings.LastIndexAny(resourceId, ":/")+1:]
+}
+
+// resourceCostKey returns the key of the cost of a resource of a service in an account
+// The service is part of the key since the short IDs of the resources of several
+// services, such as a bucket and a queue with the same name, can be the same
+func resourceCostKey(account, productCode, resourceId string) string {
+	return account + "/" + productCode + "/" + shortResourceId(resourceId)
+}
+
+// getResourcesCosts returns the spend of each resource of the line items between two dates,
+// by resourceCostKey
+func getResourcesCosts(ctx context.Context, userId int, begin, end time.Time) (map[string]float64, error) {
+	index := es.IndexNameForUserId(userId, es.IndexPrefixLineItems)
+	query := elastic.NewBoolQuery().Filter(elastic.NewRangeQuery("usageStartDate").From(begin).To(end))
+	costs := make(map[string]float64)
+	for partition := 0; partition < costNumPartitions; partition++ {
+		res, err := es.Client.Search().Index(index).Size(0).Query(query).
+			Aggregation("resources", elastic.NewTermsAggregation().Field("resourceId").Size(usageReports.MaxAggregationSize).
+				Partition(partition).NumPartitions(costNumPartitions).
+				SubAggregation("accounts", elastic.NewTermsAggregation().Field("usageAccountId").Size(usageReports.MaxAggregationSize).
+					SubAggregation("products", elastic.NewTermsAggregation().Field("productCode").Size(usageReports.MaxAggregationSize).
+						SubAggregation("cost", elastic.NewSumAggregation().Field("unblendedCost"))))).Do(ctx)
+		if elastic.IsNotFound(err) {
+			return costs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		resources, found := res.Aggregations.Terms("resources")
+		if !found {
+			continue
+		}
+		for _, resource := range resources.Buckets {
+			resourceId, ok := resource.Key.(string)
+			if !ok || resourceId == "" {
+				continue
+			}
+			accounts, found := resource.Aggregations.Terms("accounts")
+			if !found {
+				continue
+			}
+			for _, account := range accounts.Buckets {
+				accountId, ok := account.Key.(string)
+				products, found := account.Aggregations.Terms("products")
+				if !ok || !found {
+					continue
+				}
+				for _, product := range products.Buckets {
+					cost, found := product.Aggregations.Sum("cost")
+					if code, ok := product.Key.(string); ok && found && cost.Value != nil {
+						costs[resourceCostKey(accountId, code, resourceId)] += *cost.Value
+					}
+				}
+			}
+		}
+	}
+	return costs, nil
+}
+
+// breakdownsFromMap returns the breakdowns sorted by decreasing spend
+func breakdownsFromMap(costs map[string]*CostCompliance) []CostComplianceBreakdown {
+	breakdowns := make([]CostComplianceBreakdown, 0, len(costs))
+	for key, cost := range costs {
+		cost.computePercentages()
+		breakdowns = append(breakdowns, CostComplianceBreakdown{key, *cost})
+	}
+	sort.Slice(breakdowns, func(i, j int) bool {
+		if breakdowns[i].TotalCost != breakdowns[j].TotalCost {
+			return breakdowns[i].TotalCost > breakdowns[j].TotalCost
+		}
+		return breakdowns[i].Key < breakdowns[j].Key
+	})
+	return breakdowns
+}
+
+// getCostCompliance weights the resources of the latest tagging report by their spend
+// over the last days, per account and per resource type
+// The tags of the resources are resolved with the tag key aliases of the user
+func getCostCompliance(ctx context.Context, userId int, mostUsedTags []string, resolver aliases.Resolver) (costComplianceReport, error) {
+	report := costComplianceReport{}
+	end := time.Now().UTC()
+	costs, err := getResourcesCosts(ctx, userId, end.AddDate(0, 0, -costPeriodDays), end)
+	if err != nil {
+		return report, err
+	}
+	userPolicies, err := policies.GetPoliciesForUser(db.Db, userId)
+	if err != nil {
+		return report, err
+	}
+	byAccount := make(map[string]*CostCompliance)
+	byResourceType := make(map[string]*CostCompliance)
+	err = utils.ScrollLatestReport(ctx, es.IndexNameForUserId(userId, IndexPrefixTaggingReport), elastic.NewBoolQuery(),
+		func(hit *elastic.SearchHit) error {
+			var document utils.TaggingReportDocument
+			if err := json.Unmarshal(*hit.Source, &document); err != nil {
+				return err
+			}
+			cost := costs[resourceCostKey(document.Account, productCode(document.ResourceType), document.ResourceID)]
+			document.Tags = resolver.NormalizeTags(document.Tags)
+			tagsCount := countMostUsedTags(document.Tags, mostUsedTags)
+			violations, _ := policies.EvaluatePolicies(userPolicies, document)
+			totallyTagged := len(mostUsedTags) > 0 && tagsCount == len(mostUsedTags)
+			notTagged := tagsCount == 0
+			nonCompliant := len(violations) > 0
+			if byAccount[document.Account] == nil {
+				byAccount[document.Account] = &CostCompliance{}
+			}
+			if byResourceType[document.ResourceType] == nil {
+				byResourceType[document.ResourceType] = &CostCompliance{}
+			}
+			report.Cost.add(cost, totallyTagged, notTagged, nonCompliant)
+			byAccount[document.Account].add(cost, totallyTagged, notTagged, nonCompliant)
+			byResourceType[document.ResourceType].add(cost, totallyTagged, notTagged, nonCompliant)
+			return nil
+		})
+	if err != nil && !elastic.IsNotFound(err) {
+		return report, err
+	}
+	report.Cost.computePercentages()
+	report.CostByAccount = breakdownsFromMap(byAccount)
+	report.CostByResourceType = breakdownsFromMap(byResourceType)
+	return report, nil
+}
+
+// countMostUsedTags returns the number of most used tags keys set on a resource
+func countMostUsedTags(tags []usageReports.Tag, mostUsedTags []string) (count int) {
+	for _, mostUsedTag := range mostUsedTags {
+		for _, tag := range tags {
+			if tag.Key == mostUsedTag {
+				count++
+				break
+			}
+		}
+	}
+	return
+}
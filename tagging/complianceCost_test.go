@@ -0,0 +1,136 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package tagging
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResourceCostKey(t *testing.T) {
+	cases := []struct {
+		name        string
+		account     string
+		productCode string
+		resourceId  string
+		expected    string
+	}{
+		{"ShortId", "123456789012", "AmazonEC2", "i-0123456789abcdef0", "123456789012/AmazonEC2/i-0123456789abcdef0"},
+		{"Arn", "123456789012", "AWSLambda", "arn:aws:lambda:us-east-1:123456789012:function:prod", "123456789012/AWSLambda/prod"},
+		{"ArnWithPath", "123456789012", "AmazonEC2", "arn:aws:ec2:us-east-1:123456789012:volume/vol-0123456789abcdef0", "123456789012/AmazonEC2/vol-0123456789abcdef0"},
+		{"Bucket", "123456789012", "AmazonS3", "prod", "123456789012/AmazonS3/prod"},
+		{"QueueUrl", "123456789012", "AWSQueueService", "https://sqs.us-east-1.amazonaws.com/123456789012/prod", "123456789012/AWSQueueService/prod"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if key := resourceCostKey(tc.account, tc.productCode, tc.resourceId); key != tc.expected {
+				t.Errorf("Key is %s, expected %s", key, tc.expected)
+			}
+		})
+	}
+}
+
+// TestResourceCostKeyServices checks that the resources of the tagging reports
+// match the line items of their service only
+func TestResourceCostKeyServices(t *testing.T) {
+	lineItems := map[string]float64{
+		resourceCostKey("123456789012", "AmazonS3", "prod"):                                                 10,
+		resourceCostKey("123456789012", "AWSLambda", "arn:aws:lambda:us-east-1:123456789012:function:prod"): 20,
+		resourceCostKey("123456789012", "AWSQueueService", "arn:aws:sqs:us-east-1:123456789012:prod"):       30,
+		resourceCostKey("210987654321", "AmazonS3", "prod"):                                                 40,
+	}
+	cases := []struct {
+		resourceType string
+		account      string
+		resourceId   string
+		cost         float64
+	}{
+		{"s3", "123456789012", "prod", 10},
+		{"lambda", "123456789012", "prod", 20},
+		{"sqs", "123456789012", "https://sqs.us-east-1.amazonaws.com/123456789012/prod", 30},
+		{"s3", "210987654321", "prod", 40},
+		{"rds", "123456789012", "prod", 0},
+		{"unknown", "123456789012", "prod", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.resourceType+"/"+tc.account, func(t *testing.T) {
+			if cost := lineItems[resourceCostKey(tc.account, productCode(tc.resourceType), tc.resourceId)]; cost != tc.cost {
+				t.Errorf("Cost is %v, expected %v", cost, tc.cost)
+			}
+		})
+	}
+}
+
+func TestCostComplianceAdd(t *testing.T) {
+	var c CostCompliance
+	c.add(10, true, false, false)
+	c.add(20, false, false, true)
+	c.add(30, false, true, true)
+	c.add(40, false, false, false)
+	c.add(0, false, true, true)
+	expected := CostCompliance{
+		TotalCost:           100,
+		TotallyTaggedCost:   10,
+		PartiallyTaggedCost: 60,
+		NotTaggedCost:       30,
+		NonCompliantCost:    50,
+	}
+	if c != expected {
+		t.Errorf("Cost compliance is %+v, expected %+v", c, expected)
+	}
+}
+
+func TestComputePercentages(t *testing.T) {
+	cases := []struct {
+		name         string
+		cost         CostCompliance
+		untagged     float64
+		nonCompliant float64
+	}{
+		{"NoCost", CostCompliance{}, 0, 0},
+		{"NegativeCost", CostCompliance{TotalCost: -10, NotTaggedCost: -10}, 0, 0},
+		{"TotallyTagged", CostCompliance{TotalCost: 50, TotallyTaggedCost: 50}, 0, 0},
+		{"Mixed", CostCompliance{TotalCost: 200, TotallyTaggedCost: 50, PartiallyTaggedCost: 100, NotTaggedCost: 50, NonCompliantCost: 30}, 75, 15},
+		{"Untagged", CostCompliance{TotalCost: 3, NotTaggedCost: 3, NonCompliantCost: 1}, 100, 100.0 / 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cost.computePercentages()
+			if math.Abs(tc.cost.UntaggedPercentage-tc.untagged) > 1e-9 || math.Abs(tc.cost.NonCompliantPercentage-tc.nonCompliant) > 1e-9 {
+				t.Errorf("Percentages are (%v, %v), expected (%v, %v)", tc.cost.UntaggedPercentage, tc.cost.NonCompliantPercentage, tc.untagged, tc.nonCompliant)
+			}
+		})
+	}
+}
+
+func TestBreakdownsFromMap(t *testing.T) {
+	breakdowns := breakdownsFromMap(map[string]*CostCompliance{
+		"s3":     {TotalCost: 10, NotTaggedCost: 10},
+		"ec2":    {TotalCost: 30, TotallyTaggedCost: 30},
+		"lambda": {TotalCost: 10, TotallyTaggedCost: 5, PartiallyTaggedCost: 5},
+	})
+	expected := []string{"ec2", "lambda", "s3"}
+	if len(breakdowns) != len(expected) {
+		t.Fatalf("Got %d breakdowns, expected %d", len(breakdowns), len(expected))
+	}
+	for i, key := range expected {
+		if breakdowns[i].Key != key {
+			t.Errorf("Breakdown %d is %s, expected %s", i, breakdowns[i].Key, key)
+		}
+	}
+	if breakdowns[1].UntaggedPercentage != 50 {
+		t.Errorf("Untagged percentage of lambda is %v, expected 50", breakdowns[1].UntaggedPercentage)
+	}
+}
@@ -41,7 +41,7 @@ func init() {
 const templateTaggingCompliance = `
 {
     "template":"*-tagging-compliance",
-    "version":2,
+    "version":4,
     "mappings":{
         "tagging-compliance":{
             "properties":{
@@ -65,6 +65,102 @@ const templateTaggingCompliance = `
                 },
                 "mostUsedTags":{
                     "type":"text"
+                },
+                "policies":{
+                    "properties":{
+                        "evaluated":{
+                            "type":"long"
+                        },
+                        "compliant":{
+                            "type":"long"
+                        },
+                        "nonCompliant":{
+                            "type":"long"
+                        }
+                    }
+                },
+                "cost":{
+                    "properties":{
+                        "totalCost":{
+                            "type":"double"
+                        },
+                        "totallyTaggedCost":{
+                            "type":"double"
+                        },
+                        "partiallyTaggedCost":{
+                            "type":"double"
+                        },
+                        "notTaggedCost":{
+                            "type":"double"
+                        },
+                        "nonCompliantCost":{
+                            "type":"double"
+                        },
+                        "untaggedPercentage":{
+                            "type":"double"
+                        },
+                        "nonCompliantPercentage":{
+                            "type":"double"
+                        }
+                    }
+                },
+                "costByAccount":{
+                    "type":"nested",
+                    "properties":{
+                        "totalCost":{
+                            "type":"double"
+                        },
+                        "totallyTaggedCost":{
+                            "type":"double"
+                        },
+                        "partiallyTaggedCost":{
+                            "type":"double"
+                        },
+                        "notTaggedCost":{
+                            "type":"double"
+                        },
+                        "nonCompliantCost":{
+                            "type":"double"
+                        },
+                        "untaggedPercentage":{
+                            "type":"double"
+                        },
+                        "nonCompliantPercentage":{
+                            "type":"double"
+                        },
+                        "key":{
+                            "type":"keyword"
+                        }
+                    }
+                },
+                "costByResourceType":{
+                    "type":"nested",
+                    "properties":{
+                        "totalCost":{
+                            "type":"double"
+                        },
+                        "totallyTaggedCost":{
+                            "type":"double"
+                        },
+                        "partiallyTaggedCost":{
+                            "type":"double"
+                        },
+                        "notTaggedCost":{
+                            "type":"double"
+                        },
+                        "nonCompliantCost":{
+                            "type":"double"
+                        },
+                        "untaggedPercentage":{
+                            "type":"double"
+                        },
+                        "nonCompliantPercentage":{
+                            "type":"double"
+                        },
+                        "key":{
+                            "type":"keyword"
+                        }
+                    }
                 }
             },
             "_all": {
@@ -0,0 +1,148 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package policies
+
+import (
+	"strings"
+
+	usageReports "github.com/trackit/trackit/aws/usageReports"
+	"github.com/trackit/trackit/tagging/utils"
+)
+
+// Types of violations of a rule
+const (
+	ViolationMissing    = "missing"
+	ViolationKeyCase    = "keyCase"
+	ViolationNotAllowed = "notAllowed"
+	ViolationPattern    = "patternMismatch"
+	ViolationValueCase  = "valueCase"
+)
+
+// Violation is a rule of a policy that a resource does not follow
+// Value is the value of the tag, or the key found with another case for ViolationKeyCase
+type Violation struct {
+	PolicyId   int    `json:"policyId"`
+	PolicyName string `json:"policyName"`
+	Key        string `json:"key"`
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+}
+
+// appliesTo returns true if the policy applies to the resource type
+func (p Policy) appliesTo(resourceType string) bool {
+	if len(p.ResourceTypes) == 0 {
+		return true
+	}
+	for _, policyResourceType := range p.ResourceTypes {
+		if policyResourceType == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// findTag returns the tag with the given key
+// If the key is only found with another case, the tag is returned with
+// exactCase set to false
+func findTag(tags []usageReports.Tag, key string) (tag usageReports.Tag, found bool, exactCase bool) {
+	for _, t := range tags {
+		if t.Key == key {
+			return t, true, true
+		} else if !found && strings.EqualFold(t.Key, key) {
+			tag, found = t, true
+		}
+	}
+	return tag, found, false
+}
+
+// isAllowedValue returns true if the value is one of the allowed values of the rule
+// Every value is allowed if the rule has no allowed values
+func (r Rule) isAllowedValue(value string) bool {
+	if len(r.AllowedValues) == 0 {
+		return true
+	}
+	for _, allowedValue := range r.AllowedValues {
+		if value == allowedValue {
+			return true
+		}
+	}
+	return false
+}
+
+// hasValidCase returns true if the value has the case required by the rule
+func (r Rule) hasValidCase(value string) bool {
+	switch r.ValueCase {
+	case CaseLower:
+		return value == strings.ToLower(value)
+	case CaseUpper:
+		return value == strings.ToUpper(value)
+	}
+	return true
+}
+
+// evaluate returns the violations of a rule by a set of tags
+func (r Rule) evaluate(tags []usageReports.Tag) []Violation {
+	violations := []Violation{}
+	tag, found, exactCase := findTag(tags, r.Key)
+	if !found {
+		if r.Required {
+			violations = append(violations, Violation{Key: r.Key, Type: ViolationMissing})
+		}
+		return violations
+	} else if !exactCase {
+		violations = append(violations, Violation{Key: r.Key, Type: ViolationKeyCase, Value: tag.Key})
+	}
+	if !r.hasValidCase(tag.Value) {
+		violations = append(violations, Violation{Key: r.Key, Type: ViolationValueCase, Value: tag.Value})
+	}
+	if !r.isAllowedValue(tag.Value) {
+		violations = append(violations, Violation{Key: r.Key, Type: ViolationNotAllowed, Value: tag.Value})
+	}
+	// The pattern has been compiled when the policy was validated or loaded
+	if r.pattern != nil && !r.pattern.MatchString(tag.Value) {
+		violations = append(violations, Violation{Key: r.Key, Type: ViolationPattern, Value: tag.Value})
+	}
+	return violations
+}
+
+// Evaluate returns the violations of the policy by a resource
+// The violations are empty if the policy does not apply to the resource
+func (p Policy) Evaluate(document utils.TaggingReportDocument) []Violation {
+	violations := []Violation{}
+	if !p.appliesTo(document.ResourceType) {
+		return violations
+	}
+	for _, rule := range p.Rules {
+		for _, violation := range rule.evaluate(document.Tags) {
+			violation.PolicyId = p.Id
+			violation.PolicyName = p.Name
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// EvaluatePolicies returns the violations of a set of policies by a resource
+// along with whether at least one of the policies applies to the resource
+func EvaluatePolicies(policies []Policy, document utils.TaggingReportDocument) (violations []Violation, applies bool) {
+	violations = []Violation{}
+	for _, policy := range policies {
+		if policy.appliesTo(document.ResourceType) {
+			applies = true
+			violations = append(violations, policy.Evaluate(document)...)
+		}
+	}
+	return
+}
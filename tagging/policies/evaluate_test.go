@@ -0,0 +1,199 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package policies
+
+import (
+	"reflect"
+	"testing"
+
+	usageReports "github.com/trackit/trackit/aws/usageReports"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/tagging/utils"
+)
+
+// validPolicy validates a policy, which compiles the patterns of its rules
+func validPolicy(t *testing.T, policy Policy) Policy {
+	t.Helper()
+	if err := policy.validate(); err != nil {
+		t.Fatalf("Unexpected error validating policy %s: %s", policy.Name, err.Error())
+	}
+	return policy
+}
+
+func document(resourceType string, tags ...string) utils.TaggingReportDocument {
+	document := utils.TaggingReportDocument{ResourceType: resourceType, Tags: []usageReports.Tag{}}
+	for i := 0; i+1 < len(tags); i += 2 {
+		document.Tags = append(document.Tags, usageReports.Tag{Key: tags[i], Value: tags[i+1]})
+	}
+	return document
+}
+
+func TestEvaluate(t *testing.T) {
+	policy := validPolicy(t, Policy{
+		Id:            1,
+		Name:          "Production",
+		ResourceTypes: []string{"ec2"},
+		Rules: []Rule{
+			{Key: "Environment", Required: true, AllowedValues: []string{"prod", "staging"}, ValueCase: CaseLower},
+			{Key: "Owner", Pattern: "[a-z]+@trackit\\.io"},
+			{Key: "Team", Required: true, ValueCase: CaseUpper},
+		},
+	})
+	violation := func(key, kind, value string) Violation {
+		return Violation{PolicyId: 1, PolicyName: "Production", Key: key, Type: kind, Value: value}
+	}
+	for _, tc := range []struct {
+		name     string
+		document utils.TaggingReportDocument
+		expected []Violation
+	}{
+		{
+			"Compliant",
+			document("ec2", "Environment", "prod", "Owner", "jane@trackit.io", "Team", "OPS"),
+			[]Violation{},
+		},
+		{
+			"Optional tag missing",
+			document("ec2", "Environment", "staging", "Team", "OPS"),
+			[]Violation{},
+		},
+		{
+			"Required tags missing",
+			document("ec2"),
+			[]Violation{violation("Environment", ViolationMissing, ""), violation("Team", ViolationMissing, "")},
+		},
+		{
+			"Key case",
+			document("ec2", "environment", "prod", "Team", "OPS"),
+			[]Violation{violation("Environment", ViolationKeyCase, "environment")},
+		},
+		{
+			"Value not allowed and wrong case",
+			document("ec2", "Environment", "Dev", "Team", "ops"),
+			[]Violation{
+				violation("Environment", ViolationValueCase, "Dev"),
+				violation("Environment", ViolationNotAllowed, "Dev"),
+				violation("Team", ViolationValueCase, "ops"),
+			},
+		},
+		{
+			"Pattern must match the whole value",
+			document("ec2", "Environment", "prod", "Owner", "jane@trackit.io.example.com", "Team", "OPS"),
+			[]Violation{violation("Owner", ViolationPattern, "jane@trackit.io.example.com")},
+		},
+		{
+			"Other resource type",
+			document("rds"),
+			[]Violation{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if violations := policy.Evaluate(tc.document); !reflect.DeepEqual(violations, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, violations)
+			}
+		})
+	}
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	policies := []Policy{
+		validPolicy(t, Policy{Id: 1, Name: "Everything", Rules: []Rule{{Key: "Owner", Required: true}}}),
+		validPolicy(t, Policy{Id: 2, Name: "Databases", ResourceTypes: []string{"rds", "elasticache"}, Rules: []Rule{{Key: "Backup", Pattern: "daily|weekly"}}}),
+	}
+	for _, tc := range []struct {
+		name     string
+		policies []Policy
+		document utils.TaggingReportDocument
+		expected []Violation
+		applies  bool
+	}{
+		{
+			"Compliant with every policy",
+			policies,
+			document("rds", "Owner", "jane", "Backup", "daily"),
+			[]Violation{},
+			true,
+		},
+		{
+			"Violations of every policy",
+			policies,
+			document("rds", "Backup", "monthly"),
+			[]Violation{
+				{PolicyId: 1, PolicyName: "Everything", Key: "Owner", Type: ViolationMissing},
+				{PolicyId: 2, PolicyName: "Databases", Key: "Backup", Type: ViolationPattern, Value: "monthly"},
+			},
+			true,
+		},
+		{
+			"Only applying policies",
+			policies,
+			document("ec2", "Backup", "monthly"),
+			[]Violation{{PolicyId: 1, PolicyName: "Everything", Key: "Owner", Type: ViolationMissing}},
+			true,
+		},
+		{
+			"No applying policy",
+			policies[1:],
+			document("ec2"),
+			[]Violation{},
+			false,
+		},
+		{
+			"No policy",
+			nil,
+			document("ec2"),
+			[]Violation{},
+			false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, applies := EvaluatePolicies(tc.policies, tc.document)
+			if applies != tc.applies {
+				t.Errorf("Expected applies to be %t, got %t", tc.applies, applies)
+			}
+			if !reflect.DeepEqual(violations, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, violations)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsInvalidPattern(t *testing.T) {
+	policy := Policy{Name: "Invalid", Rules: []Rule{{Key: "Owner", Pattern: "[a-z"}}}
+	if err := policy.validate(); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
+
+func TestPolicyFromDbPolicy(t *testing.T) {
+	dbPolicy := models.TagPolicy{
+		ID:            3,
+		Name:          "Stored",
+		ResourceTypes: []byte(`[]`),
+		Rules:         []byte(`[{"key":"Owner","pattern":"[a-z]+"}]`),
+	}
+	policy, err := policyFromDbPolicy(dbPolicy)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	expected := []Violation{{PolicyId: 3, PolicyName: "Stored", Key: "Owner", Type: ViolationPattern, Value: "Jane"}}
+	if violations := policy.Evaluate(document("ec2", "Owner", "Jane")); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Expected %v, got %v", expected, violations)
+	}
+	dbPolicy.Rules = []byte(`[{"key":"Owner","pattern":"[a-z"}]`)
+	if _, err := policyFromDbPolicy(dbPolicy); err == nil {
+		t.Errorf("Expected an error for an invalid stored pattern")
+	}
+}
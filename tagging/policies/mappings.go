@@ -0,0 +1,99 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package policies
+
+import (
+	"context"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/es"
+)
+
+const IndexPrefixTagPolicyReport = "tagging-policy-reports"
+const TypeTagPolicyReport = "tagging-policy-reports"
+const templateNameTagPolicyReport = "tagging-policy-reports"
+
+// put the ElasticSearch index for *-tagging-policy-reports indices at startup.
+func init() {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+	res, err := es.Client.IndexPutTemplate(templateNameTagPolicyReport).BodyString(templateTagPolicyReport).Do(ctx)
+	if err != nil {
+		jsonlog.DefaultLogger.Error("Failed to put ES index tagging-policy-reports.", err)
+	} else {
+		jsonlog.DefaultLogger.Info("Put ES index tagging-policy-reports.", res)
+	}
+}
+
+const templateTagPolicyReport = `
+{
+    "template":"*-tagging-policy-reports",
+    "version":1,
+    "mappings":{
+        "tagging-policy-reports":{
+            "properties":{
+                "account":{
+                    "type":"keyword"
+                },
+                "region":{
+                    "type":"keyword"
+                },
+                "reportDate":{
+                    "type":"date"
+                },
+                "resourceId":{
+                    "type":"keyword"
+                },
+                "resourceType":{
+                    "type":"keyword"
+                },
+                "url":{
+                    "type":"keyword"
+                },
+                "compliant":{
+                    "type":"boolean"
+                },
+                "violations":{
+                    "type":"nested",
+                    "properties":{
+                        "policyId":{
+                            "type":"integer"
+                        },
+                        "policyName":{
+                            "type":"keyword"
+                        },
+                        "key":{
+                            "type":"keyword"
+                        },
+                        "type":{
+                            "type":"keyword"
+                        },
+                        "value":{
+                            "type":"keyword"
+                        }
+                    }
+                }
+            },
+            "_all": {
+                "enabled": false
+            },
+            "date_detection": false,
+            "numeric_detection": false
+        }
+    }
+}
+`
@@ -0,0 +1,156 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package policies evaluates the tagging reports against the tag policies
+// defined by the users.
+package policies
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/trackit/trackit/models"
+)
+
+// Cases which can be required for the values of a tag
+const (
+	CaseAny   = ""
+	CaseLower = "lower"
+	CaseUpper = "upper"
+)
+
+// Rule describes the constraints on a tag key
+type Rule struct {
+	Key           string   `json:"key"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowedValues"`
+	Pattern       string   `json:"pattern"`
+	ValueCase     string   `json:"valueCase"`
+	// pattern is the compiled Pattern, nil if it is empty
+	pattern *regexp.Regexp
+}
+
+// Policy is a set of rules applied to the resources of some types
+// A policy without resource types applies to every resource
+type Policy struct {
+	Id            int      `json:"id"`
+	Name          string   `json:"name"`
+	ResourceTypes []string `json:"resourceTypes"`
+	Rules         []Rule   `json:"rules"`
+}
+
+// validate checks that a rule can be evaluated and compiles its pattern
+func (r *Rule) validate() error {
+	if r.Key == "" {
+		return errors.New("rule key is required")
+	} else if r.ValueCase != CaseAny && r.ValueCase != CaseLower && r.ValueCase != CaseUpper {
+		return fmt.Errorf("valueCase of rule %s must be empty, %s or %s", r.Key, CaseLower, CaseUpper)
+	}
+	return r.compile()
+}
+
+// compile compiles the pattern of a rule, which must match the whole value
+func (r *Rule) compile() (err error) {
+	r.pattern = nil
+	if r.Pattern != "" {
+		if r.pattern, err = regexp.Compile("^(?:" + r.Pattern + ")$"); err != nil {
+			return fmt.Errorf("pattern of rule %s is invalid: %s", r.Key, err.Error())
+		}
+	}
+	return nil
+}
+
+// validate checks that a policy can be evaluated and compiles the patterns
+// of its rules
+func (p *Policy) validate() error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	} else if len(p.Rules) == 0 {
+		return errors.New("at least one rule is required")
+	}
+	keys := make(map[string]bool, len(p.Rules))
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if err := rule.validate(); err != nil {
+			return err
+		} else if keys[rule.Key] {
+			return fmt.Errorf("key %s has several rules", rule.Key)
+		}
+		keys[rule.Key] = true
+	}
+	return nil
+}
+
+// policyFromDbPolicy builds a Policy from its database row and compiles the
+// patterns of its rules
+func policyFromDbPolicy(dbPolicy models.TagPolicy) (Policy, error) {
+	policy := Policy{
+		Id:   dbPolicy.ID,
+		Name: dbPolicy.Name,
+	}
+	if err := json.Unmarshal(dbPolicy.ResourceTypes, &policy.ResourceTypes); err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(dbPolicy.Rules, &policy.Rules); err != nil {
+		return policy, err
+	}
+	for i := range policy.Rules {
+		if err := policy.Rules[i].compile(); err != nil {
+			return policy, err
+		}
+	}
+	return policy, nil
+}
+
+// setDbPolicy sets the fields of a database row from a Policy
+func setDbPolicy(dbPolicy *models.TagPolicy, policy Policy) (err error) {
+	if policy.ResourceTypes == nil {
+		policy.ResourceTypes = []string{}
+	}
+	dbPolicy.Name = policy.Name
+	if dbPolicy.ResourceTypes, err = json.Marshal(policy.ResourceTypes); err == nil {
+		dbPolicy.Rules, err = json.Marshal(policy.Rules)
+	}
+	return
+}
+
+// GetPoliciesForUser returns the tag policies of a user
+func GetPoliciesForUser(db models.DB, userId int) ([]Policy, error) {
+	dbPolicies, err := models.TagPolicyByUserID(db, userId)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]Policy, 0, len(dbPolicies))
+	for _, dbPolicy := range dbPolicies {
+		policy, err := policyFromDbPolicy(*dbPolicy)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// getDbPolicyForUser returns the database row of a policy if it belongs to the user
+func getDbPolicyForUser(db models.DB, userId, policyId int) (*models.TagPolicy, error) {
+	dbPolicy, err := models.TagPolicyByID(db, policyId)
+	if err != nil {
+		return nil, err
+	} else if dbPolicy.UserID != userId {
+		return nil, errors.New("policy does not belong to the user")
+	}
+	return dbPolicy, nil
+}
@@ -0,0 +1,246 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package policies
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+// policyIdQueryArg allows to get the ID of a tag policy in the URL parameters.
+var policyIdQueryArg = routes.QueryArg{
+	Name:        "policy",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of a tag policy.",
+}
+
+// policyIdOptionalQueryArg allows to filter the violations of a tag policy.
+var policyIdOptionalQueryArg = routes.QueryArg{
+	Name:        "policy",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of a tag policy.",
+	Optional:    true,
+}
+
+// resourceTypesQueryArg allows to filter the violations by resource type.
+var resourceTypesQueryArg = routes.QueryArg{
+	Name:        "resourceTypes",
+	Type:        routes.QueryArgStringSlice{},
+	Description: "Comma separated resource types, e.g. ec2,ebs.",
+	Optional:    true,
+}
+
+// policyExample is the example body of the tag policy routes.
+var policyExample = Policy{
+	Name:          "Production resources",
+	ResourceTypes: []string{"ec2", "ebs"},
+	Rules: []Rule{
+		{Key: "env", Required: true, AllowedValues: []string{"prod", "staging"}, ValueCase: CaseLower},
+		{Key: "cost-center", Required: true, Pattern: "CC-[0-9]{4}"},
+	},
+}
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getPolicies).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.Documentation{
+				Summary:     "get the tag policies",
+				Description: "Responds with the tag policies of the user.",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+		http.MethodPost: routes.H(postPolicy).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{policyExample},
+			routes.Documentation{
+				Summary:     "create a tag policy",
+				Description: "Creates a tag policy with rules on the keys and values of the tags of some resource types.",
+			},
+			users.RequirePermission{users.PermissionManageTagging},
+		),
+		http.MethodPatch: routes.H(patchPolicy).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.QueryArgs{policyIdQueryArg},
+			routes.RequestBody{policyExample},
+			routes.Documentation{
+				Summary:     "edit a tag policy",
+				Description: "Replaces the name, resource types and rules of a tag policy.",
+			},
+			users.RequirePermission{users.PermissionManageTagging},
+		),
+		http.MethodDelete: routes.H(deletePolicy).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			routes.QueryArgs{policyIdQueryArg},
+			routes.Documentation{
+				Summary:     "delete a tag policy",
+				Description: "Deletes a tag policy. Its violations are not reported anymore.",
+			},
+			users.RequirePermission{users.PermissionManageTagging},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.Documentation{
+			Summary:     "interact with the tag policies",
+			Description: "A tag policy lists the tag keys required on resources, with their allowed values, pattern and case. Policies are evaluated after each tagging report.",
+		},
+	).Register("/tagging/policies")
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getViolations).With(
+			db.RequestTransaction{Db: db.Db},
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.QueryArgs{
+				routes.AwsAccountsOptionalQueryArg,
+				policyIdOptionalQueryArg,
+				resourceTypesQueryArg,
+			},
+			routes.Documentation{
+				Summary:     "get the tag policies violations",
+				Description: "Responds with the resources of the latest tagging report which violate at least one tag policy.",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+	}.H().Register("/tagging/policies/violations")
+}
+
+// getPolicies is a route handler which returns the tag policies of the user.
+func getPolicies(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	policies, err := GetPoliciesForUser(tx, user.Id)
+	if err != nil {
+		l.Error("Failed to get tag policies.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve tag policies.")
+	}
+	return http.StatusOK, policies
+}
+
+// getViolations is a route handler which returns the violations of the tag
+// policies of the user.
+func getViolations(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	params := ViolationsQueryParams{}
+	if accounts, ok := a[routes.AwsAccountsOptionalQueryArg].([]string); ok {
+		params.AccountList = accounts
+	}
+	if policyId, ok := a[policyIdOptionalQueryArg].(int); ok {
+		params.PolicyId = policyId
+	}
+	if resourceTypes, ok := a[resourceTypesQueryArg].([]string); ok {
+		params.ResourceTypes = resourceTypes
+	}
+	policies, err := GetPoliciesForUser(tx, user.Id)
+	if err != nil {
+		l.Error("Failed to get tag policies.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve tag policies.")
+	}
+	violations, err := GetLatestViolations(r.Context(), user.Id, policies, params)
+	if err != nil {
+		l.Error("Failed to get tag policies violations.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve tag policies violations.")
+	}
+	return http.StatusOK, violations
+}
+
+// postPolicy is a route handler which creates a tag policy.
+func postPolicy(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Policy
+	routes.MustRequestBody(a, &body)
+	if err := body.validate(); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbPolicy := models.TagPolicy{UserID: user.Id}
+	return savePolicy(r, tx, &dbPolicy, body)
+}
+
+// patchPolicy is a route handler which replaces a tag policy.
+func patchPolicy(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Policy
+	routes.MustRequestBody(a, &body)
+	if err := body.validate(); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbPolicy, err := getDbPolicyForUser(tx, user.Id, a[policyIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Tag policy not found.")
+	}
+	return savePolicy(r, tx, dbPolicy, body)
+}
+
+// savePolicy saves a valid tag policy in the database.
+func savePolicy(r *http.Request, tx *sql.Tx, dbPolicy *models.TagPolicy, policy Policy) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	err := setDbPolicy(dbPolicy, policy)
+	if err == nil {
+		err = dbPolicy.Save(tx)
+	}
+	if err == nil {
+		policy, err = policyFromDbPolicy(*dbPolicy)
+	}
+	if err != nil {
+		l.Error("Failed to save tag policy.", map[string]interface{}{
+			"policy": policy,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save tag policy.")
+	}
+	return http.StatusOK, policy
+}
+
+// deletePolicy is a route handler which deletes a tag policy.
+func deletePolicy(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[users.AuthenticatedUser].(users.User)
+	dbPolicy, err := getDbPolicyForUser(tx, user.Id, a[policyIdQueryArg].(int))
+	if err != nil {
+		return http.StatusNotFound, errors.New("Tag policy not found.")
+	}
+	if err := dbPolicy.Delete(tx); err != nil {
+		l.Error("Failed to delete tag policy.", map[string]interface{}{
+			"policyId": dbPolicy.ID,
+			"error":    err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to delete tag policy.")
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,173 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package policies
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/olivere/elastic"
+	"github.com/trackit/jsonlog"
+
+	bulk "github.com/trackit/trackit/aws/usageReports"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/es"
+	"github.com/trackit/trackit/tagging/aliases"
+	"github.com/trackit/trackit/tagging/utils"
+)
+
+// indexPrefixTaggingReport is the prefix of the indices of the tagging reports
+// evaluated against the policies
+const indexPrefixTaggingReport = "tagging-reports"
+
+// ResourceReport is the result of the evaluation of the policies for a resource
+type ResourceReport struct {
+	utils.TaggingReportDocument
+	Compliant  bool        `json:"compliant"`
+	Violations []Violation `json:"violations"`
+}
+
+// Summary counts the resources to which at least one policy applies
+type Summary struct {
+	Evaluated    int64 `json:"evaluated"`
+	Compliant    int64 `json:"compliant"`
+	NonCompliant int64 `json:"nonCompliant"`
+}
+
+// EvaluatePoliciesForUser evaluates the policies of a user against the latest tagging reports
+// and saves the result of every resource to which a policy applies
+// The tags of the resources are resolved with the tag key aliases of the user before the evaluation
+func EvaluatePoliciesForUser(ctx context.Context, userId int) (Summary, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	summary := Summary{}
+	policies, err := GetPoliciesForUser(db.Db, userId)
+	if err != nil || len(policies) == 0 {
+		return summary, err
+	}
+	resolver, err := aliases.GetResolverForUser(db.Db, userId)
+	if err != nil {
+		return summary, err
+	}
+	bulkProcessor, err := bulk.GetBulkProcessor(ctx)
+	if err != nil {
+		return summary, err
+	}
+	destIndexName := es.IndexNameForUserId(userId, IndexPrefixTagPolicyReport)
+	err = utils.ScrollLatestReport(ctx, es.IndexNameForUserId(userId, indexPrefixTaggingReport), elastic.NewBoolQuery(),
+		func(hit *elastic.SearchHit) error {
+			var document utils.TaggingReportDocument
+			if err := json.Unmarshal(*hit.Source, &document); err != nil {
+				return err
+			}
+			normalized := document
+			normalized.Tags = resolver.NormalizeTags(document.Tags)
+			violations, applies := EvaluatePolicies(policies, normalized)
+			if !applies {
+				return nil
+			}
+			report := ResourceReport{document, len(violations) == 0, violations}
+			summary.Evaluated++
+			if report.Compliant {
+				summary.Compliant++
+			} else {
+				summary.NonCompliant++
+			}
+			documentID, err := utils.GenerateBulkID(document)
+			if err != nil {
+				logger.Error("Could not add a tag policy report to bulk processor.", err.Error())
+				return nil
+			}
+			bulkProcessor = bulk.AddDocToBulkProcessor(bulkProcessor, report, TypeTagPolicyReport, destIndexName, documentID)
+			return nil
+		})
+	if err == nil {
+		err = bulkProcessor.Flush()
+	}
+	if closeErr := bulkProcessor.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logger.Error("Failed to put tag policy reports in ES", err.Error())
+		return summary, err
+	}
+	logger.Info("Tag policies evaluated.", map[string]interface{}{
+		"userId":  userId,
+		"summary": summary,
+	})
+	return summary, nil
+}
+
+// ViolationsQueryParams filters the violations of the latest policy reports
+type ViolationsQueryParams struct {
+	AccountList   []string
+	PolicyId      int
+	ResourceTypes []string
+}
+
+// createQueryTermsFilter creates and return a new *elastic.TermsQuery on the values array
+func createQueryTermsFilter(name string, values []string) *elastic.TermsQuery {
+	valuesFormatted := make([]interface{}, len(values))
+	for i, v := range values {
+		valuesFormatted[i] = v
+	}
+	return elastic.NewTermsQuery(name, valuesFormatted...)
+}
+
+// GetLatestViolations returns the non compliant resources of the latest policy reports
+// Violations of policies which do not exist anymore are ignored
+func GetLatestViolations(ctx context.Context, userId int, policies []Policy, params ViolationsQueryParams) ([]ResourceReport, error) {
+	policyIds := make(map[int]bool, len(policies))
+	for _, policy := range policies {
+		if params.PolicyId == 0 || params.PolicyId == policy.Id {
+			policyIds[policy.Id] = true
+		}
+	}
+	reports := []ResourceReport{}
+	if len(policyIds) == 0 {
+		return reports, nil
+	}
+	query := elastic.NewBoolQuery().Filter(elastic.NewTermQuery("compliant", false))
+	if len(params.AccountList) > 0 {
+		query = query.Filter(createQueryTermsFilter("account", params.AccountList))
+	}
+	if len(params.ResourceTypes) > 0 {
+		query = query.Filter(createQueryTermsFilter("resourceType", params.ResourceTypes))
+	}
+	if params.PolicyId != 0 {
+		query = query.Filter(elastic.NewNestedQuery("violations", elastic.NewTermQuery("violations.policyId", params.PolicyId)))
+	}
+	err := utils.ScrollLatestReport(ctx, es.IndexNameForUserId(userId, IndexPrefixTagPolicyReport), query,
+		func(hit *elastic.SearchHit) error {
+			var report ResourceReport
+			if err := json.Unmarshal(*hit.Source, &report); err != nil {
+				return err
+			}
+			violations := []Violation{}
+			for _, violation := range report.Violations {
+				if policyIds[violation.PolicyId] {
+					violations = append(violations, violation)
+				}
+			}
+			if len(violations) > 0 {
+				report.Violations = violations
+				reports = append(reports, report)
+			}
+			return nil
+		})
+	if elastic.IsNotFound(err) {
+		return reports, nil
+	}
+	return reports, err
+}
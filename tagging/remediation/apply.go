@@ -0,0 +1,259 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package remediation
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/trackit/jsonlog"
+
+	taws "github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/models"
+)
+
+// remediationStsSessionName is the name of the session used to tag the resources
+const remediationStsSessionName = "trackit-tag-remediation"
+
+// defaultRegion is used for the global resources, whose ARN has no region
+const defaultRegion = "us-east-1"
+
+// Limits of the number of ARNs per call of the Resource Groups Tagging API
+const (
+	getResourcesMaxArns = 100
+	tagResourcesMaxArns = 20
+)
+
+// chunkArns splits a list of ARNs in lists of at most size ARNs
+func chunkArns(arns []string, size int) (chunks [][]string) {
+	for len(arns) > size {
+		chunks = append(chunks, arns[:size])
+		arns = arns[size:]
+	}
+	if len(arns) > 0 {
+		chunks = append(chunks, arns)
+	}
+	return
+}
+
+// groupArnsByRegion groups the ARNs by the region of the resources
+func groupArnsByRegion(arns []string) map[string][]string {
+	regions := make(map[string][]string)
+	for _, resourceArn := range arns {
+		region := defaultRegion
+		if parsedArn, err := arn.Parse(resourceArn); err == nil && parsedArn.Region != "" {
+			region = parsedArn.Region
+		}
+		regions[region] = append(regions[region], resourceArn)
+	}
+	return regions
+}
+
+// getTaggingClient creates a Resource Groups Tagging API client for a region
+func getTaggingClient(creds *credentials.Credentials, region string) *resourcegroupstaggingapi.ResourceGroupsTaggingAPI {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: creds,
+		Region:      aws.String(region),
+	}))
+	return resourcegroupstaggingapi.New(sess)
+}
+
+// getCurrentTags returns the tags of the resources
+// Resources which were never tagged are not returned by the API and have no tags
+func getCurrentTags(svc *resourcegroupstaggingapi.ResourceGroupsTaggingAPI, arns []string) (map[string]map[string]string, error) {
+	tags := make(map[string]map[string]string, len(arns))
+	for _, resourceArn := range arns {
+		tags[resourceArn] = map[string]string{}
+	}
+	for _, chunk := range chunkArns(arns, getResourcesMaxArns) {
+		err := svc.GetResourcesPages(&resourcegroupstaggingapi.GetResourcesInput{
+			ResourceARNList: aws.StringSlice(chunk),
+		}, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+			for _, resource := range page.ResourceTagMappingList {
+				resourceTags := map[string]string{}
+				for _, tag := range resource.Tags {
+					resourceTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+				tags[aws.StringValue(resource.ResourceARN)] = resourceTags
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// applyTags sets the tags on the resources and returns the errors of the resources
+// which could not be tagged
+func applyTags(svc *resourcegroupstaggingapi.ResourceGroupsTaggingAPI, arns []string, tags map[string]string) map[string]string {
+	failures := make(map[string]string)
+	for _, chunk := range chunkArns(arns, tagResourcesMaxArns) {
+		res, err := svc.TagResources(&resourcegroupstaggingapi.TagResourcesInput{
+			ResourceARNList: aws.StringSlice(chunk),
+			Tags:            aws.StringMap(tags),
+		})
+		if err != nil {
+			for _, resourceArn := range chunk {
+				failures[resourceArn] = err.Error()
+			}
+			continue
+		}
+		for resourceArn, failure := range res.FailedResourcesMap {
+			failures[resourceArn] = aws.StringValue(failure.ErrorMessage)
+		}
+	}
+	return failures
+}
+
+// planRegion computes the changes of the resources of a region
+// The resources to tag are pending unless the request is a dry run
+func planRegion(svc *resourcegroupstaggingapi.ResourceGroupsTaggingAPI, arns []string, request Request, remediations []Remediation) []Remediation {
+	tags := request.tagsMap()
+	currentTags, err := getCurrentTags(svc, arns)
+	if err != nil {
+		for i := range remediations {
+			remediations[i].Status = StatusFailed
+			remediations[i].Error = err.Error()
+		}
+		return remediations
+	}
+	for i := range remediations {
+		remediations[i].PreviousTags = currentTags[remediations[i].ResourceArn]
+		remediations[i].Tags = changedTags(remediations[i].PreviousTags, tags)
+		if len(remediations[i].Tags) == 0 {
+			remediations[i].Status = StatusUnchanged
+		} else if request.DryRun {
+			remediations[i].Status = StatusDryRun
+		} else {
+			remediations[i].Status = StatusPending
+		}
+	}
+	return remediations
+}
+
+// applyRegion sets the tags on the pending resources of a region and sets the
+// status of each of them
+func applyRegion(svc *resourcegroupstaggingapi.ResourceGroupsTaggingAPI, request Request, remediations []Remediation) {
+	toApply := []string{}
+	for _, remediation := range remediations {
+		if remediation.Status == StatusPending {
+			toApply = append(toApply, remediation.ResourceArn)
+		}
+	}
+	if len(toApply) == 0 {
+		return
+	}
+	failures := applyTags(svc, toApply, request.tagsMap())
+	for i := range remediations {
+		if remediations[i].Status != StatusPending {
+			continue
+		} else if failure, ok := failures[remediations[i].ResourceArn]; ok {
+			remediations[i].Status = StatusFailed
+			remediations[i].Error = failure
+		} else {
+			remediations[i].Status = StatusApplied
+		}
+	}
+}
+
+// Remediate sets the tags of the request on the resources of an AWS account, using
+// the role of the account, and saves the changes of each resource in the database
+// The changes are saved as pending before the tags are set, then updated with the
+// result of each resource, so db should not be a transaction which can be rolled
+// back once the resources are tagged
+// The request must have been validated
+func Remediate(ctx context.Context, db models.DB, aa taws.AwsAccount, author string, request Request) ([]Remediation, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	creds, err := taws.GetTemporaryCredentials(aa, remediationStsSessionName)
+	if err != nil {
+		logger.Error("Failed to get credentials for tag remediation.", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return nil, err
+	}
+	created := time.Now().UTC()
+	arnsByRegion := groupArnsByRegion(request.Resources)
+	regions := make([]string, 0, len(arnsByRegion))
+	for region := range arnsByRegion {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	clients := make(map[string]*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, len(regions))
+	remediationsByRegion := make(map[string][]Remediation, len(regions))
+	for _, region := range regions {
+		regionRemediations := make([]Remediation, 0, len(arnsByRegion[region]))
+		for _, resourceArn := range arnsByRegion[region] {
+			regionRemediations = append(regionRemediations, Remediation{
+				AwsAccountId: aa.Id,
+				ResourceArn:  resourceArn,
+				DryRun:       request.DryRun,
+				Author:       author,
+				Created:      created,
+			})
+		}
+		clients[region] = getTaggingClient(creds, region)
+		remediationsByRegion[region] = planRegion(clients[region], arnsByRegion[region], request, regionRemediations)
+	}
+	dbRemediations := make(map[string][]models.TagRemediation, len(regions))
+	for _, region := range regions {
+		for i, remediation := range remediationsByRegion[region] {
+			dbRemediation, err := dbRemediationFromRemediation(remediation)
+			if err == nil {
+				err = dbRemediation.Insert(db)
+			}
+			if err != nil {
+				return nil, err
+			}
+			remediationsByRegion[region][i].Id = dbRemediation.ID
+			dbRemediations[region] = append(dbRemediations[region], dbRemediation)
+		}
+	}
+	remediations := make([]Remediation, 0, len(request.Resources))
+	for _, region := range regions {
+		applyRegion(clients[region], request, remediationsByRegion[region])
+		for i, remediation := range remediationsByRegion[region] {
+			dbRemediation := &dbRemediations[region][i]
+			if dbRemediation.Status != remediation.Status {
+				dbRemediation.Status = remediation.Status
+				dbRemediation.Error = remediation.Error
+				if err := dbRemediation.Update(db); err != nil {
+					logger.Error("Failed to save the result of a tag remediation.", map[string]interface{}{
+						"awsAccountId":  aa.Id,
+						"remediationId": dbRemediation.ID,
+						"status":        remediation.Status,
+						"error":         err.Error(),
+					})
+					return nil, err
+				}
+			}
+		}
+		remediations = append(remediations, remediationsByRegion[region]...)
+	}
+	logger.Info("Tag remediation done.", map[string]interface{}{
+		"awsAccountId": aa.Id,
+		"resources":    len(remediations),
+		"dryRun":       request.DryRun,
+	})
+	return remediations, nil
+}
@@ -0,0 +1,62 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package remediation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkArns(t *testing.T) {
+	arns := []string{"a", "b", "c", "d", "e"}
+	cases := []struct {
+		name     string
+		arns     []string
+		size     int
+		expected [][]string
+	}{
+		{"Empty", nil, 2, nil},
+		{"Smaller", arns[:1], 2, [][]string{{"a"}}},
+		{"Exact", arns[:4], 2, [][]string{{"a", "b"}, {"c", "d"}}},
+		{"Remainder", arns, 2, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{"One", arns[:3], 1, [][]string{{"a"}, {"b"}, {"c"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if chunks := chunkArns(tc.arns, tc.size); !reflect.DeepEqual(chunks, tc.expected) {
+				t.Errorf("Chunks are %v, expected %v", chunks, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGroupArnsByRegion(t *testing.T) {
+	instance := "arn:aws:ec2:eu-west-1:123456789012:instance/i-0123456789abcdef0"
+	volume := "arn:aws:ec2:eu-west-1:123456789012:volume/vol-0123456789abcdef0"
+	function := "arn:aws:lambda:us-west-2:123456789012:function:my-function"
+	bucket := "arn:aws:s3:::my-bucket"
+	role := "arn:aws:iam::123456789012:role/my-role"
+	expected := map[string][]string{
+		"eu-west-1":   {instance, volume},
+		"us-west-2":   {function},
+		defaultRegion: {bucket, role},
+	}
+	if regions := groupArnsByRegion([]string{instance, bucket, function, volume, role}); !reflect.DeepEqual(regions, expected) {
+		t.Errorf("ARNs are grouped as %v, expected %v", regions, expected)
+	}
+	if regions := groupArnsByRegion(nil); len(regions) != 0 {
+		t.Errorf("No ARNs are grouped as %v", regions)
+	}
+}
@@ -0,0 +1,184 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package remediation applies tags to the resources of the AWS accounts
+// through the Resource Groups Tagging API and keeps track of the changes.
+package remediation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+
+	"github.com/trackit/trackit/models"
+)
+
+// Statuses of the remediation of a resource
+const (
+	StatusDryRun    = "dryRun"
+	StatusPending   = "pending"
+	StatusApplied   = "applied"
+	StatusUnchanged = "unchanged"
+	StatusFailed    = "failed"
+)
+
+// Limits of the Resource Groups Tagging API
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+	maxTagsCount      = 50
+	maxResourcesCount = 1000
+)
+
+// Tag is a tag to set on the resources
+type Tag struct {
+	Key   string `json:"key" req:"nonzero"`
+	Value string `json:"value"`
+}
+
+// Request lists the resources to tag, identified by their ARN, and the tags to set
+// A dry run reports the changes without applying them
+type Request struct {
+	Resources []string `json:"resources" req:"nonzero"`
+	Tags      []Tag    `json:"tags"      req:"nonzero"`
+	DryRun    bool     `json:"dryRun"`
+}
+
+// Remediation is the change of the tags of a resource
+// Tags only contains the tags which were added or whose value changed
+type Remediation struct {
+	Id           int               `json:"id"`
+	AwsAccountId int               `json:"awsAccountId"`
+	ResourceArn  string            `json:"resourceArn"`
+	PreviousTags map[string]string `json:"previousTags"`
+	Tags         map[string]string `json:"tags"`
+	DryRun       bool              `json:"dryRun"`
+	Status       string            `json:"status"`
+	Error        string            `json:"error"`
+	Author       string            `json:"author"`
+	Created      time.Time         `json:"created"`
+}
+
+// validate checks that the request can be sent to the Resource Groups Tagging API
+// for the resources of an AWS account
+func (r Request) validate(awsIdentity string) error {
+	if len(r.Resources) > maxResourcesCount {
+		return fmt.Errorf("at most %d resources can be tagged at once", maxResourcesCount)
+	} else if len(r.Tags) > maxTagsCount {
+		return fmt.Errorf("at most %d tags can be set at once", maxTagsCount)
+	}
+	resources := make(map[string]bool, len(r.Resources))
+	for _, resource := range r.Resources {
+		resourceArn, err := arn.Parse(resource)
+		if err != nil {
+			return fmt.Errorf("resource %s is not a valid ARN", resource)
+		} else if resourceArn.AccountID != "" && resourceArn.AccountID != awsIdentity {
+			return fmt.Errorf("resource %s does not belong to the AWS account", resource)
+		} else if resources[resource] {
+			return fmt.Errorf("resource %s is set several times", resource)
+		}
+		resources[resource] = true
+	}
+	keys := make(map[string]bool, len(r.Tags))
+	for _, tag := range r.Tags {
+		if len(tag.Key) > maxTagKeyLength {
+			return fmt.Errorf("key %s is longer than %d characters", tag.Key, maxTagKeyLength)
+		} else if len(tag.Value) > maxTagValueLength {
+			return fmt.Errorf("value of key %s is longer than %d characters", tag.Key, maxTagValueLength)
+		} else if strings.HasPrefix(strings.ToLower(tag.Key), "aws:") {
+			return errors.New("keys starting with aws: are reserved")
+		} else if keys[tag.Key] {
+			return fmt.Errorf("key %s is set several times", tag.Key)
+		}
+		keys[tag.Key] = true
+	}
+	return nil
+}
+
+// tagsMap returns the tags of the request as a map
+func (r Request) tagsMap() map[string]string {
+	tags := make(map[string]string, len(r.Tags))
+	for _, tag := range r.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags
+}
+
+// changedTags returns the tags which are missing or have another value in the previous tags
+func changedTags(previousTags, tags map[string]string) map[string]string {
+	changed := make(map[string]string)
+	for key, value := range tags {
+		if previousValue, ok := previousTags[key]; !ok || previousValue != value {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+// remediationFromDbRemediation builds a Remediation from its database row
+func remediationFromDbRemediation(dbRemediation models.TagRemediation) (Remediation, error) {
+	remediation := Remediation{
+		Id:           dbRemediation.ID,
+		AwsAccountId: dbRemediation.AwsAccountID,
+		ResourceArn:  dbRemediation.ResourceArn,
+		DryRun:       dbRemediation.DryRun,
+		Status:       dbRemediation.Status,
+		Error:        dbRemediation.Error,
+		Author:       dbRemediation.Author,
+		Created:      dbRemediation.Created,
+	}
+	if err := json.Unmarshal(dbRemediation.PreviousTags, &remediation.PreviousTags); err != nil {
+		return remediation, err
+	}
+	err := json.Unmarshal(dbRemediation.Tags, &remediation.Tags)
+	return remediation, err
+}
+
+// dbRemediationFromRemediation builds the database row of a Remediation
+func dbRemediationFromRemediation(remediation Remediation) (dbRemediation models.TagRemediation, err error) {
+	dbRemediation = models.TagRemediation{
+		AwsAccountID: remediation.AwsAccountId,
+		ResourceArn:  remediation.ResourceArn,
+		DryRun:       remediation.DryRun,
+		Status:       remediation.Status,
+		Error:        remediation.Error,
+		Author:       remediation.Author,
+		Created:      remediation.Created,
+	}
+	if dbRemediation.PreviousTags, err = json.Marshal(remediation.PreviousTags); err == nil {
+		dbRemediation.Tags, err = json.Marshal(remediation.Tags)
+	}
+	return
+}
+
+// GetRemediations returns the remediations of an AWS account, including dry runs
+func GetRemediations(db models.DB, awsAccountId int) ([]Remediation, error) {
+	dbRemediations, err := models.TagRemediationByAwsAccountID(db, awsAccountId)
+	if err != nil {
+		return nil, err
+	}
+	remediations := make([]Remediation, 0, len(dbRemediations))
+	for _, dbRemediation := range dbRemediations {
+		remediation, err := remediationFromDbRemediation(*dbRemediation)
+		if err != nil {
+			return nil, err
+		}
+		remediations = append(remediations, remediation)
+	}
+	return remediations, nil
+}
@@ -0,0 +1,104 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package remediation
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/aws"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getRemediations).With(
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			aws.RequireAwsAccountId{},
+			routes.Documentation{
+				Summary:     "get the tag remediations of an aws account",
+				Description: "Responds with the audit trail of the tags set on the resources of an AWS account, including dry runs.",
+			},
+			users.RequirePermission{users.PermissionViewCosts},
+		),
+		http.MethodPost: routes.H(postRemediation).With(
+			users.RequireAuthenticatedUser{users.ViewerCannot},
+			aws.RequireAwsAccountId{},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{Request{
+				Resources: []string{"arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0"},
+				Tags:      []Tag{{Key: "env", Value: "prod"}},
+				DryRun:    true,
+			}},
+			routes.Documentation{
+				Summary:     "tag resources",
+				Description: "Sets the tags on the resources, identified by their ARN, with the role of the AWS account. A dry run responds with the changes without applying them.",
+			},
+			users.RequirePermission{users.PermissionManageTagging},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.QueryArgs{routes.AwsAccountIdQueryArg},
+		routes.Documentation{
+			Summary:     "remediate the tags of resources",
+			Description: "Tags are written through the Resource Groups Tagging API. Every change is recorded with its author and the previous tags of the resource.",
+		},
+	).Register("/tagging/remediation")
+}
+
+// getRemediations is a route handler which returns the remediations of an AWS account.
+func getRemediations(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	remediations, err := GetRemediations(tx, aa.Id)
+	if err != nil {
+		l.Error("Failed to get tag remediations.", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve tag remediations.")
+	}
+	return http.StatusOK, remediations
+}
+
+// postRemediation is a route handler which tags resources.
+func postRemediation(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	var body Request
+	routes.MustRequestBody(a, &body)
+	user := a[users.AuthenticatedUser].(users.User)
+	aa := a[aws.AwsAccountSelection].(aws.AwsAccount)
+	if err := body.validate(aa.AwsIdentity); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	// The changes are recorded outside of the request transaction so that the
+	// audit trail is kept even if the request fails after the resources are tagged
+	remediations, err := Remediate(r.Context(), db.Db, aa, user.Email, body)
+	if err != nil {
+		l.Error("Failed to remediate tags.", map[string]interface{}{
+			"awsAccountId": aa.Id,
+			"error":        err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to tag resources.")
+	}
+	return http.StatusOK, remediations
+}
@@ -0,0 +1,84 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package remediation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testAwsIdentity = "123456789012"
+
+func TestRequestValidate(t *testing.T) {
+	instance := "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0"
+	bucket := "arn:aws:s3:::my-bucket"
+	tooManyResources := make([]string, maxResourcesCount+1)
+	for i := range tooManyResources {
+		tooManyResources[i] = fmt.Sprintf("arn:aws:ec2:us-east-1:123456789012:volume/vol-%d", i)
+	}
+	tooManyTags := make([]Tag, maxTagsCount+1)
+	for i := range tooManyTags {
+		tooManyTags[i] = Tag{Key: fmt.Sprintf("key%d", i)}
+	}
+	cases := []struct {
+		name    string
+		request Request
+		fails   bool
+	}{
+		{"Valid", Request{Resources: []string{instance, bucket}, Tags: []Tag{{"env", "prod"}, {"team", ""}}}, false},
+		{"MaxLengths", Request{Resources: []string{instance}, Tags: []Tag{{strings.Repeat("k", maxTagKeyLength), strings.Repeat("v", maxTagValueLength)}}}, false},
+		{"TooManyResources", Request{Resources: tooManyResources, Tags: []Tag{{"env", "prod"}}}, true},
+		{"TooManyTags", Request{Resources: []string{instance}, Tags: tooManyTags}, true},
+		{"InvalidArn", Request{Resources: []string{"i-0123456789abcdef0"}, Tags: []Tag{{"env", "prod"}}}, true},
+		{"OtherAccount", Request{Resources: []string{"arn:aws:ec2:us-east-1:210987654321:instance/i-0123456789abcdef0"}, Tags: []Tag{{"env", "prod"}}}, true},
+		{"DuplicateResource", Request{Resources: []string{instance, instance}, Tags: []Tag{{"env", "prod"}}}, true},
+		{"KeyTooLong", Request{Resources: []string{instance}, Tags: []Tag{{strings.Repeat("k", maxTagKeyLength+1), "prod"}}}, true},
+		{"ValueTooLong", Request{Resources: []string{instance}, Tags: []Tag{{"env", strings.Repeat("v", maxTagValueLength+1)}}}, true},
+		{"ReservedKey", Request{Resources: []string{instance}, Tags: []Tag{{"AWS:cloudformation", "prod"}}}, true},
+		{"DuplicateKey", Request{Resources: []string{instance}, Tags: []Tag{{"env", "prod"}, {"env", "dev"}}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.request.validate(testAwsIdentity); (err != nil) != tc.fails {
+				t.Errorf("Validation returned %v, expected failure: %t", err, tc.fails)
+			}
+		})
+	}
+}
+
+func TestChangedTags(t *testing.T) {
+	cases := []struct {
+		name         string
+		previousTags map[string]string
+		tags         map[string]string
+		expected     map[string]string
+	}{
+		{"Untagged", nil, map[string]string{"env": "prod"}, map[string]string{"env": "prod"}},
+		{"Unchanged", map[string]string{"env": "prod", "team": "a"}, map[string]string{"env": "prod"}, map[string]string{}},
+		{"ValueChanged", map[string]string{"env": "dev"}, map[string]string{"env": "prod"}, map[string]string{"env": "prod"}},
+		{"EmptyValue", map[string]string{"env": "prod"}, map[string]string{"env": ""}, map[string]string{"env": ""}},
+		{"CaseSensitiveKeys", map[string]string{"Env": "prod"}, map[string]string{"env": "prod"}, map[string]string{"env": "prod"}},
+		{"Mixed", map[string]string{"env": "prod", "team": "a"}, map[string]string{"env": "prod", "team": "b", "owner": "c"}, map[string]string{"team": "b", "owner": "c"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if changed := changedTags(tc.previousTags, tc.tags); !reflect.DeepEqual(changed, tc.expected) {
+				t.Errorf("Changed tags are %v, expected %v", changed, tc.expected)
+			}
+		})
+	}
+}
@@ -53,6 +53,7 @@ func init() {
 				Summary:     "get most used tags",
 				Description: "Responds with most used tags for a user.",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/tagging/mostusedtags")
 
@@ -64,6 +65,7 @@ func init() {
 				Summary:     "get most used tags history",
 				Description: "Responds with most used tags history of a user.",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/tagging/mostusedtags-history")
 
@@ -76,6 +78,7 @@ func init() {
 				Summary:     "get tagging compliance",
 				Description: "Responds with tagging compliance data in a specified range",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/tagging/compliance")
 
@@ -89,6 +92,7 @@ func init() {
 				Summary:     "get list of resources",
 				Description: "Responds with the list of resources based on the request body passed to it",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/tagging/resources")
 
@@ -101,6 +105,7 @@ func init() {
 				Summary:     "get suggestions for a tag's value",
 				Description: "Responds with suggestions for a tag's value for a user.",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/tagging/suggestions/tag-value")
 
@@ -112,6 +117,7 @@ func init() {
 				Summary:     "get Tagbot access",
 				Description: "Returns whether or not to display subscription popup",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/tagging/should-popup")
 	routes.MethodMuxer{
@@ -124,6 +130,7 @@ func init() {
 				Summary:     "Create a stripe customer",
 				Description: "Responds with customer information",
 			},
+			users.RequirePermission{users.PermissionBillingAdmin},
 		),
 	}.H().Register("/tagging/create-customer")
 
@@ -137,6 +144,7 @@ func init() {
 				Summary:     "Create stripe payment method",
 				Description: "Responds with payment method information",
 			},
+			users.RequirePermission{users.PermissionBillingAdmin},
 		),
 	}.H().Register("/tagging/create-subscription")
 
@@ -150,6 +158,7 @@ func init() {
 				Summary:     "Handle retry invoice",
 				Description: "Updates stripe customer with new payment method",
 			},
+			users.RequirePermission{users.PermissionBillingAdmin},
 		),
 	}.H().Register("/tagging/retry-invoice")
 
@@ -163,6 +172,7 @@ func init() {
 				Summary:     "Cancel subscription",
 				Description: "Cancels customer subscription",
 			},
+			users.RequirePermission{users.PermissionBillingAdmin},
 		),
 	}.H().Register("/tagging/cancel-subscription")
 
@@ -176,6 +186,7 @@ func init() {
 				Summary:     "Retrieve subscription",
 				Description: "Retrieves customer subscription information",
 			},
+			users.RequirePermission{users.PermissionBillingAdmin},
 		),
 	}.H().Register("/tagging/retrieve-subscription")
 
@@ -189,6 +200,7 @@ func init() {
 				Summary:     "Change payment method",
 				Description: "Changes customer payment method",
 			},
+			users.RequirePermission{users.PermissionBillingAdmin},
 		),
 	}.H().Register("/tagging/change-payment-method")
 
@@ -200,6 +212,7 @@ func init() {
 				Summary:     "get stripe customer information",
 				Description: "Returns stripe customer information",
 			},
+			users.RequirePermission{users.PermissionBillingAdmin},
 		),
 	}.H().Register("/tagging/stripe-customer-information")
 }
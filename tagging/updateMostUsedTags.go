@@ -27,6 +27,7 @@ import (
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/es"
 	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/tagging/aliases"
 )
 
 var ignoredTagsRegexp = []string{
@@ -38,6 +39,7 @@ var ignoredTagsRegexp = []string{
 }
 
 // UpdateMostUsedTagsForUser updates most used tags in MySQL for the specified user
+// Tag keys are counted under their canonical key when they have aliases
 func UpdateMostUsedTagsForUser(ctx context.Context, userId int) error {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 
@@ -88,14 +90,25 @@ func getMostUsedTagsForUser(ctx context.Context, userId int, ignoredTagsRegexp [
 		return []string{}, nil
 	}
 
+	resolver, err := aliases.GetResolverForUser(db.Db, userId)
+	if err != nil {
+		return nil, err
+	}
+
 	filterQueries := getFilterQueriesFromIgnoredTags(ignoredTagsRegexp)
+	keysAggregation := elastic.NewTermsAggregation().Size(5)
+	if script := resolver.KeyScript("tags.key"); script != nil {
+		keysAggregation = keysAggregation.Script(script)
+	} else {
+		keysAggregation = keysAggregation.Field("tags.key")
+	}
 
 	index := client.Search().Index(indexName)
 	res, err := index.Size(0).Query(elastic.NewMatchAllQuery()).
 		Aggregation("reportDate", elastic.NewTermsAggregation().Field("reportDate").Order("_term", false).Size(1).
 			SubAggregation("nested", elastic.NewNestedAggregation().Path("tags").
 				SubAggregation("filter", elastic.NewFilterAggregation().Filter(elastic.NewBoolQuery().MustNot(filterQueries...)).
-					SubAggregation("terms", elastic.NewTermsAggregation().Field("tags.key").Size(5))))).Do(ctx)
+					SubAggregation("terms", keysAggregation)))).Do(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -27,16 +27,22 @@ import (
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/es"
 	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/tagging/aliases"
+	"github.com/trackit/trackit/tagging/policies"
 )
 
 type ComplianceReport struct {
-	ReportDate      time.Time `json:"reportDate"`
-	Total           int64     `json:"total"`
-	TotallyTagged   int64     `json:"totallyTagged"`
-	PartiallyTagged int64     `json:"partiallyTagged"`
-	NotTagged       int64     `json:"notTagged"`
-	MostUsedTagsId  string    `json:"mostUsedTagsId"`
-	MostUsedTags    []string  `json:"mostUsedTags"`
+	ReportDate         time.Time                 `json:"reportDate"`
+	Total              int64                     `json:"total"`
+	TotallyTagged      int64                     `json:"totallyTagged"`
+	PartiallyTagged    int64                     `json:"partiallyTagged"`
+	NotTagged          int64                     `json:"notTagged"`
+	MostUsedTagsId     string                    `json:"mostUsedTagsId"`
+	MostUsedTags       []string                  `json:"mostUsedTags"`
+	Policies           policies.Summary          `json:"policies"`
+	Cost               CostCompliance            `json:"cost"`
+	CostByAccount      []CostComplianceBreakdown `json:"costByAccount"`
+	CostByResourceType []CostComplianceBreakdown `json:"costByResourceType"`
 }
 
 const invalidMostUsedTagsId = "-1"
@@ -52,6 +58,11 @@ func UpdateTaggingComplianceForUser(ctx context.Context, userId int) error {
 		return errors.New("no most used tags data available")
 	}
 
+	resolver, err := aliases.GetResolverForUser(db.Db, userId)
+	if err != nil {
+		return err
+	}
+
 	count, err := getReportsCount(ctx, userId)
 	if err != nil {
 		return err
@@ -62,7 +73,7 @@ func UpdateTaggingComplianceForUser(ctx context.Context, userId int) error {
 			"userId": userId,
 		})
 
-		return pushComplianceToEs(ctx, userId, ComplianceReport{
+		return pushComplianceToEs(ctx, userId, resolver, ComplianceReport{
 			Total:           count,
 			TotallyTagged:   0,
 			PartiallyTagged: 0,
@@ -73,19 +84,19 @@ func UpdateTaggingComplianceForUser(ctx context.Context, userId int) error {
 		})
 	}
 
-	totallyTagged, err := getTotallyTaggedReportsCount(ctx, userId, mostUsedTags)
+	totallyTagged, err := getTotallyTaggedReportsCount(ctx, userId, mostUsedTags, resolver)
 	if err != nil {
 		return err
 	}
 
-	untagged, err := getNotTaggedReportsCount(ctx, userId, mostUsedTags)
+	untagged, err := getNotTaggedReportsCount(ctx, userId, mostUsedTags, resolver)
 	if err != nil {
 		return err
 	}
 
 	partiallyTagged := count - totallyTagged - untagged
 
-	return pushComplianceToEs(ctx, userId, ComplianceReport{
+	return pushComplianceToEs(ctx, userId, resolver, ComplianceReport{
 		Total:           count,
 		TotallyTagged:   totallyTagged,
 		PartiallyTagged: partiallyTagged,
@@ -113,10 +124,10 @@ func getMostUsedTagsFromDb(userId int) ([]string, string, error) {
 	return res, strconv.Itoa(mostUsedTags.ID), nil
 }
 
-func mostUsedTagsToTermQueries(mostUsedTags []string) []elastic.Query {
+func mostUsedTagsToTermQueries(mostUsedTags []string, resolver aliases.Resolver) []elastic.Query {
 	termQueries := []elastic.Query{}
 	for _, mostUsedTag := range mostUsedTags {
-		termQueries = append(termQueries, elastic.NewNestedQuery("tags", elastic.NewBoolQuery().Must(elastic.NewTermQuery("tags.key", mostUsedTag))))
+		termQueries = append(termQueries, elastic.NewNestedQuery("tags", elastic.NewBoolQuery().Must(resolver.KeysQuery("tags.key", mostUsedTag))))
 	}
 	return termQueries
 }
@@ -144,12 +155,12 @@ func getReportsCount(ctx context.Context, userId int) (int64, error) {
 	return handleComplianceEsReponse(res, err)
 }
 
-func getTotallyTaggedReportsCount(ctx context.Context, userId int, mostUsedTags []string) (int64, error) {
+func getTotallyTaggedReportsCount(ctx context.Context, userId int, mostUsedTags []string, resolver aliases.Resolver) (int64, error) {
 	client := es.Client
 	indexName := es.IndexNameForUserId(userId, "tagging-reports")
 	index := client.Search().Index(indexName)
 
-	termQueries := mostUsedTagsToTermQueries(mostUsedTags)
+	termQueries := mostUsedTagsToTermQueries(mostUsedTags, resolver)
 	query := elastic.NewBoolQuery().Must(termQueries...)
 
 	res, err := index.Size(0).Query(query).
@@ -157,12 +168,12 @@ func getTotallyTaggedReportsCount(ctx context.Context, userId int, mostUsedTags
 	return handleComplianceEsReponse(res, err)
 }
 
-func getNotTaggedReportsCount(ctx context.Context, userId int, mostUsedTags []string) (int64, error) {
+func getNotTaggedReportsCount(ctx context.Context, userId int, mostUsedTags []string, resolver aliases.Resolver) (int64, error) {
 	client := es.Client
 	indexName := es.IndexNameForUserId(userId, "tagging-reports")
 	index := client.Search().Index(indexName)
 
-	termQueries := mostUsedTagsToTermQueries(mostUsedTags)
+	termQueries := mostUsedTagsToTermQueries(mostUsedTags, resolver)
 	query := elastic.NewBoolQuery().MustNot(termQueries...)
 
 	res, err := index.Size(0).Query(query).
@@ -170,11 +181,29 @@ func getNotTaggedReportsCount(ctx context.Context, userId int, mostUsedTags []st
 	return handleComplianceEsReponse(res, err)
 }
 
-func pushComplianceToEs(ctx context.Context, userId int, compliance ComplianceReport) error {
+func pushComplianceToEs(ctx context.Context, userId int, resolver aliases.Resolver, compliance ComplianceReport) error {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	policiesSummary, err := policies.EvaluatePoliciesForUser(ctx, userId)
+	if err != nil {
+		logger.Error("Failed to evaluate tag policies.", map[string]interface{}{
+			"userId": userId,
+			"error":  err.Error(),
+		})
+	}
+	compliance.Policies = policiesSummary
+	costCompliance, err := getCostCompliance(ctx, userId, compliance.MostUsedTags, resolver)
+	if err != nil {
+		logger.Error("Failed to get tagging compliance spend.", map[string]interface{}{
+			"userId": userId,
+			"error":  err.Error(),
+		})
+	}
+	compliance.Cost = costCompliance.Cost
+	compliance.CostByAccount = costCompliance.CostByAccount
+	compliance.CostByResourceType = costCompliance.CostByResourceType
 	client := es.Client
 	indexName := es.IndexNameForUserId(userId, "tagging-compliance")
-	_, err := client.Index().Index(indexName).Type("tagging-compliance").BodyJson(compliance).Do(ctx)
+	_, err = client.Index().Index(indexName).Type("tagging-compliance").BodyJson(compliance).Do(ctx)
 
 	if err == nil {
 		logger.Info("Tagging compliance pushed to ES.", map[string]interface{}{
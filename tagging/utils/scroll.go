@@ -0,0 +1,66 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/olivere/elastic"
+
+	"github.com/trackit/trackit/es"
+)
+
+const scrollSize = 1000
+
+// GetLatestReportDate returns the date of the latest report of an index, as it is stored
+// The date is empty if the index has no document
+func GetLatestReportDate(ctx context.Context, index string) (string, error) {
+	res, err := es.Client.Search().Index(index).Size(1).Sort("reportDate", false).
+		FetchSourceContext(elastic.NewFetchSourceContext(true).Include("reportDate")).Do(ctx)
+	if err != nil || len(res.Hits.Hits) == 0 {
+		return "", err
+	}
+	var source struct {
+		ReportDate string `json:"reportDate"`
+	}
+	err = json.Unmarshal(*res.Hits.Hits[0].Source, &source)
+	return source.ReportDate, err
+}
+
+// ScrollLatestReport calls fn with each document of the latest report of an index
+// which matches the query
+func ScrollLatestReport(ctx context.Context, index string, query *elastic.BoolQuery, fn func(*elastic.SearchHit) error) error {
+	reportDate, err := GetLatestReportDate(ctx, index)
+	if err != nil || reportDate == "" {
+		return err
+	}
+	scroll := es.Client.Scroll(index).Size(scrollSize).Query(query.Filter(elastic.NewTermQuery("reportDate", reportDate)))
+	defer scroll.Clear(context.Background())
+	for {
+		res, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for _, hit := range res.Hits.Hits {
+			if err := fn(hit); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -59,6 +59,7 @@ func init() {
 				Summary:     "get the list of EBS snapshots",
 				Description: "Responds with the list of EBS snapshots based on the queryparams passed to it",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/ebs")
 }
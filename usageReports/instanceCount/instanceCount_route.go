@@ -59,6 +59,7 @@ func init() {
 				Summary:     "get the list of InstanceCount",
 				Description: "Responds with the list of InstanceCount based on the queryparams passed to it",
 			},
+			users.RequirePermission{users.PermissionViewCosts},
 		),
 	}.H().Register("/instanceCount")
 }
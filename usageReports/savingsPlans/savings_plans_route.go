@@ -0,0 +1,78 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package savingsPlans
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/trackit/trackit/cache"
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+type (
+	// SavingsPlansQueryParams will store the parsed query params
+	SavingsPlansQueryParams struct {
+		AccountList []string
+		IndexList   []string
+		Date        time.Time
+	}
+)
+
+var (
+	// savingsPlansQueryArgs allows to get required queryArgs params
+	savingsPlansQueryArgs = []routes.QueryArg{
+		routes.AwsAccountsOptionalQueryArg,
+		routes.DateQueryArg,
+	}
+)
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getSavingsPlans).With(
+			db.RequestTransaction{Db: db.Db},
+			users.RequireAuthenticatedUser{users.ViewerAsParent},
+			routes.QueryArgs(savingsPlansQueryArgs),
+			users.RequirePermission{users.PermissionViewCosts},
+			cache.UsersCache{},
+			routes.Documentation{
+				Summary:     "get the Savings Plans coverage and utilization",
+				Description: "Responds with the Savings Plans coverage, utilization and unused commitment of each account for the month of the date passed as query param",
+			},
+		),
+	}.H().Register("/savingsplans")
+}
+
+// getSavingsPlans returns the Savings Plans reports based on the query params, in JSON format.
+func getSavingsPlans(request *http.Request, a routes.Arguments) (int, interface{}) {
+	user := a[users.AuthenticatedUser].(users.User)
+	tx := a[db.Transaction].(*sql.Tx)
+	parsedParams := SavingsPlansQueryParams{
+		AccountList: []string{},
+		Date:        a[routes.DateQueryArg].(time.Time),
+	}
+	if a[routes.AwsAccountsOptionalQueryArg] != nil {
+		parsedParams.AccountList = a[routes.AwsAccountsOptionalQueryArg].([]string)
+	}
+	returnCode, report, err := GetSavingsPlansData(request.Context(), parsedParams, user, tx)
+	if err != nil {
+		return returnCode, err
+	} else {
+		return returnCode, report
+	}
+}
@@ -0,0 +1,253 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package users
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+)
+
+const (
+	// ApiTokenScopeRead only allows the safe HTTP methods.
+	ApiTokenScopeRead = "read"
+	// ApiTokenScopeReadWrite allows all the routes available to the user.
+	ApiTokenScopeReadWrite = "readWrite"
+
+	apiTokenPrefix       = "trackit_"
+	apiTokenRandomBytes  = 32
+	maxApiTokensPerUser  = 50
+	apiTokenLastUsedStep = time.Minute
+)
+
+var (
+	ErrInvalidApiToken  = errors.New("API token is invalid, expired or revoked")
+	ErrReadOnlyApiToken = errors.New("API token is read-only")
+)
+
+// ApiToken is a long-lived token a user creates to access the API without
+// their password. It can be read-only and restricted to some AWS accounts,
+// all of them being available if AwsAccounts is empty.
+type ApiToken struct {
+	Id          int        `json:"id"`
+	Name        string     `json:"name"        req:"nonzero"`
+	Scope       string     `json:"scope"`
+	AwsAccounts []int      `json:"awsAccounts"`
+	Expires     *time.Time `json:"expires,omitempty"`
+	Created     time.Time  `json:"created"`
+	LastUsed    *time.Time `json:"lastUsed,omitempty"`
+}
+
+// createdApiToken is an API token along with its secret, which is only
+// returned when it is created.
+type createdApiToken struct {
+	ApiToken
+	Token string `json:"token"`
+}
+
+// isApiToken tells whether an Authorization header holds an API token
+// rather than a JWT.
+func isApiToken(tokenString string) bool {
+	return len(tokenString) > len(apiTokenPrefix) && tokenString[:len(apiTokenPrefix)] == apiTokenPrefix
+}
+
+// hashApiToken returns the hash of an API token, which is the only form in
+// which it is stored.
+func hashApiToken(tokenString string) string {
+	hash := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(hash[:])
+}
+
+// generateApiToken returns a new random API token.
+func generateApiToken() (string, error) {
+	var random [apiTokenRandomBytes]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + hex.EncodeToString(random[:]), nil
+}
+
+// allowsMethod tells whether the scope of a token allows an HTTP method.
+func (t ApiToken) allowsMethod(method string) bool {
+	if t.Scope == ApiTokenScopeReadWrite {
+		return true
+	}
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// validate checks an API token before its creation. awsAccounts are the
+// ids of the AWS accounts available to the user.
+func (t *ApiToken) validate(awsAccounts []int) error {
+	if t.Name == "" || len(t.Name) > 255 {
+		return errors.New("name must be between 1 and 255 characters")
+	} else if t.Scope == "" {
+		t.Scope = ApiTokenScopeRead
+	} else if t.Scope != ApiTokenScopeRead && t.Scope != ApiTokenScopeReadWrite {
+		return fmt.Errorf("scope %s does not exist", t.Scope)
+	}
+	if t.Expires != nil && !t.Expires.After(time.Now()) {
+		return errors.New("expires must be in the future")
+	}
+	for _, id := range t.AwsAccounts {
+		found := false
+		for _, available := range awsAccounts {
+			found = found || available == id
+		}
+		if !found {
+			return fmt.Errorf("AWS account %d is not available", id)
+		}
+	}
+	return nil
+}
+
+// apiTokenFromDbApiToken builds an ApiToken from its database row
+func apiTokenFromDbApiToken(dbToken models.ApiToken) (ApiToken, error) {
+	token := ApiToken{
+		Id:      dbToken.ID,
+		Name:    dbToken.Name,
+		Scope:   dbToken.Scope,
+		Created: dbToken.Created,
+	}
+	if dbToken.Expires.Valid {
+		token.Expires = &dbToken.Expires.Time
+	}
+	if dbToken.LastUsed.Valid {
+		token.LastUsed = &dbToken.LastUsed.Time
+	}
+	err := json.Unmarshal(dbToken.AwsAccounts, &token.AwsAccounts)
+	return token, err
+}
+
+// createApiToken creates an API token for a user and returns it along with
+// its secret.
+func createApiToken(db models.DB, user User, token ApiToken) (createdApiToken, error) {
+	tokenString, err := generateApiToken()
+	if err != nil {
+		return createdApiToken{}, err
+	}
+	if token.AwsAccounts == nil {
+		token.AwsAccounts = []int{}
+	}
+	dbToken := models.ApiToken{
+		UserID:    user.Id,
+		Name:      token.Name,
+		TokenHash: hashApiToken(tokenString),
+		Scope:     token.Scope,
+		Created:   time.Now().UTC(),
+	}
+	if token.Expires != nil {
+		dbToken.Expires = mysql.NullTime{Time: token.Expires.UTC(), Valid: true}
+	}
+	if dbToken.AwsAccounts, err = json.Marshal(token.AwsAccounts); err != nil {
+		return createdApiToken{}, err
+	}
+	if err = dbToken.Insert(db); err != nil {
+		return createdApiToken{}, err
+	}
+	token, err = apiTokenFromDbApiToken(dbToken)
+	return createdApiToken{token, tokenString}, err
+}
+
+// GetApiTokensForUser returns the API tokens of a user.
+func GetApiTokensForUser(db models.DB, userId int) ([]ApiToken, error) {
+	dbTokens, err := models.ApiTokenByUserID(db, userId)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]ApiToken, 0, len(dbTokens))
+	for _, dbToken := range dbTokens {
+		token, err := apiTokenFromDbApiToken(*dbToken)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// recordApiTokenUse records the last use of an API token.
+func recordApiTokenUse(db models.DB, tokenId int, used time.Time) error {
+	const sqlstr = `UPDATE api_token SET
+		last_used=?
+	WHERE id=?`
+	_, err := db.Exec(sqlstr, used, tokenId)
+	return err
+}
+
+// testApiToken checks whether an API token is valid and retrieves it along
+// with the owning User, restricted to the AWS accounts of the token. The
+// last use of the token is recorded at most once per apiTokenLastUsedStep,
+// outside of the request transaction so that it is kept even if the request
+// fails.
+func testApiToken(tx *sql.Tx, tokenString string) (User, ApiToken, error) {
+	dbToken, err := models.ApiTokenByTokenHash(tx, hashApiToken(tokenString))
+	if err == sql.ErrNoRows {
+		return User{}, ApiToken{}, ErrInvalidApiToken
+	} else if err != nil {
+		return User{}, ApiToken{}, err
+	}
+	now := time.Now().UTC()
+	if dbToken.Expires.Valid && !now.Before(dbToken.Expires.Time) {
+		return User{}, ApiToken{}, ErrInvalidApiToken
+	}
+	if !dbToken.LastUsed.Valid || now.Sub(dbToken.LastUsed.Time) >= apiTokenLastUsedStep {
+		if err = recordApiTokenUse(db.Db, dbToken.ID, now); err != nil {
+			return User{}, ApiToken{}, err
+		}
+		dbToken.LastUsed = mysql.NullTime{Time: now, Valid: true}
+	}
+	token, err := apiTokenFromDbApiToken(*dbToken)
+	if err != nil {
+		return User{}, ApiToken{}, err
+	}
+	user, err := GetUserWithId(tx, dbToken.UserID)
+	if err != nil {
+		return User{}, ApiToken{}, err
+	}
+	user.AwsAccountAllowlist = token.AwsAccounts
+	return user, token, nil
+}
+
+// getAvailableAwsAccounts returns the ids of the AWS accounts of a user,
+// including the accounts shared with them.
+func getAvailableAwsAccounts(db models.DB, user User) ([]int, error) {
+	dbAwsAccounts, err := models.AwsAccountByUserID(db, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	dbSharedAccounts, err := models.SharedAccountByUserID(db, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(dbAwsAccounts)+len(dbSharedAccounts))
+	for _, dbAwsAccount := range dbAwsAccounts {
+		ids = append(ids, dbAwsAccount.ID)
+	}
+	for _, dbSharedAccount := range dbSharedAccounts {
+		ids = append(ids, dbSharedAccount.AccountID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,165 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+)
+
+// apiTokenIdQueryArg allows to get the ID of an API token in the URL parameters.
+var apiTokenIdQueryArg = routes.QueryArg{
+	Name:        "token",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of an API token.",
+}
+
+func init() {
+	expires := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getApiTokens).With(
+			RequireAuthenticatedUser{ViewerAsSelf},
+			routes.Documentation{
+				Summary:     "get the api tokens",
+				Description: "Responds with the API tokens of the user, without their secret.",
+			},
+		),
+		http.MethodPost: routes.H(postApiToken).With(
+			RequireAuthenticatedUser{ViewerAsSelf},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{ApiToken{
+				Name:        "ci",
+				Scope:       ApiTokenScopeRead,
+				AwsAccounts: []int{1},
+				Expires:     &expires,
+			}},
+			routes.Documentation{
+				Summary:     "create an api token",
+				Description: "Creates an API token and responds with its secret, which can not be retrieved later. The token is used in the Authorization header like a login token.",
+			},
+		),
+		http.MethodDelete: routes.H(deleteApiToken).With(
+			RequireAuthenticatedUser{ViewerAsSelf},
+			routes.QueryArgs{apiTokenIdQueryArg},
+			routes.Documentation{
+				Summary:     "revoke an api token",
+				Description: "Revokes an API token, which can not be used anymore.",
+			},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.Documentation{
+			Summary:     "interact with the api tokens",
+			Description: "API tokens give machines access to the API without a password. They can be read-only, restricted to some AWS accounts and expire. They can not manage API tokens.",
+		},
+	).Register("/user/tokens")
+}
+
+// authenticatedWithApiToken tells whether the request was authenticated
+// with an API token rather than a login.
+func authenticatedWithApiToken(a routes.Arguments) bool {
+	_, ok := a[AuthenticatedApiToken]
+	return ok
+}
+
+// getApiTokens is a route handler which returns the API tokens of the user.
+func getApiTokens(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[AuthenticatedUser].(User)
+	tokens, err := GetApiTokensForUser(tx, user.Id)
+	if err != nil {
+		l.Error("Failed to get API tokens.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve API tokens.")
+	}
+	return http.StatusOK, tokens
+}
+
+// postApiToken is a route handler which creates an API token. The token
+// belongs to the authenticated user, viewers included, so its allowlist can
+// only hold the AWS accounts of that user.
+func postApiToken(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body ApiToken
+	routes.MustRequestBody(a, &body)
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[AuthenticatedUser].(User)
+	if authenticatedWithApiToken(a) {
+		return http.StatusForbidden, errors.New("API tokens can not create API tokens.")
+	}
+	awsAccounts, err := getAvailableAwsAccounts(tx, user)
+	if err != nil {
+		l.Error("Failed to get AWS accounts.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to create API token.")
+	}
+	if err := body.validate(awsAccounts); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	if dbTokens, err := models.ApiTokenByUserID(tx, user.Id); err != nil {
+		l.Error("Failed to get API tokens.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to create API token.")
+	} else if len(dbTokens) >= maxApiTokensPerUser {
+		return http.StatusBadRequest, fmt.Errorf("A user can not have more than %d API tokens.", maxApiTokensPerUser)
+	}
+	token, err := createApiToken(tx, user, body)
+	if err != nil {
+		l.Error("Failed to create API token.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to create API token.")
+	}
+	return http.StatusOK, token
+}
+
+// deleteApiToken is a route handler which revokes an API token.
+func deleteApiToken(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[AuthenticatedUser].(User)
+	if authenticatedWithApiToken(a) {
+		return http.StatusForbidden, errors.New("API tokens can not revoke API tokens.")
+	}
+	dbToken, err := models.ApiTokenByID(tx, a[apiTokenIdQueryArg].(int))
+	if err != nil || dbToken.UserID != user.Id {
+		return http.StatusNotFound, errors.New("API token not found.")
+	}
+	if err := dbToken.Delete(tx); err != nil {
+		l.Error("Failed to revoke API token.", map[string]interface{}{
+			"tokenId": dbToken.ID,
+			"error":   err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to revoke API token.")
+	}
+	return http.StatusOK, nil
+}
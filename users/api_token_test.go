@@ -0,0 +1,93 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package users
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsApiToken(t *testing.T) {
+	for _, c := range []struct {
+		token    string
+		expected bool
+	}{
+		{"trackit_0123456789abcdef", true},
+		{"trackit_", false},
+		{"trackit", false},
+		{"", false},
+		{"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.signature", false},
+		{"Trackit_0123456789abcdef", false},
+	} {
+		if actual := isApiToken(c.token); actual != c.expected {
+			t.Errorf("isApiToken(%q) should be %t, is %t.", c.token, c.expected, actual)
+		}
+	}
+}
+
+func TestGeneratedApiTokenIsApiToken(t *testing.T) {
+	token, err := generateApiToken()
+	if err != nil {
+		t.Fatalf("Error should be nil, instead is \"%s\".", err.Error())
+	} else if !isApiToken(token) {
+		t.Errorf("Generated token %q should be recognized as an API token.", token)
+	}
+}
+
+func TestApiTokenAllowsMethod(t *testing.T) {
+	methods := []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
+	for _, c := range []struct {
+		scope   string
+		allowed map[string]bool
+	}{
+		{ApiTokenScopeRead, map[string]bool{http.MethodGet: true, http.MethodHead: true, http.MethodOptions: true}},
+		{ApiTokenScopeReadWrite, map[string]bool{http.MethodGet: true, http.MethodHead: true, http.MethodOptions: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true}},
+	} {
+		token := ApiToken{Scope: c.scope}
+		for _, method := range methods {
+			if actual := token.allowsMethod(method); actual != c.allowed[method] {
+				t.Errorf("Token with scope %s allowing %s should be %t, is %t.", c.scope, method, c.allowed[method], actual)
+			}
+		}
+	}
+}
+
+func TestCanAccessAwsAccount(t *testing.T) {
+	for _, c := range []struct {
+		allowlist []int
+		aaId      int
+		expected  bool
+	}{
+		{nil, 1, true},
+		{[]int{}, 42, true},
+		{[]int{1, 2}, 1, true},
+		{[]int{1, 2}, 2, true},
+		{[]int{1, 2}, 3, false},
+		{[]int{3}, 1, false},
+	} {
+		user := User{Id: 1, AwsAccountAllowlist: c.allowlist}
+		if actual := user.CanAccessAwsAccount(c.aaId); actual != c.expected {
+			t.Errorf("User with allowlist %v accessing AWS account %d should be %t, is %t.", c.allowlist, c.aaId, c.expected, actual)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	uuid "github.com/satori/go.uuid"
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/awsSession"
 	"github.com/trackit/trackit/config"
 	"github.com/trackit/trackit/db"
@@ -75,6 +76,7 @@ func init() {
 				Summary:     "edit the current user",
 				Description: "Edit the current user, and responds with the user's data.",
 			},
+			audit.Logged{audit.ActionUserPasswordChange},
 		),
 		http.MethodGet: routes.H(me).With(
 			RequireAuthenticatedUser{ViewerAsSelf},
@@ -99,6 +101,7 @@ func init() {
 				Summary:     "register a new viewer user",
 				Description: "Registers a new viewer user linked to the current user, which will only be able to view its parent user's data.",
 			},
+			RequirePermission{PermissionManageSharing},
 		),
 		http.MethodGet: routes.H(getViewerUsers).With(
 			RequireAuthenticatedUser{ViewerAsParent},
@@ -106,6 +109,7 @@ func init() {
 				Summary:     "list viewer users",
 				Description: "Lists the viewer users registered for the current account.",
 			},
+			RequirePermission{PermissionManageSharing},
 		),
 	}.H().With(
 		db.RequestTransaction{db.Db},
@@ -255,6 +259,9 @@ type createViewerUserResponseBody struct {
 }
 
 func createViewerUser(request *http.Request, a routes.Arguments) (int, interface{}) {
+	if authenticatedWithApiToken(a) {
+		return http.StatusForbidden, errors.New("API tokens can not create viewer users.")
+	}
 	var body createViewerUserRequestBody
 	routes.MustRequestBody(a, &body)
 	currentUser := a[AuthenticatedUser].(User)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/trackit/jsonlog"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/routes"
 )
@@ -35,6 +36,12 @@ type viewerHandling uint
 const (
 	AuthenticatedUser            = authenticatedUserArgumentKey(iota)
 	TagRequireUserAuthentication = "require:userauth"
+	// AuthenticatedApiToken is set to the ApiToken used to authenticate,
+	// if any.
+	AuthenticatedApiToken = authenticatedUserArgumentKey(iota)
+	// AuthenticatedViewer is set to the viewer user when it is replaced by
+	// its parent, with ViewerAsParent.
+	AuthenticatedViewer = authenticatedUserArgumentKey(iota)
 )
 
 const (
@@ -54,7 +61,9 @@ func (d RequireAuthenticatedUser) getFunc(hf routes.HandlerFunc) routes.HandlerF
 		logger := jsonlog.LoggerFromContextOrDefault(r.Context())
 		auth := r.Header["Authorization"]
 		tx := a[db.Transaction].(*sql.Tx)
-		if len(auth) == 1 {
+		if len(auth) == 1 && isApiToken(auth[0]) {
+			return d.handleWithApiToken(auth[0], tx, hf, w, r, a)
+		} else if len(auth) == 1 {
 			tokenString := auth[0]
 			if user, err := testToken(tx, tokenString); err == nil {
 				return d.handleWithAuthenticatedUser(user, tx, hf, w, r, a)
@@ -74,16 +83,37 @@ func (d RequireAuthenticatedUser) getFunc(hf routes.HandlerFunc) routes.HandlerF
 	}
 }
 
+// handleWithApiToken authenticates the user with an API token, whose scope
+// must allow the method of the request.
+func (d RequireAuthenticatedUser) handleWithApiToken(tokenString string, tx *sql.Tx, hf routes.HandlerFunc, w http.ResponseWriter, r *http.Request, a routes.Arguments) (int, interface{}) {
+	user, token, err := testApiToken(tx, tokenString)
+	if err == ErrInvalidApiToken {
+		return http.StatusUnauthorized, err
+	} else if err != nil {
+		jsonlog.LoggerFromContextOrDefault(r.Context()).Error("Abnormal API token authentication failure.", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return http.StatusInternalServerError, ErrFailedToValidateToken
+	} else if !token.allowsMethod(r.Method) {
+		return http.StatusForbidden, ErrReadOnlyApiToken
+	}
+	a[AuthenticatedApiToken] = token
+	return d.handleWithAuthenticatedUser(user, tx, hf, w, r, a)
+}
+
 func (d RequireAuthenticatedUser) handleWithAuthenticatedUser(user User, tx *sql.Tx, hf routes.HandlerFunc, w http.ResponseWriter, r *http.Request, a routes.Arguments) (int, interface{}) {
 	switch d.ViewerHandling {
 	case ViewerAsParent:
 		if user.ParentId != nil {
 			var err error
+			allowlist := user.AwsAccountAllowlist
+			a[AuthenticatedViewer] = user
 			user, err = GetUserParent(r.Context(), tx, user)
 			if err != nil {
 				jsonlog.LoggerFromContextOrDefault(r.Context()).Error("Failed to get viewer user parent.", err.Error())
 				return http.StatusInternalServerError, errors.New("Failed to get viewer user parent.")
 			}
+			user.AwsAccountAllowlist = allowlist
 		}
 	case ViewerCannot:
 		if user.ParentId != nil {
@@ -92,9 +122,23 @@ func (d RequireAuthenticatedUser) handleWithAuthenticatedUser(user User, tx *sql
 	default:
 	}
 	a[AuthenticatedUser] = user
+	audit.SetActor(a, getAuditActor(user, a))
 	return hf(w, r, a)
 }
 
+// getAuditActor identifies the user, viewer and API token performing the
+// actions of a request.
+func getAuditActor(user User, a routes.Arguments) audit.Actor {
+	actor := audit.Actor{UserId: user.Id}
+	if viewer, ok := a[AuthenticatedViewer].(User); ok {
+		actor.ViewerId = viewer.Id
+	}
+	if token, ok := a[AuthenticatedApiToken].(ApiToken); ok {
+		actor.ApiTokenId = token.Id
+	}
+	return actor
+}
+
 func (RequireAuthenticatedUser) getDocumentation(hd routes.HandlerDocumentation) routes.HandlerDocumentation {
 	if hd.Tags == nil {
 		hd.Tags = make(routes.Tags)
@@ -68,7 +68,7 @@ func logInWithValidBody(request *http.Request, body loginRequestBody, tx *sql.Tx
 	logger := jsonlog.LoggerFromContextOrDefault(request.Context())
 	user, err := GetUserFromOriginWithEmailAndPassword(request.Context(), tx, body.Email, body.Password, body.Origin)
 	if err == nil {
-		return logAuthenticatedUserIn(request, user)
+		return LogAuthenticatedUserIn(request, user)
 	} else {
 		logger.Warning("Authentication failure.", struct {
 			Email string `json:"user"`
@@ -77,9 +77,9 @@ func logInWithValidBody(request *http.Request, body loginRequestBody, tx *sql.Tx
 	}
 }
 
-// logAuthenticatedUserIn generates a token for a user that's already been
+// LogAuthenticatedUserIn generates a token for a user that's already been
 // authenticated.
-func logAuthenticatedUserIn(request *http.Request, user User) (int, interface{}) {
+func LogAuthenticatedUserIn(request *http.Request, user User) (int, interface{}) {
 	logger := jsonlog.LoggerFromContextOrDefault(request.Context())
 	token, err := generateToken(user)
 	if err == nil {
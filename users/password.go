@@ -25,6 +25,7 @@ import (
 	"github.com/satori/go.uuid"
 
 	"github.com/trackit/jsonlog"
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/mail"
 	"github.com/trackit/trackit/models"
@@ -72,6 +73,7 @@ func init() {
 				Summary:     "reset a forgotten password",
 				Description: "Allows a user to reset a forgotten password using a temporary token",
 			},
+			audit.Logged{audit.ActionUserPasswordReset},
 		),
 	}.H().Register("/user/password/reset")
 }
@@ -164,6 +166,9 @@ func resetPassword(request *http.Request, a routes.Arguments) (int, interface{})
 	var body resetPasswordRequestBody
 	routes.MustRequestBody(a, &body)
 	tx := a[db.Transaction].(*sql.Tx)
+	if forgottenPassword, err := models.ForgottenPasswordByID(tx, body.Id); err == nil {
+		audit.SetUser(a, forgottenPassword.UserID)
+	}
 	return resetPasswordWithValidBody(request, body, tx)
 }
 
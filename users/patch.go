@@ -20,16 +20,21 @@ import (
 	"net/http"
 
 	"github.com/trackit/jsonlog"
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/models"
 	"github.com/trackit/trackit/routes"
 )
 
 func patchUser(request *http.Request, a routes.Arguments) (int, interface{}) {
+	if authenticatedWithApiToken(a) {
+		return http.StatusForbidden, errors.New("API tokens can not edit the user.")
+	}
 	var body createUserRequestBody
 	routes.MustRequestBody(a, &body)
 	tx := a[db.Transaction].(*sql.Tx)
 	user := a[AuthenticatedUser].(User)
+	audit.AddDetail(a, "email", body.Email)
 	return patchUserWithValidBody(request, user, body, tx)
 }
 
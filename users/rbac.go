@@ -0,0 +1,270 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package users
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trackit/trackit/models"
+)
+
+// Permission is an action a user may be allowed to perform on an AWS
+// account.
+type Permission string
+
+const (
+	PermissionViewCosts      = Permission("viewCosts")
+	PermissionManageAccounts = Permission("manageAccounts")
+	PermissionManageSharing  = Permission("manageSharing")
+	PermissionManageTagging  = Permission("manageTagging")
+	PermissionRunReports     = Permission("runReports")
+	PermissionBillingAdmin   = Permission("billingAdmin")
+)
+
+const (
+	RoleOwner    = "owner"
+	RoleAdmin    = "admin"
+	RoleStandard = "standard"
+	RoleRead     = "read"
+	RoleViewer   = "viewer"
+)
+
+// Permissions lists all the permissions, in the order they are documented.
+var Permissions = []Permission{
+	PermissionViewCosts,
+	PermissionManageAccounts,
+	PermissionManageSharing,
+	PermissionManageTagging,
+	PermissionRunReports,
+	PermissionBillingAdmin,
+}
+
+// Role is a named set of permissions. Built-in roles have no ID and can
+// not be changed, custom roles are defined by a user for the AWS accounts
+// they own.
+type Role struct {
+	Id          int          `json:"id,omitempty"`
+	Name        string       `json:"name"        req:"nonzero"`
+	Permissions []Permission `json:"permissions"`
+	BuiltIn     bool         `json:"builtIn"`
+}
+
+// BuiltInRoles are the roles every user has. The owner of an AWS account
+// has all the permissions on it, viewer users have the viewer role on the
+// accounts of their parent and shared accounts without a custom role have
+// the role matching their permission level.
+var BuiltInRoles = []Role{
+	{Name: RoleOwner, Permissions: Permissions, BuiltIn: true},
+	{Name: RoleAdmin, Permissions: []Permission{PermissionViewCosts, PermissionManageAccounts, PermissionManageSharing, PermissionManageTagging, PermissionRunReports}, BuiltIn: true},
+	{Name: RoleStandard, Permissions: []Permission{PermissionViewCosts, PermissionManageSharing, PermissionManageTagging, PermissionRunReports}, BuiltIn: true},
+	{Name: RoleRead, Permissions: []Permission{PermissionViewCosts, PermissionRunReports}, BuiltIn: true},
+	{Name: RoleViewer, Permissions: []Permission{PermissionViewCosts, PermissionRunReports}, BuiltIn: true},
+}
+
+// sharedAccountLevelRoles maps the permission levels of shared accounts, as
+// defined in the shared_account package, to built-in roles.
+var sharedAccountLevelRoles = map[int]string{
+	0: RoleAdmin,
+	1: RoleStandard,
+	2: RoleRead,
+}
+
+// PermissionSet is the set of permissions a user has on an AWS account.
+type PermissionSet map[Permission]bool
+
+// Has tells whether the set holds a permission.
+func (ps PermissionSet) Has(permission Permission) bool {
+	return ps[permission]
+}
+
+// Contains tells whether the set holds all the permissions of another set.
+func (ps PermissionSet) Contains(other PermissionSet) bool {
+	for permission, ok := range other {
+		if ok && !ps[permission] {
+			return false
+		}
+	}
+	return true
+}
+
+// newPermissionSet builds a PermissionSet from a list of permissions.
+func newPermissionSet(permissions []Permission) PermissionSet {
+	ps := make(PermissionSet, len(permissions))
+	for _, permission := range permissions {
+		ps[permission] = true
+	}
+	return ps
+}
+
+// intersect returns the permissions held by both sets.
+func (ps PermissionSet) intersect(other PermissionSet) PermissionSet {
+	res := make(PermissionSet, len(ps))
+	for permission := range ps {
+		if other[permission] {
+			res[permission] = true
+		}
+	}
+	return res
+}
+
+// getBuiltInRole returns a built-in role by its name.
+func getBuiltInRole(name string) Role {
+	for _, role := range BuiltInRoles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return Role{Name: name, BuiltIn: true}
+}
+
+// isPermission tells whether a permission exists.
+func isPermission(permission Permission) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks a custom role before it is saved.
+func (r Role) validate() error {
+	if r.Name == "" || len(r.Name) > 255 {
+		return errors.New("name must be between 1 and 255 characters")
+	}
+	for _, role := range BuiltInRoles {
+		if role.Name == r.Name {
+			return fmt.Errorf("%s is the name of a built-in role", r.Name)
+		}
+	}
+	for _, permission := range r.Permissions {
+		if !isPermission(permission) {
+			return fmt.Errorf("permission %s does not exist", permission)
+		}
+	}
+	return nil
+}
+
+// roleFromDbRole builds a Role from its database row.
+func roleFromDbRole(dbRole models.Role) (Role, error) {
+	role := Role{
+		Id:          dbRole.ID,
+		Name:        dbRole.Name,
+		Permissions: []Permission{},
+	}
+	err := json.Unmarshal(dbRole.Permissions, &role.Permissions)
+	return role, err
+}
+
+// setDbRole copies a Role into its database row.
+func (r Role) setDbRole(dbRole *models.Role) (err error) {
+	if r.Permissions == nil {
+		r.Permissions = []Permission{}
+	}
+	dbRole.Name = r.Name
+	dbRole.Permissions, err = json.Marshal(r.Permissions)
+	return
+}
+
+// GetRolesForUser returns the built-in roles followed by the custom roles
+// of a user.
+func GetRolesForUser(db models.DB, userId int) ([]Role, error) {
+	dbRoles, err := models.RoleByUserID(db, userId)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]Role, 0, len(BuiltInRoles)+len(dbRoles))
+	roles = append(roles, BuiltInRoles...)
+	for _, dbRole := range dbRoles {
+		role, err := roleFromDbRole(*dbRole)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// getSharedAccountRole returns the role of a shared account: its custom role
+// if it has one, or the built-in role of its permission level. Custom roles
+// are only used if they are defined by the owner of the AWS account.
+func getSharedAccountRole(db models.DB, dbSharedAccount models.SharedAccount, ownerId int) (Role, error) {
+	if dbSharedAccount.RoleID.Valid {
+		dbRole, err := models.RoleByID(db, int(dbSharedAccount.RoleID.Int64))
+		if err != nil && err != sql.ErrNoRows {
+			return Role{}, err
+		} else if err == nil && dbRole.UserID == ownerId {
+			return roleFromDbRole(*dbRole)
+		}
+	}
+	return getBuiltInRole(sharedAccountLevelRoles[dbSharedAccount.UserPermission]), nil
+}
+
+// SharedAccountLevelPermissions returns the permissions of the built-in role
+// matching a shared account permission level.
+func SharedAccountLevelPermissions(level int) PermissionSet {
+	return newPermissionSet(getBuiltInRole(sharedAccountLevelRoles[level]).Permissions)
+}
+
+// GetSharedAccountPermissions returns the permissions a shared account grants
+// on its AWS account.
+func GetSharedAccountPermissions(db models.DB, dbSharedAccount models.SharedAccount) (PermissionSet, error) {
+	dbAwsAccount, err := models.AwsAccountByID(db, dbSharedAccount.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := getSharedAccountRole(db, dbSharedAccount, dbAwsAccount.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return newPermissionSet(role.Permissions), nil
+}
+
+// GetAwsAccountsPermissions returns the permissions of a user on each of the
+// AWS accounts they own or which are shared with them, by AWS account ID.
+func GetAwsAccountsPermissions(db models.DB, user User) (map[int]PermissionSet, error) {
+	dbAwsAccounts, err := models.AwsAccountByUserID(db, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	dbSharedAccounts, err := models.SharedAccountByUserID(db, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make(map[int]PermissionSet, len(dbAwsAccounts)+len(dbSharedAccounts))
+	for _, dbAwsAccount := range dbAwsAccounts {
+		if user.CanAccessAwsAccount(dbAwsAccount.ID) {
+			permissions[dbAwsAccount.ID] = newPermissionSet(Permissions)
+		}
+	}
+	for _, dbSharedAccount := range dbSharedAccounts {
+		if _, ok := permissions[dbSharedAccount.AccountID]; ok || !user.CanAccessAwsAccount(dbSharedAccount.AccountID) {
+			continue
+		}
+		dbAwsAccount, err := models.AwsAccountByID(db, dbSharedAccount.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		role, err := getSharedAccountRole(db, *dbSharedAccount, dbAwsAccount.UserID)
+		if err != nil {
+			return nil, err
+		}
+		permissions[dbSharedAccount.AccountID] = newPermissionSet(role.Permissions)
+	}
+	return permissions, nil
+}
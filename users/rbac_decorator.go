@@ -0,0 +1,153 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+)
+
+const TagRequirePermission = "require:permission"
+
+// RequirePermission decorates handlers to require that the authenticated
+// user has a permission on the AWS accounts the request is about. It must be
+// used after RequireAuthenticatedUser and the QueryArgs decorator.
+//
+// The AWS accounts are the ones selected with AwsAccountIdQueryArg,
+// AwsAccountIdsOptionalQueryArg, AwsAccountQueryArg,
+// AwsAccountsOptionalQueryArg or ShareIdQueryArg. The request is refused if
+// the user lacks the permission on any of them. If none is selected, the
+// request is refused if the user lacks the permission on all of their AWS
+// accounts. In both cases, the accounts the user may access during the
+// request are restricted to those they have the permission on.
+type RequirePermission struct {
+	Permission Permission
+}
+
+func (d RequirePermission) Decorate(h routes.Handler) routes.Handler {
+	h.Func = d.getFunc(h.Func)
+	h.Documentation = d.getDocumentation(h.Documentation)
+	return h
+}
+
+func (d RequirePermission) getFunc(hf routes.HandlerFunc) routes.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, a routes.Arguments) (int, interface{}) {
+		logger := jsonlog.LoggerFromContextOrDefault(r.Context())
+		user, ok := a[AuthenticatedUser].(User)
+		tx, txOk := a[db.Transaction].(*sql.Tx)
+		if !ok || !txOk {
+			logger.Error("Missing transaction or user for handler with permission.", nil)
+			return http.StatusInternalServerError, nil
+		}
+		if _, ok := a[AuthenticatedViewer]; ok && !d.Permission.grantedToViewers() {
+			return http.StatusForbidden, errors.New("This action is unavailable to viewer users.")
+		}
+		permissions, err := GetAwsAccountsPermissions(tx, user)
+		if err != nil {
+			logger.Error("Failed to get AWS accounts permissions.", map[string]interface{}{
+				"userId": user.Id,
+				"error":  err.Error(),
+			})
+			return http.StatusInternalServerError, errors.New("Failed to check permissions.")
+		}
+		selected, err := getSelectedAwsAccounts(tx, a, permissions)
+		if err != nil {
+			logger.Error("Failed to get selected AWS accounts.", err.Error())
+			return http.StatusInternalServerError, errors.New("Failed to check permissions.")
+		}
+		allowlist := make([]int, 0, len(permissions))
+		for aaId, permissionSet := range permissions {
+			if permissionSet.Has(d.Permission) {
+				allowlist = append(allowlist, aaId)
+			}
+		}
+		for _, aaId := range selected {
+			if !permissions[aaId].Has(d.Permission) {
+				return http.StatusForbidden, fmt.Errorf("The %s permission is required on the selected AWS accounts.", d.Permission)
+			}
+		}
+		if len(permissions) > 0 && len(allowlist) == 0 {
+			return http.StatusForbidden, fmt.Errorf("The %s permission is required on at least one AWS account.", d.Permission)
+		} else if len(allowlist) > 0 {
+			user.AwsAccountAllowlist = allowlist
+			a[AuthenticatedUser] = user
+		}
+		return hf(w, r, a)
+	}
+}
+
+func (d RequirePermission) getDocumentation(hd routes.HandlerDocumentation) routes.HandlerDocumentation {
+	if hd.Tags == nil {
+		hd.Tags = make(routes.Tags)
+	}
+	hd.Tags[TagRequirePermission] = []string{string(d.Permission)}
+	return hd
+}
+
+// grantedToViewers tells whether viewer users may have a permission on the
+// AWS accounts of their parent.
+func (p Permission) grantedToViewers() bool {
+	return newPermissionSet(getBuiltInRole(RoleViewer).Permissions).Has(p)
+}
+
+// getSelectedAwsAccounts returns the IDs of the AWS accounts selected by the
+// query arguments of a request. AWS account identities are resolved among
+// the accounts the user has permissions on; the others are left to the
+// handler, which will not find them.
+func getSelectedAwsAccounts(tx *sql.Tx, a routes.Arguments, permissions map[int]PermissionSet) ([]int, error) {
+	var selected []int
+	if aaId, ok := a[routes.AwsAccountIdQueryArg].(int); ok {
+		selected = append(selected, aaId)
+	}
+	if aaIds, ok := a[routes.AwsAccountIdsOptionalQueryArg].([]int); ok {
+		selected = append(selected, aaIds...)
+	}
+	if shareId, ok := a[routes.ShareIdQueryArg].(int); ok {
+		if dbSharedAccount, err := models.SharedAccountByID(tx, shareId); err == nil {
+			selected = append(selected, dbSharedAccount.AccountID)
+		} else if err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+	var identities []string
+	if identity, ok := a[routes.AwsAccountQueryArg].(string); ok {
+		identities = append(identities, identity)
+	}
+	if accounts, ok := a[routes.AwsAccountsOptionalQueryArg].([]string); ok {
+		identities = append(identities, accounts...)
+	}
+	if len(identities) > 0 {
+		for aaId := range permissions {
+			dbAwsAccount, err := models.AwsAccountByID(tx, aaId)
+			if err != nil {
+				return nil, err
+			}
+			for _, identity := range identities {
+				if dbAwsAccount.AwsIdentity == identity {
+					selected = append(selected, aaId)
+				}
+			}
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,240 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package users
+
+// These tests are intended to be run against an empty database with the schema
+// already in place. They run in a transaction which is rolled back.
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+)
+
+type rbacFixture struct {
+	tx         *sql.Tx
+	owner      User
+	guest      User
+	readAa     models.AwsAccount
+	standardAa models.AwsAccount
+	customAa   models.AwsAccount
+	readShare  models.SharedAccount
+	customRole Role
+}
+
+func newRbacFixture(t *testing.T) rbacFixture {
+	ctx := context.Background()
+	var f rbacFixture
+	var err error
+	if f.tx, err = db.Db.Begin(); err != nil {
+		t.Fatalf("Failed to begin transaction: %s", err.Error())
+	}
+	t.Cleanup(func() { f.tx.Rollback() })
+	if f.owner, err = CreateUserWithPassword(ctx, f.tx, "rbac.owner@example.trackit.io", "password", "", "trackit"); err != nil {
+		t.Fatalf("Failed to create owner: %s", err.Error())
+	}
+	if f.guest, err = CreateUserWithPassword(ctx, f.tx, "rbac.guest@example.trackit.io", "password", "", "trackit"); err != nil {
+		t.Fatalf("Failed to create guest: %s", err.Error())
+	}
+	f.readAa = insertRbacAwsAccount(t, f.tx, f.owner, "111111111111")
+	f.standardAa = insertRbacAwsAccount(t, f.tx, f.owner, "222222222222")
+	f.customAa = insertRbacAwsAccount(t, f.tx, f.owner, "333333333333")
+	f.customRole = Role{Name: "sharing", Permissions: []Permission{PermissionViewCosts, PermissionManageSharing}}
+	var dbRole models.Role
+	dbRole.UserID = f.owner.Id
+	if err = f.customRole.setDbRole(&dbRole); err != nil {
+		t.Fatalf("Failed to build role: %s", err.Error())
+	} else if err = dbRole.Insert(f.tx); err != nil {
+		t.Fatalf("Failed to insert role: %s", err.Error())
+	}
+	f.customRole.Id = dbRole.ID
+	f.readShare = insertRbacSharedAccount(t, f.tx, f.readAa, f.guest, 2, 0)
+	insertRbacSharedAccount(t, f.tx, f.standardAa, f.guest, 1, 0)
+	insertRbacSharedAccount(t, f.tx, f.customAa, f.guest, 2, dbRole.ID)
+	return f
+}
+
+func insertRbacAwsAccount(t *testing.T, tx *sql.Tx, user User, identity string) models.AwsAccount {
+	now := time.Now().UTC()
+	dbAwsAccount := models.AwsAccount{
+		Created:                               now,
+		UserID:                                user.Id,
+		Pretty:                                identity,
+		RoleArn:                               "arn:aws:iam::" + identity + ":role/trackit",
+		External:                              identity,
+		NextUpdate:                            now,
+		NextUpdatePlugins:                     now,
+		AwsIdentity:                           identity,
+		LastSpreadsheetReportGeneration:       now,
+		NextSpreadsheetReportGeneration:       now,
+		NextUpdateAnomaliesDetection:          now,
+		LastAnomaliesUpdate:                   now,
+		LastMasterSpreadsheetReportGeneration: now,
+		NextMasterSpreadsheetReportGeneration: now,
+		LastTagsSpreadsheetReportGeneration:   now,
+		NextTagsSpreadsheetReportGeneration:   now,
+		TagbotOnboardingStarted:               now,
+	}
+	if err := dbAwsAccount.Insert(tx); err != nil {
+		t.Fatalf("Failed to insert AWS account %s: %s", identity, err.Error())
+	}
+	return dbAwsAccount
+}
+
+func insertRbacSharedAccount(t *testing.T, tx *sql.Tx, dbAwsAccount models.AwsAccount, user User, level int, roleId int) models.SharedAccount {
+	dbSharedAccount := models.SharedAccount{
+		AccountID:       dbAwsAccount.ID,
+		UserID:          user.Id,
+		UserPermission:  level,
+		SharingAccepted: true,
+		RoleID:          sql.NullInt64{Int64: int64(roleId), Valid: roleId != 0},
+	}
+	if err := dbSharedAccount.Insert(tx); err != nil {
+		t.Fatalf("Failed to insert shared account: %s", err.Error())
+	}
+	return dbSharedAccount
+}
+
+func TestGetAwsAccountsPermissions(t *testing.T) {
+	f := newRbacFixture(t)
+	restricted := f.guest
+	restricted.AwsAccountAllowlist = []int{f.standardAa.ID}
+	cases := []struct {
+		name     string
+		user     User
+		expected map[int]PermissionSet
+	}{
+		{"Owner", f.owner, map[int]PermissionSet{
+			f.readAa.ID:     newPermissionSet(Permissions),
+			f.standardAa.ID: newPermissionSet(Permissions),
+			f.customAa.ID:   newPermissionSet(Permissions),
+		}},
+		{"SharedLevelsAndCustomRole", f.guest, map[int]PermissionSet{
+			f.readAa.ID:     newPermissionSet(getBuiltInRole(RoleRead).Permissions),
+			f.standardAa.ID: newPermissionSet(getBuiltInRole(RoleStandard).Permissions),
+			f.customAa.ID:   newPermissionSet(f.customRole.Permissions),
+		}},
+		{"Allowlist", restricted, map[int]PermissionSet{
+			f.standardAa.ID: newPermissionSet(getBuiltInRole(RoleStandard).Permissions),
+		}},
+	}
+	for _, c := range cases {
+		permissions, err := GetAwsAccountsPermissions(f.tx, c.user)
+		if err != nil {
+			t.Errorf("%s: error should be nil, instead is \"%s\".", c.name, err.Error())
+		} else if !reflect.DeepEqual(permissions, c.expected) {
+			t.Errorf("%s: permissions should be %v, instead are %v.", c.name, c.expected, permissions)
+		}
+	}
+}
+
+func TestGetSelectedAwsAccounts(t *testing.T) {
+	f := newRbacFixture(t)
+	permissions, err := GetAwsAccountsPermissions(f.tx, f.guest)
+	if err != nil {
+		t.Fatalf("Failed to get permissions: %s", err.Error())
+	}
+	cases := []struct {
+		name     string
+		args     routes.Arguments
+		expected []int
+	}{
+		{"None", routes.Arguments{}, nil},
+		{"AccountId", routes.Arguments{routes.AwsAccountIdQueryArg: f.readAa.ID}, []int{f.readAa.ID}},
+		{"AccountIds", routes.Arguments{routes.AwsAccountIdsOptionalQueryArg: []int{f.readAa.ID, f.customAa.ID}}, []int{f.readAa.ID, f.customAa.ID}},
+		{"Share", routes.Arguments{routes.ShareIdQueryArg: f.readShare.ID}, []int{f.readAa.ID}},
+		{"MissingShare", routes.Arguments{routes.ShareIdQueryArg: -1}, nil},
+		{"Account", routes.Arguments{routes.AwsAccountQueryArg: f.standardAa.AwsIdentity}, []int{f.standardAa.ID}},
+		{"Accounts", routes.Arguments{routes.AwsAccountsOptionalQueryArg: []string{f.standardAa.AwsIdentity, f.customAa.AwsIdentity}}, []int{f.standardAa.ID, f.customAa.ID}},
+		{"UnknownAccount", routes.Arguments{routes.AwsAccountsOptionalQueryArg: []string{"444444444444"}}, nil},
+	}
+	for _, c := range cases {
+		selected, err := getSelectedAwsAccounts(f.tx, c.args, permissions)
+		sort.Ints(selected)
+		sort.Ints(c.expected)
+		if err != nil {
+			t.Errorf("%s: error should be nil, instead is \"%s\".", c.name, err.Error())
+		} else if !reflect.DeepEqual(selected, c.expected) {
+			t.Errorf("%s: selected accounts should be %v, instead are %v.", c.name, c.expected, selected)
+		}
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	f := newRbacFixture(t)
+	cases := []struct {
+		name       string
+		permission Permission
+		user       User
+		args       routes.Arguments
+		viewer     bool
+		status     int
+		allowlist  []int
+	}{
+		{"OwnerSelected", PermissionManageAccounts, f.owner, routes.Arguments{routes.AwsAccountIdQueryArg: f.readAa.ID}, false, http.StatusOK, []int{f.readAa.ID, f.standardAa.ID, f.customAa.ID}},
+		{"SharedSelected", PermissionManageSharing, f.guest, routes.Arguments{routes.AwsAccountIdQueryArg: f.standardAa.ID}, false, http.StatusOK, []int{f.standardAa.ID, f.customAa.ID}},
+		{"CustomRoleSelected", PermissionManageSharing, f.guest, routes.Arguments{routes.AwsAccountIdQueryArg: f.customAa.ID}, false, http.StatusOK, []int{f.standardAa.ID, f.customAa.ID}},
+		{"ReadSelected", PermissionManageSharing, f.guest, routes.Arguments{routes.AwsAccountIdQueryArg: f.readAa.ID}, false, http.StatusForbidden, nil},
+		{"ReadShare", PermissionManageSharing, f.guest, routes.Arguments{routes.ShareIdQueryArg: f.readShare.ID}, false, http.StatusForbidden, nil},
+		{"OneOfSelected", PermissionManageSharing, f.guest, routes.Arguments{routes.AwsAccountIdsOptionalQueryArg: []int{f.standardAa.ID, f.readAa.ID}}, false, http.StatusForbidden, nil},
+		{"NoneSelected", PermissionViewCosts, f.guest, routes.Arguments{}, false, http.StatusOK, []int{f.readAa.ID, f.standardAa.ID, f.customAa.ID}},
+		{"NoneAllowed", PermissionManageAccounts, f.guest, routes.Arguments{}, false, http.StatusForbidden, nil},
+		{"Viewer", PermissionManageSharing, f.owner, routes.Arguments{}, true, http.StatusForbidden, nil},
+		{"ViewerGranted", PermissionViewCosts, f.owner, routes.Arguments{}, true, http.StatusOK, []int{f.readAa.ID, f.standardAa.ID, f.customAa.ID}},
+	}
+	for _, c := range cases {
+		var allowlist []int
+		handler := RequirePermission{c.permission}.getFunc(func(w http.ResponseWriter, r *http.Request, a routes.Arguments) (int, interface{}) {
+			allowlist = a[AuthenticatedUser].(User).AwsAccountAllowlist
+			return http.StatusOK, nil
+		})
+		c.args[AuthenticatedUser] = c.user
+		c.args[db.Transaction] = f.tx
+		if c.viewer {
+			c.args[AuthenticatedViewer] = f.guest
+		}
+		status, _ := handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), c.args)
+		sort.Ints(allowlist)
+		sort.Ints(c.allowlist)
+		if status != c.status {
+			t.Errorf("%s: status should be %d, instead is %d.", c.name, c.status, status)
+		} else if !reflect.DeepEqual(allowlist, c.allowlist) {
+			t.Errorf("%s: allowlist should be %v, instead is %v.", c.name, c.allowlist, allowlist)
+		}
+	}
+}
+
+func TestPermissionSetContains(t *testing.T) {
+	admin := newPermissionSet(getBuiltInRole(RoleAdmin).Permissions)
+	standard := SharedAccountLevelPermissions(1)
+	if !admin.Contains(standard) {
+		t.Error("Admin permissions should contain standard permissions.")
+	}
+	if standard.Contains(admin) {
+		t.Error("Standard permissions should not contain admin permissions.")
+	}
+	if !standard.Contains(nil) {
+		t.Error("Any set should contain the empty set.")
+	}
+}
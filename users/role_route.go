@@ -0,0 +1,171 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/routes"
+)
+
+// roleIdQueryArg allows to get the ID of a custom role in the URL parameters.
+var roleIdQueryArg = routes.QueryArg{
+	Name:        "role",
+	Type:        routes.QueryArgInt{},
+	Description: "The ID of a custom role.",
+}
+
+func init() {
+	exampleRole := Role{
+		Name:        "finance",
+		Permissions: []Permission{PermissionViewCosts, PermissionRunReports},
+	}
+	routes.MethodMuxer{
+		http.MethodGet: routes.H(getRoles).With(
+			RequireAuthenticatedUser{ViewerAsParent},
+			routes.Documentation{
+				Summary:     "get the roles",
+				Description: "Responds with the built-in roles and the custom roles of the user, with their permissions.",
+			},
+		),
+		http.MethodPost: routes.H(postRole).With(
+			RequireAuthenticatedUser{ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{exampleRole},
+			routes.Documentation{
+				Summary:     "create a custom role",
+				Description: "Creates a custom role, which the user can assign to the users their AWS accounts are shared with.",
+			},
+		),
+		http.MethodPatch: routes.H(patchRole).With(
+			RequireAuthenticatedUser{ViewerCannot},
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{exampleRole},
+			routes.QueryArgs{roleIdQueryArg},
+			routes.Documentation{
+				Summary:     "update a custom role",
+				Description: "Updates the name and permissions of a custom role.",
+			},
+		),
+		http.MethodDelete: routes.H(deleteRole).With(
+			RequireAuthenticatedUser{ViewerCannot},
+			routes.QueryArgs{roleIdQueryArg},
+			routes.Documentation{
+				Summary:     "delete a custom role",
+				Description: "Deletes a custom role. Shared accounts which had it fall back to the role of their permission level.",
+			},
+		),
+	}.H().With(
+		db.RequestTransaction{Db: db.Db},
+		routes.Documentation{
+			Summary:     "interact with roles",
+			Description: "Roles are sets of permissions on AWS accounts. Permissions are viewCosts, manageAccounts, manageSharing, manageTagging, runReports and billingAdmin.",
+		},
+	).Register("/user/roles")
+}
+
+// getRoles is a route handler which returns the roles of the user.
+func getRoles(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[AuthenticatedUser].(User)
+	roles, err := GetRolesForUser(tx, user.Id)
+	if err != nil {
+		l.Error("Failed to get roles.", map[string]interface{}{
+			"userId": user.Id,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to retrieve roles.")
+	}
+	return http.StatusOK, roles
+}
+
+// postRole is a route handler which creates a custom role.
+func postRole(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Role
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[AuthenticatedUser].(User)
+	if err := body.validate(); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	dbRole := models.Role{UserID: user.Id}
+	return saveRole(r, tx, body, &dbRole)
+}
+
+// patchRole is a route handler which updates a custom role.
+func patchRole(r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body Role
+	routes.MustRequestBody(a, &body)
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[AuthenticatedUser].(User)
+	dbRole, err := models.RoleByID(tx, a[roleIdQueryArg].(int))
+	if err != nil || dbRole.UserID != user.Id {
+		return http.StatusNotFound, errors.New("Role not found.")
+	}
+	if err := body.validate(); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	return saveRole(r, tx, body, dbRole)
+}
+
+// saveRole saves a custom role and responds with it.
+func saveRole(r *http.Request, tx *sql.Tx, role Role, dbRole *models.Role) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	if existing, err := models.RoleByUserIDName(tx, dbRole.UserID, role.Name); err == nil && existing.ID != dbRole.ID {
+		return http.StatusBadRequest, fmt.Errorf("A role named %s already exists.", role.Name)
+	}
+	if err := role.setDbRole(dbRole); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Body is invalid (%s).", err.Error())
+	}
+	if err := dbRole.Save(tx); err != nil {
+		l.Error("Failed to save role.", map[string]interface{}{
+			"userId": dbRole.UserID,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to save role.")
+	}
+	role, err := roleFromDbRole(*dbRole)
+	if err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to save role.")
+	}
+	return http.StatusOK, role
+}
+
+// deleteRole is a route handler which deletes a custom role.
+func deleteRole(r *http.Request, a routes.Arguments) (int, interface{}) {
+	l := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	user := a[AuthenticatedUser].(User)
+	dbRole, err := models.RoleByID(tx, a[roleIdQueryArg].(int))
+	if err != nil || dbRole.UserID != user.Id {
+		return http.StatusNotFound, errors.New("Role not found.")
+	}
+	if err := dbRole.Delete(tx); err != nil {
+		l.Error("Failed to delete role.", map[string]interface{}{
+			"roleId": dbRole.ID,
+			"error":  err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to delete role.")
+	}
+	return http.StatusOK, nil
+}
@@ -223,16 +223,16 @@ func inviteNewUser(ctx context.Context, tx *sql.Tx, body InviteUserRequest, acco
 // InviteUserWithValidBody tries to share an account with a specific user
 func InviteUserWithValidBody(request *http.Request, body InviteUserRequest, accountId int, tx *sql.Tx, user users.User) (int, interface{}) {
 	logger := jsonlog.LoggerFromContextOrDefault(request.Context())
-	security, err := safetyCheckByAccountIdAndPermissionLevel(request.Context(), tx, accountId, body, user)
+	if !checkPermissionLevel(body.PermissionLevel) {
+		logger.Info("Non existing user permission", nil)
+		return http.StatusBadRequest, ErrorInviteUser
+	}
+	security, err := checkGrantedPermissions(request.Context(), tx, accountId, users.SharedAccountLevelPermissions(body.PermissionLevel), user)
 	if err != nil {
 		return http.StatusBadRequest, err
 	} else if !security {
 		return http.StatusForbidden, errors.New("You do not have permission to edit this sharing")
 	}
-	if !checkPermissionLevel(body.PermissionLevel) {
-		logger.Info("Non existing user permission", nil)
-		return http.StatusBadRequest, ErrorInviteUser
-	}
 	result, guestId, err := checkUserWithEmailAndAccountType(request.Context(), tx, body.Email, body.Origin, user)
 	if err == nil {
 		if result {
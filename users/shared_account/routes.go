@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/routes"
 	"github.com/trackit/trackit/users"
@@ -17,7 +18,8 @@ type InviteUserRequest struct {
 }
 
 type updateUsersSharedAccountRequest struct {
-	PermissionLevel int `json:"permissionLevel"`
+	PermissionLevel int  `json:"permissionLevel"`
+	RoleId          *int `json:"roleId,omitempty"`
 }
 
 func init() {
@@ -32,6 +34,7 @@ func init() {
 			routes.QueryArgs{
 				routes.AwsAccountIdQueryArg,
 			},
+			users.RequirePermission{users.PermissionManageSharing},
 		),
 		http.MethodPost: routes.H(inviteUser).With(
 			db.RequestTransaction{db.Db},
@@ -44,6 +47,8 @@ func init() {
 			routes.QueryArgs{
 				routes.AwsAccountIdQueryArg,
 			},
+			users.RequirePermission{users.PermissionManageSharing},
+			audit.Logged{audit.ActionSharingInvite},
 		),
 		http.MethodPatch: routes.H(updateSharedUsers).With(
 			db.RequestTransaction{db.Db},
@@ -54,8 +59,10 @@ func init() {
 			routes.RequestContentType{"application/json"},
 			routes.Documentation{
 				Summary:     "Update shared users",
-				Description: "Update shared users associated with a specific AWS account. Permission level can be 0 for admin, 1 for standard and 2 for read-only.",
+				Description: "Update shared users associated with a specific AWS account. Permission level can be 0 for admin, 1 for standard and 2 for read-only. The owner of the AWS account can also assign one of their custom roles with roleId, 0 removing it.",
 			},
+			users.RequirePermission{users.PermissionManageSharing},
+			audit.Logged{audit.ActionSharingUpdate},
 		),
 		http.MethodDelete: routes.H(deleteSharedUsers).With(
 			db.RequestTransaction{db.Db},
@@ -67,6 +74,8 @@ func init() {
 			routes.QueryArgs{
 				routes.ShareIdQueryArg,
 			},
+			users.RequirePermission{users.PermissionManageSharing},
+			audit.Logged{audit.ActionSharingDelete},
 		),
 	}.H().With(
 		db.RequestTransaction{db.Db},
@@ -18,112 +18,59 @@ const (
 	DatabaseError = "Error while getting data from database"
 )
 
-// safetyCheckByAccountId checks by AccountId if the user have a high enough
-// permission level to perform an action on a shared account
-func safetyCheckByAccountId(ctx context.Context, tx *sql.Tx, AccountId int, user users.User) (bool, error) {
+// checkGrantedPermissions checks if the user has, on an AWS account, all the
+// permissions a sharing grants, so that managing the sharing of an account
+// never gives more permissions than the user has. The manageSharing
+// permission itself is required by the routes.
+func checkGrantedPermissions(ctx context.Context, tx *sql.Tx, accountId int, granted users.PermissionSet, user users.User) (bool, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
-	dbAwsAccount, err := models.AwsAccountByID(tx, AccountId)
-	if err == sql.ErrNoRows {
-		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseItemNotFound, "This AWS Account does not exist"})
-	} else if err != nil {
-		logger.Error("Error while retrieving AWS account from DB", err)
-		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
-	}
-	if dbAwsAccount.UserID == user.Id {
-		return true, nil
-	}
-	dbSharedAccount, err := models.SharedAccountByAccountID(tx, AccountId)
-	if err == nil {
-		for _, key := range dbSharedAccount {
-			if key.UserID == user.Id && (key.UserPermission == AdminLevel || key.UserPermission == StandardLevel) {
-				return true, nil
-			}
-		}
-	}
-	if err == sql.ErrNoRows {
-		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseItemNotFound, "This Shared Account does not exist"})
-	}
-	logger.Error("Error while retrieving shared account by account ID from DB", err)
-	return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, "Unable to ensure user have enough rights to do this action"})
-}
-
-// checkLevel checks if the current user permission level is high enough to perform an action
-func checkLevel(PermissionLevelToCheck int, currentUserPermissionLevel int) bool {
-	if currentUserPermissionLevel == AdminLevel {
-		return true
-	} else if currentUserPermissionLevel == StandardLevel {
-		if currentUserPermissionLevel <= PermissionLevelToCheck {
-			return true
-		}
-	}
-	return false
-}
-
-// safetyCheckByAccountIdAndPermissionLevel checks by AccountId if the user have a high enough
-// permission level to perform an action on a shared account. It also compares current user Permission Level
-// to the permissionLevel of the viewer account.
-func safetyCheckByAccountIdAndPermissionLevel(ctx context.Context, tx *sql.Tx, AccountId int, body InviteUserRequest, user users.User) (bool, error) {
-	logger := jsonlog.LoggerFromContextOrDefault(ctx)
-	dbAwsAccount, err := models.AwsAccountByID(tx, AccountId)
-	if err == sql.ErrNoRows {
-		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseItemNotFound, "This AWS Account does not exist"})
-	} else if err != nil {
-		logger.Error("Error while retrieving AWS account from DB", err)
-		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
-	}
-	if dbAwsAccount.UserID == user.Id {
-		return true, nil
-	}
-	dbSharedAccount, err := models.SharedAccountByAccountID(tx, AccountId)
-	if err == nil {
-		for _, key := range dbSharedAccount {
-			if key.UserID == user.Id && checkLevel(body.PermissionLevel, key.UserPermission) {
-				return true, nil
-			}
-		}
-	}
-	if err == sql.ErrNoRows {
-		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseItemNotFound, "This Shared Account does not exist"})
+	permissions, err := users.GetAwsAccountsPermissions(tx, user)
+	if err != nil {
+		logger.Error("Error while retrieving AWS accounts permissions", err)
+		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, "Unable to ensure user have enough rights to do this action"})
 	}
-	logger.Error("Error while retrieving shared account by account ID from DB", err)
-	return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, "Unable to ensure user have enough rights to do this action"})
+	return permissions[accountId].Contains(granted), nil
 }
 
-// safetyCheckByShareId checks by ShareId if the user have a high enough
-// permission level to perform an action on a shared account
-func safetyCheckByShareId(ctx context.Context, tx *sql.Tx, shareId int, user users.User) (bool, error) {
+// checkSharedAccountPermissions checks if the user has all the permissions a
+// shared account grants, as well as the new permissions it is to grant, if
+// any. It returns false if the shared account does not exist.
+func checkSharedAccountPermissions(ctx context.Context, tx *sql.Tx, shareId int, newGranted users.PermissionSet, user users.User) (bool, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	dbShareAccount, err := models.SharedAccountByID(tx, shareId)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
-		logger.Error("Error while retrieving Shared Accounts", err)
+		logger.Error("Error while retrieving Shared Accounts from DB", err)
 		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
 	}
-	dbAwsAccount, err := models.AwsAccountByID(tx, dbShareAccount.AccountID)
+	granted, err := users.GetSharedAccountPermissions(tx, *dbShareAccount)
 	if err != nil {
-		logger.Error("Error while retrieving AWS account", err)
+		logger.Error("Error while retrieving shared account permissions", err)
 		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
 	}
-	if dbAwsAccount.UserID == user.Id {
-		return true, nil
+	for permission := range newGranted {
+		granted[permission] = true
 	}
-	dbShareAccountByAccountId, err := models.SharedAccountByAccountID(tx, dbShareAccount.AccountID)
-	if err == nil {
-		for _, key := range dbShareAccountByAccountId {
-			if key.UserID == user.Id && checkLevel(dbShareAccount.UserPermission, key.UserPermission) {
-				return true, nil
-			}
-		}
+	return checkGrantedPermissions(ctx, tx, dbShareAccount.AccountID, granted, user)
+}
+
+// checkPermissionLevel checks user permission level
+func checkPermissionLevel(permissionLevel int) bool {
+	if permissionLevel == AdminLevel {
+		return true
+	} else if permissionLevel == StandardLevel {
+		return true
+	} else if permissionLevel == ReadLevel {
+		return true
+	} else {
+		return false
 	}
-	logger.Error("Error while retrieving shared account by account ID from DB", err)
-	return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
 }
 
-// safetyCheckByShareIdAndPermissionLevel checks by ShareId if the user have a high enough
-// permission level to perform an action on a shared account. It also checks if permission Level  of the current user
-// is higher than the one that the users wants to set.
-func safetyCheckByShareIdAndPermissionLevel(ctx context.Context, tx *sql.Tx, shareId int, newPermissionLevel int, user users.User) (bool, error) {
+// checkRoleAssignment checks if the user can assign a custom role to a shared
+// account: only the owner of the AWS account can, with one of their roles.
+func checkRoleAssignment(ctx context.Context, tx *sql.Tx, shareId int, roleId int, user users.User) (bool, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	dbShareAccount, err := models.SharedAccountByID(tx, shareId)
 	if err == sql.ErrNoRows {
@@ -137,33 +84,17 @@ func safetyCheckByShareIdAndPermissionLevel(ctx context.Context, tx *sql.Tx, sha
 		logger.Error("Error while retrieving AWS account", err)
 		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
 	}
-	if dbAwsAccount.UserID == user.Id {
+	if dbAwsAccount.UserID != user.Id {
+		return false, nil
+	} else if roleId == 0 {
 		return true, nil
 	}
-	dbShareAccountByAccountId, err := models.SharedAccountByAccountID(tx, dbShareAccount.AccountID)
-	if err == nil {
-		for _, key := range dbShareAccountByAccountId {
-			if key.UserID == user.Id && checkLevel(newPermissionLevel, key.UserPermission) {
-				if key.UserPermission == StandardLevel && dbShareAccount.UserPermission == AdminLevel {
-					return false, nil
-				}
-				return true, nil
-			}
-		}
-	}
-	logger.Error("Error while retrieving shared account by account ID from DB", err)
-	return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
-}
-
-// checkPermissionLevel checks user permission level
-func checkPermissionLevel(permissionLevel int) bool {
-	if permissionLevel == AdminLevel {
-		return true
-	} else if permissionLevel == StandardLevel {
-		return true
-	} else if permissionLevel == ReadLevel {
-		return true
-	} else {
-		return false
+	dbRole, err := models.RoleByID(tx, roleId)
+	if err == sql.ErrNoRows {
+		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseItemNotFound, "This role does not exist"})
+	} else if err != nil {
+		logger.Error("Error while retrieving role", err)
+		return false, errors.GetErrorMessage(ctx, &errors.DatabaseError{errors.DatabaseGenericError, err.Error()})
 	}
+	return dbRole.UserID == user.Id, nil
 }
@@ -30,6 +30,7 @@ type SharedResults struct {
 	Level         int    `json:"level"`
 	UserId        int    `json:"userId"        req:"nonzero"`
 	SharingStatus bool   `json:"sharingStatus"`
+	RoleId        int    `json:"roleId,omitempty"`
 }
 
 // GetSharingList returns a list of users who have access to a specific AWS account
@@ -49,14 +50,15 @@ func GetSharingList(ctx context.Context, db models.DB, accountId int) ([]SharedR
 				logger.Error("Error getting users from database.", err.Error())
 				return nil, errors.New("Error while getting data from database")
 			}
-			response = append(response, SharedResults{key.ID, dbUser.Email, key.UserPermission, key.UserID, key.SharingAccepted})
+			response = append(response, SharedResults{key.ID, dbUser.Email, key.UserPermission, key.UserID, key.SharingAccepted, int(key.RoleID.Int64)})
 		}
 		return response, nil
 	}
 }
 
-// UpdateSharedUser updates user permission level
-func UpdateSharedUser(ctx context.Context, db models.DB, shareId int, permissionLevel int) (interface{}, error) {
+// UpdateSharedUser updates user permission level, and their custom role if
+// roleId is not nil. A zero roleId removes the custom role.
+func UpdateSharedUser(ctx context.Context, db models.DB, shareId int, permissionLevel int, roleId *int) (interface{}, error) {
 	logger := jsonlog.LoggerFromContextOrDefault(ctx)
 	dbSharedAccount, err := models.SharedAccountByID(db, shareId)
 	if err != nil {
@@ -64,6 +66,9 @@ func UpdateSharedUser(ctx context.Context, db models.DB, shareId int, permission
 		return nil, err
 	}
 	dbSharedAccount.UserPermission = permissionLevel
+	if roleId != nil {
+		dbSharedAccount.RoleID = sql.NullInt64{Int64: int64(*roleId), Valid: *roleId != 0}
+	}
 	err = dbSharedAccount.Update(db)
 	if err != nil {
 		logger.Error("Error while updating user permission", err)
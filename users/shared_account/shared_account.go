@@ -18,6 +18,7 @@ import (
 	"database/sql"
 	"net/http"
 
+	"github.com/trackit/trackit/audit"
 	"github.com/trackit/trackit/db"
 	"github.com/trackit/trackit/errors"
 	"github.com/trackit/trackit/routes"
@@ -34,6 +35,8 @@ func inviteUser(request *http.Request, a routes.Arguments) (int, interface{}) {
 	}
 	tx := a[db.Transaction].(*sql.Tx)
 	user := a[users.AuthenticatedUser].(users.User)
+	audit.AddDetail(a, "email", body.Email)
+	audit.AddDetail(a, "permissionLevel", body.PermissionLevel)
 	return InviteUserWithValidBody(request, body, accountId, tx, user)
 }
 
@@ -41,8 +44,7 @@ func inviteUser(request *http.Request, a routes.Arguments) (int, interface{}) {
 func listSharedUsers(request *http.Request, a routes.Arguments) (int, interface{}) {
 	accountId := a[routes.AwsAccountIdQueryArg].(int)
 	tx := a[db.Transaction].(*sql.Tx)
-	user := a[users.AuthenticatedUser].(users.User)
-	return listSharedUserAccessWithValidBody(request, accountId, tx, user)
+	return listSharedUserAccessWithValidBody(request, accountId, tx)
 }
 
 // updateSharedUsers handles updates of user permission level for team sharing.
@@ -55,6 +57,11 @@ func updateSharedUsers(request *http.Request, a routes.Arguments) (int, interfac
 	}
 	tx := a[db.Transaction].(*sql.Tx)
 	user := a[users.AuthenticatedUser].(users.User)
+	audit.AddDetail(a, "shareId", shareId)
+	audit.AddDetail(a, "permissionLevel", body.PermissionLevel)
+	if body.RoleId != nil {
+		audit.AddDetail(a, "roleId", *body.RoleId)
+	}
 	return updateSharedUserAccessWithValidBody(request, body, shareId, tx, user)
 }
 
@@ -63,18 +70,13 @@ func deleteSharedUsers(request *http.Request, a routes.Arguments) (int, interfac
 	shareId := a[routes.ShareIdQueryArg].(int)
 	tx := a[db.Transaction].(*sql.Tx)
 	user := a[users.AuthenticatedUser].(users.User)
+	audit.AddDetail(a, "shareId", shareId)
 	return deleteSharedUserAccessWithValidBody(request, shareId, tx, user)
 }
 
 // listSharedUserAccessWithValidBody tries to list users who have an access to an AWS account
-func listSharedUserAccessWithValidBody(request *http.Request, accountId int, tx *sql.Tx, user users.User) (int, interface{}) {
+func listSharedUserAccessWithValidBody(request *http.Request, accountId int, tx *sql.Tx) (int, interface{}) {
 	ctx := request.Context()
-	security, err := safetyCheckByAccountId(ctx, tx, accountId, user)
-	if err != nil {
-		return http.StatusBadRequest, err
-	} else if !security {
-		return http.StatusForbidden, errors.GetErrorMessage(ctx, &errors.SharedAccountError{errors.SharedAccountNoPermission, "You do not have permission to view users of this account"})
-	}
 	res, err := GetSharingList(request.Context(), db.Db, accountId)
 	if err != nil {
 		return http.StatusForbidden, errors.GetErrorMessage(ctx, &errors.SharedAccountError{errors.SharedAccountRequestError, "Error retrieving shared users list"})
@@ -86,16 +88,23 @@ func listSharedUserAccessWithValidBody(request *http.Request, accountId int, tx
 // updateSharedUserAccessWithValidBody tries to update users permission level for team sharing
 func updateSharedUserAccessWithValidBody(request *http.Request, body updateUsersSharedAccountRequest, shareId int, tx *sql.Tx, user users.User) (int, interface{}) {
 	ctx := request.Context()
-	security, err := safetyCheckByShareIdAndPermissionLevel(ctx, tx, shareId, body.PermissionLevel, user)
+	if !checkPermissionLevel(body.PermissionLevel) {
+		return http.StatusBadRequest, errors.GetErrorMessage(ctx, &errors.SharedAccountError{errors.SharedAccountBadPermission, "Bad permission level"})
+	}
+	security, err := checkSharedAccountPermissions(ctx, tx, shareId, users.SharedAccountLevelPermissions(body.PermissionLevel), user)
 	if err != nil {
 		return http.StatusBadRequest, err
 	} else if !security {
 		return http.StatusForbidden, errors.GetErrorMessage(ctx, &errors.SharedAccountError{errors.SharedAccountNoPermission, "You do not have permission to edit this sharing"})
 	}
-	if !checkPermissionLevel(body.PermissionLevel) {
-		return http.StatusBadRequest, errors.GetErrorMessage(ctx, &errors.SharedAccountError{errors.SharedAccountBadPermission, "Bad permission level"})
+	if body.RoleId != nil {
+		if ok, err := checkRoleAssignment(ctx, tx, shareId, *body.RoleId, user); err != nil {
+			return http.StatusBadRequest, err
+		} else if !ok {
+			return http.StatusForbidden, errors.GetErrorMessage(ctx, &errors.SharedAccountError{errors.SharedAccountNoPermission, "Only the owner of the account can assign their roles"})
+		}
 	}
-	res, err := UpdateSharedUser(request.Context(), db.Db, shareId, body.PermissionLevel)
+	res, err := UpdateSharedUser(request.Context(), db.Db, shareId, body.PermissionLevel, body.RoleId)
 	if err != nil {
 		return http.StatusForbidden, errors.GetErrorMessage(ctx, &errors.SharedAccountError{errors.SharedAccountRequestError, "Error retrieving shared user list"})
 	}
@@ -105,7 +114,7 @@ func updateSharedUserAccessWithValidBody(request *http.Request, body updateUsers
 // deleteSharedUserAccessWithValidBody tries to delete users from accessing specific shared aws account
 func deleteSharedUserAccessWithValidBody(request *http.Request, shareId int, tx *sql.Tx, user users.User) (int, interface{}) {
 	ctx := request.Context()
-	security, err := safetyCheckByShareId(ctx, tx, shareId, user)
+	security, err := checkSharedAccountPermissions(ctx, tx, shareId, nil, user)
 	if err != nil {
 		return http.StatusBadRequest, err
 	} else if !security {
@@ -0,0 +1,356 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/trackit/trackit/config"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	oidcStateIssuer   = "trackit-sso"
+	oidcStateDuration = 10 * time.Minute
+)
+
+var (
+	ErrSsoDisabled     = errors.New("single sign-on is not configured")
+	ErrInvalidIdToken  = errors.New("ID token is invalid")
+	ErrInvalidSsoState = errors.New("state is invalid or expired")
+
+	oidcHttpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// oidcDiscovery is the part of the OpenID Connect discovery document used
+// by the login flow.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// oidcJwk is a key of the JSON Web Key Set of the provider. Only RSA
+// signature keys are supported.
+type oidcJwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// oidcProvider caches the discovery document and the signing keys of the
+// configured provider. Keys are fetched again when a token is signed with
+// an unknown key, which happens when the provider rotates its keys.
+type oidcProvider struct {
+	sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+// ssoIdentity is the identity of a user, as asserted by the provider.
+type ssoIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Groups        []string
+}
+
+// stateClaims are the claims of the state parameter, which binds the
+// callback of the provider to the nonce of the ID token.
+type stateClaims struct {
+	Nonce string `json:"nonce"`
+	jwt.StandardClaims
+}
+
+var provider oidcProvider
+
+// ssoEnabled tells whether an OpenID Connect provider is configured.
+func ssoEnabled() bool {
+	return config.OidcIssuer != "" && config.OidcClientId != ""
+}
+
+// getJson retrieves a JSON document and decodes it into v.
+func getJson(ctx context.Context, documentUrl string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, documentUrl, nil)
+	if err != nil {
+		return err
+	}
+	res, err := oidcHttpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d for %s", res.StatusCode, documentUrl)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// getDiscovery returns the discovery document of the provider, fetching it
+// the first time.
+func (p *oidcProvider) getDiscovery(ctx context.Context) (oidcDiscovery, error) {
+	p.Lock()
+	defer p.Unlock()
+	if p.discovery != nil {
+		return *p.discovery, nil
+	}
+	var discovery oidcDiscovery
+	if err := getJson(ctx, strings.TrimSuffix(config.OidcIssuer, "/")+oidcDiscoveryPath, &discovery); err != nil {
+		return discovery, err
+	} else if discovery.Issuer != config.OidcIssuer {
+		return discovery, fmt.Errorf("discovery document is for issuer %s", discovery.Issuer)
+	} else if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" {
+		return discovery, errors.New("discovery document is incomplete")
+	}
+	p.discovery = &discovery
+	return discovery, nil
+}
+
+// getKey returns the signing key with the given ID, fetching the key set of
+// the provider if the key is unknown.
+func (p *oidcProvider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.Lock()
+	defer p.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	var keySet struct {
+		Keys []oidcJwk `json:"keys"`
+	}
+	if err := getJson(ctx, discovery.JwksUri, &keySet); err != nil {
+		return nil, err
+	}
+	p.keys = make(map[string]*rsa.PublicKey, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		if jwk.Kty != "RSA" || jwk.Use == "enc" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			p.keys[jwk.Kid] = key
+		}
+	}
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %s", kid)
+}
+
+// publicKey decodes an RSA key from its JSON Web Key representation.
+func (jwk oidcJwk) publicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.N, "="))
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.E, "="))
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 3 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// generateState returns a signed state parameter along with the nonce it
+// holds, to be sent in the authorization request.
+func generateState() (string, string, error) {
+	var random [16]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return "", "", err
+	}
+	nonce := hex.EncodeToString(random[:])
+	now := time.Now()
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, stateClaims{
+		Nonce: nonce,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    oidcStateIssuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(oidcStateDuration).Unix(),
+		},
+	}).SignedString([]byte(config.AuthSecret))
+	return state, nonce, err
+}
+
+// parseState checks a state parameter and returns its nonce.
+func parseState(state string) (string, error) {
+	var claims stateClaims
+	token, err := jwt.ParseWithClaims(state, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return []byte(config.AuthSecret), nil
+	})
+	if err != nil || !token.Valid || claims.Issuer != oidcStateIssuer || claims.Nonce == "" {
+		return "", ErrInvalidSsoState
+	}
+	return claims.Nonce, nil
+}
+
+// getAuthorizationUrl returns the URL users are sent to in order to log in
+// with the provider, along with the state the callback must send back and
+// its nonce.
+func getAuthorizationUrl(ctx context.Context) (string, string, string, error) {
+	discovery, err := provider.getDiscovery(ctx)
+	if err != nil {
+		return "", "", "", err
+	}
+	state, nonce, err := generateState()
+	if err != nil {
+		return "", "", "", err
+	}
+	authorizationUrl, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", "", "", err
+	}
+	query := authorizationUrl.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", config.OidcClientId)
+	query.Set("redirect_uri", config.OidcRedirectUrl)
+	query.Set("scope", "openid email profile")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	authorizationUrl.RawQuery = query.Encode()
+	return authorizationUrl.String(), state, nonce, nil
+}
+
+// exchangeCode exchanges an authorization code for an ID token at the token
+// endpoint of the provider.
+func exchangeCode(ctx context.Context, code string) (string, error) {
+	discovery, err := provider.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {config.OidcRedirectUrl},
+		"client_id":    {config.OidcClientId},
+	}
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(config.OidcClientId), url.QueryEscape(config.OidcClientSecret))
+	res, err := oidcHttpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	var body struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", err
+	} else if res.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("token endpoint responded with status %d: %s %s", res.StatusCode, body.Error, body.ErrorDescription)
+	} else if body.IdToken == "" {
+		return "", errors.New("token endpoint did not return an ID token")
+	}
+	return body.IdToken, nil
+}
+
+// verifyIdToken checks the signature and the claims of an ID token and
+// returns the identity it asserts.
+func verifyIdToken(ctx context.Context, rawIdToken string, nonce string) (ssoIdentity, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(rawIdToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return provider.getKey(ctx, kid)
+	})
+	if err != nil || !token.Valid || !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return ssoIdentity{}, ErrInvalidIdToken
+	}
+	identity := ssoIdentity{
+		Issuer:        stringClaim(claims, "iss"),
+		Subject:       stringClaim(claims, "sub"),
+		Email:         stringClaim(claims, "email"),
+		EmailVerified: claims["email_verified"] == true || claims["email_verified"] == "true",
+		Groups:        stringsClaim(claims, config.OidcGroupsClaim),
+	}
+	if identity.Issuer != config.OidcIssuer || identity.Subject == "" {
+		return ssoIdentity{}, ErrInvalidIdToken
+	} else if !hasAudience(claims, config.OidcClientId) {
+		return ssoIdentity{}, ErrInvalidIdToken
+	} else if stringClaim(claims, "nonce") != nonce {
+		return ssoIdentity{}, ErrInvalidIdToken
+	}
+	return identity, nil
+}
+
+// stringClaim returns a claim if it is a string.
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// stringsClaim returns a claim which is either a string or a list of
+// strings.
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// hasAudience tells whether the aud claim, a string or a list of strings,
+// holds the client ID.
+func hasAudience(claims jwt.MapClaims, clientId string) bool {
+	for _, audience := range stringsClaim(claims, "aud") {
+		if audience == clientId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,314 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/trackit/trackit/config"
+)
+
+const (
+	fakeIssuerKid    = "fake-key"
+	fakeClientId     = "trackit"
+	fakeClientSecret = "secret"
+)
+
+// fakeIssuer is an OpenID Connect provider serving a discovery document, a
+// key set and a token endpoint which returns idToken for code.
+type fakeIssuer struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	code    string
+	idToken string
+}
+
+// newFakeIssuer starts a fake provider and configures single sign-on to use
+// it until the end of the test.
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err.Error())
+	}
+	issuer := &fakeIssuer{key: key, code: "authorization-code"}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                issuer.server.URL,
+			AuthorizationEndpoint: issuer.server.URL + "/authorize",
+			TokenEndpoint:         issuer.server.URL + "/token",
+			JwksUri:               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string][]oidcJwk{"keys": {{
+			Kty: "RSA",
+			Kid: fakeIssuerKid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, ok := r.BasicAuth()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost || !ok || clientId != fakeClientId || clientSecret != fakeClientSecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		} else if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("code") != issuer.code {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		} else {
+			json.NewEncoder(w).Encode(map[string]string{"id_token": issuer.idToken})
+		}
+	})
+	issuer.server = httptest.NewServer(mux)
+	setOidcConfig(t, issuer.server.URL, fakeClientSecret)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+// setOidcConfig configures single sign-on until the end of the test.
+func setOidcConfig(t *testing.T, issuerUrl string, clientSecret string) {
+	previous := []string{config.OidcIssuer, config.OidcClientId, config.OidcClientSecret, config.OidcRedirectUrl, config.OidcGroupsClaim, config.AuthSecret}
+	config.OidcIssuer = issuerUrl
+	config.OidcClientId = fakeClientId
+	config.OidcClientSecret = clientSecret
+	config.OidcRedirectUrl = "https://trackit.example.com/sso/callback"
+	config.OidcGroupsClaim = "groups"
+	config.AuthSecret = "sso-test-secret"
+	provider.discovery, provider.keys = nil, nil
+	t.Cleanup(func() {
+		config.OidcIssuer, config.OidcClientId, config.OidcClientSecret = previous[0], previous[1], previous[2]
+		config.OidcRedirectUrl, config.OidcGroupsClaim, config.AuthSecret = previous[3], previous[4], previous[5]
+		provider.discovery, provider.keys = nil, nil
+	})
+}
+
+// claims returns the claims of a valid ID token for a nonce.
+func (issuer *fakeIssuer) claims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            issuer.server.URL,
+		"sub":            "jane",
+		"aud":            fakeClientId,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          nonce,
+		"email":          "jane@example.trackit.io",
+		"email_verified": true,
+		"groups":         []string{"engineering"},
+	}
+}
+
+// sign signs claims with the key of the provider.
+func (issuer *fakeIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = fakeIssuerKid
+	idToken, err := token.SignedString(issuer.key)
+	if err != nil {
+		t.Fatalf("Failed to sign ID token: %s", err.Error())
+	}
+	return idToken
+}
+
+func TestGetAuthorizationUrl(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	authorizationUrl, state, nonce, err := getAuthorizationUrl(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get authorization URL: %s", err.Error())
+	}
+	parsed, err := url.Parse(authorizationUrl)
+	if err != nil {
+		t.Fatalf("Failed to parse authorization URL: %s", err.Error())
+	}
+	query := parsed.Query()
+	if got := parsed.Scheme + "://" + parsed.Host + parsed.Path; got != issuer.server.URL+"/authorize" {
+		t.Errorf("Authorization endpoint is %s, expected %s", got, issuer.server.URL+"/authorize")
+	}
+	expected := map[string]string{
+		"response_type": "code",
+		"client_id":     fakeClientId,
+		"redirect_uri":  config.OidcRedirectUrl,
+		"state":         state,
+		"nonce":         nonce,
+	}
+	for name, value := range expected {
+		if query.Get(name) != value {
+			t.Errorf("Query parameter %s is %q, expected %q", name, query.Get(name), value)
+		}
+	}
+	if parsedNonce, err := parseState(state); err != nil || parsedNonce != nonce {
+		t.Errorf("State holds nonce %q (%v), expected %q", parsedNonce, err, nonce)
+	}
+}
+
+func TestParseState(t *testing.T) {
+	setOidcConfig(t, "https://issuer.example.com", fakeClientSecret)
+	state, nonce, err := generateState()
+	if err != nil {
+		t.Fatalf("Failed to generate state: %s", err.Error())
+	}
+	signState := func(claims stateClaims, secret string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatalf("Failed to sign state: %s", err.Error())
+		}
+		return signed
+	}
+	now := time.Now()
+	valid := jwt.StandardClaims{Issuer: oidcStateIssuer, ExpiresAt: now.Add(time.Minute).Unix()}
+	expired := jwt.StandardClaims{Issuer: oidcStateIssuer, ExpiresAt: now.Add(-time.Minute).Unix()}
+	otherIssuer := jwt.StandardClaims{Issuer: "someone-else", ExpiresAt: now.Add(time.Minute).Unix()}
+	cases := []struct {
+		name  string
+		state string
+		nonce string
+		err   error
+	}{
+		{"Generated", state, nonce, nil},
+		{"Empty", "", "", ErrInvalidSsoState},
+		{"Tampered", state + "x", "", ErrInvalidSsoState},
+		{"OtherSecret", signState(stateClaims{"nonce", valid}, "other-secret"), "", ErrInvalidSsoState},
+		{"Expired", signState(stateClaims{"nonce", expired}, config.AuthSecret), "", ErrInvalidSsoState},
+		{"OtherIssuer", signState(stateClaims{"nonce", otherIssuer}, config.AuthSecret), "", ErrInvalidSsoState},
+		{"NoNonce", signState(stateClaims{"", valid}, config.AuthSecret), "", ErrInvalidSsoState},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nonce, err := parseState(tc.state)
+			if nonce != tc.nonce || err != tc.err {
+				t.Errorf("Parsed (%q, %v), expected (%q, %v)", nonce, err, tc.nonce, tc.err)
+			}
+		})
+	}
+}
+
+func TestStateCookie(t *testing.T) {
+	setOidcConfig(t, "https://issuer.example.com", fakeClientSecret)
+	cookie := stateCookie("nonce")
+	if cookie.Value != stateBinding("nonce") || cookie.Value == "nonce" {
+		t.Errorf("Cookie value is %q, expected the hash of the nonce", cookie.Value)
+	}
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != oidcStateCookiePath {
+		t.Errorf("Cookie %v is not HttpOnly, Secure and SameSite", cookie)
+	}
+	if cookie.MaxAge != int(oidcStateDuration.Seconds()) {
+		t.Errorf("Cookie lasts %d seconds, expected %v", cookie.MaxAge, oidcStateDuration)
+	}
+	if cleared := stateCookie(""); cleared.MaxAge >= 0 || cleared.Value != "" {
+		t.Errorf("Cookie %v does not remove the state cookie", cleared)
+	}
+	config.OidcRedirectUrl = "http://localhost:3000/sso/callback"
+	if stateCookie("nonce").Secure {
+		t.Errorf("Cookie is Secure although the redirect URL is not HTTPS")
+	}
+}
+
+func TestExchangeCode(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	issuer.idToken = "id-token"
+	if idToken, err := exchangeCode(context.Background(), issuer.code); err != nil || idToken != issuer.idToken {
+		t.Errorf("Exchanged code for (%q, %v), expected %q", idToken, err, issuer.idToken)
+	}
+	if _, err := exchangeCode(context.Background(), "other-code"); err == nil {
+		t.Errorf("Exchanged an unknown code")
+	}
+	config.OidcClientSecret = "other-secret"
+	if _, err := exchangeCode(context.Background(), issuer.code); err == nil {
+		t.Errorf("Exchanged a code with the wrong client secret")
+	}
+}
+
+func TestVerifyIdToken(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err.Error())
+	}
+	with := func(name string, value interface{}) jwt.MapClaims {
+		claims := issuer.claims("nonce")
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	signWith := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, issuer.claims("nonce"))
+		token.Header["kid"] = kid
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("Failed to sign ID token: %s", err.Error())
+		}
+		return idToken
+	}
+	valid := ssoIdentity{
+		Issuer:        issuer.server.URL,
+		Subject:       "jane",
+		Email:         "jane@example.trackit.io",
+		EmailVerified: true,
+		Groups:        []string{"engineering"},
+	}
+	unverified := valid
+	unverified.EmailVerified = false
+	noGroups := valid
+	noGroups.Groups = nil
+	cases := []struct {
+		name     string
+		idToken  string
+		expected ssoIdentity
+		err      error
+	}{
+		{"Valid", issuer.sign(t, issuer.claims("nonce")), valid, nil},
+		{"AudienceList", issuer.sign(t, with("aud", []string{"other", fakeClientId})), valid, nil},
+		{"GroupString", issuer.sign(t, with("groups", "engineering")), valid, nil},
+		{"NoGroups", issuer.sign(t, with("groups", nil)), noGroups, nil},
+		{"UnverifiedEmail", issuer.sign(t, with("email_verified", false)), unverified, nil},
+		{"OtherNonce", issuer.sign(t, with("nonce", "other")), ssoIdentity{}, ErrInvalidIdToken},
+		{"NoNonce", issuer.sign(t, with("nonce", nil)), ssoIdentity{}, ErrInvalidIdToken},
+		{"OtherAudience", issuer.sign(t, with("aud", "other")), ssoIdentity{}, ErrInvalidIdToken},
+		{"OtherIssuer", issuer.sign(t, with("iss", "https://other.example.com")), ssoIdentity{}, ErrInvalidIdToken},
+		{"NoSubject", issuer.sign(t, with("sub", nil)), ssoIdentity{}, ErrInvalidIdToken},
+		{"Expired", issuer.sign(t, with("exp", time.Now().Add(-time.Minute).Unix())), ssoIdentity{}, ErrInvalidIdToken},
+		{"NoExpiry", issuer.sign(t, with("exp", nil)), ssoIdentity{}, ErrInvalidIdToken},
+		{"Hmac", signWith(jwt.SigningMethodHS256, fakeIssuerKid, []byte(fakeClientSecret)), ssoIdentity{}, ErrInvalidIdToken},
+		{"UnknownKey", signWith(jwt.SigningMethodRS256, "other-key", issuer.key), ssoIdentity{}, ErrInvalidIdToken},
+		{"OtherKey", signWith(jwt.SigningMethodRS256, fakeIssuerKid, otherKey), ssoIdentity{}, ErrInvalidIdToken},
+		{"Tampered", issuer.sign(t, issuer.claims("nonce")) + "x", ssoIdentity{}, ErrInvalidIdToken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			identity, err := verifyIdToken(context.Background(), tc.idToken, "nonce")
+			if err != tc.err || !reflect.DeepEqual(identity, tc.expected) {
+				t.Errorf("Verified (%v, %v), expected (%v, %v)", identity, err, tc.expected, tc.err)
+			}
+		})
+	}
+}
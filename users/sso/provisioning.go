@@ -0,0 +1,208 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/users"
+	"github.com/trackit/trackit/users/shared_account"
+)
+
+// ssoAccountType is the account type of the users provisioned by single
+// sign-on, which log in to the regular TrackIt product.
+const ssoAccountType = "trackit"
+
+var (
+	ErrUnverifiedEmail = errors.New("the provider did not assert a verified email")
+	ErrEmailTaken      = errors.New("a user outside of the organization already uses this email")
+
+	permissionLevels = map[string]int{
+		"admin":    shared_account.AdminLevel,
+		"standard": shared_account.StandardLevel,
+		"read":     shared_account.ReadLevel,
+	}
+)
+
+// parseGroupPermissions parses the mapping of groups to shared account
+// permission levels, formatted as "group:level,group:level".
+func parseGroupPermissions(mapping string) (map[string]int, error) {
+	groupPermissions := make(map[string]int)
+	for _, entry := range strings.Split(mapping, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		separator := strings.LastIndex(entry, ":")
+		if separator <= 0 {
+			return nil, fmt.Errorf("group permission %s is not formatted as group:level", entry)
+		}
+		level, ok := permissionLevels[entry[separator+1:]]
+		if !ok {
+			return nil, fmt.Errorf("permission level %s does not exist", entry[separator+1:])
+		}
+		groupPermissions[entry[:separator]] = level
+	}
+	return groupPermissions, nil
+}
+
+// getPermissionLevel returns the most privileged permission level granted
+// by the groups of a user, and false if none of them is mapped.
+func getPermissionLevel(groups []string, groupPermissions map[string]int) (int, bool) {
+	level, mapped := 0, false
+	for _, group := range groups {
+		if groupLevel, ok := groupPermissions[group]; ok && (!mapped || groupLevel < level) {
+			level, mapped = groupLevel, true
+		}
+	}
+	return level, mapped
+}
+
+// getSsoUser returns the user an identity maps to, provisioning it the first
+// time the identity logs in. Users whose groups map to a permission level
+// are regular users the AWS accounts of the parent user are shared with,
+// the others are viewers of the parent user. The shared accounts are
+// updated at each login so that the provider stays the source of truth.
+// The kind of user is only chosen when it is provisioned: viewers whose
+// groups are later mapped to a level stay viewers, since a user can not be
+// turned from a viewer into a regular user without losing its data.
+func getSsoUser(ctx context.Context, tx *sql.Tx, identity ssoIdentity) (users.User, error) {
+	parent, err := users.GetUserWithId(tx, config.OidcParentUser)
+	if err != nil {
+		return users.User{}, fmt.Errorf("failed to get parent user %d: %s", config.OidcParentUser, err.Error())
+	}
+	groupPermissions, err := parseGroupPermissions(config.OidcGroupPermissions)
+	if err != nil {
+		return users.User{}, err
+	}
+	level, mapped := getPermissionLevel(identity.Groups, groupPermissions)
+	var user users.User
+	if dbIdentity, err := models.SsoIdentityByIssuerSubject(tx, identity.Issuer, identity.Subject); err == nil {
+		if user, err = users.GetUserWithId(tx, dbIdentity.UserID); err != nil {
+			return user, err
+		}
+	} else if err != sql.ErrNoRows {
+		return user, err
+	} else if user, err = provisionUser(ctx, tx, identity, parent, mapped); err != nil {
+		return user, err
+	}
+	if user.ParentId == nil && user.Id != parent.Id {
+		err = syncSharedAccounts(tx, user, parent, level, mapped)
+	} else if user.ParentId != nil && mapped {
+		jsonlog.LoggerFromContextOrDefault(ctx).Warning("Single sign-on viewer is mapped to a permission level and stays a viewer.", map[string]interface{}{
+			"userId": user.Id,
+			"groups": identity.Groups,
+		})
+	}
+	return user, err
+}
+
+// provisionUser links an identity to a user of the organization with the
+// same email, or creates the user.
+func provisionUser(ctx context.Context, tx *sql.Tx, identity ssoIdentity, parent users.User, mapped bool) (users.User, error) {
+	logger := jsonlog.LoggerFromContextOrDefault(ctx)
+	if identity.Email == "" || !identity.EmailVerified {
+		return users.User{}, ErrUnverifiedEmail
+	}
+	var user users.User
+	dbUser, err := models.UserByEmailAccountType(tx, identity.Email, ssoAccountType)
+	if err == sql.ErrNoRows {
+		// Viewers are created without an account type
+		dbUser, err = models.UserByEmailAccountType(tx, identity.Email, "")
+	}
+	if err == nil {
+		if dbUser.ID != parent.Id && !(dbUser.ParentUserID.Valid && int(dbUser.ParentUserID.Int64) == parent.Id) {
+			return user, ErrEmailTaken
+		}
+		user = users.UserFromDbUser(*dbUser)
+	} else if err != sql.ErrNoRows {
+		return user, err
+	} else if mapped {
+		var password [24]byte
+		if _, err := rand.Read(password[:]); err != nil {
+			return user, err
+		}
+		if user, err = users.CreateUserWithPassword(ctx, tx, identity.Email, hex.EncodeToString(password[:]), "", ssoAccountType); err != nil {
+			return user, err
+		}
+	} else if user, _, err = users.CreateUserWithParent(ctx, tx, identity.Email, parent); err != nil {
+		return user, err
+	}
+	dbIdentity := models.SsoIdentity{
+		UserID:  user.Id,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	}
+	if err := dbIdentity.Insert(tx); err != nil {
+		return user, err
+	}
+	logger.Info("Single sign-on identity linked to user.", map[string]interface{}{
+		"userId":  user.Id,
+		"issuer":  identity.Issuer,
+		"subject": identity.Subject,
+	})
+	return user, nil
+}
+
+// syncSharedAccounts shares the AWS accounts of the parent user with a user
+// at a permission level, or stops sharing them if the user is not mapped to
+// any level.
+func syncSharedAccounts(tx *sql.Tx, user users.User, parent users.User, level int, mapped bool) error {
+	dbAwsAccounts, err := models.AwsAccountByUserID(tx, parent.Id)
+	if err != nil {
+		return err
+	}
+	for _, dbAwsAccount := range dbAwsAccounts {
+		dbSharedAccounts, err := models.SharedAccountByAccountID(tx, dbAwsAccount.ID)
+		if err != nil {
+			return err
+		}
+		var dbSharedAccount *models.SharedAccount
+		for _, candidate := range dbSharedAccounts {
+			if candidate.UserID == user.Id {
+				dbSharedAccount = candidate
+			}
+		}
+		if dbSharedAccount == nil && mapped {
+			dbSharedAccount = &models.SharedAccount{
+				AccountID:       dbAwsAccount.ID,
+				UserID:          user.Id,
+				UserPermission:  level,
+				SharingAccepted: true,
+			}
+			err = dbSharedAccount.Insert(tx)
+		} else if dbSharedAccount != nil && !mapped {
+			err = dbSharedAccount.Delete(tx)
+		} else if dbSharedAccount != nil && (dbSharedAccount.UserPermission != level || !dbSharedAccount.SharingAccepted) {
+			dbSharedAccount.UserPermission = level
+			dbSharedAccount.SharingAccepted = true
+			err = dbSharedAccount.Update(tx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package sso
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/models"
+	"github.com/trackit/trackit/users"
+	"github.com/trackit/trackit/users/shared_account"
+)
+
+func TestParseGroupPermissions(t *testing.T) {
+	cases := []struct {
+		name     string
+		mapping  string
+		expected map[string]int
+		fails    bool
+	}{
+		{"Empty", "", map[string]int{}, false},
+		{"Levels", "finops:admin, engineering:standard,finance:read,", map[string]int{
+			"finops":      shared_account.AdminLevel,
+			"engineering": shared_account.StandardLevel,
+			"finance":     shared_account.ReadLevel,
+		}, false},
+		{"GroupWithColon", "team:finops:admin", map[string]int{"team:finops": shared_account.AdminLevel}, false},
+		{"NoLevel", "finops", nil, true},
+		{"NoGroup", ":admin", nil, true},
+		{"UnknownLevel", "finops:owner", nil, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			groupPermissions, err := parseGroupPermissions(tc.mapping)
+			if (err != nil) != tc.fails {
+				t.Fatalf("Parsing returned error %v, expected failure: %t", err, tc.fails)
+			} else if !reflect.DeepEqual(groupPermissions, tc.expected) {
+				t.Errorf("Parsed %v, expected %v", groupPermissions, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGetPermissionLevel(t *testing.T) {
+	groupPermissions := map[string]int{
+		"finops":      shared_account.AdminLevel,
+		"engineering": shared_account.StandardLevel,
+		"finance":     shared_account.ReadLevel,
+	}
+	cases := []struct {
+		name   string
+		groups []string
+		level  int
+		mapped bool
+	}{
+		{"NoGroups", nil, 0, false},
+		{"UnmappedGroups", []string{"sales"}, 0, false},
+		{"Read", []string{"sales", "finance"}, shared_account.ReadLevel, true},
+		{"MostPrivileged", []string{"finance", "engineering"}, shared_account.StandardLevel, true},
+		{"Admin", []string{"engineering", "finops", "finance"}, shared_account.AdminLevel, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			level, mapped := getPermissionLevel(tc.groups, groupPermissions)
+			if level != tc.level || mapped != tc.mapped {
+				t.Errorf("Got (%d, %t), expected (%d, %t)", level, mapped, tc.level, tc.mapped)
+			}
+		})
+	}
+}
+
+// These tests are intended to be run against an empty database with the schema
+// already in place. They run in a transaction which is rolled back.
+
+type ssoFixture struct {
+	tx          *sql.Tx
+	parent      users.User
+	awsAccounts []models.AwsAccount
+}
+
+// newSsoFixture creates a parent user owning two AWS accounts and
+// configures single sign-on to provision users under it.
+func newSsoFixture(t *testing.T) ssoFixture {
+	ctx := context.Background()
+	f := ssoFixture{tx: beginTestTransaction(t)}
+	var err error
+	if f.parent, err = users.CreateUserWithPassword(ctx, f.tx, "sso.parent@example.trackit.io", "password", "", ssoAccountType); err != nil {
+		t.Fatalf("Failed to create parent user: %s", err.Error())
+	}
+	for _, identity := range []string{"444444444444", "555555555555"} {
+		f.awsAccounts = append(f.awsAccounts, insertSsoAwsAccount(t, f.tx, f.parent, identity))
+	}
+	previousParent, previousGroupPermissions := config.OidcParentUser, config.OidcGroupPermissions
+	config.OidcParentUser = f.parent.Id
+	config.OidcGroupPermissions = "finops:admin,engineering:standard,finance:read"
+	t.Cleanup(func() {
+		config.OidcParentUser, config.OidcGroupPermissions = previousParent, previousGroupPermissions
+	})
+	return f
+}
+
+func insertSsoAwsAccount(t *testing.T, tx *sql.Tx, user users.User, identity string) models.AwsAccount {
+	now := time.Now().UTC()
+	dbAwsAccount := models.AwsAccount{
+		Created:                               now,
+		UserID:                                user.Id,
+		Pretty:                                identity,
+		RoleArn:                               "arn:aws:iam::" + identity + ":role/trackit",
+		External:                              identity,
+		NextUpdate:                            now,
+		NextUpdatePlugins:                     now,
+		AwsIdentity:                           identity,
+		LastSpreadsheetReportGeneration:       now,
+		NextSpreadsheetReportGeneration:       now,
+		NextUpdateAnomaliesDetection:          now,
+		LastAnomaliesUpdate:                   now,
+		LastMasterSpreadsheetReportGeneration: now,
+		NextMasterSpreadsheetReportGeneration: now,
+		LastTagsSpreadsheetReportGeneration:   now,
+		NextTagsSpreadsheetReportGeneration:   now,
+		TagbotOnboardingStarted:               now,
+	}
+	if err := dbAwsAccount.Insert(tx); err != nil {
+		t.Fatalf("Failed to insert AWS account %s: %s", identity, err.Error())
+	}
+	return dbAwsAccount
+}
+
+// sharedLevels returns the permission level at which each AWS account of
+// the parent user is shared with a user.
+func (f ssoFixture) sharedLevels(t *testing.T, user users.User) map[int]int {
+	levels := make(map[int]int)
+	for _, dbAwsAccount := range f.awsAccounts {
+		dbSharedAccounts, err := models.SharedAccountByAccountID(f.tx, dbAwsAccount.ID)
+		if err != nil {
+			t.Fatalf("Failed to get shared accounts: %s", err.Error())
+		}
+		for _, dbSharedAccount := range dbSharedAccounts {
+			if dbSharedAccount.UserID == user.Id && dbSharedAccount.SharingAccepted {
+				levels[dbAwsAccount.ID] = dbSharedAccount.UserPermission
+			}
+		}
+	}
+	return levels
+}
+
+// allAt returns the levels of the AWS accounts of the fixture all shared
+// at a level.
+func (f ssoFixture) allAt(level int) map[int]int {
+	levels := make(map[int]int)
+	for _, dbAwsAccount := range f.awsAccounts {
+		levels[dbAwsAccount.ID] = level
+	}
+	return levels
+}
+
+func ssoTestIdentity(subject string, email string, groups ...string) ssoIdentity {
+	return ssoIdentity{
+		Issuer:        "https://issuer.example.com",
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: true,
+		Groups:        groups,
+	}
+}
+
+func TestGetSsoUserRegular(t *testing.T) {
+	f := newSsoFixture(t)
+	ctx := context.Background()
+	steps := []struct {
+		name   string
+		groups []string
+		levels map[int]int
+	}{
+		{"Provisioned", []string{"finance", "engineering"}, f.allAt(shared_account.StandardLevel)},
+		{"LevelChanged", []string{"finance"}, f.allAt(shared_account.ReadLevel)},
+		{"Unmapped", []string{"sales"}, map[int]int{}},
+		{"MappedAgain", []string{"finops"}, f.allAt(shared_account.AdminLevel)},
+	}
+	var userId int
+	for _, step := range steps {
+		user, err := getSsoUser(ctx, f.tx, ssoTestIdentity("regular", "sso.regular@example.trackit.io", step.groups...))
+		if err != nil {
+			t.Fatalf("%s: failed to get user: %s", step.name, err.Error())
+		} else if user.ParentId != nil {
+			t.Errorf("%s: user was provisioned as a viewer", step.name)
+		} else if userId != 0 && user.Id != userId {
+			t.Errorf("%s: identity logged in as user %d, expected %d", step.name, user.Id, userId)
+		}
+		userId = user.Id
+		if levels := f.sharedLevels(t, user); !reflect.DeepEqual(levels, step.levels) {
+			t.Errorf("%s: accounts shared at %v, expected %v", step.name, levels, step.levels)
+		}
+	}
+}
+
+func TestGetSsoUserViewer(t *testing.T) {
+	f := newSsoFixture(t)
+	ctx := context.Background()
+	viewer, err := getSsoUser(ctx, f.tx, ssoTestIdentity("viewer", "sso.viewer@example.trackit.io"))
+	if err != nil {
+		t.Fatalf("Failed to provision viewer: %s", err.Error())
+	} else if viewer.ParentId == nil || *viewer.ParentId != f.parent.Id {
+		t.Fatalf("User was not provisioned as a viewer of the parent user")
+	}
+	// The kind of user is only chosen when it is provisioned
+	mapped, err := getSsoUser(ctx, f.tx, ssoTestIdentity("viewer", "sso.viewer@example.trackit.io", "finops"))
+	if err != nil {
+		t.Fatalf("Failed to get viewer: %s", err.Error())
+	} else if mapped.Id != viewer.Id || mapped.ParentId == nil {
+		t.Errorf("Viewer mapped to a permission level became user %d", mapped.Id)
+	} else if levels := f.sharedLevels(t, mapped); len(levels) != 0 {
+		t.Errorf("Accounts shared with viewer at %v", levels)
+	}
+}
+
+func TestGetSsoUserLinking(t *testing.T) {
+	f := newSsoFixture(t)
+	ctx := context.Background()
+	viewer, _, err := users.CreateUserWithParent(ctx, f.tx, "sso.existing@example.trackit.io", f.parent)
+	if err != nil {
+		t.Fatalf("Failed to create viewer: %s", err.Error())
+	}
+	if _, err := users.CreateUserWithPassword(ctx, f.tx, "sso.outsider@example.trackit.io", "password", "", ssoAccountType); err != nil {
+		t.Fatalf("Failed to create outsider: %s", err.Error())
+	}
+	unverified := ssoTestIdentity("unverified", "sso.unverified@example.trackit.io", "finops")
+	unverified.EmailVerified = false
+	cases := []struct {
+		name     string
+		identity ssoIdentity
+		userId   int
+		err      error
+	}{
+		{"Parent", ssoTestIdentity("parent", f.parent.Email, "finops"), f.parent.Id, nil},
+		{"ExistingViewer", ssoTestIdentity("existing", viewer.Email), viewer.Id, nil},
+		{"Outsider", ssoTestIdentity("outsider", "sso.outsider@example.trackit.io", "finops"), 0, ErrEmailTaken},
+		{"UnverifiedEmail", unverified, 0, ErrUnverifiedEmail},
+		{"NoEmail", ssoTestIdentity("noemail", "", "finops"), 0, ErrUnverifiedEmail},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, err := getSsoUser(ctx, f.tx, tc.identity)
+			if err != tc.err || user.Id != tc.userId {
+				t.Errorf("Got user %d (%v), expected %d (%v)", user.Id, err, tc.userId, tc.err)
+			}
+		})
+	}
+	if levels := f.sharedLevels(t, f.parent); len(levels) != 0 {
+		t.Errorf("Accounts of the parent user shared with itself at %v", levels)
+	}
+}
+
+// TestOidcLogin follows a login from the authorization URL to the user,
+// against a fake provider.
+func TestOidcLogin(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	f := newSsoFixture(t)
+	ctx := context.Background()
+	_, state, nonce, err := getAuthorizationUrl(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get authorization URL: %s", err.Error())
+	} else if err = recordState(f.tx, nonce, time.Now()); err != nil {
+		t.Fatalf("Failed to record state: %s", err.Error())
+	}
+	claims := issuer.claims(nonce)
+	claims["groups"] = []string{"engineering", "sales"}
+	issuer.idToken = issuer.sign(t, claims)
+	consumed, err := consumeState(f.tx, callbackRequest(nonce), state, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to consume state: %s", err.Error())
+	}
+	rawIdToken, err := exchangeCode(ctx, issuer.code)
+	if err != nil {
+		t.Fatalf("Failed to exchange code: %s", err.Error())
+	}
+	identity, err := verifyIdToken(ctx, rawIdToken, consumed)
+	if err != nil {
+		t.Fatalf("Failed to verify ID token: %s", err.Error())
+	}
+	user, err := getSsoUser(ctx, f.tx, identity)
+	if err != nil {
+		t.Fatalf("Failed to get user: %s", err.Error())
+	} else if user.Email != "jane@example.trackit.io" || user.ParentId != nil {
+		t.Errorf("Logged in as %s, expected a regular user jane@example.trackit.io", user.Email)
+	} else if levels := f.sharedLevels(t, user); !reflect.DeepEqual(levels, f.allAt(shared_account.StandardLevel)) {
+		t.Errorf("Accounts shared at %v, expected %v", levels, f.allAt(shared_account.StandardLevel))
+	}
+	dbIdentity, err := models.SsoIdentityByIssuerSubject(f.tx, issuer.server.URL, "jane")
+	if err != nil || dbIdentity.UserID != user.Id {
+		t.Errorf("Identity was not linked to user %d: %v", user.Id, err)
+	}
+	// A replayed callback is refused before the code reaches the provider
+	if _, err := consumeState(f.tx, callbackRequest(nonce), state, time.Now()); err != ErrInvalidSsoState {
+		t.Errorf("Consumed state twice: %v", err)
+	}
+}
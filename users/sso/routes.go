@@ -0,0 +1,135 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package sso
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/trackit/jsonlog"
+
+	"github.com/trackit/trackit/db"
+	"github.com/trackit/trackit/routes"
+	"github.com/trackit/trackit/users"
+)
+
+type (
+	// oidcLoginResponseBody is the response to a request for the URL of
+	// the provider.
+	oidcLoginResponseBody struct {
+		Url   string `json:"url"`
+		State string `json:"state"`
+	}
+
+	// oidcCallbackRequestBody holds the parameters the provider redirected
+	// the user with.
+	oidcCallbackRequestBody struct {
+		Code  string `json:"code"  req:"nonzero"`
+		State string `json:"state" req:"nonzero"`
+	}
+)
+
+func init() {
+	routes.MethodMuxer{
+		http.MethodGet: routes.Handler{Func: getOidcLogin}.With(
+			db.RequestTransaction{Db: db.Db},
+			routes.Documentation{
+				Summary:     "start a single sign-on login",
+				Description: "Responds with the URL of the OpenID Connect provider the user must be sent to, and the state the provider will send back. The state is bound to the user agent by an HttpOnly cookie, which must be sent back with the state.",
+			},
+		),
+		http.MethodPost: routes.Handler{Func: postOidcCallback}.With(
+			routes.RequestContentType{"application/json"},
+			routes.RequestBody{oidcCallbackRequestBody{"authorizationcode", "state"}},
+			db.RequestTransaction{Db: db.Db},
+			routes.Documentation{
+				Summary:     "finish a single sign-on login",
+				Description: "Exchanges the code the OpenID Connect provider redirected the user with for an identity, provisions the user if needed and returns a JWT token and the user's data. The state must have been issued to the same user agent, and can only be used once.",
+			},
+		),
+	}.H().Register("/user/sso/oidc")
+}
+
+// getOidcLogin is a route handler which returns the authorization URL of
+// the provider. The state is recorded and bound to the user agent with a
+// cookie.
+func getOidcLogin(w http.ResponseWriter, r *http.Request, a routes.Arguments) (int, interface{}) {
+	logger := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	if !ssoEnabled() {
+		return http.StatusNotFound, errors.New("Single sign-on is not configured.")
+	}
+	authorizationUrl, state, nonce, err := getAuthorizationUrl(r.Context())
+	if err != nil {
+		logger.Error("Failed to get OpenID Connect authorization URL.", err.Error())
+		return http.StatusInternalServerError, errors.New("Failed to contact the single sign-on provider.")
+	}
+	if err := recordState(tx, nonce, time.Now()); err != nil {
+		logger.Error("Failed to record single sign-on state.", err.Error())
+		return http.StatusInternalServerError, errors.New("Failed to start the single sign-on login.")
+	}
+	http.SetCookie(w, stateCookie(nonce))
+	return http.StatusOK, oidcLoginResponseBody{authorizationUrl, state}
+}
+
+// postOidcCallback is a route handler which logs in the user the provider
+// asserted the identity of.
+func postOidcCallback(w http.ResponseWriter, r *http.Request, a routes.Arguments) (int, interface{}) {
+	var body oidcCallbackRequestBody
+	routes.MustRequestBody(a, &body)
+	logger := jsonlog.LoggerFromContextOrDefault(r.Context())
+	tx := a[db.Transaction].(*sql.Tx)
+	if !ssoEnabled() {
+		return http.StatusNotFound, errors.New("Single sign-on is not configured.")
+	}
+	// The state is consumed outside of the request transaction, so that it
+	// can not be used again even if the login fails
+	nonce, err := consumeState(db.Db, r, body.State, time.Now())
+	http.SetCookie(w, stateCookie(""))
+	if err == ErrInvalidSsoState {
+		return http.StatusBadRequest, errors.New("The single sign-on state is invalid or expired. Try again.")
+	} else if err != nil {
+		logger.Error("Failed to consume single sign-on state.", err.Error())
+		return http.StatusInternalServerError, errors.New("Failed to log in with single sign-on.")
+	}
+	rawIdToken, err := exchangeCode(r.Context(), body.Code)
+	if err != nil {
+		logger.Warning("Failed to exchange OpenID Connect code.", err.Error())
+		return http.StatusForbidden, errors.New("The single sign-on provider refused the login. Try again.")
+	}
+	identity, err := verifyIdToken(r.Context(), rawIdToken, nonce)
+	if err != nil {
+		logger.Warning("Invalid OpenID Connect ID token.", err.Error())
+		return http.StatusForbidden, errors.New("The single sign-on provider refused the login. Try again.")
+	}
+	user, err := getSsoUser(r.Context(), tx, identity)
+	if err == ErrUnverifiedEmail || err == ErrEmailTaken {
+		logger.Warning("Single sign-on user can not be provisioned.", map[string]interface{}{
+			"subject": identity.Subject,
+			"email":   identity.Email,
+			"error":   err.Error(),
+		})
+		return http.StatusForbidden, errors.New("No user can be provisioned for this identity. Contact your administrator.")
+	} else if err != nil {
+		logger.Error("Failed to get single sign-on user.", map[string]interface{}{
+			"subject": identity.Subject,
+			"error":   err.Error(),
+		})
+		return http.StatusInternalServerError, errors.New("Failed to log in with single sign-on.")
+	}
+	return users.LogAuthenticatedUserIn(r, user)
+}
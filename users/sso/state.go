@@ -0,0 +1,95 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package sso
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trackit/trackit/config"
+	"github.com/trackit/trackit/models"
+)
+
+const (
+	oidcStateCookie     = "trackit_sso_state"
+	oidcStateCookiePath = "/user/sso/oidc"
+)
+
+// stateBinding returns the hash of the nonce of a state. It is the value of
+// the cookie binding the state to the user agent which started the login,
+// and the key of the state in the sso_login_state table.
+func stateBinding(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// stateCookie returns the cookie binding a state to the user agent which
+// started the login. An empty nonce returns a cookie removing it.
+func stateCookie(nonce string) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     oidcStateCookiePath,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(config.OidcRedirectUrl, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+	if nonce == "" {
+		cookie.MaxAge = -1
+	} else {
+		cookie.Value = stateBinding(nonce)
+		cookie.MaxAge = int(oidcStateDuration.Seconds())
+	}
+	return cookie
+}
+
+// recordState stores a new state until it is used or expires. The expired
+// states are removed at the same time.
+func recordState(db models.DB, nonce string, now time.Time) error {
+	const deleteExpired = `DELETE FROM sso_login_state WHERE expires <= ?`
+	const insert = `INSERT INTO sso_login_state(nonce_hash, expires) VALUES (?, ?)`
+	if _, err := db.Exec(deleteExpired, now); err != nil {
+		return err
+	}
+	_, err := db.Exec(insert, stateBinding(nonce), now.Add(oidcStateDuration))
+	return err
+}
+
+// consumeState checks that a state was issued to the user agent sending it
+// back and was not used yet, and returns its nonce. The state is removed so
+// that it can not be used again.
+func consumeState(db models.DB, r *http.Request, state string, now time.Time) (string, error) {
+	const consume = `DELETE FROM sso_login_state WHERE nonce_hash = ? AND expires > ?`
+	nonce, err := parseState(state)
+	if err != nil {
+		return "", err
+	}
+	binding := stateBinding(nonce)
+	if cookie, err := r.Cookie(oidcStateCookie); err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(binding)) != 1 {
+		return "", ErrInvalidSsoState
+	}
+	res, err := db.Exec(consume, binding, now)
+	if err != nil {
+		return "", err
+	} else if consumed, err := res.RowsAffected(); err != nil {
+		return "", err
+	} else if consumed != 1 {
+		return "", ErrInvalidSsoState
+	}
+	return nonce, nil
+}
@@ -0,0 +1,92 @@
+//   Copyright 2021 MSolution.IO
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package sso
+
+// These tests are intended to be run against an empty database with the schema
+// already in place. They run in a transaction which is rolled back.
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/trackit/trackit/db"
+)
+
+// beginTestTransaction begins a transaction which is rolled back at the end
+// of the test.
+func beginTestTransaction(t *testing.T) *sql.Tx {
+	tx, err := db.Db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %s", err.Error())
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// callbackRequest returns a callback request sending the cookie of a nonce,
+// or no cookie if it is empty.
+func callbackRequest(nonce string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, oidcStateCookiePath, nil)
+	if nonce != "" {
+		r.AddCookie(stateCookie(nonce))
+	}
+	return r
+}
+
+func TestConsumeState(t *testing.T) {
+	setOidcConfig(t, "https://issuer.example.com", fakeClientSecret)
+	tx := beginTestTransaction(t)
+	now := time.Now()
+	newState := func(recordedAt time.Time) (string, string) {
+		state, nonce, err := generateState()
+		if err != nil {
+			t.Fatalf("Failed to generate state: %s", err.Error())
+		} else if err = recordState(tx, nonce, recordedAt); err != nil {
+			t.Fatalf("Failed to record state: %s", err.Error())
+		}
+		return state, nonce
+	}
+	state, nonce := newState(now)
+	otherState, otherNonce := newState(now)
+	if _, err := consumeState(tx, callbackRequest(""), state, now); err != ErrInvalidSsoState {
+		t.Errorf("Consumed state without cookie: %v", err)
+	}
+	if _, err := consumeState(tx, callbackRequest(otherNonce), state, now); err != ErrInvalidSsoState {
+		t.Errorf("Consumed state with the cookie of another state: %v", err)
+	}
+	if consumed, err := consumeState(tx, callbackRequest(nonce), state, now); err != nil || consumed != nonce {
+		t.Errorf("Consumed state as (%q, %v), expected %q", consumed, err, nonce)
+	}
+	if _, err := consumeState(tx, callbackRequest(nonce), state, now); err != ErrInvalidSsoState {
+		t.Errorf("Consumed state twice: %v", err)
+	}
+	if _, err := consumeState(tx, callbackRequest(otherNonce), otherState, now.Add(oidcStateDuration)); err != ErrInvalidSsoState {
+		t.Errorf("Consumed expired state: %v", err)
+	}
+	if _, err := consumeState(tx, callbackRequest(otherNonce), "not a state", now); err != ErrInvalidSsoState {
+		t.Errorf("Consumed invalid state: %v", err)
+	}
+	// The state is signed, but was not recorded by the login route
+	unrecordedState, unrecordedNonce, err := generateState()
+	if err != nil {
+		t.Fatalf("Failed to generate state: %s", err.Error())
+	}
+	if _, err := consumeState(tx, callbackRequest(unrecordedNonce), unrecordedState, now); err != ErrInvalidSsoState {
+		t.Errorf("Consumed unrecorded state: %v", err)
+	}
+}
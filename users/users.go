@@ -41,6 +41,22 @@ type User struct {
 	NextExternal           string `json:"-"`
 	ParentId               *int   `json:"parentId,omitempty"`
 	AwsCustomerEntitlement bool   `json:"aws_customer_entitlement"`
+	AwsAccountAllowlist    []int  `json:"-"`
+}
+
+// CanAccessAwsAccount tells whether the user may access an AWS account. It
+// is only false if the user authenticated with an API token restricted to
+// other accounts.
+func (u User) CanAccessAwsAccount(aaId int) bool {
+	if len(u.AwsAccountAllowlist) == 0 {
+		return true
+	}
+	for _, id := range u.AwsAccountAllowlist {
+		if id == aaId {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateUserWithPassword creates a user with an email and a password. A nil